@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+// fileStore is a dashboard.WidgetStore persisted as a single JSON document.
+// Every mutation rewrites the file, so it suits local and small deployments
+// and lets widgetctl administer dashboards without a database.
+type fileStore struct {
+	path string
+
+	mu    sync.Mutex
+	state fileStoreState
+}
+
+// fileStoreState is the on-disk document. Maps keep the output sorted so
+// the file diffs cleanly.
+type fileStoreState struct {
+	Areas       map[string]dashboard.WidgetAreaDefinition `json:"areas"`
+	Definitions map[string]dashboard.WidgetDefinition     `json:"definitions"`
+	Instances   map[string]fileStoreInstance              `json:"instances"`
+	Assignments map[string][]string                       `json:"assignments"`
+	NextID      int                                       `json:"next_id"`
+}
+
+type fileStoreInstance struct {
+	ID            string              `json:"id"`
+	DefinitionID  string              `json:"definition_id"`
+	Configuration map[string]any      `json:"configuration,omitempty"`
+	Metadata      map[string]any      `json:"metadata,omitempty"`
+	Visibility    fileStoreVisibility `json:"visibility"`
+}
+
+type fileStoreVisibility struct {
+	Roles    []string   `json:"roles,omitempty"`
+	StartAt  *time.Time `json:"start_at,omitempty"`
+	EndAt    *time.Time `json:"end_at,omitempty"`
+	Audience []string   `json:"audience,omitempty"`
+}
+
+var (
+	_ dashboard.WidgetStore          = (*fileStore)(nil)
+	_ dashboard.WidgetInstanceLister = (*fileStore)(nil)
+)
+
+// openFileStore loads path; a missing file is an empty store that is created
+// on the first write.
+func openFileStore(path string) (*fileStore, error) {
+	store := &fileStore{path: path}
+	data, err := os.ReadFile(path) // #nosec G304 -- the store path comes from the operator's store configuration.
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("widgetctl: read store %s: %w", path, err)
+	default:
+		if err := json.Unmarshal(data, &store.state); err != nil {
+			return nil, fmt.Errorf("widgetctl: decode store %s: %w", path, err)
+		}
+	}
+	if store.state.Areas == nil {
+		store.state.Areas = map[string]dashboard.WidgetAreaDefinition{}
+	}
+	if store.state.Definitions == nil {
+		store.state.Definitions = map[string]dashboard.WidgetDefinition{}
+	}
+	if store.state.Instances == nil {
+		store.state.Instances = map[string]fileStoreInstance{}
+	}
+	if store.state.Assignments == nil {
+		store.state.Assignments = map[string][]string{}
+	}
+	return store, nil
+}
+
+// save writes the document through a temporary file so a failed write never
+// truncates the store.
+func (s *fileStore) save() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("widgetctl: encode store: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("widgetctl: mkdir %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, ".widgetctl-store-*")
+	if err != nil {
+		return fmt.Errorf("widgetctl: write store %s: %w", s.path, err)
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	err = errors.Join(err, tmp.Close())
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		return errors.Join(fmt.Errorf("widgetctl: write store %s: %w", s.path, err), os.Remove(tmp.Name()))
+	}
+	return nil
+}
+
+// Areas lists the registered areas, sorted by code.
+func (s *fileStore) Areas() []dashboard.WidgetAreaDefinition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]dashboard.WidgetAreaDefinition, 0, len(s.state.Areas))
+	for _, code := range slices.Sorted(maps.Keys(s.state.Areas)) {
+		out = append(out, s.state.Areas[code])
+	}
+	return out
+}
+
+func (s *fileStore) EnsureArea(_ context.Context, def dashboard.WidgetAreaDefinition) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.state.Areas[def.Code]; ok {
+		return false, nil
+	}
+	s.state.Areas[def.Code] = def
+	return true, s.save()
+}
+
+func (s *fileStore) EnsureDefinition(_ context.Context, def dashboard.WidgetDefinition) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.state.Definitions[def.Code]; ok {
+		return false, nil
+	}
+	s.state.Definitions[def.Code] = def
+	return true, s.save()
+}
+
+func (s *fileStore) CreateInstance(_ context.Context, input dashboard.CreateWidgetInstanceInput) (dashboard.WidgetInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.NextID++
+	record := fileStoreInstance{
+		ID:            fmt.Sprintf("widget-%d", s.state.NextID),
+		DefinitionID:  input.DefinitionID,
+		Configuration: input.Configuration,
+		Metadata:      input.Metadata,
+		Visibility: fileStoreVisibility{
+			Roles:    input.Visibility.Roles,
+			StartAt:  input.Visibility.StartAt,
+			EndAt:    input.Visibility.EndAt,
+			Audience: input.Visibility.Audience,
+		},
+	}
+	s.state.Instances[record.ID] = record
+	return s.instance(record), s.save()
+}
+
+func (s *fileStore) GetInstance(_ context.Context, instanceID string) (dashboard.WidgetInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.state.Instances[instanceID]
+	if !ok {
+		return dashboard.WidgetInstance{}, fmt.Errorf("widgetctl: widget %s not found", instanceID)
+	}
+	return s.instance(record), nil
+}
+
+// ListInstances returns every instance, sorted by ID.
+func (s *fileStore) ListInstances(_ context.Context) ([]dashboard.WidgetInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]dashboard.WidgetInstance, 0, len(s.state.Instances))
+	for _, id := range slices.Sorted(maps.Keys(s.state.Instances)) {
+		out = append(out, s.instance(s.state.Instances[id]))
+	}
+	return out, nil
+}
+
+func (s *fileStore) DeleteInstance(_ context.Context, instanceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.state.Instances[instanceID]; !ok {
+		return fmt.Errorf("widgetctl: widget %s not found", instanceID)
+	}
+	delete(s.state.Instances, instanceID)
+	for area, ids := range s.state.Assignments {
+		s.state.Assignments[area] = slices.DeleteFunc(ids, func(id string) bool { return id == instanceID })
+	}
+	return s.save()
+}
+
+func (s *fileStore) AssignInstance(_ context.Context, input dashboard.AssignWidgetInput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.state.Instances[input.InstanceID]; !ok {
+		return fmt.Errorf("widgetctl: widget %s not found", input.InstanceID)
+	}
+	ids := s.state.Assignments[input.AreaCode]
+	position := len(ids)
+	if input.Position != nil && *input.Position >= 0 && *input.Position < len(ids) {
+		position = *input.Position
+	}
+	s.state.Assignments[input.AreaCode] = slices.Insert(ids, position, input.InstanceID)
+	return s.save()
+}
+
+func (s *fileStore) UpdateInstance(_ context.Context, input dashboard.UpdateWidgetInstanceInput) (dashboard.WidgetInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.state.Instances[input.InstanceID]
+	if !ok {
+		return dashboard.WidgetInstance{}, fmt.Errorf("widgetctl: widget %s not found", input.InstanceID)
+	}
+	if input.Configuration != nil {
+		record.Configuration = input.Configuration
+	}
+	if input.Metadata != nil {
+		record.Metadata = input.Metadata
+	}
+	s.state.Instances[record.ID] = record
+	return s.instance(record), s.save()
+}
+
+func (s *fileStore) ReorderArea(_ context.Context, input dashboard.ReorderAreaInput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Assignments[input.AreaCode] = slices.Clone(input.WidgetIDs)
+	return s.save()
+}
+
+// ResolveArea returns the area's instances in order, keeping those visible
+// to the audience (when one is given) at the current time.
+func (s *fileStore) ResolveArea(_ context.Context, input dashboard.ResolveAreaInput) (dashboard.ResolvedArea, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	area := dashboard.ResolvedArea{AreaCode: input.AreaCode, Widgets: []dashboard.WidgetInstance{}}
+	for _, id := range s.state.Assignments[input.AreaCode] {
+		record, ok := s.state.Instances[id]
+		if !ok || !record.Visibility.allows(input.Audience, now) {
+			continue
+		}
+		area.Widgets = append(area.Widgets, s.instance(record))
+	}
+	return area, nil
+}
+
+// AreaInstances returns every instance assigned to area in order,
+// regardless of visibility.
+func (s *fileStore) AreaInstances(area string) []dashboard.WidgetInstance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []dashboard.WidgetInstance{}
+	for _, id := range s.state.Assignments[area] {
+		if record, ok := s.state.Instances[id]; ok {
+			out = append(out, s.instance(record))
+		}
+	}
+	return out
+}
+
+func (v fileStoreVisibility) allows(audience []string, now time.Time) bool {
+	if v.StartAt != nil && now.Before(*v.StartAt) {
+		return false
+	}
+	if v.EndAt != nil && now.After(*v.EndAt) {
+		return false
+	}
+	if len(audience) == 0 || len(v.Roles) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(v.Roles, func(role string) bool { return slices.Contains(audience, role) })
+}
+
+// instance converts a record, filling in the area it is assigned to.
+func (s *fileStore) instance(record fileStoreInstance) dashboard.WidgetInstance {
+	inst := dashboard.WidgetInstance{
+		ID:            record.ID,
+		DefinitionID:  record.DefinitionID,
+		Configuration: record.Configuration,
+		Metadata:      record.Metadata,
+	}
+	for area, ids := range s.state.Assignments {
+		if slices.Contains(ids, record.ID) {
+			inst.AreaCode = area
+			break
+		}
+	}
+	return inst
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+type i18nCmd struct {
+	Extract i18nExtractCmd `cmd:"" help:"Extract translation keys from templates, Go sources, manifests, and definitions."`
+}
+
+type i18nExtractCmd struct {
+	Templates []string `type:"existingdir" help:"Template directories to scan for T(...) calls (use multiple --templates flags)."`
+	Source    []string `type:"existingdir" help:"Go source directories to scan for translateOrFallback calls."`
+	Manifest  []string `type:"existingfile" help:"Widget manifests whose definitions contribute title keys."`
+	Builtin   bool     `default:"true" negatable:"" help:"Include keys from the embedded templates, built-in providers, and default definitions."`
+	Format    string   `enum:"json,yaml" default:"json" help:"Output format (json or yaml)."`
+	Messages  bool     `help:"Write a flat key -> fallback map instead of the annotated catalog."`
+	Out       string   `type:"path" help:"Output file (defaults to stdout)."`
+}
+
+func (cmd *i18nExtractCmd) Run(_ context.Context) error {
+	catalog := dashboard.NewTranslationCatalog()
+	if cmd.Builtin {
+		builtin, err := dashboard.BuiltinTranslationCatalog()
+		if err != nil {
+			return fmt.Errorf("widgetctl: builtin catalog: %w", err)
+		}
+		catalog = builtin
+	}
+	for _, dir := range append(append([]string{}, cmd.Templates...), cmd.Source...) {
+		if err := catalog.AddFS(os.DirFS(dir), filepath.ToSlash(filepath.Clean(dir))+"/"); err != nil {
+			return fmt.Errorf("widgetctl: scan %s: %w", dir, err)
+		}
+	}
+	for _, path := range cmd.Manifest {
+		doc, err := dashboard.ReadManifest(path)
+		if err != nil {
+			return err
+		}
+		catalog.AddManifest(doc)
+	}
+
+	var payload any = map[string]any{"keys": catalog.Keys()}
+	if cmd.Messages {
+		payload = catalog.Messages()
+	}
+
+	var out io.Writer = os.Stdout
+	if cmd.Out != "" {
+		if err := os.MkdirAll(filepath.Dir(cmd.Out), 0o750); err != nil {
+			return fmt.Errorf("widgetctl: mkdir %s: %w", filepath.Dir(cmd.Out), err)
+		}
+		file, err := os.Create(cmd.Out) // #nosec G304 -- output path is supplied by the CLI user.
+		if err != nil {
+			return fmt.Errorf("widgetctl: create %s: %w", cmd.Out, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := writeCatalog(out, cmd.Format, payload); err != nil {
+		return err
+	}
+	if cmd.Out != "" {
+		if _, err := fmt.Fprintf(os.Stderr, "✓ Wrote %d keys to %s\n", len(catalog.Keys()), cmd.Out); err != nil {
+			return fmt.Errorf("widgetctl: write status: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeCatalog(out io.Writer, format string, payload any) error {
+	if format == "yaml" {
+		encoder := yaml.NewEncoder(out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(payload); err != nil {
+			return fmt.Errorf("widgetctl: write catalog: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("widgetctl: close catalog encoder: %w", err)
+		}
+		return nil
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(payload); err != nil {
+		return fmt.Errorf("widgetctl: write catalog: %w", err)
+	}
+	return nil
+}
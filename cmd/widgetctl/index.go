@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+type indexCmd struct {
+	Paths   []string `arg:"" type:"existingpath" help:"Manifest files or directories (scanned for .yaml, .yml and .json files)."`
+	Name    string   `help:"Index name recorded in the output."`
+	Channel []string `help:"Only include widgets from these channels (community, partner, internal; use multiple --channel flags)."`
+	Out     string   `short:"o" type:"path" help:"Output file (.json writes JSON, anything else YAML); defaults to stdout."`
+}
+
+func (cmd *indexCmd) Run(_ context.Context) error {
+	index, err := cmd.build()
+	if err != nil {
+		return err
+	}
+	if cmd.Out == "" {
+		return writeCatalogIndex(os.Stdout, index, false)
+	}
+	if err := os.MkdirAll(filepath.Dir(cmd.Out), 0o750); err != nil {
+		return fmt.Errorf("widgetctl: mkdir %s: %w", filepath.Dir(cmd.Out), err)
+	}
+	file, err := os.Create(cmd.Out) // #nosec G304 -- the index path is supplied by the CLI user.
+	if err != nil {
+		return fmt.Errorf("widgetctl: create index %s: %w", cmd.Out, err)
+	}
+	writeErr := writeCatalogIndex(file, index, strings.EqualFold(filepath.Ext(cmd.Out), ".json"))
+	if closeErr := file.Close(); writeErr == nil && closeErr != nil {
+		return fmt.Errorf("widgetctl: close index %s: %w", cmd.Out, closeErr)
+	}
+	return writeErr
+}
+
+// build reads every manifest and keeps the widgets of the selected channels,
+// dropping manifests left without widgets.
+func (cmd *indexCmd) build() (*dashboard.CatalogIndex, error) {
+	paths, err := manifestPaths(cmd.Paths)
+	if err != nil {
+		return nil, err
+	}
+	channels := make([]string, 0, len(cmd.Channel))
+	for _, channel := range cmd.Channel {
+		channels = append(channels, strings.ToLower(strings.TrimSpace(channel)))
+	}
+	docs := make([]*dashboard.WidgetManifestDocument, 0, len(paths))
+	for _, path := range paths {
+		doc, err := dashboard.ReadManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(channels) > 0 {
+			doc.Widgets = slices.DeleteFunc(doc.Widgets, func(widget dashboard.ManifestWidget) bool {
+				channel := strings.ToLower(strings.TrimSpace(widget.Provider.Channel))
+				if channel == "" {
+					channel = dashboard.ChannelCommunity
+				}
+				return !slices.Contains(channels, channel)
+			})
+		}
+		if len(doc.Widgets) == 0 {
+			continue
+		}
+		if doc.Name == "" {
+			doc.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		docs = append(docs, doc)
+	}
+	index := dashboard.NewCatalogIndex(cmd.Name, docs...)
+	if err := index.Validate(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+func writeCatalogIndex(out io.Writer, index *dashboard.CatalogIndex, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(index); err != nil {
+			return fmt.Errorf("widgetctl: write index: %w", err)
+		}
+		return nil
+	}
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(index); err != nil {
+		return fmt.Errorf("widgetctl: write index: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("widgetctl: close index encoder: %w", err)
+	}
+	return nil
+}
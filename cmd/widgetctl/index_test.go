@@ -0,0 +1,61 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+func TestIndexAggregatesManifestsByChannel(t *testing.T) {
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "community.yaml", `
+version: 2
+name: acme-community
+widgets:
+  - definition:
+      code: acme.widget.weather
+      name: Weather
+  - definition:
+      code: acme.widget.secret
+      name: Secret
+    provider:
+      channel: internal
+`)
+	writeTestManifest(t, dir, "partner.yaml", `
+version: 2
+widgets:
+  - definition:
+      code: acme.widget.billing
+      name: Billing
+    provider:
+      channel: partner
+`)
+	out := filepath.Join(dir, "dist", "index.json")
+	cmd := indexCmd{Paths: []string{dir}, Name: "acme", Channel: []string{"community", "Partner"}, Out: out}
+	if err := cmd.Run(t.Context()); err != nil {
+		t.Fatalf("index: %v", err)
+	}
+
+	index, err := dashboard.ReadCatalogIndex(out)
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	if index.Name != "acme" || len(index.Manifests) != 2 {
+		t.Fatalf("unexpected index: %+v", index)
+	}
+	if widgets := index.Manifests[0].Widgets; index.Manifests[0].Name != "acme-community" || len(widgets) != 1 || widgets[0].Definition.Code != "acme.widget.weather" {
+		t.Fatalf("expected internal widget to be dropped, got %+v", index.Manifests[0])
+	}
+	if index.Manifests[1].Name != "partner" {
+		t.Fatalf("expected unnamed manifest to take its file name, got %q", index.Manifests[1].Name)
+	}
+
+	registry := dashboard.NewRegistry()
+	if err := registry.MergeCatalogIndex(index, dashboard.ChannelPartner); err != nil {
+		t.Fatalf("merge index: %v", err)
+	}
+	if entries := registry.Catalog().Index; len(entries) != 1 || entries[0].Definition.Code != "acme.widget.billing" {
+		t.Fatalf("unexpected merged catalog: %+v", entries)
+	}
+}
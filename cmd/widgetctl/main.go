@@ -19,8 +19,16 @@ import (
 
 type cli struct {
 	Scaffold scaffoldCmd `cmd:"" help:"Scaffold a widget definition, provider stub, and manifest entry."`
+	I18n     i18nCmd     `cmd:"" name:"i18n" help:"Translation catalog tooling."`
+	Validate validateCmd `cmd:"" help:"Validate manifests, definition schemas, and translation coverage."`
+	Lint     lintCmd     `cmd:"" help:"Validate manifests and flag incomplete or outdated entries."`
+	Preview  previewCmd  `cmd:"" help:"Serve a live-reloading preview of a single manifest widget."`
+	Store    storeCmd    `cmd:"" help:"Inspect and administer the widgets of a dashboard store."`
+	Index    indexCmd    `cmd:"" help:"Aggregate manifests into a catalog index for the widget catalog browser."`
 }
 
+const defaultProviderPackage = "github.com/goliatone/go-dashboard/components/dashboard"
+
 type scaffoldCmd struct {
 	Code            string   `required:"" help:"Fully-qualified widget code (e.g. acme.widget.stats)."`
 	Name            string   `required:"" help:"Display name for the widget."`
@@ -33,19 +41,24 @@ type scaffoldCmd struct {
 	Capabilities    []string `help:"Provider capability labels (html,json,sse,...)."`
 	DocsURL         string   `help:"Link to provider documentation."`
 	Channel         string   `help:"Distribution channel label (community, partner, internal)."`
-	ProviderPackage string   `default:"github.com/goliatone/go-dashboard/components/dashboard" help:"Go package where the provider factory lives."`
+	ProviderPackage string   `default:"github.com/goliatone/go-dashboard/components/dashboard" help:"Go package where the provider factory lives (typed widgets default to the generated package)."`
 	ProviderEntry   string   `help:"Factory identifier recorded in the manifest (defaults to New<Widget>Provider)."`
 	ProviderOut     string   `help:"File path for the generated provider stub (defaults to components/dashboard/providers/<code>_provider.go)."`
 	Overwrite       bool     `help:"Overwrite existing provider stub / manifest entry if present."`
 	SkipProvider    bool     `name:"skip-provider" help:"Skip provider stub generation."`
+	Typed           bool     `help:"Generate a typed WidgetSpec package, widget template, test, and registration hook instead of the legacy provider stub."`
+	OutDir          string   `type:"path" help:"Directory of the generated typed widget package (defaults to widgets/<name>)."`
+	Package         string   `help:"Go package name of the typed widget (defaults to the output directory name)."`
+	TemplateDir     string   `type:"path" default:"templates/widgets" help:"Directory for the generated widget template."`
 }
 
 func main() {
 	ctx := kong.Parse(&cli{},
-		kong.Description("Widget scaffolding utility for go-dashboard manifests."),
+		kong.Description("Widget scaffolding and translation tooling for go-dashboard manifests."),
 		kong.UsageOnError(),
+		kong.BindTo(context.Background(), (*context.Context)(nil)),
 	)
-	err := ctx.Run(context.Background())
+	err := ctx.Run()
 	ctx.FatalIfErrorf(err)
 }
 
@@ -76,12 +89,33 @@ func (cmd *scaffoldCmd) Run(_ context.Context) error {
 
 	baseName := deriveBaseName(cmd.Code)
 	providerType := baseName + "Provider"
+	providerPackage := cmd.ProviderPackage
 	providerEntry := cmd.ProviderEntry
+	var typed typedScaffold
+	if cmd.Typed {
+		typed = newTypedScaffold(cmd.Code, cmd.Name, schema, cmd.OutDir, cmd.Package, cmd.TemplateDir)
+		if providerPackage == defaultProviderPackage {
+			if providerPackage, err = importPath(typed.DirPath); err != nil {
+				return err
+			}
+		}
+		if providerEntry == "" {
+			providerEntry = fmt.Sprintf("%s.New%sProvider", providerPackage, typed.Base)
+		}
+	}
 	if providerEntry == "" {
-		providerEntry = fmt.Sprintf("%s.New%s", cmd.ProviderPackage, providerType)
+		providerEntry = fmt.Sprintf("%s.New%s", providerPackage, providerType)
 	}
 
 	entry := cmd.manifestEntry(schema, providerEntry)
+	entry.Provider.Package = providerPackage
+	if cmd.Typed {
+		// Typed widgets are wired through their provider factory (manifest v2).
+		doc.Version = dashboard.ManifestVersion
+		entry.Version = "0.1.0"
+		entry.Requires.Dashboard = dashboard.Version
+		entry.Provider.Factory = cmd.Code
+	}
 	cmd.applyManifestEntry(doc, entry)
 
 	sort.Slice(doc.Widgets, func(i, j int) bool {
@@ -92,6 +126,17 @@ func (cmd *scaffoldCmd) Run(_ context.Context) error {
 		return err
 	}
 
+	if cmd.Typed && !cmd.SkipProvider {
+		paths, err := typed.write(cmd.Overwrite)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(os.Stdout, "✓ Added %s to %s and generated %s\n", cmd.Code, manifestPath, strings.Join(paths, ", ")); err != nil {
+			return fmt.Errorf("widgetctl: write status: %w", err)
+		}
+		return nil
+	}
+
 	if cmd.SkipProvider {
 		if _, err := fmt.Fprintf(os.Stdout, "✓ Added %s to %s (provider entry recorded as %s)\n", cmd.Code, manifestPath, providerEntry); err != nil {
 			return fmt.Errorf("widgetctl: write status: %w", err)
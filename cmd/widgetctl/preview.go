@@ -0,0 +1,490 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+// previewArea is the only area of the preview dashboard.
+const previewArea = "preview.main"
+
+// previewTemplates holds the page that renders the preview area on its own.
+//
+//go:embed templates/widgetctl/preview.html
+var previewTemplates embed.FS
+
+type previewCmd struct {
+	Manifest  string   `required:"" type:"existingfile" help:"Manifest that defines the widget."`
+	Widget    string   `required:"" help:"Definition code of the widget to preview."`
+	Config    string   `type:"existingfile" help:"JSON file with the instance configuration."`
+	Data      string   `type:"existingfile" help:"JSON fixture served as the widget data instead of the registered provider."`
+	Templates string   `type:"path" default:"templates" help:"Template directory layered over the embedded templates (ignored when missing)."`
+	Themes    string   `type:"existingdir" help:"Directory of theme documents offered in the theme switcher."`
+	Locale    []string `help:"Locales offered in the locale switcher (defaults to en plus the definition's localized names)."`
+	Addr      string   `default:"127.0.0.1:8090" help:"Address the preview server listens on."`
+}
+
+func (cmd *previewCmd) Run(_ context.Context) error {
+	server, err := newPreviewServer(*cmd)
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{
+		Addr:              cmd.Addr,
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	if _, err := fmt.Fprintf(os.Stdout, "Previewing %s at http://%s/\n", cmd.Widget, cmd.Addr); err != nil {
+		return fmt.Errorf("widgetctl: write status: %w", err)
+	}
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("widgetctl: preview server: %w", err)
+	}
+	return nil
+}
+
+// previewServer renders one manifest widget on an otherwise empty dashboard.
+// The manifest, configuration and data files are read on every request and
+// templates are rendered in dev mode, so edits show up on the next render.
+type previewServer struct {
+	cmd      previewCmd
+	renderer dashboard.Renderer
+	themes   *dashboard.FileThemeProvider
+}
+
+func newPreviewServer(cmd previewCmd) (*previewServer, error) {
+	pages, err := fs.Sub(previewTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	options := []dashboard.TemplateRendererOption{
+		dashboard.WithTemplateDevMode(true),
+		dashboard.WithTemplateOverlay(pages),
+	}
+	if info, err := os.Stat(cmd.Templates); err == nil && info.IsDir() {
+		options = append(options, dashboard.WithTemplateOverlay(os.DirFS(cmd.Templates)))
+	}
+	renderer, err := dashboard.NewTemplateRenderer(options...)
+	if err != nil {
+		return nil, fmt.Errorf("widgetctl: template renderer: %w", err)
+	}
+	server := &previewServer{cmd: cmd, renderer: renderer}
+	if cmd.Themes != "" {
+		if server.themes, err = dashboard.NewDirThemeProvider(cmd.Themes, dashboard.WithThemeReload(0)); err != nil {
+			return nil, fmt.Errorf("widgetctl: load themes: %w", err)
+		}
+	}
+	if _, err := server.widget(); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// Handler serves the preview page, the rendered widget, its view model, the
+// change fingerprint polled for live reload, and the shell assets.
+func (p *previewServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", p.handleIndex)
+	mux.HandleFunc("GET /render", p.handleRender)
+	mux.HandleFunc("GET /view-model", p.handleViewModel)
+	mux.HandleFunc("GET /version", p.handleVersion)
+	mux.Handle("GET "+dashboard.DefaultShellAssetsPath, dashboard.ShellAssetsHandler(dashboard.DefaultShellAssetsPath))
+	return mux
+}
+
+// widget reads the manifest entry being previewed.
+func (p *previewServer) widget() (dashboard.ManifestWidget, error) {
+	doc, err := dashboard.ReadManifest(p.cmd.Manifest)
+	if err != nil {
+		return dashboard.ManifestWidget{}, err
+	}
+	for _, widget := range doc.Widgets {
+		if widget.Definition.Code == p.cmd.Widget {
+			return widget, nil
+		}
+	}
+	return dashboard.ManifestWidget{}, fmt.Errorf("widgetctl: manifest %s does not define widget %s", p.cmd.Manifest, p.cmd.Widget)
+}
+
+// previewTelemetry keeps the errors the service reports while rendering.
+type previewTelemetry struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+func (t *previewTelemetry) Record(_ context.Context, event string, payload map[string]any) {
+	if message, ok := payload["error"].(string); ok {
+		t.mu.Lock()
+		t.errors = append(t.errors, event+": "+message)
+		t.mu.Unlock()
+	}
+}
+
+// previewRun is everything needed to resolve the widget for one request.
+type previewRun struct {
+	controller *dashboard.Controller
+	viewer     dashboard.ViewerContext
+	telemetry  *previewTelemetry
+	problems   []string
+}
+
+func (p *previewServer) run(r *http.Request) (*previewRun, error) {
+	widget, err := p.widget()
+	if err != nil {
+		return nil, err
+	}
+	def := widget.Definition
+	registry := dashboard.NewRegistry()
+	if err := registry.RegisterDefinition(def); err != nil {
+		return nil, fmt.Errorf("widgetctl: register %s: %w", def.Code, err)
+	}
+	run := &previewRun{telemetry: &previewTelemetry{}}
+	switch {
+	case p.cmd.Data != "":
+		if err := registry.RegisterProvider(def.Code, dashboard.ProviderFunc(func(context.Context, dashboard.WidgetContext) (dashboard.WidgetData, error) {
+			data := dashboard.WidgetData{}
+			return data, readJSONFile(p.cmd.Data, &data)
+		})); err != nil {
+			return nil, fmt.Errorf("widgetctl: register fixture provider: %w", err)
+		}
+	case widget.Provider.Factory != "":
+		factory, ok := registry.ProviderFactory(widget.Provider.Factory)
+		if !ok {
+			run.problems = append(run.problems, fmt.Sprintf("provider factory %q is not linked into widgetctl; pass --data to preview with a fixture", widget.Provider.Factory))
+			break
+		}
+		provider, err := factory(def)
+		if err == nil {
+			err = registry.RegisterProvider(def.Code, provider)
+		}
+		if err != nil {
+			run.problems = append(run.problems, fmt.Sprintf("provider factory %s: %v", widget.Provider.Factory, err))
+		}
+	}
+	if _, ok := registry.Provider(def.Code); !ok && p.cmd.Data == "" && widget.Provider.Factory == "" {
+		run.problems = append(run.problems, "no provider is registered for this widget; pass --data to preview with a fixture")
+	}
+
+	config := map[string]any{}
+	if p.cmd.Config != "" {
+		if err := readJSONFile(p.cmd.Config, &config); err != nil {
+			return nil, err
+		}
+	}
+	if err := dashboard.NewJSONSchemaValidator().Validate(def, config); err != nil {
+		run.problems = append(run.problems, err.Error())
+	}
+	instance := dashboard.WidgetInstance{
+		ID:            "preview",
+		DefinitionID:  def.Code,
+		AreaCode:      previewArea,
+		Configuration: config,
+	}
+	if def.SchemaVersion > 0 {
+		// The preview configuration is written against the current schema.
+		instance.Metadata = map[string]any{"schema_version": def.SchemaVersion}
+	}
+
+	query := r.URL.Query()
+	themeName, variant, _ := strings.Cut(query.Get("theme"), "/")
+	opts := dashboard.Options{
+		WidgetStore: previewStore{instance: instance},
+		Providers:   registry,
+		Telemetry:   run.telemetry,
+		Areas:       []string{previewArea},
+	}
+	if p.themes != nil {
+		opts.ThemeProvider = p.themes
+		opts.ThemeSelector = func(context.Context, dashboard.ViewerContext) dashboard.ThemeSelector {
+			return dashboard.ThemeSelector{Name: themeName, Variant: variant}
+		}
+	}
+	run.viewer = dashboard.ViewerContext{UserID: "preview", Locale: query.Get("locale")}
+	if run.viewer.Locale == "" {
+		run.viewer.Locale = p.locales(def)[0]
+	}
+	run.controller = dashboard.NewController(dashboard.ControllerOptions{
+		Service:  dashboard.NewService(opts),
+		Renderer: p.renderer,
+		Template: "widgetctl/preview.html",
+		Areas:    []dashboard.AreaSlot{{Slot: "main", Code: previewArea}},
+		PageDecorator: func(_ context.Context, viewer dashboard.ViewerContext, page dashboard.Page) (dashboard.Page, error) {
+			page.Title = dashboard.ResolveLocalizedValue(def.NameLocalized, viewer.Locale, def.Name)
+			page.Description = def.Code
+			if page.Assets == nil {
+				page.Assets = &dashboard.PageAssets{}
+			}
+			page.Assets.AddShellAssets(dashboard.DefaultShellAssetsPath)
+			return page, nil
+		},
+	})
+	return run, nil
+}
+
+// errors lists configuration problems followed by render-time errors.
+func (run *previewRun) errors() []string {
+	run.telemetry.mu.Lock()
+	defer run.telemetry.mu.Unlock()
+	return append(append([]string{}, run.problems...), run.telemetry.errors...)
+}
+
+func (p *previewServer) handleRender(w http.ResponseWriter, r *http.Request) {
+	run, err := p.run(r)
+	if err == nil {
+		var html strings.Builder
+		if err = run.controller.RenderPage(r.Context(), run.viewer, &html); err == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(html.String()))
+			return
+		}
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// previewViewModel is the JSON shown next to the rendered widget.
+type previewViewModel struct {
+	Widget *dashboard.WidgetFrame `json:"widget,omitempty"`
+	Errors []string               `json:"errors"`
+}
+
+func (p *previewServer) handleViewModel(w http.ResponseWriter, r *http.Request) {
+	result := previewViewModel{Errors: []string{}}
+	run, err := p.run(r)
+	if err == nil {
+		var page dashboard.Page
+		if page, err = run.controller.Page(r.Context(), run.viewer); err == nil {
+			if area, ok := page.Area("main"); ok && len(area.Widgets) > 0 {
+				result.Widget = &area.Widgets[0]
+			}
+		}
+		result.Errors = run.errors()
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+}
+
+func (p *previewServer) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte(p.fingerprint()))
+}
+
+// fingerprint changes whenever a watched file is added, removed or modified.
+func (p *previewServer) fingerprint() string {
+	hash := fnv.New64a()
+	for _, root := range []string{p.cmd.Manifest, p.cmd.Config, p.cmd.Data, p.cmd.Templates, p.cmd.Themes} {
+		if root == "" {
+			continue
+		}
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			_, _ = fmt.Fprintf(hash, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+	}
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// locales lists the locale switcher options: the --locale values in order,
+// or "en" followed by the definition's localized names. Each locale appears
+// once.
+func (p *previewServer) locales(def dashboard.WidgetDefinition) []string {
+	candidates := p.cmd.Locale
+	if len(candidates) == 0 {
+		candidates = append([]string{"en"}, slices.Sorted(maps.Keys(def.NameLocalized))...)
+	}
+	locales := make([]string, 0, len(candidates))
+	for _, locale := range candidates {
+		if locale != "" && locale != "default" && !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	if len(locales) == 0 {
+		return []string{"en"}
+	}
+	return locales
+}
+
+// themeOptions lists every theme, and every variant of switchable themes, as
+// `name` or `name/variant` values.
+func (p *previewServer) themeOptions(ctx context.Context) []string {
+	if p.themes == nil {
+		return nil
+	}
+	var options []string
+	for _, name := range p.themes.Themes() {
+		selection, err := p.themes.SelectTheme(ctx, dashboard.ThemeSelector{Name: name})
+		if err != nil || len(selection.Variants) == 0 {
+			options = append(options, name)
+			continue
+		}
+		for variant := range selection.Variants {
+			options = append(options, name+"/"+variant)
+		}
+	}
+	slices.Sort(options)
+	return options
+}
+
+func (p *previewServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	widget, err := p.widget()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err = previewPage.Execute(w, map[string]any{
+		"Code":    widget.Definition.Code,
+		"Name":    widget.Definition.Name,
+		"Locales": p.locales(widget.Definition),
+		"Themes":  p.themeOptions(r.Context()),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func readJSONFile(path string, target any) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- preview files are explicitly passed on the command line.
+	if err != nil {
+		return fmt.Errorf("widgetctl: read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("widgetctl: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// previewStore is a read-only WidgetStore holding the previewed instance.
+type previewStore struct {
+	instance dashboard.WidgetInstance
+}
+
+var errPreviewReadOnly = errors.New("widgetctl: preview store is read-only")
+
+func (previewStore) EnsureArea(context.Context, dashboard.WidgetAreaDefinition) (bool, error) {
+	return false, nil
+}
+
+func (previewStore) EnsureDefinition(context.Context, dashboard.WidgetDefinition) (bool, error) {
+	return false, nil
+}
+
+func (previewStore) CreateInstance(context.Context, dashboard.CreateWidgetInstanceInput) (dashboard.WidgetInstance, error) {
+	return dashboard.WidgetInstance{}, errPreviewReadOnly
+}
+
+func (s previewStore) GetInstance(_ context.Context, instanceID string) (dashboard.WidgetInstance, error) {
+	if instanceID != s.instance.ID {
+		return dashboard.WidgetInstance{}, fmt.Errorf("widgetctl: instance %s not found", instanceID)
+	}
+	return s.instance, nil
+}
+
+func (previewStore) DeleteInstance(context.Context, string) error {
+	return errPreviewReadOnly
+}
+
+func (previewStore) AssignInstance(context.Context, dashboard.AssignWidgetInput) error {
+	return errPreviewReadOnly
+}
+
+func (previewStore) UpdateInstance(context.Context, dashboard.UpdateWidgetInstanceInput) (dashboard.WidgetInstance, error) {
+	return dashboard.WidgetInstance{}, errPreviewReadOnly
+}
+
+func (previewStore) ReorderArea(context.Context, dashboard.ReorderAreaInput) error {
+	return errPreviewReadOnly
+}
+
+func (s previewStore) ResolveArea(_ context.Context, input dashboard.ResolveAreaInput) (dashboard.ResolvedArea, error) {
+	area := dashboard.ResolvedArea{AreaCode: input.AreaCode}
+	if input.AreaCode == previewArea {
+		area.Widgets = []dashboard.WidgetInstance{s.instance}
+	}
+	return area, nil
+}
+
+var previewPage = template.Must(template.New("preview").Parse(`<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{ .Name }} · widgetctl preview</title>
+  <style>
+    body { margin: 0; font: 14px/1.4 system-ui, sans-serif; display: flex; flex-direction: column; height: 100vh; }
+    header { display: flex; gap: 1rem; align-items: center; padding: .5rem 1rem; border-bottom: 1px solid #ddd; }
+    header h1 { font-size: 1rem; margin: 0 auto 0 0; }
+    main { flex: 1; display: grid; grid-template-columns: 3fr 2fr; min-height: 0; }
+    iframe { width: 100%; height: 100%; border: 0; }
+    pre { margin: 0; padding: 1rem; overflow: auto; background: #f6f8fa; border-left: 1px solid #ddd; }
+    .errors { color: #b42318; }
+  </style>
+</head>
+<body>
+  <header>
+    <h1>{{ .Name }} <small>{{ .Code }}</small></h1>
+    <label>Locale <select id="locale">{{ range .Locales }}<option>{{ . }}</option>{{ end }}</select></label>
+    {{ if .Themes }}<label>Theme <select id="theme">{{ range .Themes }}<option>{{ . }}</option>{{ end }}</select></label>{{ end }}
+  </header>
+  <main>
+    <iframe id="widget" title="Widget preview"></iframe>
+    <pre id="view-model"></pre>
+  </main>
+  <script>
+    const controls = ["locale", "theme"].map((id) => document.getElementById(id)).filter(Boolean);
+    const query = () => new URLSearchParams(controls.map((el) => [el.id, el.value])).toString();
+    const refresh = async () => {
+      document.getElementById("widget").src = "/render?" + query();
+      const pane = document.getElementById("view-model");
+      try {
+        const res = await fetch("/view-model?" + query());
+        const body = await res.json();
+        pane.className = body.errors.length ? "errors" : "";
+        pane.textContent = JSON.stringify(body, null, 2);
+      } catch (err) {
+        pane.className = "errors";
+        pane.textContent = String(err);
+      }
+    };
+    controls.forEach((el) => el.addEventListener("change", refresh));
+    let version = null;
+    setInterval(async () => {
+      try {
+        const next = await (await fetch("/version")).text();
+        if (version !== null && next !== version) refresh();
+        version = next;
+      } catch (err) {}
+    }, 1000);
+    refresh();
+  </script>
+</body>
+</html>
+`))
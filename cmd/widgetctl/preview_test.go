@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+func TestPreviewRendersWidgetWithFixtureData(t *testing.T) {
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "widgets.yaml", `
+version: 2
+widgets:
+  - definition:
+      code: acme.widget.greeting
+      name: Greeting
+      name_localized:
+        es: Saludo
+      schema:
+        type: object
+        properties:
+          limit:
+            type: integer
+    provider:
+      factory: acme.widget.greeting
+`)
+	writeTestManifest(t, dir, "config.json", `{"limit": 3}`)
+	writeTestManifest(t, dir, "data.json", `{"message": "hello preview"}`)
+	if err := os.MkdirAll(filepath.Join(dir, "templates", "widgets"), 0o750); err != nil {
+		t.Fatalf("mkdir templates: %v", err)
+	}
+	templatePath := filepath.Join(dir, "templates", "widgets", "greeting.html")
+	if err := os.WriteFile(templatePath, []byte(`<p class="greeting">{{ widget.data.message }} ({{ widget.config.limit|integer }})</p>`), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	server, err := newPreviewServer(previewCmd{
+		Manifest:  filepath.Join(dir, "widgets.yaml"),
+		Widget:    "acme.widget.greeting",
+		Config:    filepath.Join(dir, "config.json"),
+		Data:      filepath.Join(dir, "data.json"),
+		Templates: filepath.Join(dir, "templates"),
+	})
+	if err != nil {
+		t.Fatalf("newPreviewServer: %v", err)
+	}
+	handler := server.Handler()
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d: %s", target, rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	if body := get("/").Body.String(); !strings.Contains(body, "<option>es</option>") {
+		t.Fatalf("expected locale switcher to offer es, got %s", body)
+	}
+	if body := get("/render?locale=es").Body.String(); !strings.Contains(body, "hello preview (3)") || !strings.Contains(body, "Saludo") {
+		t.Fatalf("expected rendered widget, got %s", body)
+	}
+
+	var viewModel previewViewModel
+	if err := json.Unmarshal(get("/view-model").Body.Bytes(), &viewModel); err != nil {
+		t.Fatalf("decode view model: %v", err)
+	}
+	data, _ := viewModel.Widget.Data.(map[string]any)
+	if len(viewModel.Errors) != 0 || data["message"] != "hello preview" {
+		t.Fatalf("unexpected view model: %+v", viewModel)
+	}
+
+	version := get("/version").Body.String()
+	writeTestManifest(t, dir, "config.json", `{"limit": "many"}`)
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(filepath.Join(dir, "config.json"), later, later); err != nil {
+		t.Fatalf("touch config: %v", err)
+	}
+	if get("/version").Body.String() == version {
+		t.Fatalf("expected version to change after editing the config")
+	}
+	viewModel = previewViewModel{}
+	if err := json.Unmarshal(get("/view-model").Body.Bytes(), &viewModel); err != nil {
+		t.Fatalf("decode view model: %v", err)
+	}
+	if len(viewModel.Errors) == 0 {
+		t.Fatalf("expected schema error for invalid config, got %+v", viewModel)
+	}
+}
+
+func TestPreviewRejectsUnknownWidget(t *testing.T) {
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "widgets.yaml", "version: 2\nwidgets:\n  - definition:\n      code: acme.widget.one\n      name: One\n")
+	_, err := newPreviewServer(previewCmd{Manifest: filepath.Join(dir, "widgets.yaml"), Widget: "acme.widget.two"})
+	if err == nil || !strings.Contains(err.Error(), "does not define widget acme.widget.two") {
+		t.Fatalf("expected unknown widget error, got %v", err)
+	}
+}
+
+func TestPreviewLocalesListEachLocaleOnce(t *testing.T) {
+	def := dashboard.WidgetDefinition{NameLocalized: map[string]string{"es": "Hola", "en": "Hello", "default": "Hi", "de": "Hallo"}}
+	if got := (&previewServer{}).locales(def); !slices.Equal(got, []string{"en", "de", "es"}) {
+		t.Fatalf("unexpected default locales %v", got)
+	}
+	server := &previewServer{cmd: previewCmd{Locale: []string{"fr", "en", "fr", "", "en"}}}
+	if got := server.locales(def); !slices.Equal(got, []string{"fr", "en"}) {
+		t.Fatalf("unexpected flag locales %v", got)
+	}
+}
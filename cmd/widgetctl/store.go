@@ -0,0 +1,556 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+	"github.com/goliatone/go-dashboard/components/dashboard/commands"
+)
+
+const fileStoreDriver = "file"
+
+type storeCmd struct {
+	StoreConfig string `required:"" type:"existingfile" env:"WIDGETCTL_STORE_CONFIG" help:"Store configuration file (YAML or JSON)."`
+	Format      string `enum:"table,json" default:"table" help:"Output format (table or json)."`
+
+	Areas   storeAreasCmd   `cmd:"" help:"List widget areas and how many widgets each holds."`
+	List    storeListCmd    `cmd:"" help:"List widget instances in area order."`
+	Show    storeShowCmd    `cmd:"" help:"Show a widget instance's configuration and metadata."`
+	Add     storeAddCmd     `cmd:"" help:"Add a widget instance to an area."`
+	Update  storeUpdateCmd  `cmd:"" help:"Replace or patch a widget instance's configuration."`
+	Remove  storeRemoveCmd  `cmd:"" help:"Remove widget instances."`
+	Reorder storeReorderCmd `cmd:"" help:"Reorder the widgets of an area."`
+	Seed    storeSeedCmd    `cmd:"" help:"Register the default areas and definitions, optionally with the starter layout."`
+}
+
+// storeConfig describes the store widgetctl administers. Relative paths are
+// resolved against the configuration file.
+type storeConfig struct {
+	// Driver selects the store implementation; widgetctl ships "file".
+	Driver string `json:"driver" yaml:"driver"`
+	// Path is the JSON document the file driver reads and writes.
+	Path string `json:"path" yaml:"path"`
+	// Areas lists the area codes the dashboard renders (defaults to the
+	// built-in areas).
+	Areas []string `json:"areas,omitempty" yaml:"areas,omitempty"`
+	// Manifests contribute the definitions used to validate configurations.
+	Manifests []string `json:"manifests,omitempty" yaml:"manifests,omitempty"`
+}
+
+// storeAdmin wires a store to the service and commands the subcommands use.
+type storeAdmin struct {
+	store    *fileStore
+	registry *dashboard.Registry
+	service  *dashboard.Service
+	areas    []string
+	events   *storeEvents
+	format   string
+	out      io.Writer
+}
+
+// storeEvents remembers the last refresh event so commands that do not
+// return the instance they touched (e.g. assign) can report it.
+type storeEvents struct {
+	last dashboard.WidgetEvent
+}
+
+func (e *storeEvents) WidgetUpdated(_ context.Context, event dashboard.WidgetEvent) error {
+	e.last = event
+	return nil
+}
+
+func (cmd *storeCmd) open(out io.Writer) (*storeAdmin, error) {
+	raw, err := os.ReadFile(cmd.StoreConfig) // #nosec G304 -- the store configuration is passed explicitly by the operator.
+	if err != nil {
+		return nil, fmt.Errorf("widgetctl: read store config: %w", err)
+	}
+	var cfg storeConfig
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("widgetctl: decode store config %s: %w", cmd.StoreConfig, err)
+	}
+	if cfg.Driver == "" {
+		cfg.Driver = fileStoreDriver
+	}
+	if cfg.Driver != fileStoreDriver {
+		return nil, fmt.Errorf("widgetctl: unsupported store driver %q (widgetctl ships the %q driver)", cfg.Driver, fileStoreDriver)
+	}
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("widgetctl: store config %s requires path", cmd.StoreConfig)
+	}
+	base := filepath.Dir(cmd.StoreConfig)
+	resolve := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(base, path)
+	}
+	store, err := openFileStore(resolve(cfg.Path))
+	if err != nil {
+		return nil, err
+	}
+	registry := dashboard.NewRegistry()
+	for _, path := range cfg.Manifests {
+		doc, err := dashboard.ReadManifest(resolve(path))
+		if err != nil {
+			return nil, err
+		}
+		// Only definitions are needed to validate configurations; providers
+		// are never invoked here.
+		for _, widget := range doc.Widgets {
+			if err := registry.RegisterDefinition(widget.Definition); err != nil {
+				return nil, fmt.Errorf("widgetctl: register %s from %s: %w", widget.Definition.Code, path, err)
+			}
+		}
+	}
+	areas := slices.Clone(cfg.Areas)
+	if len(areas) == 0 {
+		for _, area := range dashboard.DefaultAreaDefinitions() {
+			areas = append(areas, area.Code)
+		}
+	}
+	for _, area := range store.Areas() {
+		if !slices.Contains(areas, area.Code) {
+			areas = append(areas, area.Code)
+		}
+	}
+	events := &storeEvents{}
+	return &storeAdmin{
+		store:    store,
+		registry: registry,
+		service: dashboard.NewService(dashboard.Options{
+			WidgetStore: store,
+			Providers:   registry,
+			RefreshHook: events,
+			Areas:       areas,
+		}),
+		areas:  areas,
+		events: events,
+		format: cmd.Format,
+		out:    out,
+	}, nil
+}
+
+// storeAreaRow is one line of `store areas`.
+type storeAreaRow struct {
+	Code    string `json:"code"`
+	Name    string `json:"name,omitempty"`
+	Widgets int    `json:"widgets"`
+}
+
+// storeWidgetRow is one instance as listed and shown by the store commands.
+type storeWidgetRow struct {
+	ID            string         `json:"id"`
+	Area          string         `json:"area,omitempty"`
+	Position      int            `json:"position,omitempty"`
+	DefinitionID  string         `json:"definition_id"`
+	SchemaVersion int            `json:"schema_version"`
+	Configuration map[string]any `json:"configuration"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+}
+
+func (a *storeAdmin) areaRows() []storeAreaRow {
+	names := map[string]string{}
+	for _, area := range dashboard.DefaultAreaDefinitions() {
+		names[area.Code] = area.Name
+	}
+	for _, area := range a.store.Areas() {
+		names[area.Code] = area.Name
+	}
+	rows := make([]storeAreaRow, 0, len(a.areas))
+	for _, code := range a.areas {
+		rows = append(rows, storeAreaRow{Code: code, Name: names[code], Widgets: len(a.store.AreaInstances(code))})
+	}
+	return rows
+}
+
+// widgetRows lists the instances of one area, or of every area followed by
+// unassigned instances when area is empty.
+func (a *storeAdmin) widgetRows(ctx context.Context, area string) ([]storeWidgetRow, error) {
+	areas := a.areas
+	if area != "" {
+		areas = []string{area}
+	}
+	rows := []storeWidgetRow{}
+	for _, code := range areas {
+		for i, inst := range a.store.AreaInstances(code) {
+			row := newStoreWidgetRow(inst)
+			row.Area, row.Position = code, i+1
+			rows = append(rows, row)
+		}
+	}
+	if area != "" {
+		return rows, nil
+	}
+	instances, err := a.store.ListInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, inst := range instances {
+		if !slices.ContainsFunc(rows, func(row storeWidgetRow) bool { return row.ID == inst.ID }) {
+			rows = append(rows, newStoreWidgetRow(inst))
+		}
+	}
+	return rows, nil
+}
+
+func newStoreWidgetRow(inst dashboard.WidgetInstance) storeWidgetRow {
+	config := inst.Configuration
+	if config == nil {
+		config = map[string]any{}
+	}
+	return storeWidgetRow{
+		ID:            inst.ID,
+		DefinitionID:  inst.DefinitionID,
+		SchemaVersion: dashboard.WidgetSchemaVersion(inst),
+		Configuration: config,
+		Metadata:      inst.Metadata,
+	}
+}
+
+func (a *storeAdmin) widgetRow(ctx context.Context, id string) (storeWidgetRow, error) {
+	inst, err := a.store.GetInstance(ctx, id)
+	if err != nil {
+		return storeWidgetRow{}, err
+	}
+	row := newStoreWidgetRow(inst)
+	if inst.AreaCode != "" {
+		row.Area = inst.AreaCode
+		row.Position = slices.IndexFunc(a.store.AreaInstances(inst.AreaCode), func(w dashboard.WidgetInstance) bool { return w.ID == id }) + 1
+	}
+	return row, nil
+}
+
+func (a *storeAdmin) writeJSON(value any) error {
+	encoder := json.NewEncoder(a.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("widgetctl: write output: %w", err)
+	}
+	return nil
+}
+
+// writeTable writes tab-separated rows under header.
+func (a *storeAdmin) writeTable(header string, rows []string) error {
+	writer := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	for _, line := range append([]string{header}, rows...) {
+		if _, err := fmt.Fprintln(writer, line); err != nil {
+			return fmt.Errorf("widgetctl: write output: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("widgetctl: write output: %w", err)
+	}
+	return nil
+}
+
+func (a *storeAdmin) printAreas() error {
+	rows := a.areaRows()
+	if a.format == "json" {
+		return a.writeJSON(rows)
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%d", row.Code, row.Name, row.Widgets))
+	}
+	return a.writeTable("AREA\tNAME\tWIDGETS", lines)
+}
+
+func (a *storeAdmin) printWidgets(ctx context.Context, area string) error {
+	rows, err := a.widgetRows(ctx, area)
+	if err != nil {
+		return err
+	}
+	if a.format == "json" {
+		return a.writeJSON(rows)
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		area, position := row.Area, strconv.Itoa(row.Position)
+		if area == "" {
+			area, position = "-", "-"
+		}
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", area, position, row.ID, row.DefinitionID, compactJSON(row.Configuration, 60)))
+	}
+	return a.writeTable("AREA\tPOS\tID\tDEFINITION\tCONFIGURATION", lines)
+}
+
+func (a *storeAdmin) printWidget(ctx context.Context, id string) error {
+	row, err := a.widgetRow(ctx, id)
+	if err != nil {
+		return err
+	}
+	if a.format == "json" {
+		return a.writeJSON(row)
+	}
+	area, position := row.Area, strconv.Itoa(row.Position)
+	if area == "" {
+		area, position = "-", "-"
+	}
+	config, err := json.MarshalIndent(row.Configuration, "", "  ")
+	if err != nil {
+		return fmt.Errorf("widgetctl: encode configuration: %w", err)
+	}
+	if err := a.writeTable("FIELD\tVALUE", []string{
+		"id\t" + row.ID,
+		"definition\t" + row.DefinitionID,
+		"area\t" + area,
+		"position\t" + position,
+		"schema_version\t" + strconv.Itoa(row.SchemaVersion),
+		"metadata\t" + compactJSON(row.Metadata, 0),
+	}); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(a.out, "\nconfiguration:\n%s\n", config); err != nil {
+		return fmt.Errorf("widgetctl: write output: %w", err)
+	}
+	return nil
+}
+
+func (a *storeAdmin) printStatus(format string, args ...any) error {
+	if _, err := fmt.Fprintf(a.out, format+"\n", args...); err != nil {
+		return fmt.Errorf("widgetctl: write status: %w", err)
+	}
+	return nil
+}
+
+// compactJSON renders value on one line, truncated to limit runes (0 keeps
+// it whole).
+func compactJSON(value any, limit int) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	text := []rune(string(data))
+	if limit > 0 && len(text) > limit {
+		return string(text[:limit-1]) + "…"
+	}
+	return string(text)
+}
+
+// configuration reads the JSON file at path (when set) or starts from base,
+// then applies key=value overrides. Values are decoded as JSON when possible
+// and kept as strings otherwise.
+func configuration(path string, base map[string]any, sets []string) (map[string]any, error) {
+	config := maps.Clone(base)
+	if path != "" {
+		config = map[string]any{}
+		if err := readJSONFile(path, &config); err != nil {
+			return nil, err
+		}
+	}
+	if config == nil {
+		config = map[string]any{}
+	}
+	for _, set := range sets {
+		key, raw, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("widgetctl: --set %q must be key=value", set)
+		}
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		config[key] = value
+	}
+	return config, nil
+}
+
+type storeAreasCmd struct{}
+
+func (cmd *storeAreasCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return admin.printAreas()
+}
+
+type storeListCmd struct {
+	Area string `help:"Only list the widgets of this area."`
+}
+
+func (cmd *storeListCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return admin.printWidgets(ctx, cmd.Area)
+}
+
+type storeShowCmd struct {
+	ID string `arg:"" help:"Widget instance ID."`
+}
+
+func (cmd *storeShowCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return admin.printWidget(ctx, cmd.ID)
+}
+
+type storeAddCmd struct {
+	Definition string   `required:"" help:"Widget definition code."`
+	Area       string   `required:"" help:"Area code to add the widget to."`
+	Config     string   `type:"existingfile" help:"JSON file with the widget configuration."`
+	Set        []string `help:"Configuration values as key=value (JSON values are decoded)."`
+	Position   int      `help:"1-based position in the area (appends by default)."`
+	Role       []string `help:"Restrict the widget to these roles."`
+}
+
+func (cmd *storeAddCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return cmd.run(ctx, admin)
+}
+
+func (cmd *storeAddCmd) run(ctx context.Context, admin *storeAdmin) error {
+	if _, ok := admin.registry.Definition(cmd.Definition); !ok {
+		return fmt.Errorf("widgetctl: unknown widget definition %s (add its manifest to the store config)", cmd.Definition)
+	}
+	config, err := configuration(cmd.Config, nil, cmd.Set)
+	if err != nil {
+		return err
+	}
+	req := dashboard.AddWidgetRequest{
+		DefinitionID:  cmd.Definition,
+		AreaCode:      cmd.Area,
+		Configuration: config,
+		Roles:         cmd.Role,
+	}
+	if cmd.Position > 0 {
+		position := cmd.Position - 1
+		req.Position = &position
+	}
+	if err := commands.NewAssignWidgetCommand(admin.service, nil).Execute(ctx, req); err != nil {
+		return err
+	}
+	return admin.printWidget(ctx, admin.events.last.Instance.ID)
+}
+
+type storeUpdateCmd struct {
+	ID     string   `arg:"" help:"Widget instance ID."`
+	Config string   `type:"existingfile" help:"JSON file replacing the widget configuration."`
+	Set    []string `help:"Configuration values as key=value, applied over the current (or --config) configuration."`
+}
+
+func (cmd *storeUpdateCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return cmd.run(ctx, admin)
+}
+
+func (cmd *storeUpdateCmd) run(ctx context.Context, admin *storeAdmin) error {
+	if cmd.Config == "" && len(cmd.Set) == 0 {
+		return fmt.Errorf("widgetctl: update requires --config or --set")
+	}
+	current, err := admin.store.GetInstance(ctx, cmd.ID)
+	if err != nil {
+		return err
+	}
+	config, err := configuration(cmd.Config, current.Configuration, cmd.Set)
+	if err != nil {
+		return err
+	}
+	if err := commands.NewUpdateWidgetCommand(admin.service, nil).Execute(ctx, commands.UpdateWidgetInput{
+		WidgetID:      cmd.ID,
+		Configuration: config,
+	}); err != nil {
+		return err
+	}
+	return admin.printWidget(ctx, cmd.ID)
+}
+
+type storeRemoveCmd struct {
+	IDs []string `arg:"" name:"id" help:"Widget instance IDs."`
+}
+
+func (cmd *storeRemoveCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return cmd.run(ctx, admin)
+}
+
+func (cmd *storeRemoveCmd) run(ctx context.Context, admin *storeAdmin) error {
+	remove := commands.NewRemoveWidgetCommand(admin.service, nil)
+	for _, id := range cmd.IDs {
+		if err := remove.Execute(ctx, commands.RemoveWidgetInput{WidgetID: id}); err != nil {
+			return err
+		}
+	}
+	if admin.format == "json" {
+		return admin.writeJSON(map[string]any{"removed": cmd.IDs})
+	}
+	return admin.printStatus("✓ Removed %s", strings.Join(cmd.IDs, ", "))
+}
+
+type storeReorderCmd struct {
+	Area string   `required:"" help:"Area code to reorder."`
+	IDs  []string `arg:"" name:"id" help:"Every widget instance ID of the area, in the new order."`
+}
+
+func (cmd *storeReorderCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return cmd.run(ctx, admin)
+}
+
+func (cmd *storeReorderCmd) run(ctx context.Context, admin *storeAdmin) error {
+	instances := admin.store.AreaInstances(cmd.Area)
+	current := make([]string, 0, len(instances))
+	for _, inst := range instances {
+		current = append(current, inst.ID)
+	}
+	// A partial list would drop the missing widgets from the area.
+	requested := slices.Sorted(slices.Values(cmd.IDs))
+	if !slices.Equal(requested, slices.Sorted(slices.Values(current))) {
+		return fmt.Errorf("widgetctl: reorder must list every widget of %s exactly once (%s)", cmd.Area, strings.Join(current, ", "))
+	}
+	if err := commands.NewReorderWidgetsCommand(admin.service, nil).Execute(ctx, commands.ReorderWidgetsInput{
+		AreaCode:  cmd.Area,
+		WidgetIDs: cmd.IDs,
+	}); err != nil {
+		return err
+	}
+	return admin.printWidgets(ctx, cmd.Area)
+}
+
+type storeSeedCmd struct {
+	Layout bool `help:"Also add the starter widgets to the default areas."`
+}
+
+func (cmd *storeSeedCmd) Run(ctx context.Context, parent *storeCmd) error {
+	admin, err := parent.open(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return cmd.run(ctx, admin)
+}
+
+func (cmd *storeSeedCmd) run(ctx context.Context, admin *storeAdmin) error {
+	seed := commands.NewSeedDashboardCommand(admin.store, admin.registry, admin.service, nil)
+	if err := seed.Execute(ctx, commands.SeedDashboardInput{SeedLayout: cmd.Layout}); err != nil {
+		return err
+	}
+	return admin.printAreas()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTestStore(t *testing.T, cmd storeCmd) (*storeAdmin, *bytes.Buffer) {
+	t.Helper()
+	var out bytes.Buffer
+	admin, err := cmd.open(&out)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	return admin, &out
+}
+
+func TestStoreCommandsAdministerFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "widgets.yaml", `
+version: 2
+widgets:
+  - definition:
+      code: acme.widget.counter
+      name: Counter
+      schema:
+        type: object
+        properties:
+          limit:
+            type: integer
+`)
+	writeTestManifest(t, dir, "store.yaml", "driver: file\npath: data/dashboard.json\nmanifests:\n  - widgets.yaml\n")
+	cmd := storeCmd{StoreConfig: filepath.Join(dir, "store.yaml"), Format: "json"}
+
+	admin, _ := openTestStore(t, cmd)
+	if err := (&storeSeedCmd{Layout: true}).run(ctx, admin); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if err := (&storeAddCmd{Definition: "acme.widget.counter", Area: "admin.dashboard.main", Set: []string{"limit=5"}, Position: 1}).run(ctx, admin); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	err := (&storeAddCmd{Definition: "acme.widget.counter", Area: "admin.dashboard.main", Set: []string{"limit=many"}}).run(ctx, admin)
+	if err == nil {
+		t.Fatalf("expected schema validation error")
+	}
+	if err := (&storeAddCmd{Definition: "acme.widget.unknown", Area: "admin.dashboard.main"}).run(ctx, admin); err == nil || !strings.Contains(err.Error(), "unknown widget definition") {
+		t.Fatalf("expected unknown definition error, got %v", err)
+	}
+
+	// Reopen to read back what was persisted.
+	admin, out := openTestStore(t, cmd)
+	if err := admin.printWidgets(ctx, "admin.dashboard.main"); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var rows []storeWidgetRow
+	if err := json.Unmarshal(out.Bytes(), &rows); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(rows) != 2 || rows[0].DefinitionID != "acme.widget.counter" || rows[0].Configuration["limit"] != float64(5) || rows[1].DefinitionID != "admin.widget.user_stats" {
+		t.Fatalf("unexpected main area: %+v", rows)
+	}
+	counter, stats := rows[0].ID, rows[1].ID
+
+	if err := (&storeReorderCmd{Area: "admin.dashboard.main", IDs: []string{stats}}).run(ctx, admin); err == nil || !strings.Contains(err.Error(), "every widget") {
+		t.Fatalf("expected partial reorder to be rejected, got %v", err)
+	}
+	if err := (&storeReorderCmd{Area: "admin.dashboard.main", IDs: []string{stats, counter}}).run(ctx, admin); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	if err := (&storeUpdateCmd{ID: counter, Set: []string{`title="Orders"`}}).run(ctx, admin); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	row, err := admin.widgetRow(ctx, counter)
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	if row.Position != 2 || row.Configuration["limit"] != float64(5) || row.Configuration["title"] != "Orders" {
+		t.Fatalf("unexpected widget after update: %+v", row)
+	}
+
+	if err := (&storeRemoveCmd{IDs: []string{counter}}).run(ctx, admin); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	admin, out = openTestStore(t, storeCmd{StoreConfig: cmd.StoreConfig, Format: "table"})
+	if err := admin.printAreas(); err != nil {
+		t.Fatalf("areas: %v", err)
+	}
+	if !strings.Contains(out.String(), "admin.dashboard.main") || !strings.Contains(out.String(), "WIDGETS") {
+		t.Fatalf("unexpected areas table:\n%s", out.String())
+	}
+	if _, err := admin.store.GetInstance(ctx, counter); err == nil {
+		t.Fatalf("expected %s to be removed", counter)
+	}
+}
+
+func TestStoreConfigRejectsUnknownDriver(t *testing.T) {
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "store.yaml", "driver: postgres\npath: db\n")
+	_, err := (&storeCmd{StoreConfig: filepath.Join(dir, "store.yaml")}).open(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "unsupported store driver") {
+		t.Fatalf("expected driver error, got %v", err)
+	}
+}
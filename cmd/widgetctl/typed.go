@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/ettle/strcase"
+)
+
+// typedScaffold describes the files generated by `scaffold --typed`.
+type typedScaffold struct {
+	Code        string
+	Name        string
+	Package     string
+	Base        string
+	Slug        string
+	Class       string
+	Fields      []typedField
+	DirPath     string
+	TemplateDir string
+}
+
+// typedField is one config struct field derived from a schema property.
+type typedField struct {
+	Name     string
+	JSON     string
+	Type     string
+	Label    string
+	Required bool
+	Sample   string
+	// Templatable is false for property names pongo2 cannot address with
+	// dot syntax; the generated template leaves those out.
+	Templatable bool
+}
+
+var templateIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// schemaFields maps top-level schema properties to Go struct fields, sorted
+// by property name so regeneration is stable.
+func schemaFields(schema map[string]any) []typedField {
+	properties, _ := schema["properties"].(map[string]any)
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]any); ok {
+		for _, item := range list {
+			if name, ok := item.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	fields := make([]typedField, 0, len(names))
+	for _, name := range names {
+		property, _ := properties[name].(map[string]any)
+		goType := schemaGoType(property)
+		label, _ := property["title"].(string)
+		if label == "" {
+			label = strings.ReplaceAll(strcase.ToSnake(name), "_", " ")
+			label = strings.ToUpper(label[:1]) + label[1:]
+		}
+		goName := strcase.ToGoPascal(name)
+		if goName == "" || !unicode.IsLetter(rune(goName[0])) {
+			goName = "Field" + goName
+		}
+		fields = append(fields, typedField{
+			Name:        goName,
+			JSON:        name,
+			Type:        goType,
+			Label:       label,
+			Required:    required[name],
+			Sample:      sampleLiteral(goType, property["default"]),
+			Templatable: templateIdentifier.MatchString(name),
+		})
+	}
+	return fields
+}
+
+func schemaGoType(property map[string]any) string {
+	switch property["type"] {
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		items, _ := property["items"].(map[string]any)
+		if item := schemaGoType(items); item != "any" {
+			return "[]" + item
+		}
+		return "[]any"
+	case "object":
+		return "map[string]any"
+	}
+	return "any"
+}
+
+// sampleLiteral renders a Go literal for the generated test configuration,
+// preferring the schema default.
+func sampleLiteral(goType string, fallback any) string {
+	switch goType {
+	case "string":
+		if value, ok := fallback.(string); ok {
+			return fmt.Sprintf("%q", value)
+		}
+		return `"sample"`
+	case "int":
+		if value, ok := fallback.(int); ok {
+			return fmt.Sprintf("%d", value)
+		}
+		if value, ok := fallback.(float64); ok {
+			return fmt.Sprintf("%d", int(value))
+		}
+		return "1"
+	case "float64":
+		if value, ok := fallback.(float64); ok {
+			return fmt.Sprintf("%v", value)
+		}
+		return "1.5"
+	case "bool":
+		if value, ok := fallback.(bool); ok {
+			return fmt.Sprintf("%t", value)
+		}
+		return "true"
+	case "map[string]any":
+		return "map[string]any{}"
+	}
+	return "[]any{}"
+}
+
+func newTypedScaffold(code, name string, schema map[string]any, dir, pkg, templateDir string) typedScaffold {
+	parts := strings.Split(code, ".")
+	slug := sanitizeFileName(parts[len(parts)-1])
+	if dir == "" {
+		dir = filepath.Join("widgets", slug)
+	}
+	if pkg == "" {
+		pkg = strings.ReplaceAll(sanitizeFileName(filepath.Base(dir)), "_", "")
+	}
+	return typedScaffold{
+		Code:        code,
+		Name:        name,
+		Package:     pkg,
+		Base:        strcase.ToGoPascal(slug),
+		Slug:        slug,
+		Class:       strings.ReplaceAll(slug, "_", "-"),
+		Fields:      schemaFields(schema),
+		DirPath:     dir,
+		TemplateDir: templateDir,
+	}
+}
+
+// importPath derives the Go import path of dir from the nearest go.mod, so
+// the manifest's provider.entry points at the generated package.
+func importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		data, err := os.ReadFile(filepath.Join(root, "go.mod")) // #nosec G304 -- walks up from the CLI user's output directory.
+		if err == nil {
+			module := modulePath(data)
+			if module == "" {
+				return "", fmt.Errorf("widgetctl: %s has no module path", filepath.Join(root, "go.mod"))
+			}
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return module, nil
+			}
+			return module + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("widgetctl: no go.mod found above %s", abs)
+		}
+	}
+}
+
+func modulePath(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// files renders every generated file keyed by path.
+func (s typedScaffold) files() (map[string][]byte, error) {
+	out := map[string][]byte{}
+	sources := []struct {
+		path  string
+		tmpl  *template.Template
+		gofmt bool
+	}{
+		{filepath.Join(s.DirPath, s.Slug+"_widget.go"), typedSpecTemplate, true},
+		{filepath.Join(s.DirPath, s.Slug+"_widget_test.go"), typedTestTemplate, true},
+		{filepath.Join(s.TemplateDir, s.Slug+".html"), typedHTMLTemplate, false},
+	}
+	for _, source := range sources {
+		var buf bytes.Buffer
+		if err := source.tmpl.Execute(&buf, s); err != nil {
+			return nil, fmt.Errorf("widgetctl: render %s: %w", source.path, err)
+		}
+		content := buf.Bytes()
+		if source.gofmt {
+			formatted, err := format.Source(content)
+			if err != nil {
+				return nil, fmt.Errorf("widgetctl: format %s: %w", source.path, err)
+			}
+			content = formatted
+		}
+		out[source.path] = content
+	}
+	return out, nil
+}
+
+// write creates the generated files, refusing to replace existing ones
+// unless overwrite is set.
+func (s typedScaffold) write(overwrite bool) ([]string, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil && !overwrite {
+			return nil, fmt.Errorf("widgetctl: %s already exists (use --overwrite)", path)
+		}
+	}
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return nil, fmt.Errorf("widgetctl: mkdir %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, files[path], 0o600); err != nil {
+			return nil, fmt.Errorf("widgetctl: write %s: %w", path, err)
+		}
+	}
+	return paths, nil
+}
+
+var typedSpecTemplate = template.Must(template.New("spec").Parse(`package {{ .Package }}
+
+import (
+	"context"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+// {{ .Base }}Code is the definition code of the {{ .Name }} widget. It is also
+// the provider factory name referenced by the manifest.
+const {{ .Base }}Code = {{ printf "%q" .Code }}
+
+// {{ .Base }}Config is the widget configuration, derived from its JSON schema.
+type {{ .Base }}Config struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `json:"{{ .JSON }}{{ if not .Required }},omitempty{{ end }}"` + "`" + `
+{{- end }}
+}
+
+// {{ .Base }}Data is the data Fetch loads for one render.
+type {{ .Base }}Data struct {
+	Title  string
+	Config {{ .Base }}Config
+}
+
+// {{ .Base }}View is the view model templates read as widget.data.
+type {{ .Base }}View struct {
+	Title  string ` + "`" + `json:"title"` + "`" + `
+	Config {{ .Base }}Config ` + "`" + `json:"config"` + "`" + `
+}
+
+// New{{ .Base }}Spec builds the typed spec for {{ .Code }} widgets.
+func New{{ .Base }}Spec(def dashboard.WidgetDefinition) dashboard.WidgetSpec[{{ .Base }}Config, {{ .Base }}Data, dashboard.JSONViewModel[{{ .Base }}View]] {
+	return dashboard.WidgetSpec[{{ .Base }}Config, {{ .Base }}Data, dashboard.JSONViewModel[{{ .Base }}View]]{
+		Definition: def,
+		Fetch: func(_ context.Context, req dashboard.WidgetRequest[{{ .Base }}Config]) ({{ .Base }}Data, error) {
+			// TODO: load the widget's data.
+			return {{ .Base }}Data{Title: def.NameForLocale(req.Viewer.Locale), Config: req.Config}, nil
+		},
+		BuildView: func(_ context.Context, data {{ .Base }}Data, _ dashboard.WidgetViewContext[{{ .Base }}Config]) (dashboard.JSONViewModel[{{ .Base }}View], error) {
+			return dashboard.JSONViewModel[{{ .Base }}View]{Value: {{ .Base }}View{Title: data.Title, Config: data.Config}}, nil
+		},
+	}
+}
+
+// New{{ .Base }}Provider is the provider factory registered for {{ .Base }}Code.
+func New{{ .Base }}Provider(def dashboard.WidgetDefinition) (dashboard.Provider, error) {
+	return dashboard.NewWidgetProvider(New{{ .Base }}Spec(def)), nil
+}
+
+func init() {
+	dashboard.RegisterWidgetHook(func(reg *dashboard.Registry) error {
+		return reg.RegisterProviderFactory({{ .Base }}Code, New{{ .Base }}Provider)
+	})
+}
+`))
+
+var typedTestTemplate = template.Must(template.New("test").Parse(`package {{ .Package }}
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+func Test{{ .Base }}Provider(t *testing.T) {
+	def := dashboard.WidgetDefinition{Code: {{ .Base }}Code, Name: {{ printf "%q" .Name }}}
+	provider, err := New{{ .Base }}Provider(def)
+	if err != nil {
+		t.Fatalf("New{{ .Base }}Provider returned error: %v", err)
+	}
+	cases := []struct {
+		name   string
+		config map[string]any
+	}{
+		{name: "empty configuration", config: nil},
+		{name: "sample configuration", config: map[string]any{
+{{- range .Fields }}
+			{{ printf "%q" .JSON }}: {{ .Sample }},
+{{- end }}
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := provider.Fetch(context.Background(), dashboard.WidgetContext{
+				Instance: dashboard.WidgetInstance{ID: "w1", DefinitionID: {{ .Base }}Code, Configuration: tc.config},
+			})
+			if err != nil {
+				t.Fatalf("Fetch returned error: %v", err)
+			}
+			if data["title"] != {{ printf "%q" .Name }} {
+				t.Fatalf("expected title, got %+v", data)
+			}
+		})
+	}
+}
+
+func Test{{ .Base }}FactoryIsRegistered(t *testing.T) {
+	reg := dashboard.NewRegistry()
+	if _, ok := reg.ProviderFactory({{ .Base }}Code); !ok {
+		t.Fatalf("expected provider factory %s to be registered", {{ .Base }}Code)
+	}
+}
+`))
+
+var typedHTMLTemplate = template.Must(template.New("html").Delims("[[", "]]").Parse(`<div class="widget widget--[[ .Class ]]">
+  <header>
+    <h3>{{ coalesce(widget.data.title, T("dashboard.widget.[[ .Code ]].title", locale, [[ printf "%q" .Name ]])) }}</h3>
+  </header>
+  <dl class="widget__body">
+[[- range .Fields ]][[ if .Templatable ]]
+    <dt>[[ .Label ]]</dt>
+    <dd>{{ widget.data.config.[[ .JSON ]] }}</dd>
+[[- end ]][[ end ]]
+  </dl>
+</div>
+`))
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaFieldsMapTypes(t *testing.T) {
+	fields := schemaFields(map[string]any{
+		"type":     "object",
+		"required": []any{"repos"},
+		"properties": map[string]any{
+			"repos":          map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"window_minutes": map[string]any{"type": "integer", "default": float64(60)},
+			"show-trend":     map[string]any{"type": "boolean", "title": "Show trend"},
+			"options":        map[string]any{"type": "object"},
+		},
+	})
+	cases := []struct {
+		name, json, goType, sample string
+		required, templatable      bool
+	}{
+		{"Options", "options", "map[string]any", "map[string]any{}", false, true},
+		{"Repos", "repos", "[]string", "[]any{}", true, true},
+		{"ShowTrend", "show-trend", "bool", "true", false, false},
+		{"WindowMinutes", "window_minutes", "int", "60", false, true},
+	}
+	if len(fields) != len(cases) {
+		t.Fatalf("expected %d fields, got %+v", len(cases), fields)
+	}
+	for i, tc := range cases {
+		got := fields[i]
+		if got.Name != tc.name || got.JSON != tc.json || got.Type != tc.goType || got.Sample != tc.sample ||
+			got.Required != tc.required || got.Templatable != tc.templatable {
+			t.Fatalf("field %d: expected %+v, got %+v", i, tc, got)
+		}
+	}
+}
+
+func TestTypedScaffoldRendersFiles(t *testing.T) {
+	schema := map[string]any{"type": "object", "properties": map[string]any{"limit": map[string]any{"type": "integer"}}}
+	scaffold := newTypedScaffold("acme.widget.order_totals", "Order Totals", schema, "", "", "templates/widgets")
+	if scaffold.Package != "ordertotals" || scaffold.Base != "OrderTotals" {
+		t.Fatalf("unexpected naming: %+v", scaffold)
+	}
+	files, err := scaffold.files()
+	if err != nil {
+		t.Fatalf("files returned error: %v", err)
+	}
+	spec := string(files[filepath.Join("widgets", "order_totals", "order_totals_widget.go")])
+	for _, want := range []string{
+		"package ordertotals",
+		"Limit int `json:\"limit,omitempty\"`",
+		"dashboard.WidgetSpec[OrderTotalsConfig, OrderTotalsData, dashboard.JSONViewModel[OrderTotalsView]]",
+		"reg.RegisterProviderFactory(OrderTotalsCode, NewOrderTotalsProvider)",
+	} {
+		if !strings.Contains(spec, want) {
+			t.Fatalf("expected spec to contain %q:\n%s", want, spec)
+		}
+	}
+	if test := string(files[filepath.Join("widgets", "order_totals", "order_totals_widget_test.go")]); !strings.Contains(test, `"limit": 1,`) {
+		t.Fatalf("expected sample configuration in test:\n%s", test)
+	}
+	html := string(files[filepath.Join("templates", "widgets", "order_totals.html")])
+	if !strings.Contains(html, "widget--order-totals") || !strings.Contains(html, "{{ widget.data.config.limit }}") {
+		t.Fatalf("unexpected template:\n%s", html)
+	}
+}
+
+func TestImportPathUsesNearestModule(t *testing.T) {
+	got, err := importPath(filepath.Join("..", "..", "widgets", "orders"))
+	if err != nil {
+		t.Fatalf("importPath returned error: %v", err)
+	}
+	if got != "github.com/goliatone/go-dashboard/widgets/orders" {
+		t.Fatalf("unexpected import path %s", got)
+	}
+}
+
+// TestTypedScaffoldBuildsInModule writes a scaffold into a throwaway module
+// that replaces go-dashboard with this checkout and runs go vet and go test
+// on it, so template changes that stop compiling are caught here.
+func TestTypedScaffoldBuildsInModule(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go toolchain run in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	repoRoot, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatalf("resolve repo root: %v", err)
+	}
+	sum, err := os.ReadFile(filepath.Join(repoRoot, "go.sum"))
+	if err != nil {
+		t.Fatalf("read go.sum: %v", err)
+	}
+	dir := t.TempDir()
+	gomod := "module example.com/scaffold\n\ngo 1.26.0\n\n" +
+		"require github.com/goliatone/go-dashboard v0.0.0\n\n" +
+		"replace github.com/goliatone/go-dashboard => " + repoRoot + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o600); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0o600); err != nil {
+		t.Fatalf("write go.sum: %v", err)
+	}
+
+	schema := map[string]any{
+		"type":     "object",
+		"required": []any{"repos"},
+		"properties": map[string]any{
+			"repos":      map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"limit":      map[string]any{"type": "integer", "default": float64(5)},
+			"ratio":      map[string]any{"type": "number"},
+			"show-trend": map[string]any{"type": "boolean"},
+			"options":    map[string]any{"type": "object"},
+		},
+	}
+	scaffold := newTypedScaffold("acme.widget.order_totals", "Order Totals", schema,
+		filepath.Join(dir, "widgets", "order_totals"), "", filepath.Join(dir, "templates", "widgets"))
+	if _, err := scaffold.write(false); err != nil {
+		t.Fatalf("write scaffold: %v", err)
+	}
+
+	for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+		cmd := exec.Command(goBin, args...) // #nosec G204 -- fixed go subcommands.
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
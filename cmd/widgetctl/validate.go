@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+type validateCmd struct {
+	Paths         []string `arg:"" type:"existingpath" help:"Manifest files or directories (scanned for .yaml, .yml and .json files)."`
+	Locale        []string `help:"Locales every definition must translate its name and description into (use multiple --locale flags)."`
+	DefaultLocale string   `default:"en" help:"Locale the default name/description strings are written in."`
+	Format        string   `enum:"text,json" default:"text" help:"Output format (text or json)."`
+}
+
+type lintCmd struct {
+	validateCmd `embed:""`
+	Strict      bool `help:"Fail on warnings as well as errors."`
+}
+
+// validationIssue is one finding, keyed by the check that produced it.
+type validationIssue struct {
+	Manifest string `json:"manifest"`
+	Widget   string `json:"widget,omitempty"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type manifestSummary struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	Widgets int    `json:"widgets"`
+}
+
+// validationReport is the machine-readable result written with --format json.
+type validationReport struct {
+	Valid     bool              `json:"valid"`
+	Errors    int               `json:"errors"`
+	Warnings  int               `json:"warnings"`
+	Manifests []manifestSummary `json:"manifests"`
+	Issues    []validationIssue `json:"issues"`
+}
+
+func (r *validationReport) add(issue validationIssue) {
+	r.Issues = append(r.Issues, issue)
+	if issue.Severity == severityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+func (cmd *validateCmd) Run(_ context.Context) error {
+	report, err := cmd.check(false)
+	if err != nil {
+		return err
+	}
+	return finishReport(os.Stdout, cmd.Format, report, false)
+}
+
+func (cmd *lintCmd) Run(_ context.Context) error {
+	report, err := cmd.check(true)
+	if err != nil {
+		return err
+	}
+	return finishReport(os.Stdout, cmd.Format, report, cmd.Strict)
+}
+
+// check decodes every manifest and runs the validation checks, plus the
+// style checks when lint is set.
+func (cmd *validateCmd) check(lint bool) (validationReport, error) {
+	report := validationReport{Manifests: []manifestSummary{}, Issues: []validationIssue{}}
+	paths, err := manifestPaths(cmd.Paths)
+	if err != nil {
+		return report, err
+	}
+	owners := map[string]string{}
+	for _, path := range paths {
+		doc, err := dashboard.ReadManifest(path)
+		if err != nil {
+			report.Manifests = append(report.Manifests, manifestSummary{Path: path})
+			report.add(validationIssue{Manifest: path, Check: "decode", Severity: severityError, Message: err.Error()})
+			continue
+		}
+		report.Manifests = append(report.Manifests, manifestSummary{Path: path, Version: doc.Version, Widgets: len(doc.Widgets)})
+		for _, widget := range doc.Widgets {
+			def := widget.Definition
+			issue := func(check, severity, format string, args ...any) {
+				report.add(validationIssue{Manifest: path, Widget: def.Code, Check: check, Severity: severity, Message: fmt.Sprintf(format, args...)})
+			}
+			if owner, ok := owners[def.Code]; ok {
+				issue("duplicate", severityError, "widget code already defined in %s", owner)
+			} else {
+				owners[def.Code] = path
+			}
+			if err := dashboard.CompileSchema(def); err != nil {
+				issue("schema", severityError, "%v", err)
+			}
+			for _, locale := range cmd.Locale {
+				if strings.EqualFold(locale, cmd.DefaultLocale) {
+					continue
+				}
+				if !dashboard.HasLocalizedValue(def.NameLocalized, locale) {
+					issue("locale", severityError, "name_localized is missing locale %s", locale)
+				}
+				if def.Description != "" && !dashboard.HasLocalizedValue(def.DescriptionLocalized, locale) {
+					issue("locale", severityError, "description_localized is missing locale %s", locale)
+				}
+			}
+			if lint {
+				lintWidget(doc, widget, issue)
+			}
+		}
+		if lint {
+			lintManifest(path, doc, &report)
+		}
+	}
+	report.Valid = report.Errors == 0
+	return report, nil
+}
+
+// lintWidget flags entries that load fine but are incomplete for a catalog.
+func lintWidget(doc *dashboard.WidgetManifestDocument, widget dashboard.ManifestWidget, issue func(check, severity, format string, args ...any)) {
+	def := widget.Definition
+	if def.Description == "" {
+		issue("description", severityWarning, "definition has no description")
+	}
+	if def.Category == "" {
+		issue("category", severityWarning, "definition has no category")
+	}
+	if len(def.Schema) > 0 && def.Schema["type"] == nil {
+		issue("schema_type", severityWarning, "schema does not declare a type")
+	}
+	if len(widget.Maintainers) == 0 {
+		issue("maintainers", severityWarning, "widget lists no maintainers")
+	}
+	if doc.Version != dashboard.ManifestVersion {
+		return
+	}
+	if widget.Version == "" {
+		issue("version", severityWarning, "widget has no version")
+	}
+	if widget.Provider.Entry != "" && widget.Provider.Factory == "" {
+		issue("factory", severityWarning, "provider.entry is informational; set provider.factory to wire the provider")
+	}
+}
+
+func lintManifest(path string, doc *dashboard.WidgetManifestDocument, report *validationReport) {
+	if doc.Version != dashboard.ManifestVersion {
+		report.add(validationIssue{Manifest: path, Check: "version", Severity: severityWarning, Message: fmt.Sprintf("manifest version %s is outdated; version %s wires providers through factories", doc.Version, dashboard.ManifestVersion)})
+	}
+	codes := make([]string, 0, len(doc.Widgets))
+	for _, widget := range doc.Widgets {
+		codes = append(codes, widget.Definition.Code)
+	}
+	if !slices.IsSorted(codes) {
+		report.add(validationIssue{Manifest: path, Check: "order", Severity: severityWarning, Message: "widgets are not sorted by definition.code"})
+	}
+}
+
+// manifestPaths expands directories into the manifest files they contain.
+func manifestPaths(inputs []string) ([]string, error) {
+	var paths []string
+	for _, input := range inputs {
+		info, err := os.Stat(input)
+		if err != nil {
+			return nil, fmt.Errorf("widgetctl: stat %s: %w", input, err)
+		}
+		if !info.IsDir() {
+			paths = append(paths, input)
+			continue
+		}
+		err = filepath.WalkDir(input, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					paths = append(paths, path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("widgetctl: scan %s: %w", input, err)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("widgetctl: no manifests found")
+	}
+	return paths, nil
+}
+
+// finishReport writes the report and turns failures into a non-zero exit.
+func finishReport(out io.Writer, format string, report validationReport, strict bool) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("widgetctl: write report: %w", err)
+		}
+	} else if err := writeTextReport(out, report); err != nil {
+		return fmt.Errorf("widgetctl: write report: %w", err)
+	}
+	if report.Errors > 0 || (strict && report.Warnings > 0) {
+		return fmt.Errorf("widgetctl: %d error(s), %d warning(s)", report.Errors, report.Warnings)
+	}
+	return nil
+}
+
+func writeTextReport(out io.Writer, report validationReport) error {
+	for _, issue := range report.Issues {
+		location := issue.Manifest
+		if issue.Widget != "" {
+			location += " " + issue.Widget
+		}
+		if _, err := fmt.Fprintf(out, "%s: %s [%s] %s\n", location, issue.Severity, issue.Check, issue.Message); err != nil {
+			return err
+		}
+	}
+	widgets := 0
+	for _, manifest := range report.Manifests {
+		widgets += manifest.Widgets
+	}
+	mark := "✓"
+	if !report.Valid {
+		mark = "✗"
+	}
+	_, err := fmt.Fprintf(out, "%s %d manifest(s), %d widget(s): %d error(s), %d warning(s)\n", mark, len(report.Manifests), widgets, report.Errors, report.Warnings)
+	return err
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestManifest(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+}
+
+func TestValidateReportsSchemaLocaleAndDuplicateErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "a.yaml", `
+version: 2
+widgets:
+  - definition:
+      code: acme.widget.alpha
+      name: Alpha
+      name_localized:
+        es: Alfa
+      schema:
+        type: object
+        properties:
+          limit:
+            type: 12
+  - definition:
+      code: acme.widget.beta
+      name: Beta
+      description: Second widget.
+`)
+	writeTestManifest(t, dir, "b.json", `{"version": "2", "widgets": [{"definition": {"code": "acme.widget.alpha", "name": "Again"}}]}`)
+	writeTestManifest(t, dir, "broken.yaml", "version: 9\nwidgets: []\n")
+
+	cmd := &validateCmd{Paths: []string{dir}, Locale: []string{"en", "es-MX"}, DefaultLocale: "en", Format: "json"}
+	report, err := cmd.check(false)
+	if err != nil {
+		t.Fatalf("check returned error: %v", err)
+	}
+	checks := map[string]int{}
+	for _, issue := range report.Issues {
+		checks[issue.Check]++
+	}
+	if report.Valid || checks["schema"] != 1 || checks["duplicate"] != 1 || checks["decode"] != 1 {
+		t.Fatalf("unexpected issues: %+v", report.Issues)
+	}
+	// alpha's "es" covers es-MX; beta misses both strings and b.json's
+	// duplicate misses its name.
+	if checks["locale"] != 3 {
+		t.Fatalf("expected 3 locale issues, got %+v", report.Issues)
+	}
+
+	var out bytes.Buffer
+	if err := finishReport(&out, "json", report, false); err == nil {
+		t.Fatalf("expected a non-nil error for a failing report")
+	}
+	var decoded validationReport
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected JSON report, got %q: %v", out.String(), err)
+	}
+	if decoded.Errors != report.Errors || len(decoded.Manifests) != 3 {
+		t.Fatalf("unexpected decoded report: %+v", decoded)
+	}
+}
+
+func TestLintWarnsWithoutFailingUnlessStrict(t *testing.T) {
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "pack.yaml", `
+version: 2
+widgets:
+  - definition:
+      code: acme.widget.zeta
+      name: Zeta
+    provider:
+      entry: github.com/acme/widgets.NewZeta
+  - definition:
+      code: acme.widget.alpha
+      name: Alpha
+`)
+	cmd := &lintCmd{validateCmd: validateCmd{Paths: []string{dir}, Format: "text"}}
+	report, err := cmd.check(true)
+	if err != nil {
+		t.Fatalf("check returned error: %v", err)
+	}
+	if !report.Valid || report.Warnings == 0 {
+		t.Fatalf("expected warnings only, got %+v", report)
+	}
+	found := map[string]bool{}
+	for _, issue := range report.Issues {
+		found[issue.Check] = true
+	}
+	for _, check := range []string{"order", "factory", "version", "description"} {
+		if !found[check] {
+			t.Fatalf("expected %s warning, got %+v", check, report.Issues)
+		}
+	}
+	var out bytes.Buffer
+	if err := finishReport(&out, "text", report, false); err != nil {
+		t.Fatalf("expected warnings not to fail, got %v", err)
+	}
+	if err := finishReport(&out, "text", report, true); err == nil {
+		t.Fatalf("expected strict mode to fail on warnings")
+	}
+}
@@ -0,0 +1,710 @@
+(function (global) {
+  'use strict';
+
+  var COLUMNS = 12;
+  var DRAG_TYPE = 'application/x-dashboard-widget';
+  var MESSAGES = {
+    moved: 'Moved to position {position} of {count} in {area}',
+    resized: 'Width {span} of 12',
+    hidden: 'Hidden',
+    shown: 'Shown',
+    rowBreak: 'Starts a new row',
+    rowJoin: 'Joins the previous row',
+    saved: 'Layout saved',
+    error: 'Could not save the layout',
+  };
+
+  function clampSpan(span) {
+    span = Math.round(Number(span));
+    if (!isFinite(span)) return COLUMNS;
+    return Math.max(1, Math.min(COLUMNS, span));
+  }
+
+  function format(message, values) {
+    return String(message || '').replace(/\{(\w+)\}/g, function (match, key) {
+      return Object.prototype.hasOwnProperty.call(values || {}, key) ? String(values[key]) : match;
+    });
+  }
+
+  // packRows groups the visible widgets of an area into rows of at most 12
+  // columns. A widget starts a new row when it asks for one or does not fit.
+  function packRows(widgets) {
+    var rows = [];
+    var current = null;
+    var used = 0;
+    (widgets || []).forEach(function (widget) {
+      if (widget.hidden) return;
+      var span = clampSpan(widget.span);
+      if (!current || widget.breakBefore || used + span > COLUMNS) {
+        current = [];
+        rows.push(current);
+        used = 0;
+      }
+      current.push(widget);
+      used += span;
+    });
+    return rows;
+  }
+
+  // preferencesPayload converts the editor model into the
+  // SaveLayoutPreferencesInput body posted to the preferences endpoint.
+  // Free-form grid areas keep their placements; only widths change.
+  // Per-breakpoint widths are posted back as read; filters and
+  // breakpoint_order are left out so the server keeps the stored values.
+  function preferencesPayload(model) {
+    var payload = { area_order: {}, layout_rows: {}, hidden_widget_ids: [] };
+    (model.areas || []).forEach(function (area) {
+      payload.area_order[area.code] = area.widgets.map(function (widget) { return widget.id; });
+      if (area.grid) {
+        payload.layout_grid = payload.layout_grid || {};
+        payload.layout_grid[area.code] = area.widgets.map(function (widget) {
+          var grid = widget.grid || { x: 0, y: 0, h: 1 };
+          return { id: widget.id, x: grid.x, y: grid.y, w: clampSpan(widget.span), h: grid.h };
+        });
+      } else {
+        payload.layout_rows[area.code] = packRows(area.widgets).map(function (row) {
+          return {
+            widgets: row.map(function (widget) {
+              var slot = { id: widget.id, width: clampSpan(widget.span) };
+              if (widget.widths) slot.widths = widget.widths;
+              return slot;
+            }),
+          };
+        });
+      }
+      area.widgets.forEach(function (widget) {
+        if (widget.hidden) payload.hidden_widget_ids.push(widget.id);
+      });
+    });
+    return payload;
+  }
+
+  function findWidget(model, id) {
+    var areas = model.areas || [];
+    for (var a = 0; a < areas.length; a++) {
+      for (var i = 0; i < areas[a].widgets.length; i++) {
+        if (areas[a].widgets[i].id === id) {
+          return { area: areas[a], areaIndex: a, index: i, widget: areas[a].widgets[i] };
+        }
+      }
+    }
+    return null;
+  }
+
+  function findArea(model, code) {
+    var areas = model.areas || [];
+    for (var a = 0; a < areas.length; a++) {
+      if (areas[a].code === code) return areas[a];
+    }
+    return null;
+  }
+
+  // moveWidget moves a widget to index in the target area. Index is clamped
+  // and refers to the target list without the moved widget.
+  function moveWidget(model, id, areaCode, index) {
+    var found = findWidget(model, id);
+    var target = findArea(model, areaCode);
+    if (!found || !target) return false;
+    found.area.widgets.splice(found.index, 1);
+    index = Math.max(0, Math.min(target.widgets.length, index == null ? target.widgets.length : index));
+    target.widgets.splice(index, 0, found.widget);
+    return found.area !== target || found.index !== index;
+  }
+
+  function moveBy(model, id, delta) {
+    var found = findWidget(model, id);
+    if (!found) return false;
+    var index = found.index + delta;
+    if (index < 0 || index >= found.area.widgets.length) return false;
+    return moveWidget(model, id, found.area.code, index);
+  }
+
+  function moveToArea(model, id, delta) {
+    var found = findWidget(model, id);
+    if (!found) return false;
+    var target = model.areas[found.areaIndex + delta];
+    if (!target) return false;
+    return moveWidget(model, id, target.code, target.widgets.length);
+  }
+
+  function resizeWidget(model, id, delta) {
+    var found = findWidget(model, id);
+    if (!found) return null;
+    found.widget.span = clampSpan(clampSpan(found.widget.span) + delta);
+    return found.widget.span;
+  }
+
+  function toggleHidden(model, id) {
+    var found = findWidget(model, id);
+    if (!found) return null;
+    found.widget.hidden = !found.widget.hidden;
+    return found.widget.hidden;
+  }
+
+  function toggleBreak(model, id) {
+    var found = findWidget(model, id);
+    if (!found) return null;
+    found.widget.breakBefore = !found.widget.breakBefore;
+    return found.widget.breakBefore;
+  }
+
+  // spanFromPointer converts a horizontal drag distance into a column span.
+  // Dragging towards the inline end widens the widget, which is leftwards
+  // in right-to-left layouts.
+  function spanFromPointer(startSpan, dx, columnWidth, dir) {
+    if (!columnWidth) return clampSpan(startSpan);
+    var delta = (dir === 'rtl' ? -dx : dx) / columnWidth;
+    return clampSpan(startSpan + Math.round(delta));
+  }
+
+  // dropIndex returns the insertion index for a pointer over an area given
+  // the bounding rects of its widgets (excluding the dragged one). Widgets
+  // sharing the pointer's row are compared horizontally, honouring dir.
+  function dropIndex(rects, point, dir) {
+    for (var i = 0; i < rects.length; i++) {
+      var rect = rects[i];
+      if (point.y < rect.top) return i;
+      if (point.y <= rect.bottom) {
+        var middle = rect.left + rect.width / 2;
+        if (dir === 'rtl' ? point.x > middle : point.x < middle) return i;
+      }
+    }
+    return rects.length;
+  }
+
+  function widgetDirection(node) {
+    var scoped = node && node.closest ? node.closest('[dir]') : null;
+    return scoped && scoped.getAttribute('dir') === 'rtl' ? 'rtl' : 'ltr';
+  }
+
+  // parseWidths reads the explicit per-breakpoint widths of a widget,
+  // ignoring malformed values.
+  function parseWidths(value) {
+    if (!value) return null;
+    var parsed;
+    try {
+      parsed = JSON.parse(value);
+    } catch (err) {
+      return null;
+    }
+    var widths = null;
+    Object.keys(parsed || {}).forEach(function (bp) {
+      var width = Number(parsed[bp]);
+      if (!isFinite(width) || width <= 0) return;
+      widths = widths || {};
+      widths[bp] = clampSpan(width);
+    });
+    return widths;
+  }
+
+  function readModel(root) {
+    var areas = [];
+    root.querySelectorAll('[data-area]').forEach(function (areaEl) {
+      var code = areaEl.getAttribute('data-area');
+      if (!code || findArea({ areas: areas }, code)) return;
+      var previousRow = null;
+      var widgets = [];
+      var grid = false;
+      areaEl.querySelectorAll(':scope > [data-widget]').forEach(function (el) {
+        var row = el.getAttribute('data-widget-row');
+        var widget = {
+          id: el.getAttribute('data-widget'),
+          span: clampSpan(el.getAttribute('data-widget-span') || COLUMNS),
+          hidden: el.getAttribute('data-widget-hidden') === 'true',
+          breakBefore: row !== null && previousRow !== null && row !== previousRow,
+        };
+        var widths = parseWidths(el.getAttribute('data-widget-widths'));
+        if (widths) widget.widths = widths;
+        if (el.hasAttribute('data-grid-h')) {
+          grid = true;
+          widget.grid = {
+            x: Number(el.getAttribute('data-grid-x')) || 0,
+            y: Number(el.getAttribute('data-grid-y')) || 0,
+            h: Number(el.getAttribute('data-grid-h')) || 1,
+          };
+        }
+        widgets.push(widget);
+        if (row !== null) previousRow = row;
+      });
+      areas.push({ code: code, widgets: widgets, grid: grid });
+    });
+    return { areas: areas };
+  }
+
+  function LayoutEditor(root, options) {
+    options = options || {};
+    this.root = root;
+    this.doc = root.ownerDocument || global.document;
+    this.win = (this.doc && this.doc.defaultView) || global;
+    this.config = options.config || parseConfig(root);
+    this.fetch = options.fetch || (this.win.fetch ? this.win.fetch.bind(this.win) : null);
+    this.toolbar = root.querySelector('[data-edit-toolbar]');
+    this.messages = Object.assign({}, MESSAGES, toolbarMessages(this.toolbar));
+    this.model = readModel(root);
+    this.cleanups = [];
+    this.dragging = null;
+  }
+
+  function parseConfig(root) {
+    try {
+      return JSON.parse(root.getAttribute('data-dashboard-edit') || '{}') || {};
+    } catch (error) {
+      return {};
+    }
+  }
+
+  function toolbarMessages(toolbar) {
+    var out = {};
+    if (!toolbar) return out;
+    Object.keys(MESSAGES).forEach(function (key) {
+      var value = toolbar.getAttribute('data-msg-' + key.replace(/[A-Z]/g, function (c) { return '-' + c.toLowerCase(); }));
+      if (value) out[key] = value;
+    });
+    return out;
+  }
+
+  LayoutEditor.prototype.widgetEl = function (id) {
+    var match = null;
+    this.root.querySelectorAll('[data-widget]').forEach(function (el) {
+      if (el.getAttribute('data-widget') === id) match = el;
+    });
+    return match;
+  };
+
+  LayoutEditor.prototype.areaEl = function (code) {
+    var match = null;
+    this.root.querySelectorAll('[data-area]').forEach(function (el) {
+      if (!match && el.getAttribute('data-area') === code) match = el;
+    });
+    return match;
+  };
+
+  LayoutEditor.prototype.announce = function (message) {
+    var status = this.root.querySelector('[data-edit-status]');
+    if (status) status.textContent = message;
+  };
+
+  // render applies the model to the DOM: order, area membership, spans,
+  // row breaks and hidden state.
+  LayoutEditor.prototype.render = function () {
+    var self = this;
+    this.model.areas.forEach(function (area) {
+      var areaEl = self.areaEl(area.code);
+      if (!areaEl) return;
+      area.widgets.forEach(function (widget) {
+        var el = self.widgetEl(widget.id);
+        if (!el) return;
+        if (el.parentNode !== areaEl || areaEl.lastElementChild !== el) areaEl.appendChild(el);
+        applyWidgetState(el, widget);
+      });
+      var empty = area.widgets.length === 0;
+      areaEl.classList.toggle('dashboard-area--empty', empty);
+      areaEl.querySelectorAll(':scope > p').forEach(function (placeholder) {
+        placeholder.hidden = !empty;
+      });
+    });
+  };
+
+  function applyWidgetState(el, widget) {
+    var span = clampSpan(widget.span);
+    Array.prototype.slice.call(el.classList).forEach(function (name) {
+      if (/^dashboard-widget--span-\d+$/.test(name)) el.classList.remove(name);
+    });
+    el.classList.add('dashboard-widget--span-' + span);
+    el.setAttribute('data-widget-span', String(span));
+    el.style.setProperty('--dashboard-widget-span', String(span));
+    if (widget.hidden) el.setAttribute('data-widget-hidden', 'true');
+    else el.removeAttribute('data-widget-hidden');
+    if (widget.breakBefore) el.setAttribute('data-widget-break', 'true');
+    else el.removeAttribute('data-widget-break');
+    var output = el.querySelector('[data-edit-span]');
+    if (output) output.textContent = span + '/' + COLUMNS;
+    var hide = el.querySelector('[data-edit-action="toggle-hidden"]');
+    if (hide) {
+      hide.setAttribute('aria-pressed', widget.hidden ? 'true' : 'false');
+      var label = hide.getAttribute(widget.hidden ? 'data-label-show' : 'data-label-hide');
+      if (label) hide.textContent = label;
+    }
+    var rowBreak = el.querySelector('[data-edit-action="row-break"]');
+    if (rowBreak) rowBreak.setAttribute('aria-pressed', widget.breakBefore ? 'true' : 'false');
+  }
+
+  LayoutEditor.prototype.perform = function (id, action) {
+    var model = this.model;
+    var changed = false;
+    var message = '';
+    switch (action) {
+      case 'move-prev':
+        changed = moveBy(model, id, -1);
+        break;
+      case 'move-next':
+        changed = moveBy(model, id, 1);
+        break;
+      case 'area-prev':
+        changed = moveToArea(model, id, -1);
+        break;
+      case 'area-next':
+        changed = moveToArea(model, id, 1);
+        break;
+      case 'narrower':
+      case 'wider': {
+        var found = findWidget(model, id);
+        var before = found ? found.widget.span : null;
+        var span = resizeWidget(model, id, action === 'wider' ? 1 : -1);
+        changed = span !== null && span !== before;
+        message = format(this.messages.resized, { span: span });
+        break;
+      }
+      case 'toggle-hidden': {
+        var hidden = toggleHidden(model, id);
+        changed = hidden !== null;
+        message = hidden ? this.messages.hidden : this.messages.shown;
+        break;
+      }
+      case 'row-break': {
+        var rowBreak = toggleBreak(model, id);
+        changed = rowBreak !== null;
+        message = rowBreak ? this.messages.rowBreak : this.messages.rowJoin;
+        break;
+      }
+      case 'configure':
+        this.configure(id);
+        return false;
+      default:
+        return false;
+    }
+    if (!changed) return false;
+    this.render();
+    if (!message) message = this.positionMessage(id);
+    this.announce(message);
+    var el = this.widgetEl(id);
+    if (el && this.doc.activeElement !== el && !el.contains(this.doc.activeElement)) el.focus();
+    this.root.setAttribute('data-edit-dirty', 'true');
+    return true;
+  };
+
+  LayoutEditor.prototype.positionMessage = function (id) {
+    var found = findWidget(this.model, id);
+    if (!found) return '';
+    return format(this.messages.moved, {
+      position: found.index + 1,
+      count: found.area.widgets.length,
+      area: found.area.code,
+    });
+  };
+
+  // keyAction maps Alt-modified keys on a focused widget to editor actions,
+  // mirroring horizontal keys for right-to-left layouts.
+  function keyAction(event, dir) {
+    if (!event.altKey) return '';
+    var forward = dir === 'rtl' ? 'ArrowLeft' : 'ArrowRight';
+    var backward = dir === 'rtl' ? 'ArrowRight' : 'ArrowLeft';
+    switch (event.key) {
+      case 'ArrowUp':
+        return event.shiftKey ? '' : 'move-prev';
+      case 'ArrowDown':
+        return event.shiftKey ? '' : 'move-next';
+      case forward:
+        return event.shiftKey ? 'wider' : 'move-next';
+      case backward:
+        return event.shiftKey ? 'narrower' : 'move-prev';
+      case 'PageUp':
+        return 'area-prev';
+      case 'PageDown':
+        return 'area-next';
+      case 'Enter':
+        return 'row-break';
+      case 'h':
+      case 'H':
+        return 'toggle-hidden';
+      default:
+        return '';
+    }
+  }
+
+  LayoutEditor.prototype.init = function () {
+    var self = this;
+    var root = this.root;
+    function listen(target, type, handler) {
+      target.addEventListener(type, handler);
+      self.cleanups.push(function () { target.removeEventListener(type, handler); });
+    }
+    function widgetFor(node) {
+      var el = node && node.closest ? node.closest('[data-widget]') : null;
+      return el && root.contains(el) ? el : null;
+    }
+
+    listen(root, 'click', function (event) {
+      var target = event.target;
+      if (!target || !target.closest) return;
+      var button = target.closest('[data-edit-action]');
+      var widget = widgetFor(button);
+      if (button && widget) {
+        event.preventDefault();
+        self.perform(widget.getAttribute('data-widget'), button.getAttribute('data-edit-action'));
+        return;
+      }
+      if (target.closest('[data-edit-save]')) {
+        event.preventDefault();
+        self.save();
+      } else if (target.closest('[data-edit-add]')) {
+        event.preventDefault();
+        self.add();
+      } else if (target.closest('[data-edit-dialog-close]')) {
+        event.preventDefault();
+        self.closeDialog();
+      }
+    });
+
+    listen(root, 'keydown', function (event) {
+      var widget = widgetFor(event.target);
+      if (!widget) return;
+      var action = keyAction(event, widgetDirection(widget));
+      if (!action) return;
+      event.preventDefault();
+      self.perform(widget.getAttribute('data-widget'), action);
+    });
+
+    listen(root, 'dragstart', function (event) {
+      var widget = widgetFor(event.target);
+      if (!widget || event.target !== widget) return;
+      self.dragging = widget.getAttribute('data-widget');
+      widget.classList.add('is-dragging');
+      if (event.dataTransfer) {
+        event.dataTransfer.effectAllowed = 'move';
+        event.dataTransfer.setData(DRAG_TYPE, self.dragging);
+        event.dataTransfer.setData('text/plain', self.dragging);
+      }
+    });
+
+    listen(root, 'dragover', function (event) {
+      if (!self.dragging) return;
+      var area = event.target && event.target.closest ? event.target.closest('[data-area]') : null;
+      if (!area) return;
+      event.preventDefault();
+      if (event.dataTransfer) event.dataTransfer.dropEffect = 'move';
+      root.querySelectorAll('.is-drop-target').forEach(function (el) { el.classList.remove('is-drop-target'); });
+      area.classList.add('is-drop-target');
+    });
+
+    listen(root, 'drop', function (event) {
+      if (!self.dragging) return;
+      var area = event.target && event.target.closest ? event.target.closest('[data-area]') : null;
+      if (!area) return;
+      event.preventDefault();
+      var id = self.dragging;
+      var rects = [];
+      area.querySelectorAll(':scope > [data-widget]').forEach(function (el) {
+        if (el.getAttribute('data-widget') !== id) rects.push(el.getBoundingClientRect());
+      });
+      var index = dropIndex(rects, { x: event.clientX, y: event.clientY }, widgetDirection(area));
+      if (moveWidget(self.model, id, area.getAttribute('data-area'), index)) {
+        self.render();
+        self.announce(self.positionMessage(id));
+        self.root.setAttribute('data-edit-dirty', 'true');
+      }
+    });
+
+    listen(root, 'dragend', function () {
+      root.querySelectorAll('.is-dragging, .is-drop-target').forEach(function (el) {
+        el.classList.remove('is-dragging');
+        el.classList.remove('is-drop-target');
+      });
+      self.dragging = null;
+    });
+
+    listen(root, 'pointerdown', function (event) {
+      var handle = event.target && event.target.closest ? event.target.closest('[data-edit-resize]') : null;
+      var widget = widgetFor(handle);
+      if (!handle || !widget) return;
+      event.preventDefault();
+      self.startResize(widget, event);
+    });
+
+    return this;
+  };
+
+  LayoutEditor.prototype.startResize = function (widget, event) {
+    var self = this;
+    var id = widget.getAttribute('data-widget');
+    var found = findWidget(this.model, id);
+    if (!found) return;
+    var area = widget.parentNode;
+    var columnWidth = area && area.getBoundingClientRect ? area.getBoundingClientRect().width / COLUMNS : 0;
+    var startX = event.clientX;
+    var startSpan = found.widget.span;
+    var dir = widgetDirection(widget);
+    var doc = this.doc;
+    widget.draggable = false;
+    function onMove(moveEvent) {
+      var span = spanFromPointer(startSpan, moveEvent.clientX - startX, columnWidth, dir);
+      if (span === found.widget.span) return;
+      found.widget.span = span;
+      applyWidgetState(widget, found.widget);
+    }
+    function onUp() {
+      doc.removeEventListener('pointermove', onMove);
+      doc.removeEventListener('pointerup', onUp);
+      widget.draggable = true;
+      if (found.widget.span !== startSpan) {
+        self.root.setAttribute('data-edit-dirty', 'true');
+        self.announce(format(self.messages.resized, { span: found.widget.span }));
+      }
+    }
+    doc.addEventListener('pointermove', onMove);
+    doc.addEventListener('pointerup', onUp);
+  };
+
+  LayoutEditor.prototype.exitURL = function () {
+    var loc = this.win.location;
+    if (!loc || !this.win.URL) return '?';
+    var url = new this.win.URL(loc.href);
+    url.searchParams.delete('edit');
+    return url.pathname + url.search + url.hash;
+  };
+
+  LayoutEditor.prototype.save = function () {
+    var self = this;
+    if (!this.fetch || !this.config.preferences_url) return Promise.resolve(false);
+    return this.fetch(this.config.preferences_url, {
+      method: 'POST',
+      credentials: 'same-origin',
+      headers: { 'Content-Type': 'application/json', Accept: 'application/json' },
+      body: JSON.stringify(preferencesPayload(this.model)),
+    }).then(function (response) {
+      if (!response.ok) throw new Error('status ' + response.status);
+      self.root.removeAttribute('data-edit-dirty');
+      self.announce(self.messages.saved);
+      if (self.win.location) self.win.location.assign(self.exitURL());
+      return true;
+    }).catch(function () {
+      self.announce(self.messages.error);
+      return false;
+    });
+  };
+
+  LayoutEditor.prototype.dialog = function () {
+    return this.root.querySelector('[data-edit-dialog]');
+  };
+
+  LayoutEditor.prototype.closeDialog = function () {
+    var dialog = this.dialog();
+    if (dialog && dialog.open && dialog.close) dialog.close();
+  };
+
+  // openForm loads a configuration form partial into the dialog and submits
+  // it with fetch. onDone runs after a successful save or create.
+  LayoutEditor.prototype.openForm = function (url, extra, onDone) {
+    var self = this;
+    var dialog = this.dialog();
+    if (!dialog || !this.fetch) return Promise.resolve(false);
+    var body = dialog.querySelector('[data-edit-dialog-body]') || dialog;
+    function show(html) {
+      body.innerHTML = html;
+      var form = body.querySelector('[data-config-form]');
+      if (!form) return;
+      form.addEventListener('submit', function (event) {
+        event.preventDefault();
+        var data = new self.win.URLSearchParams(new self.win.FormData(form, event.submitter || undefined));
+        Object.keys(extra || {}).forEach(function (key) { data.set(key, extra[key]); });
+        self.fetch(withFormat(form.getAttribute('action') || url), {
+          method: 'POST',
+          credentials: 'same-origin',
+          headers: { 'Content-Type': 'application/x-www-form-urlencoded', Accept: 'text/html' },
+          body: data.toString(),
+        }).then(function (response) {
+          return response.text().then(function (text) {
+            if (response.ok && !data.get('validate')) {
+              self.closeDialog();
+              onDone();
+              return;
+            }
+            show(text);
+          });
+        });
+      });
+    }
+    return this.fetch(withFormat(url), { credentials: 'same-origin', headers: { Accept: 'text/html' } })
+      .then(function (response) { return response.text(); })
+      .then(function (html) {
+        show(html);
+        if (dialog.showModal && !dialog.open) dialog.showModal();
+        return true;
+      });
+  };
+
+  function withFormat(url) {
+    if (/[?&]format=/.test(url)) return url;
+    return url + (url.indexOf('?') >= 0 ? '&' : '?') + 'format=html';
+  }
+
+  LayoutEditor.prototype.reload = function () {
+    if (this.win.location) this.win.location.reload();
+  };
+
+  LayoutEditor.prototype.add = function () {
+    var catalog = this.root.querySelector('[data-edit-catalog]');
+    var area = this.root.querySelector('[data-edit-target-area]');
+    if (!catalog || !catalog.value || !this.config.definition_config_url) return Promise.resolve(false);
+    var url = this.config.definition_config_url.replace(':code', encodeURIComponent(catalog.value));
+    var self = this;
+    return this.openForm(url, { area_code: area ? area.value : '' }, function () { self.reload(); });
+  };
+
+  LayoutEditor.prototype.configure = function (id) {
+    if (!this.config.widget_config_url) return Promise.resolve(false);
+    var self = this;
+    var url = this.config.widget_config_url.replace(':id', encodeURIComponent(id));
+    return this.openForm(url, null, function () { self.reload(); });
+  };
+
+  LayoutEditor.prototype.destroy = function () {
+    this.cleanups.forEach(function (cleanup) { cleanup(); });
+    this.cleanups.length = 0;
+  };
+
+  function initEditors(scope, options) {
+    scope = scope || global.document;
+    if (!scope || !scope.querySelectorAll) return [];
+    var editors = [];
+    scope.querySelectorAll('[data-dashboard-edit]').forEach(function (root) {
+      if (root.getAttribute('data-edit-init') === 'true') return;
+      editors.push(new LayoutEditor(root, options).init());
+      root.setAttribute('data-edit-init', 'true');
+    });
+    return editors;
+  }
+
+  var api = {
+    COLUMNS: COLUMNS,
+    clampSpan: clampSpan,
+    packRows: packRows,
+    preferencesPayload: preferencesPayload,
+    moveWidget: moveWidget,
+    moveBy: moveBy,
+    moveToArea: moveToArea,
+    resizeWidget: resizeWidget,
+    toggleHidden: toggleHidden,
+    toggleBreak: toggleBreak,
+    spanFromPointer: spanFromPointer,
+    dropIndex: dropIndex,
+    keyAction: keyAction,
+    parseWidths: parseWidths,
+    readModel: readModel,
+    LayoutEditor: LayoutEditor,
+    initEditors: initEditors,
+  };
+
+  if (typeof module !== 'undefined' && module.exports) {
+    module.exports = api;
+  }
+  global.DashboardEdit = api;
+
+  if (global.document) {
+    if (global.document.readyState === 'loading') {
+      global.document.addEventListener('DOMContentLoaded', function () { initEditors(global.document); });
+    } else {
+      initEditors(global.document);
+    }
+  }
+})(typeof window !== 'undefined' ? window : globalThis);
@@ -0,0 +1,110 @@
+import test from 'node:test';
+import assert from 'node:assert/strict';
+import { createRequire } from 'node:module';
+
+const require = createRequire(import.meta.url);
+const edit = require('./edit.js');
+
+function model() {
+  return {
+    areas: [
+      {
+        code: 'admin.dashboard.main',
+        widgets: [
+          { id: 'a', span: 6, hidden: false, breakBefore: false },
+          { id: 'b', span: 6, hidden: false, breakBefore: false },
+          { id: 'c', span: 8, hidden: false, breakBefore: false },
+          { id: 'd', span: 4, hidden: true, breakBefore: false },
+        ],
+      },
+      { code: 'admin.dashboard.sidebar', widgets: [{ id: 'e', span: 12, hidden: false, breakBefore: false }] },
+    ],
+  };
+}
+
+test('packRows wraps overflowing widgets, honours breaks and skips hidden widgets', () => {
+  const widgets = model().areas[0].widgets;
+  assert.deepEqual(edit.packRows(widgets).map((row) => row.map((w) => w.id)), [['a', 'b'], ['c']]);
+  widgets[1].breakBefore = true;
+  assert.deepEqual(edit.packRows(widgets).map((row) => row.map((w) => w.id)), [['a'], ['b'], ['c']]);
+});
+
+test('preferencesPayload produces order, rows and hidden ids', () => {
+  const payload = edit.preferencesPayload(model());
+  assert.deepEqual(payload.area_order['admin.dashboard.main'], ['a', 'b', 'c', 'd']);
+  assert.deepEqual(payload.layout_rows['admin.dashboard.main'], [
+    { widgets: [{ id: 'a', width: 6 }, { id: 'b', width: 6 }] },
+    { widgets: [{ id: 'c', width: 8 }] },
+  ]);
+  assert.deepEqual(payload.hidden_widget_ids, ['d']);
+});
+
+test('preferencesPayload keeps grid placements for free-form areas', () => {
+  const payload = edit.preferencesPayload({
+    areas: [{ code: 'ops', grid: true, widgets: [{ id: 'g1', span: 5, grid: { x: 2, y: 1, h: 3 } }, { id: 'g2', span: 12 }] }],
+  });
+  assert.deepEqual(payload.layout_grid.ops, [
+    { id: 'g1', x: 2, y: 1, w: 5, h: 3 },
+    { id: 'g2', x: 0, y: 0, w: 12, h: 1 },
+  ]);
+  assert.equal(payload.layout_rows.ops, undefined);
+});
+
+test('preferencesPayload posts breakpoint widths back and leaves stored fields alone', () => {
+  const m = model();
+  m.areas[0].widgets[0].widths = edit.parseWidths('{"sm":6,"lg":"4","xl":0}');
+  assert.deepEqual(m.areas[0].widgets[0].widths, { sm: 6, lg: 4 });
+  assert.equal(edit.parseWidths('not json'), null);
+
+  const payload = edit.preferencesPayload(m);
+  assert.deepEqual(payload.layout_rows['admin.dashboard.main'][0].widgets[0], { id: 'a', width: 6, widths: { sm: 6, lg: 4 } });
+  assert.deepEqual(payload.layout_rows['admin.dashboard.main'][0].widgets[1], { id: 'b', width: 6 });
+  assert.equal('filters' in payload, false);
+  assert.equal('breakpoint_order' in payload, false);
+});
+
+test('moves reorder within and across areas', () => {
+  const m = model();
+  assert.equal(edit.moveBy(m, 'a', 1), true);
+  assert.deepEqual(m.areas[0].widgets.map((w) => w.id), ['b', 'a', 'c', 'd']);
+  assert.equal(edit.moveBy(m, 'b', -1), false);
+  assert.equal(edit.moveToArea(m, 'c', 1), true);
+  assert.deepEqual(m.areas[1].widgets.map((w) => w.id), ['e', 'c']);
+  assert.equal(edit.moveToArea(m, 'e', 1), false);
+  assert.equal(edit.moveWidget(m, 'e', 'admin.dashboard.main', 99), true);
+  assert.deepEqual(m.areas[0].widgets.map((w) => w.id), ['b', 'a', 'd', 'e']);
+});
+
+test('resize clamps to the grid and toggles flip state', () => {
+  const m = model();
+  assert.equal(edit.resizeWidget(m, 'e', 1), 12);
+  assert.equal(edit.resizeWidget(m, 'a', -10), 1);
+  assert.equal(edit.toggleHidden(m, 'd'), false);
+  assert.equal(edit.toggleBreak(m, 'b'), true);
+  assert.equal(edit.resizeWidget(m, 'missing', 1), null);
+});
+
+test('spanFromPointer and keyAction mirror right-to-left layouts', () => {
+  assert.equal(edit.spanFromPointer(4, 100, 50, 'ltr'), 6);
+  assert.equal(edit.spanFromPointer(4, 100, 50, 'rtl'), 2);
+  assert.equal(edit.spanFromPointer(11, 500, 50, 'ltr'), 12);
+  assert.equal(edit.keyAction({ altKey: true, key: 'ArrowRight' }, 'ltr'), 'move-next');
+  assert.equal(edit.keyAction({ altKey: true, key: 'ArrowRight' }, 'rtl'), 'move-prev');
+  assert.equal(edit.keyAction({ altKey: true, shiftKey: true, key: 'ArrowLeft' }, 'rtl'), 'wider');
+  assert.equal(edit.keyAction({ altKey: true, key: 'PageDown' }, 'ltr'), 'area-next');
+  assert.equal(edit.keyAction({ altKey: true, key: 'h' }, 'ltr'), 'toggle-hidden');
+  assert.equal(edit.keyAction({ key: 'ArrowUp' }, 'ltr'), '');
+});
+
+test('dropIndex compares rows vertically and siblings horizontally', () => {
+  const rects = [
+    { top: 0, bottom: 100, left: 0, width: 100 },
+    { top: 0, bottom: 100, left: 100, width: 100 },
+    { top: 120, bottom: 200, left: 0, width: 200 },
+  ];
+  assert.equal(edit.dropIndex(rects, { x: 20, y: 50 }, 'ltr'), 0);
+  assert.equal(edit.dropIndex(rects, { x: 120, y: 50 }, 'ltr'), 1);
+  assert.equal(edit.dropIndex(rects, { x: 180, y: 50 }, 'ltr'), 2);
+  assert.equal(edit.dropIndex(rects, { x: 20, y: 50 }, 'rtl'), 2);
+  assert.equal(edit.dropIndex(rects, { x: 20, y: 300 }, 'ltr'), 3);
+});
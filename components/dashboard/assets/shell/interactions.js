@@ -0,0 +1,345 @@
+(function (global) {
+  'use strict';
+
+  var EVENT_NAME = 'dashboard:interaction';
+  var FILTER_PREFIX = 'var-';
+  var DATUM_PREFIX = 'datum';
+  var SAFE_SCHEMES = ['http', 'https'];
+  // sectionOptions keeps the options each section was bound with so later
+  // rebinds (theme changes, subscriber refreshes) reuse them.
+  var sectionOptions = typeof WeakMap === 'function' ? new WeakMap() : null;
+
+  function parseInteractions(section) {
+    var raw = section && section.getAttribute('data-widget-interactions');
+    if (!raw) return null;
+    try {
+      var parsed = JSON.parse(raw);
+      return parsed && typeof parsed === 'object' ? parsed : null;
+    } catch (error) {
+      return null;
+    }
+  }
+
+  // readDatum collects data-datum-* attributes into a plain object.
+  function readDatum(element) {
+    var datum = {};
+    if (!element || !element.dataset) return datum;
+    Object.keys(element.dataset).forEach(function (key) {
+      if (key.indexOf(DATUM_PREFIX) !== 0 || key.length === DATUM_PREFIX.length) return;
+      var field = key.slice(DATUM_PREFIX.length);
+      field = field.charAt(0).toLowerCase() + field.slice(1);
+      datum[field] = element.dataset[key];
+    });
+    return datum;
+  }
+
+  function datumValue(datum, field) {
+    if (!datum || !(field in datum) || datum[field] === null || typeof datum[field] === 'undefined') return '';
+    return String(datum[field]);
+  }
+
+  function publishedFilters(interactions, datum) {
+    var filters = {};
+    var publish = (interactions && interactions.publish) || {};
+    Object.keys(publish).forEach(function (name) {
+      var value = datumValue(datum, publish[name]);
+      if (value !== '') filters[name] = value;
+    });
+    return filters;
+  }
+
+  function findTarget(interactions, event) {
+    var targets = (interactions && interactions.drilldown) || [];
+    for (var i = 0; i < targets.length; i++) {
+      if ((targets[i].event || 'select') === event) return targets[i];
+    }
+    return null;
+  }
+
+  // safeHref returns href when it is relative or uses an http(s) scheme and ''
+  // otherwise, mirroring the server-side drilldown validation. Control
+  // characters are rejected because browsers drop them before reading the
+  // scheme ("java\tscript:").
+  function safeHref(href) {
+    href = String(href || '').trim();
+    if (!href || /[\u0000-\u001f\u007f]/.test(href)) return '';
+    var scheme = /^([A-Za-z][A-Za-z0-9+.-]*):/.exec(href);
+    if (scheme && SAFE_SCHEMES.indexOf(scheme[1].toLowerCase()) < 0) return '';
+    return href;
+  }
+
+  function resolveHref(target, datum) {
+    if (target.kind === 'url') {
+      return safeHref(String(target.url || '').replace(/\{([A-Za-z0-9_.-]+)\}/g, function (_, field) {
+        return encodeURIComponent(datumValue(datum, field));
+      }));
+    }
+    if (target.kind === 'dashboard') {
+      var params = [];
+      var filters = target.filters || {};
+      Object.keys(filters).sort().forEach(function (name) {
+        var value = datumValue(datum, filters[name]);
+        if (value !== '') params.push(encodeURIComponent(FILTER_PREFIX + name) + '=' + encodeURIComponent(value));
+      });
+      var dashboard = String(target.dashboard || '');
+      if (params.length === 0) return safeHref(dashboard);
+      return safeHref(dashboard + (dashboard.indexOf('?') >= 0 ? '&' : '?') + params.join('&'));
+    }
+    return '';
+  }
+
+  function renderDetail(section, target, datum) {
+    var doc = section.ownerDocument;
+    var panel = section.querySelector('[data-widget-detail]');
+    if (!panel) {
+      panel = doc.createElement('aside');
+      panel.className = 'dashboard-widget__detail';
+      panel.setAttribute('data-widget-detail', '');
+      section.appendChild(panel);
+    }
+    panel.textContent = '';
+    if (target.title) {
+      var heading = doc.createElement('h4');
+      heading.textContent = target.title;
+      panel.appendChild(heading);
+    }
+    var list = doc.createElement('dl');
+    Object.keys(datum).forEach(function (field) {
+      var term = doc.createElement('dt');
+      term.textContent = field;
+      var value = doc.createElement('dd');
+      value.textContent = datumValue(datum, field);
+      list.appendChild(term);
+      list.appendChild(value);
+    });
+    panel.appendChild(list);
+    var close = doc.createElement('button');
+    close.type = 'button';
+    close.textContent = '×';
+    close.setAttribute('aria-label', 'Close');
+    close.addEventListener('click', function () { panel.hidden = true; });
+    panel.appendChild(close);
+    panel.hidden = false;
+    return panel;
+  }
+
+  function subscribers(root, filters, source) {
+    var names = Object.keys(filters);
+    var matches = [];
+    root.querySelectorAll('[data-widget-interactions]').forEach(function (section) {
+      if (section === source) return;
+      var subscribes = (parseInteractions(section) || {}).subscribes || [];
+      if (names.some(function (name) { return subscribes.indexOf(name) >= 0; })) matches.push(section);
+    });
+    return matches;
+  }
+
+  function filterURL(location, filters) {
+    var url = new URL(location.href);
+    Object.keys(filters).forEach(function (name) {
+      url.searchParams.set(FILTER_PREFIX + name, filters[name]);
+    });
+    return url;
+  }
+
+  // runScripts re-executes inline chart scripts, which innerHTML leaves inert.
+  function runScripts(section) {
+    var doc = section.ownerDocument;
+    section.querySelectorAll('script').forEach(function (stale) {
+      var script = doc.createElement('script');
+      Array.prototype.forEach.call(stale.attributes, function (attr) {
+        script.setAttribute(attr.name, attr.value);
+      });
+      script.nonce = stale.nonce;
+      script.textContent = stale.textContent;
+      stale.parentNode.replaceChild(script, stale);
+    });
+  }
+
+  // refreshSubscribers re-renders the dashboard with the published filters and
+  // swaps only the widgets that consume them.
+  function refreshSubscribers(root, filters, source, options) {
+    var targets = subscribers(root, filters, source);
+    if (targets.length === 0) return Promise.resolve([]);
+    var win = root.defaultView || global;
+    var url = filterURL(win.location, filters);
+    if (win.history && win.history.replaceState) win.history.replaceState(null, '', url.toString());
+    var fetcher = (options && options.fetch) || win.fetch;
+    if (!fetcher) return Promise.resolve([]);
+    return fetcher.call(win, url.toString(), { headers: { Accept: 'text/html' } })
+      .then(function (response) { return response.text(); })
+      .then(function (html) {
+        var doc = new win.DOMParser().parseFromString(html, 'text/html');
+        targets.forEach(function (section) {
+          var id = section.getAttribute('data-widget');
+          var fresh = doc.querySelector('[data-widget="' + id + '"]');
+          if (!fresh) return;
+          section.innerHTML = fresh.innerHTML;
+          runScripts(section);
+          bindCharts(section, boundOptions(section, options));
+        });
+        return targets;
+      });
+  }
+
+  function handleInteraction(section, datum, options) {
+    var interactions = parseInteractions(section);
+    if (!interactions) return null;
+    var doc = section.ownerDocument;
+    var eventName = (options && options.event) || 'select';
+    var filters = publishedFilters(interactions, datum);
+    var detail = {
+      widget: section.getAttribute('data-widget'),
+      definition: section.getAttribute('data-widget-definition'),
+      event: eventName,
+      datum: datum,
+      filters: filters,
+    };
+    var dispatched = section.dispatchEvent(new doc.defaultView.CustomEvent(EVENT_NAME, {
+      bubbles: true,
+      cancelable: true,
+      detail: detail,
+    }));
+    if (!dispatched) return detail;
+
+    var target = findTarget(interactions, eventName);
+    if (target && target.kind === 'detail') {
+      renderDetail(section, target, datum);
+    } else if (target) {
+      var href = safeHref(resolveHref(target, datum));
+      if (href) (options && options.navigate ? options.navigate : function (next) { doc.defaultView.location.assign(next); })(href);
+      return detail;
+    }
+    if (Object.keys(filters).length > 0) {
+      detail.refresh = refreshSubscribers(doc, filters, section, options);
+    }
+    return detail;
+  }
+
+  function boundOptions(section, fallback) {
+    return sectionOptions && sectionOptions.has(section) ? sectionOptions.get(section) : fallback;
+  }
+
+  function bindCharts(section, options) {
+    if (sectionOptions) sectionOptions.set(section, options);
+    var echarts = global.echarts;
+    if (!echarts || !echarts.getInstanceByDom) return;
+    section.querySelectorAll('.widget__content--chart > div').forEach(function (el) {
+      var chart = echarts.getInstanceByDom(el);
+      if (!chart) return;
+      chart.on('click', function (params) {
+        handleInteraction(section, {
+          name: params.name,
+          series: params.seriesName,
+          value: params.value,
+        }, options);
+      });
+    });
+  }
+
+  function initInteractions(scope, options) {
+    scope = scope || global.document;
+    if (!scope || !scope.querySelectorAll) return [];
+    var bound = [];
+    scope.querySelectorAll('[data-widget-interactions]').forEach(function (section) {
+      if (section.getAttribute('data-widget-interactions-init') === 'true') return;
+      var interactions = parseInteractions(section);
+      if (!interactions || (!interactions.publish && !interactions.drilldown)) return;
+      section.addEventListener('click', function (event) {
+        var element = event.target && event.target.closest ? event.target.closest('[data-widget-datum]') : null;
+        if (!element || !section.contains(element)) return;
+        handleInteraction(section, readDatum(element), options);
+      });
+      bindCharts(section, options);
+      section.setAttribute('data-widget-interactions-init', 'true');
+      bound.push(section);
+    });
+    return bound;
+  }
+
+  // toolbarFilters reads the toolbar's filter fields, keyed by filter name.
+  // "All" is sent as an empty value so it clears the saved selection.
+  function toolbarFilters(form) {
+    var filters = {};
+    if (!form || !form.querySelectorAll) return filters;
+    form.querySelectorAll('[data-filter]').forEach(function (wrapper) {
+      var field = wrapper.querySelector('select, input');
+      if (!field) return;
+      filters[wrapper.getAttribute('data-filter')] = field.value || '';
+    });
+    return filters;
+  }
+
+  // saveToolbarFilters posts the selected filters to the preferences endpoint.
+  // keepalive lets the request finish while the form navigates.
+  function saveToolbarFilters(form, options) {
+    var url = form && form.getAttribute('data-preferences-url');
+    var win = (form && form.ownerDocument && form.ownerDocument.defaultView) || global;
+    var fetcher = (options && options.fetch) || win.fetch;
+    if (!url || !fetcher) return Promise.resolve(false);
+    return fetcher.call(win, url, {
+      method: 'POST',
+      credentials: 'same-origin',
+      keepalive: true,
+      headers: { 'Content-Type': 'application/json', Accept: 'application/json' },
+      body: JSON.stringify({ filters: toolbarFilters(form) }),
+    }).then(function (response) {
+      return Boolean(response && response.ok);
+    }, function () {
+      return false;
+    });
+  }
+
+  function initToolbar(scope, options) {
+    scope = scope || global.document;
+    if (!scope || !scope.querySelectorAll) return [];
+    var bound = [];
+    scope.querySelectorAll('form[data-dashboard-toolbar][data-preferences-url]').forEach(function (form) {
+      if (form.getAttribute('data-toolbar-init') === 'true') return;
+      form.addEventListener('submit', function () {
+        saveToolbarFilters(form, options);
+      });
+      form.setAttribute('data-toolbar-init', 'true');
+      bound.push(form);
+    });
+    return bound;
+  }
+
+  var api = {
+    EVENT_NAME: EVENT_NAME,
+    readDatum: readDatum,
+    publishedFilters: publishedFilters,
+    safeHref: safeHref,
+    resolveHref: resolveHref,
+    handleInteraction: handleInteraction,
+    refreshSubscribers: refreshSubscribers,
+    initInteractions: initInteractions,
+    toolbarFilters: toolbarFilters,
+    saveToolbarFilters: saveToolbarFilters,
+    initToolbar: initToolbar,
+  };
+
+  if (typeof module !== 'undefined' && module.exports) {
+    module.exports = api;
+  }
+  global.DashboardInteractions = api;
+
+  if (global.document) {
+    // Switching theme variants re-creates chart instances, so chart click
+    // handlers must be bound again.
+    global.document.addEventListener('dashboard:themechange', function () {
+      global.document.querySelectorAll('[data-widget-interactions-init="true"]').forEach(function (section) {
+        bindCharts(section, boundOptions(section));
+      });
+    });
+    if (global.document.readyState === 'loading') {
+      global.document.addEventListener('DOMContentLoaded', function () {
+        initInteractions(global.document);
+        initToolbar(global.document);
+      });
+    } else {
+      initInteractions(global.document);
+      initToolbar(global.document);
+    }
+  }
+})(typeof window !== 'undefined' ? window : globalThis);
@@ -0,0 +1,78 @@
+import test from 'node:test';
+import assert from 'node:assert/strict';
+import { createRequire } from 'node:module';
+
+const require = createRequire(import.meta.url);
+const interactions = require('./interactions.js');
+
+test('readDatum collects data-datum attributes', () => {
+  const element = { dataset: { widgetDatum: '', datumLabel: 'Signup', datumValue: '42', datum: 'ignored' } };
+  assert.deepEqual(interactions.readDatum(element), { label: 'Signup', value: '42' });
+});
+
+test('publishedFilters maps datum fields to dashboard filters', () => {
+  const filters = interactions.publishedFilters(
+    { publish: { funnel_step: 'label', region: 'missing' } },
+    { label: 'Checkout' },
+  );
+  assert.deepEqual(filters, { funnel_step: 'Checkout' });
+});
+
+test('resolveHref expands url templates and dashboard filters', () => {
+  assert.equal(
+    interactions.resolveHref({ kind: 'url', url: '/reports/{label}?v={value}' }, { label: 'A B', value: 3 }),
+    '/reports/A%20B?v=3',
+  );
+  assert.equal(
+    interactions.resolveHref(
+      { kind: 'dashboard', dashboard: '/admin/cohorts', filters: { step: 'label', region: 'region' } },
+      { label: 'Signup', region: 'emea' },
+    ),
+    '/admin/cohorts?var-region=emea&var-step=Signup',
+  );
+  assert.equal(interactions.resolveHref({ kind: 'detail' }, {}), '');
+});
+
+function fakeToolbar(url, fields) {
+  const wrappers = Object.entries(fields).map(([name, value]) => ({
+    getAttribute: (attr) => (attr === 'data-filter' ? name : null),
+    querySelector: () => ({ value }),
+  }));
+  return {
+    ownerDocument: { defaultView: {} },
+    getAttribute: (attr) => (attr === 'data-preferences-url' ? url : null),
+    querySelectorAll: () => wrappers,
+  };
+}
+
+test('saveToolbarFilters posts the selected filters to the preferences endpoint', async () => {
+  const calls = [];
+  const form = fakeToolbar('/admin/dashboard/preferences', { region: 'emea', segment: '' });
+  const saved = await interactions.saveToolbarFilters(form, {
+    fetch: (url, init) => {
+      calls.push({ url, init });
+      return Promise.resolve({ ok: true });
+    },
+  });
+  assert.equal(saved, true);
+  assert.equal(calls.length, 1);
+  assert.equal(calls[0].url, '/admin/dashboard/preferences');
+  assert.equal(calls[0].init.method, 'POST');
+  assert.deepEqual(JSON.parse(calls[0].init.body), { filters: { region: 'emea', segment: '' } });
+});
+
+test('saveToolbarFilters does nothing without a preferences url', async () => {
+  const saved = await interactions.saveToolbarFilters(fakeToolbar(null, { region: 'emea' }), {
+    fetch: () => assert.fail('unexpected request'),
+  });
+  assert.equal(saved, false);
+});
+
+test('resolveHref drops javascript and other non-http targets', () => {
+  assert.equal(interactions.resolveHref({ kind: 'url', url: 'javascript:alert({label})' }, { label: 'x' }), '');
+  assert.equal(interactions.resolveHref({ kind: 'url', url: ' JavaScript:alert(1)' }, {}), '');
+  assert.equal(interactions.resolveHref({ kind: 'url', url: 'java\tscript:alert(1)' }, {}), '');
+  assert.equal(interactions.resolveHref({ kind: 'dashboard', dashboard: 'data:text/html,hi' }, {}), '');
+  assert.equal(interactions.resolveHref({ kind: 'url', url: 'https://example.com/{label}' }, { label: 'a' }), 'https://example.com/a');
+  assert.equal(interactions.safeHref('?tab=2'), '?tab=2');
+});
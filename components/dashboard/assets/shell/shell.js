@@ -117,6 +117,20 @@
     return Number.isFinite(n) ? n : undefined;
   }
 
+  function shellDirection(root) {
+    var el = root && root.closest ? root.closest('[dir]') : null;
+    var dir = el ? String(el.getAttribute('dir')).toLowerCase() : '';
+    return dir === 'rtl' ? 'rtl' : 'ltr';
+  }
+
+  // resizeDelta converts a horizontal pointer/key movement into a size change
+  // for a pane whose splitter sits on its leading or trailing edge. In RTL
+  // layouts the trailing edge is on the physical left.
+  function resizeDelta(edge, dx, dir) {
+    var delta = edge === 'leading' ? -dx : dx;
+    return dir === 'rtl' ? -delta : delta;
+  }
+
   function buildShellConfig(root, options) {
     options = options || {};
     var regions = [];
@@ -145,6 +159,7 @@
       namespace: root.getAttribute('data-dashboard-shell-namespace') || options.namespace || STORAGE_NAMESPACE,
       version: version,
       surface: root.getAttribute('data-dashboard-shell-surface') || 'dashboard',
+      dir: shellDirection(root),
       viewer: root.getAttribute('data-dashboard-shell-viewer') || options.viewer || '',
       module: root.getAttribute('data-dashboard-shell-module') || options.module || '',
       regions: regions,
@@ -333,8 +348,8 @@
       if (!def || !state || state.collapsed) return;
       var current = state.size === null ? def.defaultSize : state.size;
       var next = current;
-      if (event.key === 'ArrowLeft') next = current + (def.edge === 'leading' ? RESIZE_STEP : -RESIZE_STEP);
-      else if (event.key === 'ArrowRight') next = current + (def.edge === 'leading' ? -RESIZE_STEP : RESIZE_STEP);
+      if (event.key === 'ArrowLeft') next = current + resizeDelta(def.edge, -RESIZE_STEP, self.config.dir);
+      else if (event.key === 'ArrowRight') next = current + resizeDelta(def.edge, RESIZE_STEP, self.config.dir);
       else if (event.key === 'Home') next = def.min;
       else if (event.key === 'End') next = def.max;
       else return;
@@ -363,8 +378,7 @@
     var startSize = state.size === null ? el.getBoundingClientRect().width : state.size;
     function onMove(move) {
       var dx = move.clientX - startX;
-      var delta = def.edge === 'leading' ? -dx : dx;
-      self.setRegionSize(id, startSize + delta, { persist: false });
+      self.setRegionSize(id, startSize + resizeDelta(def.edge, dx, self.config.dir), { persist: false });
     }
     function onUp() {
       self.endResize();
@@ -416,6 +430,8 @@
     SHELL_VERSION: SHELL_VERSION,
     STORAGE_NAMESPACE: STORAGE_NAMESPACE,
     clampSize: clampSize,
+    resizeDelta: resizeDelta,
+    shellDirection: shellDirection,
     shellStorageKey: shellStorageKey,
     defaultShellState: defaultShellState,
     sanitizeShellState: sanitizeShellState,
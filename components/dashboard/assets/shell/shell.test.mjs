@@ -203,6 +203,22 @@ test('keyboard resize adjusts separator-owned region within bounds', () => {
   controller.destroy();
 });
 
+test('resize direction mirrors for right-to-left shells', () => {
+  assert.equal(shell.resizeDelta('trailing', 10, 'ltr'), 10);
+  assert.equal(shell.resizeDelta('leading', 10, 'ltr'), -10);
+  assert.equal(shell.resizeDelta('trailing', 10, 'rtl'), -10);
+  assert.equal(shell.resizeDelta('leading', 10, 'rtl'), 10);
+
+  const root = setup(markup().replace('<section ', '<section dir="rtl" '));
+  assert.equal(shell.shellDirection(root), 'rtl');
+  const controller = shell.initShell(root, { storage: memStorage() });
+  const nav = root.querySelector('[data-shell-region="nav"]');
+  const handle = root.querySelector('[data-shell-resize="nav"]');
+  handle.dispatchEvent(new window.KeyboardEvent('keydown', { key: 'ArrowLeft', bubbles: true }));
+  assert.equal(nav.style.width, '296px');
+  controller.destroy();
+});
+
 test('initShell is idempotent and initShells skips roots without regions', () => {
   const dom = new JSDOM(`<!doctype html><html><body>
     <section data-dashboard-shell data-dashboard-shell-surface="empty"></section>
@@ -0,0 +1,172 @@
+(function (global) {
+  'use strict';
+
+  // Dashboard-level query parameters forwarded to widget actions so paging and
+  // exports see the same time range and filters as the rendered page.
+  var FORWARDED = ['range', 'from', 'to', 'locale'];
+  var FILTER_PREFIX = 'var-';
+
+  function readState(root) {
+    return {
+      sort: root.getAttribute('data-table-sort') || '',
+      desc: root.getAttribute('data-table-desc') === 'true',
+      page: parseInt(root.getAttribute('data-table-page'), 10) || 1,
+      pageSize: parseInt(root.getAttribute('data-table-page-size'), 10) || 0,
+      q: root.getAttribute('data-table-search') || '',
+    };
+  }
+
+  // nextSortState cycles a column: ascending, then descending; switching
+  // columns starts ascending. Sorting always returns to the first page.
+  function nextSortState(state, key) {
+    var next = Object.assign({}, state, { page: 1 });
+    if (state.sort === key) {
+      next.desc = !state.desc;
+    } else {
+      next.sort = key;
+      next.desc = false;
+    }
+    return next;
+  }
+
+  function actionURL(base, state, search) {
+    var params = [];
+    var current = new URLSearchParams(search || '');
+    current.forEach(function (value, key) {
+      if (key.indexOf(FILTER_PREFIX) === 0 || FORWARDED.indexOf(key) >= 0) {
+        params.push([key, value]);
+      }
+    });
+    if (state.sort) {
+      params.push(['sort', state.sort]);
+      params.push(['desc', state.desc ? 'true' : 'false']);
+    }
+    if (state.page > 1) params.push(['page', String(state.page)]);
+    if (state.pageSize > 0) params.push(['page_size', String(state.pageSize)]);
+    if (state.q) params.push(['q', state.q]);
+    if (params.length === 0) return base;
+    var query = params.map(function (pair) {
+      return encodeURIComponent(pair[0]) + '=' + encodeURIComponent(pair[1]);
+    }).join('&');
+    return base + (base.indexOf('?') >= 0 ? '&' : '?') + query;
+  }
+
+  function render(root, view) {
+    var doc = root.ownerDocument;
+    root.setAttribute('data-table-sort', view.sort || '');
+    root.setAttribute('data-table-desc', view.desc ? 'true' : 'false');
+    root.setAttribute('data-table-page', String(view.page || 1));
+    root.querySelectorAll('th[data-column]').forEach(function (th) {
+      var column = (view.columns || []).filter(function (col) { return col.key === th.getAttribute('data-column'); })[0];
+      var sorted = column && column.sorted;
+      th.setAttribute('aria-sort', sorted === 'asc' ? 'ascending' : sorted === 'desc' ? 'descending' : 'none');
+    });
+    var body = root.querySelector('tbody');
+    if (body) {
+      body.textContent = '';
+      (view.rows || []).forEach(function (row) {
+        var tr = doc.createElement('tr');
+        tr.setAttribute('data-widget-datum', '');
+        (row.cells || []).forEach(function (cell) {
+          tr.setAttribute('data-datum-' + cell.key, cell.raw || '');
+          var td = doc.createElement('td');
+          td.className = 'table__cell table__cell--' + (cell.align || 'start');
+          td.textContent = cell.display;
+          tr.appendChild(td);
+        });
+        body.appendChild(tr);
+      });
+    }
+    var status = root.querySelector('[data-table-status]');
+    if (status) status.textContent = (view.page || 1) + ' / ' + (view.pages || 1);
+    var prev = root.querySelector('[data-table-prev]');
+    if (prev) prev.disabled = (view.page || 1) <= 1;
+    var next = root.querySelector('[data-table-next]');
+    if (next) next.disabled = (view.page || 1) >= (view.pages || 1);
+  }
+
+  function syncExport(root, state, search) {
+    var link = root.querySelector('[data-table-export]');
+    if (!link) return;
+    var base = link.getAttribute('data-table-export');
+    link.setAttribute('href', actionURL(base, Object.assign({}, state, { page: 1, pageSize: 0 }), search));
+  }
+
+  function load(root, state, options) {
+    var win = root.ownerDocument.defaultView || global;
+    var search = win.location ? win.location.search : '';
+    var fetcher = (options && options.fetch) || win.fetch;
+    syncExport(root, state, search);
+    if (!fetcher) return Promise.resolve(null);
+    root.setAttribute('aria-busy', 'true');
+    return fetcher.call(win, actionURL(root.getAttribute('data-table-url'), state, search), {
+      headers: { Accept: 'application/json' },
+    })
+      .then(function (response) {
+        if (!response.ok) throw new Error('table action failed: ' + response.status);
+        return response.json();
+      })
+      .then(function (view) {
+        render(root, view);
+        return view;
+      })
+      .finally(function () {
+        root.removeAttribute('aria-busy');
+      });
+  }
+
+  function initTables(scope, options) {
+    scope = scope || global.document;
+    if (!scope || !scope.querySelectorAll) return [];
+    var bound = [];
+    scope.querySelectorAll('[data-dashboard-table]').forEach(function (root) {
+      if (root.getAttribute('data-table-init') === 'true') return;
+      root.addEventListener('click', function (event) {
+        var target = event.target && event.target.closest ? event.target.closest('[data-table-sort-key], [data-table-prev], [data-table-next]') : null;
+        if (!target || !root.contains(target)) return;
+        var state = readState(root);
+        if (target.hasAttribute('data-table-sort-key')) {
+          state = nextSortState(state, target.getAttribute('data-table-sort-key'));
+        } else {
+          state.page = Math.max(1, state.page + (target.hasAttribute('data-table-prev') ? -1 : 1));
+        }
+        load(root, state, options);
+      });
+      var form = root.querySelector('[data-table-search-form]');
+      if (form) {
+        form.addEventListener('submit', function (event) {
+          event.preventDefault();
+          var input = form.querySelector('input[name="q"]');
+          var state = Object.assign(readState(root), { page: 1, q: input ? input.value.trim() : '' });
+          root.setAttribute('data-table-search', state.q);
+          load(root, state, options);
+        });
+      }
+      var win = root.ownerDocument.defaultView || global;
+      syncExport(root, readState(root), win.location ? win.location.search : '');
+      root.setAttribute('data-table-init', 'true');
+      bound.push(root);
+    });
+    return bound;
+  }
+
+  var api = {
+    nextSortState: nextSortState,
+    actionURL: actionURL,
+    load: load,
+    initTables: initTables,
+  };
+
+  if (typeof module !== 'undefined' && module.exports) {
+    module.exports = api;
+  }
+  global.DashboardTables = api;
+
+  if (global.document) {
+    if (global.document.readyState === 'loading') {
+      global.document.addEventListener('DOMContentLoaded', function () { initTables(global.document); });
+    } else {
+      initTables(global.document);
+    }
+  }
+})(typeof window !== 'undefined' ? window : globalThis);
@@ -0,0 +1,28 @@
+import test from 'node:test';
+import assert from 'node:assert/strict';
+import { createRequire } from 'node:module';
+
+const require = createRequire(import.meta.url);
+const tables = require('./table.js');
+
+test('nextSortState toggles direction and resets the page', () => {
+  const state = { sort: 'amount', desc: false, page: 3, pageSize: 25, q: '' };
+  assert.deepEqual(tables.nextSortState(state, 'amount'), { ...state, desc: true, page: 1 });
+  assert.deepEqual(tables.nextSortState(state, 'customer'), { ...state, sort: 'customer', desc: false, page: 1 });
+});
+
+test('actionURL forwards dashboard filters and time range only', () => {
+  const url = tables.actionURL(
+    '/admin/dashboard/widgets/w1/actions/page',
+    { sort: 'amount', desc: true, page: 2, pageSize: 0, q: 'acme' },
+    '?range=7d&var-region=emea&tab=orders',
+  );
+  assert.equal(
+    url,
+    '/admin/dashboard/widgets/w1/actions/page?range=7d&var-region=emea&sort=amount&desc=true&page=2&q=acme',
+  );
+});
+
+test('actionURL returns the base path without state', () => {
+  assert.equal(tables.actionURL('/actions/page', { page: 1 }, ''), '/actions/page');
+});
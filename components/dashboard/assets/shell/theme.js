@@ -0,0 +1,225 @@
+(function (global) {
+  'use strict';
+
+  var STORAGE_KEY = 'go-dashboard:theme-variant';
+  var COOKIE_NAME = 'dashboard_theme_variant';
+  var COOKIE_MAX_AGE = 60 * 60 * 24 * 365;
+  var EVENT_NAME = 'dashboard:themechange';
+  var DARK_QUERY = '(prefers-color-scheme: dark)';
+  // Option keys ECharts copies from the active theme into getOption(); they
+  // must be dropped before re-initialising so the new theme can apply.
+  var THEME_OPTION_KEYS = ['color', 'backgroundColor', 'textStyle', 'darkMode'];
+
+  function parseVariants(root) {
+    var raw = root.getAttribute('data-theme-variants');
+    if (!raw) return {};
+    try {
+      var parsed = JSON.parse(raw);
+      return parsed && typeof parsed === 'object' && !Array.isArray(parsed) ? parsed : {};
+    } catch (error) {
+      return {};
+    }
+  }
+
+  // preferredVariant resolves the variant to show: an explicit stored choice
+  // wins, then the system color scheme while the page follows it, then the
+  // server-rendered variant.
+  function preferredVariant(options) {
+    var variants = options.variants || [];
+    if (options.stored && variants.indexOf(options.stored) >= 0) return options.stored;
+    if (options.auto) {
+      var scheme = options.prefersDark ? 'dark' : 'light';
+      if (variants.indexOf(scheme) >= 0) return scheme;
+    }
+    return options.fallback || variants[0] || '';
+  }
+
+  function nextVariant(current, variants) {
+    if (!variants || variants.length === 0) return current;
+    var idx = variants.indexOf(current);
+    return variants[(idx + 1) % variants.length];
+  }
+
+  function stripThemeOption(option) {
+    var out = Object.assign({}, option);
+    THEME_OPTION_KEYS.forEach(function (key) { delete out[key]; });
+    return out;
+  }
+
+  function rethemeCharts(scope, chartTheme, echarts) {
+    echarts = echarts || global.echarts;
+    if (!scope || !chartTheme || !echarts || !echarts.getInstanceByDom) return [];
+    var updated = [];
+    scope.querySelectorAll('[data-chart-theme]').forEach(function (container) {
+      if (container.hasAttribute('data-chart-theme-locked')) return;
+      if (container.getAttribute('data-chart-theme') === chartTheme) return;
+      container.querySelectorAll('[id]').forEach(function (el) {
+        var chart = echarts.getInstanceByDom(el);
+        if (!chart) return;
+        var option = stripThemeOption(chart.getOption());
+        chart.dispose();
+        var next = echarts.init(el, chartTheme, { renderer: 'canvas' });
+        next.setOption(option);
+        updated.push(next);
+      });
+      container.setAttribute('data-chart-theme', chartTheme);
+    });
+    return updated;
+  }
+
+  function localStorageOrNull() {
+    try {
+      return global.localStorage || null;
+    } catch (error) {
+      return null;
+    }
+  }
+
+  function readStored(storage) {
+    if (!storage) return null;
+    try {
+      return storage.getItem(STORAGE_KEY);
+    } catch (error) {
+      return null;
+    }
+  }
+
+  function writeStored(storage, variant) {
+    if (!storage) return;
+    try {
+      storage.setItem(STORAGE_KEY, variant);
+    } catch (error) {}
+  }
+
+  function writeCookie(doc, variant) {
+    if (!doc) return;
+    try {
+      doc.cookie = COOKIE_NAME + '=' + encodeURIComponent(variant) +
+        '; path=/; max-age=' + COOKIE_MAX_AGE + '; samesite=lax';
+    } catch (error) {}
+  }
+
+  function ThemeController(root, options) {
+    options = options || {};
+    this.root = root;
+    this.doc = root.ownerDocument || global.document;
+    this.win = (this.doc && this.doc.defaultView) || global;
+    this.chartThemes = parseVariants(root);
+    this.variants = Object.keys(this.chartThemes).sort();
+    this.storage = Object.prototype.hasOwnProperty.call(options, 'storage') ? options.storage : localStorageOrNull();
+    this.media = this.win.matchMedia ? this.win.matchMedia(DARK_QUERY) : null;
+    this.echarts = options.echarts;
+    this.cleanups = [];
+  }
+
+  ThemeController.prototype.stored = function () {
+    return readStored(this.storage);
+  };
+
+  ThemeController.prototype.current = function () {
+    return this.root.getAttribute('data-theme') || '';
+  };
+
+  ThemeController.prototype.apply = function (variant, manual) {
+    if (this.variants.indexOf(variant) < 0) return;
+    var changed = variant !== this.current();
+    this.root.setAttribute('data-theme', variant);
+    if (manual) this.root.setAttribute('data-theme-mode', 'manual');
+    this.root.querySelectorAll('[data-theme-toggle]').forEach(function (toggle) {
+      toggle.setAttribute('aria-pressed', variant === 'dark' ? 'true' : 'false');
+      toggle.setAttribute('data-theme-current', variant);
+    });
+    var chartTheme = this.chartThemes[variant];
+    if (chartTheme) rethemeCharts(this.root, chartTheme, this.echarts);
+    if (changed && this.doc && typeof this.win.CustomEvent === 'function') {
+      this.doc.dispatchEvent(new this.win.CustomEvent(EVENT_NAME, {
+        detail: { variant: variant, chartTheme: chartTheme || '' },
+      }));
+    }
+  };
+
+  ThemeController.prototype.choose = function (variant) {
+    if (this.variants.indexOf(variant) < 0) return;
+    writeStored(this.storage, variant);
+    writeCookie(this.doc, variant);
+    this.apply(variant, true);
+  };
+
+  ThemeController.prototype.toggle = function () {
+    this.choose(nextVariant(this.current(), this.variants));
+  };
+
+  ThemeController.prototype.init = function () {
+    var self = this;
+    var stored = this.stored();
+    var auto = this.root.getAttribute('data-theme-mode') === 'auto';
+    this.apply(preferredVariant({
+      stored: stored,
+      auto: auto,
+      prefersDark: this.media ? this.media.matches : false,
+      variants: this.variants,
+      fallback: this.current(),
+    }), this.variants.indexOf(stored) >= 0);
+
+    function onClick(event) {
+      var toggle = event.target && event.target.closest ? event.target.closest('[data-theme-toggle]') : null;
+      if (!toggle || !self.root.contains(toggle)) return;
+      event.preventDefault();
+      self.toggle();
+    }
+    this.root.addEventListener('click', onClick);
+    this.cleanups.push(function () { self.root.removeEventListener('click', onClick); });
+
+    if (this.media && this.media.addEventListener) {
+      var onScheme = function (event) {
+        if (self.root.getAttribute('data-theme-mode') !== 'auto') return;
+        self.apply(event.matches ? 'dark' : 'light', false);
+      };
+      this.media.addEventListener('change', onScheme);
+      this.cleanups.push(function () { self.media.removeEventListener('change', onScheme); });
+    }
+    return this;
+  };
+
+  ThemeController.prototype.destroy = function () {
+    this.cleanups.forEach(function (cleanup) { cleanup(); });
+    this.cleanups.length = 0;
+  };
+
+  function initThemes(scope, options) {
+    scope = scope || global.document;
+    if (!scope || !scope.querySelectorAll) return [];
+    var controllers = [];
+    scope.querySelectorAll('[data-theme-variants]').forEach(function (root) {
+      if (root.getAttribute('data-theme-init') === 'true') return;
+      controllers.push(new ThemeController(root, options).init());
+      root.setAttribute('data-theme-init', 'true');
+    });
+    return controllers;
+  }
+
+  var api = {
+    STORAGE_KEY: STORAGE_KEY,
+    COOKIE_NAME: COOKIE_NAME,
+    EVENT_NAME: EVENT_NAME,
+    preferredVariant: preferredVariant,
+    nextVariant: nextVariant,
+    stripThemeOption: stripThemeOption,
+    rethemeCharts: rethemeCharts,
+    ThemeController: ThemeController,
+    initThemes: initThemes,
+  };
+
+  if (typeof module !== 'undefined' && module.exports) {
+    module.exports = api;
+  }
+  global.DashboardTheme = api;
+
+  if (global.document) {
+    if (global.document.readyState === 'loading') {
+      global.document.addEventListener('DOMContentLoaded', function () { initThemes(global.document); });
+    } else {
+      initThemes(global.document);
+    }
+  }
+})(typeof window !== 'undefined' ? window : globalThis);
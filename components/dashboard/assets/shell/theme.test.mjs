@@ -0,0 +1,53 @@
+import test from 'node:test';
+import assert from 'node:assert/strict';
+import { createRequire } from 'node:module';
+
+const require = createRequire(import.meta.url);
+const theme = require('./theme.js');
+
+test('preferredVariant honours stored choice, then system scheme, then server variant', () => {
+  const variants = ['dark', 'light'];
+  assert.equal(theme.preferredVariant({ stored: 'light', auto: true, prefersDark: true, variants, fallback: 'dark' }), 'light');
+  assert.equal(theme.preferredVariant({ stored: 'sepia', auto: true, prefersDark: true, variants, fallback: 'light' }), 'dark');
+  assert.equal(theme.preferredVariant({ auto: false, prefersDark: true, variants, fallback: 'light' }), 'light');
+  assert.equal(theme.preferredVariant({ auto: true, prefersDark: false, variants: ['dim', 'dark'], fallback: 'dim' }), 'dim');
+});
+
+test('nextVariant cycles through the available variants', () => {
+  assert.equal(theme.nextVariant('light', ['dark', 'light']), 'dark');
+  assert.equal(theme.nextVariant('dark', ['dark', 'light']), 'light');
+  assert.equal(theme.nextVariant('unknown', ['dark', 'light']), 'dark');
+});
+
+test('rethemeCharts re-initialises unlocked charts without theme-owned options', () => {
+  const inits = [];
+  const instance = {
+    disposed: false,
+    getOption: () => ({ color: ['#000'], backgroundColor: '#fff', series: [{ type: 'line' }] }),
+    dispose() { this.disposed = true; },
+  };
+  const el = { id: 'c1' };
+  const fakeContainer = (locked, current) => ({
+    attrs: { 'data-chart-theme': current },
+    hasAttribute: (name) => locked && name === 'data-chart-theme-locked',
+    getAttribute(name) { return this.attrs[name]; },
+    setAttribute(name, value) { this.attrs[name] = value; },
+    querySelectorAll: () => [el],
+  });
+  const unlocked = fakeContainer(false, 'westeros');
+  const locked = fakeContainer(true, 'westeros');
+  const scope = { querySelectorAll: () => [unlocked, locked] };
+  const echarts = {
+    getInstanceByDom: () => instance,
+    init: (target, name) => {
+      const next = { setOption: (option) => inits.push({ target, name, option }) };
+      return next;
+    },
+  };
+  const updated = theme.rethemeCharts(scope, 'wonderland', echarts);
+  assert.equal(updated.length, 1);
+  assert.equal(instance.disposed, true);
+  assert.deepEqual(inits, [{ target: el, name: 'wonderland', option: { series: [{ type: 'line' }] } }]);
+  assert.equal(unlocked.attrs['data-chart-theme'], 'wonderland');
+  assert.equal(locked.attrs['data-chart-theme'], 'westeros');
+});
@@ -0,0 +1,223 @@
+package dashboard
+
+import (
+	"maps"
+	"slices"
+)
+
+// Breakpoint names a responsive viewport range used by widget layouts.
+type Breakpoint string
+
+// Supported breakpoints, from phones to wide desktops. Ranges match
+// assets/shell/layout.css: sm < 768px, md < 1024px, lg < 1280px, xl beyond.
+const (
+	BreakpointSM Breakpoint = "sm"
+	BreakpointMD Breakpoint = "md"
+	BreakpointLG Breakpoint = "lg"
+	BreakpointXL Breakpoint = "xl"
+)
+
+var breakpoints = []Breakpoint{BreakpointSM, BreakpointMD, BreakpointLG, BreakpointXL}
+
+// Breakpoints returns the supported breakpoints, smallest first.
+func Breakpoints() []Breakpoint {
+	return slices.Clone(breakpoints)
+}
+
+// Valid reports whether bp is a supported breakpoint.
+func (bp Breakpoint) Valid() bool {
+	return slices.Contains(breakpoints, bp)
+}
+
+// BreakpointLayout is the resolved placement of a widget at one breakpoint.
+// Order is 1-based; 0 keeps the document order.
+type BreakpointLayout struct {
+	Width int `json:"width"`
+	Order int `json:"order,omitempty"`
+}
+
+// ResolveBreakpointWidths fills in the width of every breakpoint. Widgets are
+// full width on sm unless set; md defaults to the base width and lg/xl inherit
+// the next smaller breakpoint. Widths are clamped to 1-12.
+func ResolveBreakpointWidths(width int, widths map[Breakpoint]int) map[Breakpoint]int {
+	resolved := make(map[Breakpoint]int, len(breakpoints))
+	pick := func(bp Breakpoint, fallback int) int {
+		if w := widths[bp]; w > 0 {
+			return clampSpan(w)
+		}
+		return fallback
+	}
+	resolved[BreakpointSM] = pick(BreakpointSM, 12)
+	resolved[BreakpointMD] = pick(BreakpointMD, clampSpan(width))
+	resolved[BreakpointLG] = pick(BreakpointLG, resolved[BreakpointMD])
+	resolved[BreakpointXL] = pick(BreakpointXL, resolved[BreakpointLG])
+	return resolved
+}
+
+func clampSpan(width int) int {
+	if width <= 0 || width > 12 {
+		return 12
+	}
+	return width
+}
+
+// applyBreakpointMetadata adds per-breakpoint widths and order under the
+// `breakpoints` key of the layout metadata. Once an area has a breakpoint
+// order every widget gets one, unlisted widgets following the listed ones in
+// document order; otherwise only widgets with per-breakpoint widths change.
+func applyBreakpointMetadata(widgets []WidgetInstance, rows []LayoutRow, order map[Breakpoint][]string) []WidgetInstance {
+	if len(widgets) == 0 {
+		return widgets
+	}
+	widths := map[string]map[Breakpoint]int{}
+	for _, row := range rows {
+		for _, slot := range row.Widgets {
+			if len(slot.Widths) > 0 {
+				widths[slot.ID] = slot.Widths
+			}
+		}
+	}
+	positions := breakpointPositions(order)
+	if len(widths) == 0 && len(positions) == 0 {
+		return widgets
+	}
+	for i, w := range widgets {
+		if _, ok := widths[w.ID]; !ok && len(positions) == 0 {
+			continue
+		}
+		metadata := maps.Clone(w.Metadata)
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		layout, _ := metadata["layout"].(map[string]any)
+		layout = maps.Clone(layout)
+		if layout == nil {
+			layout = map[string]any{"width": 12, "columns": 12}
+		}
+		resolved := ResolveBreakpointWidths(intValue(layout["width"]), widths[w.ID])
+		payload := make(map[string]any, len(breakpoints))
+		for _, bp := range breakpoints {
+			entry := map[string]any{"width": resolved[bp]}
+			if index, ok := positions[bp]; ok {
+				pos := index[w.ID]
+				if pos == 0 {
+					pos = len(index) + i + 1
+				}
+				entry["order"] = pos
+			}
+			payload[string(bp)] = entry
+		}
+		layout["breakpoints"] = payload
+		if explicit := widths[w.ID]; len(explicit) > 0 {
+			// The editor posts the explicit widths back on save.
+			layout["widths"] = maps.Clone(explicit)
+		}
+		metadata["layout"] = layout
+		widgets[i].Metadata = metadata
+	}
+	return widgets
+}
+
+// responsive reports whether the widget carries per-breakpoint layouts.
+func (widget WidgetFrame) responsive() bool {
+	return widget.Meta.Layout != nil && len(widget.Meta.Layout.Breakpoints) > 0
+}
+
+// breakpointPositions indexes per-breakpoint orders into 1-based positions.
+func breakpointPositions(order map[Breakpoint][]string) map[Breakpoint]map[string]int {
+	positions := map[Breakpoint]map[string]int{}
+	for bp, ids := range order {
+		if !bp.Valid() || len(ids) == 0 {
+			continue
+		}
+		index := make(map[string]int, len(ids))
+		for i, id := range ids {
+			if _, ok := index[id]; !ok && id != "" {
+				index[id] = i + 1
+			}
+		}
+		positions[bp] = index
+	}
+	return positions
+}
+
+// parseBreakpointLayouts reads the `breakpoints` layout metadata written by
+// applyBreakpointMetadata, including after a JSON round trip.
+func parseBreakpointLayouts(raw any) map[Breakpoint]BreakpointLayout {
+	var out map[Breakpoint]BreakpointLayout
+	switch typed := raw.(type) {
+	case map[Breakpoint]BreakpointLayout:
+		out = maps.Clone(typed)
+	case map[string]any:
+		for key, value := range typed {
+			bp := Breakpoint(key)
+			if !bp.Valid() {
+				continue
+			}
+			var layout BreakpointLayout
+			switch entry := value.(type) {
+			case BreakpointLayout:
+				layout = entry
+			case map[string]any:
+				layout = BreakpointLayout{Width: intValue(entry["width"]), Order: intValue(entry["order"])}
+			default:
+				continue
+			}
+			if out == nil {
+				out = map[Breakpoint]BreakpointLayout{}
+			}
+			out[bp] = layout
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// parseBreakpointWidths reads the explicit `widths` layout metadata, including
+// after a JSON round trip.
+func parseBreakpointWidths(raw any) map[Breakpoint]int {
+	var out map[Breakpoint]int
+	switch typed := raw.(type) {
+	case map[Breakpoint]int:
+		out = maps.Clone(typed)
+		maps.DeleteFunc(out, func(bp Breakpoint, width int) bool { return !bp.Valid() || width <= 0 })
+	case map[string]any:
+		for key, value := range typed {
+			if bp := Breakpoint(key); bp.Valid() && intValue(value) > 0 {
+				if out == nil {
+					out = map[Breakpoint]int{}
+				}
+				out[bp] = intValue(value)
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func breakpointLayoutsPayload(layouts map[Breakpoint]BreakpointLayout) map[string]any {
+	payload := make(map[string]any, len(layouts))
+	for bp, layout := range layouts {
+		payload[string(bp)] = map[string]any{"width": layout.Width, "order": layout.Order}
+	}
+	return payload
+}
+
+func cloneBreakpointOrder(order map[string]map[Breakpoint][]string) map[string]map[Breakpoint][]string {
+	if order == nil {
+		return nil
+	}
+	out := make(map[string]map[Breakpoint][]string, len(order))
+	for area, byBreakpoint := range order {
+		copied := make(map[Breakpoint][]string, len(byBreakpoint))
+		for bp, ids := range byBreakpoint {
+			copied[bp] = slices.Clone(ids)
+		}
+		out[area] = copied
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveBreakpointWidthsFallsBack(t *testing.T) {
+	got := ResolveBreakpointWidths(6, nil)
+	if got[BreakpointSM] != 12 || got[BreakpointMD] != 6 || got[BreakpointLG] != 6 || got[BreakpointXL] != 6 {
+		t.Fatalf("expected full width on sm and base width above, got %+v", got)
+	}
+	got = ResolveBreakpointWidths(0, map[Breakpoint]int{BreakpointSM: 6, BreakpointLG: 4, BreakpointXL: 40})
+	if got[BreakpointSM] != 6 || got[BreakpointMD] != 12 || got[BreakpointLG] != 4 || got[BreakpointXL] != 12 {
+		t.Fatalf("expected explicit widths with clamped fallbacks, got %+v", got)
+	}
+}
+
+func TestConfigureLayoutAppliesBreakpointLayouts(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolved: map[string][]WidgetInstance{
+			"admin.dashboard.main": {
+				{ID: "w1", DefinitionID: "admin.widget.user_stats", Configuration: map[string]any{"metric": "total"}, Metadata: map[string]any{"data": WidgetData{"values": map[string]any{"total": 1}}}},
+				{ID: "w2", DefinitionID: "admin.widget.user_stats", Configuration: map[string]any{"metric": "total"}, Metadata: map[string]any{"data": WidgetData{"values": map[string]any{"total": 2}}}},
+				{ID: "w3", DefinitionID: "admin.widget.user_stats", Configuration: map[string]any{"metric": "total"}, Metadata: map[string]any{"data": WidgetData{"values": map[string]any{"total": 3}}}},
+			},
+		},
+	}
+	service := NewService(Options{WidgetStore: store, PreferenceStore: NewInMemoryPreferenceStore()})
+	viewer := ViewerContext{UserID: "user-1"}
+	var input SaveLayoutPreferencesInput
+	if err := json.Unmarshal([]byte(`{
+		"area_order": {"admin.dashboard.main": ["w1", "w2", "w3"]},
+		"layout_rows": {"admin.dashboard.main": [
+			{"widgets": [{"id": "w1", "width": 8, "widths": {"sm": 6, "xl": 4, "xxl": 2}}, {"id": "w2", "width": 4}]},
+			{"widgets": [{"id": "w3", "width": 12}]}
+		]},
+		"breakpoint_order": {"admin.dashboard.main": {"sm": ["w3"]}}
+	}`), &input); err != nil {
+		t.Fatalf("decode preferences: %v", err)
+	}
+	input.Viewer = viewer
+	if err := (&ServiceExecutor{Service: service}).Preferences(context.Background(), input); err != nil {
+		t.Fatalf("Preferences returned error: %v", err)
+	}
+
+	controller := NewController(ControllerOptions{Service: service})
+	page, err := controller.Page(context.Background(), viewer)
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	widgets := page.Areas[0].Widgets
+	first := widgets[0].Meta.Layout.Breakpoints
+	if first[BreakpointSM] != (BreakpointLayout{Width: 6, Order: 2}) || first[BreakpointMD].Width != 8 || first[BreakpointXL] != (BreakpointLayout{Width: 4}) {
+		t.Fatalf("expected resolved breakpoint widths for w1, got %+v", first)
+	}
+	if _, ok := first["xxl"]; ok {
+		t.Fatalf("expected unknown breakpoints dropped, got %+v", first)
+	}
+	if third := widgets[2].Meta.Layout.Breakpoints; third[BreakpointSM].Order != 1 || third[BreakpointLG].Order != 0 {
+		t.Fatalf("expected w3 first on sm only, got %+v", third)
+	}
+	if page.Assets == nil || !slices.Contains(page.Assets.CSS, LayoutStylesURL("")) {
+		t.Fatalf("expected responsive layout styles, got %+v", page.Assets)
+	}
+
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`class="dashboard-area dashboard-area--responsive"`,
+		`dashboard-widget--span-8 dashboard-widget--responsive dashboard-widget--lg-8 dashboard-widget--md-8 dashboard-widget--sm-6 dashboard-widget--sm-order-2 dashboard-widget--xl-4"`,
+		`--dashboard-widget-span-sm: 6; --dashboard-widget-order-sm: 2`,
+		`dashboard-widget--sm-order-1`,
+		`data-widget-widths="{&quot;sm&quot;:6,&quot;xl&quot;:4}"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in rendered page:\n%s", want, html)
+		}
+	}
+}
+
+func TestSavePreferencesKeepsBreakpointWidthsMissingFromTheSave(t *testing.T) {
+	prefs := NewInMemoryPreferenceStore()
+	executor := &ServiceExecutor{Service: NewService(Options{PreferenceStore: prefs})}
+	viewer := ViewerContext{UserID: "user-1"}
+	ctx := context.Background()
+	save := func(body string) LayoutOverrides {
+		t.Helper()
+		var input SaveLayoutPreferencesInput
+		if err := json.Unmarshal([]byte(body), &input); err != nil {
+			t.Fatalf("decode preferences: %v", err)
+		}
+		input.Viewer = viewer
+		if err := executor.Preferences(ctx, input); err != nil {
+			t.Fatalf("Preferences returned error: %v", err)
+		}
+		stored, err := prefs.LayoutOverrides(ctx, viewer)
+		if err != nil {
+			t.Fatalf("LayoutOverrides returned error: %v", err)
+		}
+		return stored
+	}
+
+	save(`{"layout_rows": {"admin.dashboard.main": [{"widgets": [{"id": "w1", "width": 8, "widths": {"sm": 6, "xl": 4}}, {"id": "w2", "width": 4}]}]}}`)
+	stored := save(`{"layout_rows": {"admin.dashboard.main": [{"widgets": [{"id": "w2", "width": 6}]}, {"widgets": [{"id": "w1", "width": 6}]}]}}`)
+	rows := stored.AreaRows["admin.dashboard.main"]
+	if len(rows) != 2 || rows[1].Widgets[0].Width != 6 || rows[1].Widgets[0].Widths[BreakpointSM] != 6 || rows[1].Widgets[0].Widths[BreakpointXL] != 4 {
+		t.Fatalf("expected w1 to keep its breakpoint widths, got %+v", rows)
+	}
+	if len(rows[0].Widgets[0].Widths) != 0 {
+		t.Fatalf("expected w2 to stay without breakpoint widths, got %+v", rows[0].Widgets[0])
+	}
+
+	stored = save(`{"layout_rows": {"admin.dashboard.main": [{"widgets": [{"id": "w1", "width": 6, "widths": {}}]}]}}`)
+	if widths := stored.AreaRows["admin.dashboard.main"][0].Widgets[0].Widths; len(widths) != 0 {
+		t.Fatalf("expected empty widths to clear the stored ones, got %+v", widths)
+	}
+}
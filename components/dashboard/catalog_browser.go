@@ -0,0 +1,258 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const catalogTemplate = "catalog.html"
+
+// ErrCatalogUnsupported is returned when the service or renderer cannot serve
+// the catalog browser.
+var ErrCatalogUnsupported = errors.New("dashboard: widget catalog browser not supported")
+
+// CatalogPageRequest selects the widgets shown by the catalog browser. Empty
+// filters match everything; Query matches codes, names, descriptions and tags.
+type CatalogPageRequest struct {
+	Viewer   ViewerContext `json:"-"`
+	Channels []string      `json:"channels,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Tag      string        `json:"tag,omitempty"`
+	Query    string        `json:"query,omitempty"`
+}
+
+// CatalogPage is the catalog browser view: installed definitions plus the
+// widgets merged from catalog indexes, localized for the viewer.
+type CatalogPage struct {
+	Title  string             `json:"title"`
+	Locale string             `json:"locale,omitempty"`
+	Filter CatalogPageRequest `json:"filter"`
+	// Channels and Tags list every value present before filtering, for
+	// filter controls.
+	Channels []string           `json:"channels,omitempty"`
+	Tags     []string           `json:"tags,omitempty"`
+	Entries  []CatalogPageEntry `json:"entries"`
+	// Theme is the viewer's theme, so renderers apply its template overrides.
+	Theme *ThemeSelection `json:"theme,omitempty"`
+}
+
+// CatalogPageEntry is a single widget listed by the catalog browser.
+type CatalogPageEntry struct {
+	Code             string   `json:"code"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description,omitempty"`
+	Category         string   `json:"category,omitempty"`
+	Channel          string   `json:"channel"`
+	Tags             []string `json:"tags,omitempty"`
+	Status           string   `json:"status"`
+	Version          string   `json:"version,omitempty"`
+	InstalledVersion string   `json:"installed_version,omitempty"`
+	Maintainers      []string `json:"maintainers,omitempty"`
+	Manifest         string   `json:"manifest,omitempty"`
+	Package          string   `json:"package,omitempty"`
+	Homepage         string   `json:"homepage,omitempty"`
+	DocsURL          string   `json:"docs_url,omitempty"`
+}
+
+// WidgetCatalogBrowser builds the catalog browser page.
+type WidgetCatalogBrowser interface {
+	WidgetCatalogPage(ctx context.Context, req CatalogPageRequest) (CatalogPage, error)
+}
+
+var _ WidgetCatalogBrowser = (*Service)(nil)
+
+type catalogSource interface {
+	Catalog() CatalogSnapshot
+}
+
+// WidgetCatalogPage lists the registered definitions and merged catalog index
+// entries of the channels the viewer may browse. It requires a provider
+// registry exposing Catalog, such as *Registry.
+func (s *Service) WidgetCatalogPage(ctx context.Context, req CatalogPageRequest) (CatalogPage, error) {
+	source, ok := s.opts.Providers.(catalogSource)
+	if !ok {
+		return CatalogPage{}, ErrCatalogUnsupported
+	}
+	allowed := func(channel string) bool { return channel != ChannelInternal }
+	if auth, ok := s.opts.Authorizer.(CatalogAuthorizer); ok {
+		allowed = func(channel string) bool { return auth.CanBrowseCatalogChannel(ctx, req.Viewer, channel) }
+	}
+	page := buildCatalogPage(source.Catalog(), req, allowed)
+	page.Theme = s.resolveTheme(ctx, req.Viewer)
+	return page, nil
+}
+
+// CatalogPage returns the catalog browser page for req.
+func (c *Controller) CatalogPage(ctx context.Context, req CatalogPageRequest) (CatalogPage, error) {
+	if c == nil || c.service == nil {
+		return CatalogPage{}, fmt.Errorf("dashboard: controller missing service")
+	}
+	browser, ok := c.service.(WidgetCatalogBrowser)
+	if !ok {
+		return CatalogPage{}, ErrCatalogUnsupported
+	}
+	return browser.WidgetCatalogPage(ctx, req)
+}
+
+// RenderCatalog renders page as HTML when the renderer supports the catalog
+// browser.
+func (c *Controller) RenderCatalog(page CatalogPage, out io.Writer) error {
+	if c == nil || c.renderer == nil {
+		return fmt.Errorf("dashboard: renderer not configured")
+	}
+	renderer, ok := c.renderer.(CatalogRenderer)
+	if !ok {
+		return ErrCatalogUnsupported
+	}
+	_, err := renderer.RenderCatalog(page, out)
+	return err
+}
+
+// buildCatalogPage lists the entries of the allowed channels. Links come from
+// third-party manifests, so only absolute http(s) URLs are kept.
+func buildCatalogPage(snapshot CatalogSnapshot, req CatalogPageRequest, allowed func(channel string) bool) CatalogPage {
+	locale := req.Viewer.Locale
+	all := make([]CatalogPageEntry, 0, len(snapshot.Definitions)+len(snapshot.Index))
+	indexed := make(map[string]struct{}, len(snapshot.Index))
+	for _, entry := range snapshot.Index {
+		indexed[entry.Definition.Code] = struct{}{}
+		pkg := entry.Package
+		if entry.Provider.Package != "" {
+			pkg = entry.Provider.Package
+		}
+		all = append(all, CatalogPageEntry{
+			Code:             entry.Definition.Code,
+			Name:             entry.Definition.NameForLocale(locale),
+			Description:      entry.Definition.DescriptionForLocale(locale),
+			Category:         entry.Definition.Category,
+			Channel:          entry.Channel,
+			Tags:             slices.Clone(entry.Tags),
+			Status:           entry.Status,
+			Version:          entry.Version,
+			InstalledVersion: entry.InstalledVersion,
+			Maintainers:      slices.Clone(entry.Maintainers),
+			Manifest:         entry.Manifest,
+			Package:          pkg,
+			Homepage:         safeCatalogURL(entry.Homepage),
+			DocsURL:          safeCatalogURL(entry.Provider.DocsURL),
+		})
+	}
+	// Installed definitions that no index lists are still shown, so the page
+	// is a complete picture of the host.
+	discovery := make(map[string]ProviderDiscovery, len(snapshot.Providers))
+	for _, provider := range snapshot.Providers {
+		discovery[provider.DefinitionCode] = provider
+	}
+	for _, def := range snapshot.Definitions {
+		if _, ok := indexed[def.Code]; ok || def.Code == "" {
+			continue
+		}
+		entry := CatalogPageEntry{
+			Code:        def.Code,
+			Name:        def.NameForLocale(locale),
+			Description: def.DescriptionForLocale(locale),
+			Category:    def.Category,
+			Channel:     ChannelCommunity,
+			Status:      CatalogStatusInstalled,
+		}
+		if provider, ok := discovery[def.Code]; ok {
+			entry.InstalledVersion = provider.Version
+			if provider.Manifest != nil {
+				entry.Channel = normalizeCatalogChannel(provider.Manifest.Channel)
+				entry.Package = provider.Manifest.Package
+				entry.DocsURL = safeCatalogURL(provider.Manifest.DocsURL)
+			}
+		}
+		all = append(all, entry)
+	}
+
+	page := CatalogPage{
+		Title:   "Widget catalog",
+		Locale:  locale,
+		Filter:  normalizeCatalogPageRequest(req),
+		Entries: []CatalogPageEntry{},
+	}
+	for _, entry := range all {
+		if !allowed(entry.Channel) {
+			continue
+		}
+		if !slices.Contains(page.Channels, entry.Channel) {
+			page.Channels = append(page.Channels, entry.Channel)
+		}
+		for _, tag := range entry.Tags {
+			if !slices.Contains(page.Tags, tag) {
+				page.Tags = append(page.Tags, tag)
+			}
+		}
+		if page.Filter.matches(entry) {
+			page.Entries = append(page.Entries, entry)
+		}
+	}
+	slices.Sort(page.Channels)
+	slices.Sort(page.Tags)
+	slices.SortStableFunc(page.Entries, func(a, b CatalogPageEntry) int {
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Code, b.Code)
+	})
+	return page
+}
+
+// safeCatalogURL returns raw when it is an absolute http or https URL and ""
+// otherwise, so a hostile index cannot inject javascript: links.
+func safeCatalogURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return parsed.String()
+	default:
+		return ""
+	}
+}
+
+func normalizeCatalogPageRequest(req CatalogPageRequest) CatalogPageRequest {
+	out := CatalogPageRequest{
+		Viewer: req.Viewer,
+		Status: strings.ToLower(strings.TrimSpace(req.Status)),
+		Tag:    strings.TrimSpace(req.Tag),
+		Query:  strings.TrimSpace(req.Query),
+	}
+	for _, channel := range req.Channels {
+		if strings.TrimSpace(channel) == "" {
+			continue
+		}
+		if channel = normalizeCatalogChannel(channel); !slices.Contains(out.Channels, channel) {
+			out.Channels = append(out.Channels, channel)
+		}
+	}
+	return out
+}
+
+func (req CatalogPageRequest) matches(entry CatalogPageEntry) bool {
+	if len(req.Channels) > 0 && !slices.Contains(req.Channels, entry.Channel) {
+		return false
+	}
+	if req.Status != "" && req.Status != entry.Status {
+		return false
+	}
+	if req.Tag != "" && !slices.Contains(entry.Tags, req.Tag) {
+		return false
+	}
+	if req.Query == "" {
+		return true
+	}
+	query := strings.ToLower(req.Query)
+	fields := append([]string{entry.Code, entry.Name, entry.Description}, entry.Tags...)
+	return slices.ContainsFunc(fields, func(field string) bool {
+		return strings.Contains(strings.ToLower(field), query)
+	})
+}
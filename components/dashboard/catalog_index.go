@@ -0,0 +1,335 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogIndexVersion is the current catalog index format version.
+const CatalogIndexVersion = "1"
+
+// Distribution channels recorded in `provider.channel`. Widgets without a
+// channel are treated as community widgets.
+const (
+	ChannelCommunity = "community"
+	ChannelPartner   = "partner"
+	ChannelInternal  = "internal"
+)
+
+// Catalog entry statuses, computed against the registry when a snapshot is
+// taken.
+const (
+	// CatalogStatusAvailable marks a widget that can be installed.
+	CatalogStatusAvailable = "available"
+	// CatalogStatusInstalled marks a widget whose definition is registered.
+	CatalogStatusInstalled = "installed"
+	// CatalogStatusUpdateAvailable marks an installed widget with a newer
+	// version in the index.
+	CatalogStatusUpdateAvailable = "update_available"
+	// CatalogStatusIncompatible marks a widget whose requirements (dashboard
+	// version or capabilities) this host does not meet.
+	CatalogStatusIncompatible = "incompatible"
+)
+
+// maxCatalogIndexSize bounds remote index downloads.
+const maxCatalogIndexSize = 16 << 20
+
+// CatalogIndex aggregates many widget manifests into a single document that
+// can be published (e.g. as a static file) and browsed by hosts.
+type CatalogIndex struct {
+	Version   string                   `json:"version" yaml:"version"`
+	Name      string                   `json:"name,omitempty" yaml:"name,omitempty"`
+	Manifests []WidgetManifestDocument `json:"manifests" yaml:"manifests"`
+	Source    string                   `json:"-" yaml:"-"`
+}
+
+// CatalogEntry is a widget listed by a merged catalog index.
+type CatalogEntry struct {
+	Definition  WidgetDefinition     `json:"definition"`
+	Version     string               `json:"version,omitempty"`
+	Requires    ManifestRequirements `json:"requires"`
+	Provider    ManifestProvider     `json:"provider"`
+	Maintainers []string             `json:"maintainers,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	// Channel is the normalized provider channel (community by default).
+	Channel string `json:"channel"`
+	// Manifest, Package and Homepage describe the manifest that listed the
+	// widget.
+	Manifest string `json:"manifest,omitempty"`
+	Package  string `json:"package,omitempty"`
+	Homepage string `json:"homepage,omitempty"`
+	// Status is one of the CatalogStatus constants; InstalledVersion is the
+	// registered version when the widget is installed.
+	Status           string `json:"status"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+}
+
+// NewCatalogIndex builds an index from decoded manifests.
+func NewCatalogIndex(name string, docs ...*WidgetManifestDocument) *CatalogIndex {
+	index := &CatalogIndex{
+		Version:   CatalogIndexVersion,
+		Name:      name,
+		Manifests: make([]WidgetManifestDocument, 0, len(docs)),
+	}
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		cloned := *doc
+		cloned.Source = ""
+		index.Manifests = append(index.Manifests, cloned)
+	}
+	return index
+}
+
+// ReadCatalogIndex loads a catalog index file from disk.
+func ReadCatalogIndex(path string) (*CatalogIndex, error) {
+	f, err := os.Open(path) // #nosec G304 -- indexes are explicitly loaded from caller-provided local paths.
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: open catalog index %s: %w", path, err)
+	}
+	defer func() {
+		closeErr := f.Close()
+		if closeErr != nil {
+			return
+		}
+	}()
+	index, err := DecodeCatalogIndex(f)
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: decode catalog index %s: %w", path, err)
+	}
+	index.Source = path
+	return index, nil
+}
+
+// FetchCatalogIndex downloads a catalog index published at url. A nil client
+// uses http.DefaultClient.
+func FetchCatalogIndex(ctx context.Context, client *http.Client, url string) (*CatalogIndex, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: fetch catalog index %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.5")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: fetch catalog index %s: %w", url, err)
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			return
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dashboard: fetch catalog index %s: unexpected status %s", url, resp.Status)
+	}
+	index, err := DecodeCatalogIndex(io.LimitReader(resp.Body, maxCatalogIndexSize))
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: decode catalog index %s: %w", url, err)
+	}
+	index.Source = url
+	return index, nil
+}
+
+// DecodeCatalogIndex reads a YAML or JSON catalog index from any reader.
+func DecodeCatalogIndex(r io.Reader) (*CatalogIndex, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	var index CatalogIndex
+	if err := decoder.Decode(&index); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("dashboard: catalog index is empty")
+		}
+		return nil, fmt.Errorf("dashboard: parse catalog index: %w", err)
+	}
+	if index.Version == "" {
+		index.Version = CatalogIndexVersion
+	}
+	for i := range index.Manifests {
+		index.Manifests[i].applyDefaults()
+	}
+	if err := index.Validate(); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// Validate checks the index version and every listed manifest, and rejects
+// widget codes listed by more than one manifest.
+func (index *CatalogIndex) Validate() error {
+	if index.Version != CatalogIndexVersion {
+		return fmt.Errorf("dashboard: unsupported catalog index version %q", index.Version)
+	}
+	owners := map[string]string{}
+	for i := range index.Manifests {
+		doc := &index.Manifests[i]
+		label := doc.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i)
+		}
+		if err := doc.Validate(); err != nil {
+			return fmt.Errorf("dashboard: catalog index manifest %s: %w", label, err)
+		}
+		for _, widget := range doc.Widgets {
+			code := widget.Definition.Code
+			if owner, exists := owners[code]; exists {
+				return fmt.Errorf("dashboard: catalog index lists widget %s in manifests %s and %s", code, owner, label)
+			}
+			owners[code] = label
+		}
+	}
+	return nil
+}
+
+// MergeCatalogIndex adds the widgets of index to the registry catalog,
+// keeping only the given channels (all channels when none are given).
+// Entries replace earlier ones with the same code, so hosts can layer a
+// private index over a public one. Merged widgets are listed by Catalog but
+// are not registered; install them by loading their manifests.
+func (r *Registry) MergeCatalogIndex(index *CatalogIndex, channels ...string) error {
+	if r == nil {
+		return fmt.Errorf("dashboard: registry is nil")
+	}
+	if index == nil {
+		return fmt.Errorf("dashboard: catalog index is nil")
+	}
+	if err := index.Validate(); err != nil {
+		return err
+	}
+	allowed := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		allowed = append(allowed, normalizeCatalogChannel(channel))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.catalog == nil {
+		r.catalog = map[string]CatalogEntry{}
+	}
+	for _, doc := range index.Manifests {
+		for _, widget := range doc.Widgets {
+			channel := normalizeCatalogChannel(widget.Provider.Channel)
+			if len(allowed) > 0 && !slices.Contains(allowed, channel) {
+				continue
+			}
+			code := widget.Definition.Code
+			if _, exists := r.catalog[code]; !exists {
+				r.catalogOrder = append(r.catalogOrder, code)
+			}
+			r.catalog[code] = CatalogEntry{
+				Definition:  cloneWidgetDefinition(widget.Definition),
+				Version:     widget.Version,
+				Requires:    cloneManifestRequirements(widget.Requires),
+				Provider:    cloneManifestProvider(widget.Provider),
+				Maintainers: slices.Clone(widget.Maintainers),
+				Tags:        slices.Clone(widget.Tags),
+				Channel:     channel,
+				Manifest:    doc.Name,
+				Package:     doc.Package,
+				Homepage:    doc.Homepage,
+			}
+		}
+	}
+	return nil
+}
+
+// catalogEntries returns the merged index entries in merge order with their
+// status resolved against the registry.
+func (r *Registry) catalogEntries() []CatalogEntry {
+	r.mu.RLock()
+	entries := make([]CatalogEntry, 0, len(r.catalogOrder))
+	for _, code := range r.catalogOrder {
+		entries = append(entries, cloneCatalogEntry(r.catalog[code]))
+	}
+	r.mu.RUnlock()
+	if len(entries) == 0 {
+		return nil
+	}
+	for i := range entries {
+		r.resolveCatalogStatus(&entries[i])
+	}
+	return entries
+}
+
+func (r *Registry) resolveCatalogStatus(entry *CatalogEntry) {
+	code := entry.Definition.Code
+	if _, installed := r.Definition(code); installed {
+		entry.Status = CatalogStatusInstalled
+		r.mu.RLock()
+		entry.InstalledVersion = r.versions[code]
+		r.mu.RUnlock()
+		if newerCatalogVersion(entry.Version, entry.InstalledVersion) && r.meetsCatalogRequirements(entry.Requires) {
+			entry.Status = CatalogStatusUpdateAvailable
+		}
+		return
+	}
+	entry.Status = CatalogStatusAvailable
+	if !r.meetsCatalogRequirements(entry.Requires) {
+		entry.Status = CatalogStatusIncompatible
+	}
+}
+
+func (r *Registry) meetsCatalogRequirements(requires ManifestRequirements) bool {
+	if requires.Dashboard != "" {
+		required, err := parseSemver(requires.Dashboard)
+		current, currentErr := parseSemver(Version)
+		if err != nil || currentErr != nil || current.compare(required) < 0 {
+			return false
+		}
+	}
+	for _, capability := range requires.Capabilities {
+		if !r.hasCapability(capability) {
+			return false
+		}
+	}
+	return true
+}
+
+// newerCatalogVersion reports whether the index version supersedes the
+// installed one. Unversioned installs are never flagged for update.
+func newerCatalogVersion(indexed, installed string) bool {
+	if indexed == "" || installed == "" {
+		return false
+	}
+	next, err := parseSemver(indexed)
+	if err != nil {
+		return false
+	}
+	current, err := parseSemver(installed)
+	if err != nil {
+		return false
+	}
+	return next.compare(current) > 0
+}
+
+func normalizeCatalogChannel(channel string) string {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if channel == "" {
+		return ChannelCommunity
+	}
+	return channel
+}
+
+func cloneManifestRequirements(in ManifestRequirements) ManifestRequirements {
+	in.Capabilities = slices.Clone(in.Capabilities)
+	return in
+}
+
+func cloneCatalogEntry(in CatalogEntry) CatalogEntry {
+	in.Definition = cloneWidgetDefinition(in.Definition)
+	in.Requires = cloneManifestRequirements(in.Requires)
+	in.Provider = cloneManifestProvider(in.Provider)
+	in.Maintainers = slices.Clone(in.Maintainers)
+	in.Tags = slices.Clone(in.Tags)
+	return in
+}
@@ -0,0 +1,293 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testCatalogIndex = `
+version: "1"
+name: acme-marketplace
+manifests:
+  - version: "2"
+    name: acme-community
+    homepage: https://example.com/acme
+    widgets:
+      - definition:
+          code: acme.widget.weather
+          name: Weather
+          name_localized:
+            es: Clima
+          description: Current conditions
+        version: 1.2.0
+        tags: [weather, external]
+        provider:
+          channel: community
+          docs_url: https://example.com/docs/weather
+      - definition:
+          code: acme.widget.future
+          name: Future
+        version: 1.0.0
+        requires:
+          dashboard: 99.0.0
+  - version: "2"
+    name: acme-partner
+    widgets:
+      - definition:
+          code: admin.widget.user_stats
+          name: User Stats
+        version: 2.0.0
+        provider:
+          channel: partner
+      - definition:
+          code: acme.widget.secret
+          name: Secret
+        provider:
+          channel: internal
+`
+
+func TestRegistryMergeCatalogIndexResolvesStatusAndChannels(t *testing.T) {
+	index, err := DecodeCatalogIndex(strings.NewReader(testCatalogIndex))
+	if err != nil {
+		t.Fatalf("DecodeCatalogIndex returned error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.recordWidgetVersion("admin.widget.user_stats", "1.0.0")
+	if err := reg.MergeCatalogIndex(index, ChannelCommunity, "Partner"); err != nil {
+		t.Fatalf("MergeCatalogIndex returned error: %v", err)
+	}
+
+	entries := reg.Catalog().Index
+	statuses := map[string]string{}
+	for _, entry := range entries {
+		statuses[entry.Definition.Code] = entry.Status
+	}
+	want := map[string]string{
+		"acme.widget.weather":     CatalogStatusAvailable,
+		"acme.widget.future":      CatalogStatusIncompatible,
+		"admin.widget.user_stats": CatalogStatusUpdateAvailable,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("expected internal channel to be filtered out, got %+v", statuses)
+	}
+	for code, status := range want {
+		if statuses[code] != status {
+			t.Fatalf("expected %s to be %s, got %+v", code, status, statuses)
+		}
+	}
+	if entries[0].Manifest != "acme-community" || entries[0].Homepage != "https://example.com/acme" || entries[0].Channel != ChannelCommunity {
+		t.Fatalf("expected manifest metadata on entries, got %+v", entries[0])
+	}
+
+	clone := reg.Clone()
+	if len(clone.Catalog().Index) != len(entries) {
+		t.Fatalf("expected Clone to keep merged catalog entries")
+	}
+	if err := reg.LoadManifestDocument(&WidgetManifestDocument{Version: "2", Widgets: []ManifestWidget{{
+		Definition: WidgetDefinition{Code: "acme.widget.weather", Name: "Weather"},
+		Version:    "1.2.0",
+	}}}); err != nil {
+		t.Fatalf("LoadManifestDocument returned error: %v", err)
+	}
+	for _, entry := range reg.Catalog().Index {
+		if entry.Definition.Code == "acme.widget.weather" && (entry.Status != CatalogStatusInstalled || entry.InstalledVersion != "1.2.0") {
+			t.Fatalf("expected weather to be installed after loading its manifest, got %+v", entry)
+		}
+	}
+	for _, entry := range clone.Catalog().Index {
+		if entry.Definition.Code == "acme.widget.weather" && entry.Status != CatalogStatusAvailable {
+			t.Fatalf("expected clone to be unaffected by later installs, got %+v", entry)
+		}
+	}
+}
+
+func TestCatalogIndexValidation(t *testing.T) {
+	if _, err := DecodeCatalogIndex(strings.NewReader("version: \"2\"\nmanifests: []\n")); err == nil || !strings.Contains(err.Error(), "unsupported catalog index version") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+	duplicate := `
+manifests:
+  - name: one
+    widgets:
+      - definition: {code: acme.widget.a, name: A}
+  - name: two
+    widgets:
+      - definition: {code: acme.widget.a, name: A}
+`
+	if _, err := DecodeCatalogIndex(strings.NewReader(duplicate)); err == nil || !strings.Contains(err.Error(), "in manifests one and two") {
+		t.Fatalf("expected duplicate widget error, got %v", err)
+	}
+	if err := NewRegistry().MergeCatalogIndex(nil); err == nil {
+		t.Fatalf("expected nil index error")
+	}
+}
+
+func TestFetchCatalogIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testCatalogIndex))
+	}))
+	defer server.Close()
+
+	index, err := FetchCatalogIndex(context.Background(), server.Client(), server.URL+"/index.yaml")
+	if err != nil {
+		t.Fatalf("FetchCatalogIndex returned error: %v", err)
+	}
+	if index.Name != "acme-marketplace" || len(index.Manifests) != 2 || index.Source != server.URL+"/index.yaml" {
+		t.Fatalf("unexpected index: %+v", index)
+	}
+	if _, err := FetchCatalogIndex(context.Background(), server.Client(), server.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "unexpected status") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
+
+func TestControllerRendersCatalogBrowser(t *testing.T) {
+	index, err := DecodeCatalogIndex(strings.NewReader(testCatalogIndex))
+	if err != nil {
+		t.Fatalf("DecodeCatalogIndex returned error: %v", err)
+	}
+	reg := NewRegistry()
+	if err := reg.MergeCatalogIndex(index); err != nil {
+		t.Fatalf("MergeCatalogIndex returned error: %v", err)
+	}
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	controller := NewController(ControllerOptions{Service: NewService(Options{Providers: reg}), Renderer: renderer})
+
+	page, err := controller.CatalogPage(context.Background(), CatalogPageRequest{
+		Viewer:   ViewerContext{Locale: "es"},
+		Channels: []string{"community"},
+		Query:    "clim",
+	})
+	if err != nil {
+		t.Fatalf("CatalogPage returned error: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Name != "Clima" || page.Entries[0].DocsURL != "https://example.com/docs/weather" {
+		t.Fatalf("expected localized weather entry, got %+v", page.Entries)
+	}
+	if strings.Join(page.Channels, ",") != "community,partner" {
+		t.Fatalf("expected public channels in filter options, got %v", page.Channels)
+	}
+
+	page, err = controller.CatalogPage(context.Background(), CatalogPageRequest{Status: CatalogStatusInstalled})
+	if err != nil {
+		t.Fatalf("CatalogPage returned error: %v", err)
+	}
+	installed := map[string]bool{}
+	for _, entry := range page.Entries {
+		installed[entry.Code] = true
+	}
+	if !installed["admin.widget.user_stats"] || !installed["admin.widget.quick_actions"] || installed["acme.widget.weather"] {
+		t.Fatalf("expected built-in definitions listed as installed, got %+v", page.Entries)
+	}
+
+	page, err = controller.CatalogPage(context.Background(), CatalogPageRequest{Tag: "weather", Channels: []string{ChannelCommunity}})
+	if err != nil {
+		t.Fatalf("CatalogPage returned error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := controller.RenderCatalog(page, &buf); err != nil {
+		t.Fatalf("RenderCatalog returned error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`data-widget-code="acme.widget.weather"`,
+		`data-catalog-status="available"`,
+		`<option value="weather" selected>`,
+		`<option value="community" selected>`,
+		`href="https://example.com/docs/weather"`,
+		"Current conditions",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in rendered catalog:\n%s", want, html)
+		}
+	}
+
+	bare := NewController(ControllerOptions{Service: &stubLayoutResolver{}, Renderer: renderer})
+	if _, err := bare.CatalogPage(context.Background(), CatalogPageRequest{}); !errors.Is(err, ErrCatalogUnsupported) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
+
+func TestCatalogPageDropsUnsafeLinks(t *testing.T) {
+	index, err := DecodeCatalogIndex(strings.NewReader(`
+manifests:
+  - name: hostile
+    homepage: javascript:alert(document.cookie)
+    widgets:
+      - definition: {code: acme.widget.trap, name: Trap}
+        provider:
+          docs_url: JavaScript:alert(1)
+      - definition: {code: acme.widget.relative, name: Relative}
+        provider:
+          docs_url: //example.com/docs
+`))
+	if err != nil {
+		t.Fatalf("DecodeCatalogIndex returned error: %v", err)
+	}
+	reg := NewRegistry()
+	if err := reg.MergeCatalogIndex(index); err != nil {
+		t.Fatalf("MergeCatalogIndex returned error: %v", err)
+	}
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	controller := NewController(ControllerOptions{Service: NewService(Options{Providers: reg}), Renderer: renderer})
+	page, err := controller.CatalogPage(context.Background(), CatalogPageRequest{Query: "acme."})
+	if err != nil {
+		t.Fatalf("CatalogPage returned error: %v", err)
+	}
+	if len(page.Entries) != 2 {
+		t.Fatalf("expected both index entries, got %+v", page.Entries)
+	}
+	for _, entry := range page.Entries {
+		if entry.DocsURL != "" || entry.Homepage != "" {
+			t.Fatalf("expected unsafe links dropped, got %+v", entry)
+		}
+	}
+	var buf bytes.Buffer
+	if err := controller.RenderCatalog(page, &buf); err != nil {
+		t.Fatalf("RenderCatalog returned error: %v", err)
+	}
+	if html := strings.ToLower(buf.String()); strings.Contains(html, "javascript:") || strings.Contains(html, "dashboard-catalog__links") {
+		t.Fatalf("expected no links in rendered catalog:\n%s", buf.String())
+	}
+}
+
+func TestCatalogPageChannelsFollowAuthorizer(t *testing.T) {
+	index, err := DecodeCatalogIndex(strings.NewReader(testCatalogIndex))
+	if err != nil {
+		t.Fatalf("DecodeCatalogIndex returned error: %v", err)
+	}
+	reg := NewRegistry()
+	if err := reg.MergeCatalogIndex(index); err != nil {
+		t.Fatalf("MergeCatalogIndex returned error: %v", err)
+	}
+	service := NewService(Options{Providers: reg, Authorizer: editorAuthorizer{editors: map[string]bool{"staff": true}}})
+
+	page, err := service.WidgetCatalogPage(context.Background(), CatalogPageRequest{Viewer: ViewerContext{UserID: "staff"}, Channels: []string{ChannelInternal}})
+	if err != nil {
+		t.Fatalf("WidgetCatalogPage returned error: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Code != "acme.widget.secret" {
+		t.Fatalf("expected authorized viewer to browse internal widgets, got %+v", page.Entries)
+	}
+	page, err = service.WidgetCatalogPage(context.Background(), CatalogPageRequest{Viewer: ViewerContext{UserID: "guest"}})
+	if err != nil {
+		t.Fatalf("WidgetCatalogPage returned error: %v", err)
+	}
+	if len(page.Entries) != 0 || len(page.Channels) != 0 {
+		t.Fatalf("expected refused viewer to see no channels, got %+v", page)
+	}
+}
@@ -0,0 +1,220 @@
+package dashboard
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GeneratedChartThemePrefix prefixes the names of chart themes synthesized
+// from design tokens. The suffix is a hash of the theme, so identical token
+// sets always map to the same name. Generated themes are registered by an
+// inline script in the chart markup rather than served as theme files, so
+// they work on every replica without shared state.
+const GeneratedChartThemePrefix = "dashboard-"
+
+// defaultChartPalette mirrors the ECharts default palette; it follows the
+// accent color when tokens do not declare a full palette.
+var defaultChartPalette = []string{
+	"#5470c6", "#91cc75", "#fac858", "#ee6666", "#73c0de",
+	"#3ba272", "#fc8452", "#9a60b4", "#ea7ccc",
+}
+
+// ChartThemeFromTokens synthesizes an ECharts theme (palette, background,
+// text, and axis colors) from design tokens. It reads `chart-*` tokens first
+// (`chart-color-1..n` or `chart-palette`, `chart-background`, `chart-text`,
+// `chart-muted`, `chart-axis`, `chart-grid`) and falls back to the dashboard
+// tokens (`dashboard-accent`, `dashboard-surface`, `dashboard-foreground`,
+// `dashboard-muted`, `dashboard-border`). ok is false when the tokens carry no
+// chart-relevant values.
+func ChartThemeFromTokens(tokens map[string]string) (name string, theme map[string]any, ok bool) {
+	values := chartTokenValues(tokens)
+	if len(values) == 0 {
+		return "", nil, false
+	}
+	text := firstToken(values, "chart-text", "dashboard-foreground")
+	muted := firstToken(values, "chart-muted", "dashboard-muted")
+	if muted == "" {
+		muted = text
+	}
+	axis := firstToken(values, "chart-axis", "dashboard-border")
+	if axis == "" {
+		axis = muted
+	}
+	grid := firstToken(values, "chart-grid", "dashboard-border")
+	if grid == "" {
+		grid = axis
+	}
+
+	theme = map[string]any{"color": chartPalette(values)}
+	if background := firstToken(values, "chart-background", "dashboard-surface"); background != "" {
+		theme["backgroundColor"] = background
+	}
+	if text != "" {
+		theme["textStyle"] = map[string]any{"color": text}
+		theme["title"] = map[string]any{
+			"textStyle":    map[string]any{"color": text},
+			"subtextStyle": map[string]any{"color": muted},
+		}
+		theme["legend"] = map[string]any{"textStyle": map[string]any{"color": text}}
+	}
+	if axis != "" || muted != "" {
+		axisStyle := map[string]any{
+			"axisLine":  map[string]any{"lineStyle": map[string]any{"color": axis}},
+			"axisTick":  map[string]any{"lineStyle": map[string]any{"color": axis}},
+			"axisLabel": map[string]any{"color": muted},
+			"splitLine": map[string]any{"lineStyle": map[string]any{"color": []string{grid}}},
+		}
+		for _, key := range []string{"categoryAxis", "valueAxis", "logAxis", "timeAxis"} {
+			theme[key] = axisStyle
+		}
+	}
+
+	raw, err := json.Marshal(theme)
+	if err != nil {
+		return "", nil, false
+	}
+	sum := sha256.Sum256(raw)
+	return GeneratedChartThemePrefix + hex.EncodeToString(sum[:6]), theme, true
+}
+
+// generatedChartThemeScripts returns the registration scripts of the chart
+// themes synthesized from the tokens of selection and its variants, keyed by
+// theme name and limited to the names in used.
+func generatedChartThemeScripts(selection *ThemeSelection, used []string) map[string]string {
+	if selection == nil || len(used) == 0 {
+		return nil
+	}
+	tokenSets := []map[string]string{selection.Tokens}
+	for _, variant := range selection.Variants {
+		tokenSets = append(tokenSets, variant.Tokens)
+	}
+	var scripts map[string]string
+	for _, tokens := range tokenSets {
+		name, theme, ok := ChartThemeFromTokens(tokens)
+		if !ok || !slices.Contains(used, name) {
+			continue
+		}
+		if _, exists := scripts[name]; exists {
+			continue
+		}
+		script, err := chartThemeScript(name, theme)
+		if err != nil {
+			continue
+		}
+		if scripts == nil {
+			scripts = map[string]string{}
+		}
+		scripts[name] = string(script)
+	}
+	return scripts
+}
+
+func chartThemeScript(name string, theme map[string]any) ([]byte, error) {
+	rawName, err := json.Marshal(name)
+	if err != nil {
+		return nil, err
+	}
+	rawTheme, err := json.Marshal(theme)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "(function(echarts){if(!echarts){return;}echarts.registerTheme(%s,%s);})(typeof window!==\"undefined\"?window.echarts:undefined);\n", rawName, rawTheme)
+	return buf.Bytes(), nil
+}
+
+// chartTokenValues returns sanitized tokens keyed without the `--` prefix,
+// keeping only the keys the chart theme reads.
+func chartTokenValues(tokens map[string]string) map[string]string {
+	values := map[string]string{}
+	for key, value := range tokens {
+		name := strings.TrimPrefix(normalizeCSSVariable(key), "--")
+		value = sanitizeCSSVariableValue(value)
+		if name == "" || value == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(name, "chart-"):
+		case name == "dashboard-accent", name == "dashboard-surface", name == "dashboard-foreground",
+			name == "dashboard-muted", name == "dashboard-border":
+		default:
+			continue
+		}
+		values[name] = value
+	}
+	return values
+}
+
+func firstToken(values map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := values[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func chartPalette(values map[string]string) []string {
+	type indexed struct {
+		idx   int
+		color string
+	}
+	var numbered []indexed
+	for key, value := range values {
+		if suffix, ok := strings.CutPrefix(key, "chart-color-"); ok {
+			if idx, err := strconv.Atoi(suffix); err == nil {
+				numbered = append(numbered, indexed{idx: idx, color: value})
+			}
+		}
+	}
+	if len(numbered) > 0 {
+		slices.SortFunc(numbered, func(a, b indexed) int { return a.idx - b.idx })
+		palette := make([]string, 0, len(numbered))
+		for _, item := range numbered {
+			palette = append(palette, item.color)
+		}
+		return palette
+	}
+	if palette := splitPalette(values["chart-palette"]); len(palette) > 0 {
+		return palette
+	}
+	if accent := values["dashboard-accent"]; accent != "" {
+		return append([]string{accent}, defaultChartPalette...)
+	}
+	return slices.Clone(defaultChartPalette)
+}
+
+// splitPalette splits a comma separated color list, ignoring commas inside
+// functions such as rgb(...).
+func splitPalette(value string) []string {
+	var (
+		out   []string
+		depth int
+		start int
+	)
+	for i, r := range value {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				if color := strings.TrimSpace(value[start:i]); color != "" {
+					out = append(out, color)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if color := strings.TrimSpace(value[start:]); color != "" {
+		out = append(out, color)
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package dashboard
+
+import (
+	"context"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChartThemeFromTokensDerivesPaletteAndAxes(t *testing.T) {
+	tokens := map[string]string{
+		"--chart-color-2":      "#22c55e",
+		"chart-color-1":        "#0ea5e9",
+		"chart-color-10":       "#f97316",
+		"dashboard-surface":    "#0f172a",
+		"dashboard-foreground": "#e2e8f0",
+		"dashboard-border":     "#334155",
+		"dashboard-radius":     "6px",
+	}
+	name, theme, ok := ChartThemeFromTokens(tokens)
+	if !ok || !strings.HasPrefix(name, GeneratedChartThemePrefix) {
+		t.Fatalf("expected generated theme, got %q ok=%v", name, ok)
+	}
+	if palette, _ := theme["color"].([]string); !slices.Equal(palette, []string{"#0ea5e9", "#22c55e", "#f97316"}) {
+		t.Fatalf("expected numbered palette in order, got %v", theme["color"])
+	}
+	if theme["backgroundColor"] != "#0f172a" {
+		t.Fatalf("expected surface background, got %v", theme["backgroundColor"])
+	}
+	axis, _ := theme["valueAxis"].(map[string]any)
+	label, _ := axis["axisLabel"].(map[string]any)
+	line, _ := axis["axisLine"].(map[string]any)["lineStyle"].(map[string]any)
+	if label["color"] != "#e2e8f0" || line["color"] != "#334155" {
+		t.Fatalf("unexpected axis styles %+v", axis)
+	}
+
+	again, _, _ := ChartThemeFromTokens(map[string]string{
+		"chart-color-1": "#0ea5e9", "chart-color-2": "#22c55e", "chart-color-10": "#f97316",
+		"dashboard-surface": "#0f172a", "dashboard-foreground": "#e2e8f0", "dashboard-border": "#334155",
+	})
+	if again != name {
+		t.Fatalf("expected deterministic name, got %q and %q", name, again)
+	}
+	if _, _, ok := ChartThemeFromTokens(map[string]string{"dashboard-gap": "1rem"}); ok {
+		t.Fatalf("expected no theme without chart-relevant tokens")
+	}
+}
+
+func TestChartThemePaletteFallbacks(t *testing.T) {
+	palette := chartPalette(chartTokenValues(map[string]string{"chart-palette": "rgb(1, 2, 3), #fff ,hsl(0 0% 0%)"}))
+	if !slices.Equal(palette, []string{"rgb(1, 2, 3)", "#fff", "hsl(0 0% 0%)"}) {
+		t.Fatalf("unexpected palette %v", palette)
+	}
+	palette = chartPalette(chartTokenValues(map[string]string{"dashboard-accent": "#22d3ee"}))
+	if palette[0] != "#22d3ee" || len(palette) != len(defaultChartPalette)+1 {
+		t.Fatalf("expected accent to lead the default palette, got %v", palette)
+	}
+}
+
+func TestEChartsProviderUsesGeneratedTokenTheme(t *testing.T) {
+	t.Parallel()
+	provider := NewEChartsProvider("bar")
+	ctx := sampleChartContext("admin.widget.bar_chart", map[string]any{
+		"title":  "Brand Theme",
+		"x_axis": []string{"A"},
+		"series": []map[string]any{{"name": "Series", "data": []float64{1}}},
+	})
+	ctx.Theme = &ThemeSelection{Variant: "dark", Tokens: map[string]string{"dashboard-accent": "#a855f7"}}
+
+	data, err := provider.Fetch(context.Background(), ctx)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	name, _ := data["theme"].(string)
+	if !strings.HasPrefix(name, GeneratedChartThemePrefix) || ctx.Theme.ChartTheme != name {
+		t.Fatalf("expected generated chart theme, got %q (selection %q)", name, ctx.Theme.ChartTheme)
+	}
+
+	if slices.ContainsFunc(jsAssets(data), func(url string) bool { return strings.HasSuffix(url, "themes/"+name+".js") }) {
+		t.Fatalf("expected generated theme to be inlined rather than loaded from a file, got %v", jsAssets(data))
+	}
+	markup := html(data)
+	register := strings.Index(markup, `echarts.registertheme("`+name+`"`)
+	if register < 0 || !strings.Contains(markup, "#a855f7") || register > strings.Index(markup, "echarts.init") {
+		t.Fatalf("expected inline theme registration ahead of the chart, got %s", markup)
+	}
+	if _, err := fs.Stat(EChartsAssets(), "themes/"+name+".js"); err == nil {
+		t.Fatalf("expected generated themes not to be served as files")
+	}
+}
+
+func TestChartThemeFromSelectionPrefersTokenTheme(t *testing.T) {
+	tokens := map[string]string{"dashboard-accent": "#a855f7"}
+	if got := chartThemeFromSelection(&ThemeSelection{Variant: "dark", Tokens: tokens}); !strings.HasPrefix(got, GeneratedChartThemePrefix) {
+		t.Fatalf("expected generated theme for tokens with chart colors, got %q", got)
+	}
+	if got := chartThemeFromSelection(&ThemeSelection{Variant: "dark", Tokens: map[string]string{"dashboard-gap": "1rem"}}); got != "wonderland" {
+		t.Fatalf("expected bundled dark theme without chart tokens, got %q", got)
+	}
+	if got := chartThemeFromSelection(&ThemeSelection{Variant: "dark", ChartTheme: "chalk", Tokens: tokens}); got != "chalk" {
+		t.Fatalf("expected explicit chart theme to win, got %q", got)
+	}
+}
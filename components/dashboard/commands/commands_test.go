@@ -140,6 +140,29 @@ func TestSaveLayoutPreferencesCommand(t *testing.T) {
 	}
 }
 
+func TestMigrateWidgetsCommand(t *testing.T) {
+	service := &stubService{migrateReport: dashboard.SchemaMigrationReport{Results: []dashboard.SchemaMigrationResult{
+		{InstanceID: "w1", Status: dashboard.SchemaMigrationMigrated},
+		{InstanceID: "w2", Status: dashboard.SchemaMigrationFailed, Error: "boom"},
+	}}}
+	cmd := NewMigrateWidgetsCommand(service, nil)
+	var report dashboard.SchemaMigrationReport
+	input := MigrateWidgetsInput{
+		MigrateWidgetsRequest: dashboard.MigrateWidgetsRequest{DefinitionIDs: []string{"acme.widget"}, DryRun: true},
+		Result:                &report,
+	}
+	err := cmd.Execute(context.Background(), input)
+	if err == nil {
+		t.Fatalf("expected error when a widget fails to migrate")
+	}
+	if !service.lastMigrate.DryRun || len(service.lastMigrate.DefinitionIDs) != 1 {
+		t.Fatalf("expected request forwarded, got %+v", service.lastMigrate)
+	}
+	if len(report.Results) != 2 || report.Results[1].Error != "boom" {
+		t.Fatalf("expected report to be returned, got %+v", report)
+	}
+}
+
 type stubService struct {
 	addCalls      int
 	removeCalls   int
@@ -151,6 +174,13 @@ type stubService struct {
 	lastCtx       context.Context
 	lastAddReq    dashboard.AddWidgetRequest
 	lastUpdateReq dashboard.UpdateWidgetRequest
+	migrateReport dashboard.SchemaMigrationReport
+	lastMigrate   dashboard.MigrateWidgetsRequest
+}
+
+func (s *stubService) MigrateWidgets(_ context.Context, req dashboard.MigrateWidgetsRequest) (dashboard.SchemaMigrationReport, error) {
+	s.lastMigrate = req
+	return s.migrateReport, nil
 }
 
 func (s *stubService) AddWidget(_ context.Context, req dashboard.AddWidgetRequest) error {
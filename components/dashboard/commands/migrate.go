@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gocommand "github.com/goliatone/go-command"
+	dashboard "github.com/goliatone/go-dashboard/components/dashboard"
+)
+
+// MigrateWidgetsInput selects the instances to migrate. When Result is set it
+// receives the per-instance report, including on partial failure.
+type MigrateWidgetsInput struct {
+	dashboard.MigrateWidgetsRequest
+	Result *dashboard.SchemaMigrationReport `json:"-"`
+}
+
+type migrateService interface {
+	MigrateWidgets(ctx context.Context, req dashboard.MigrateWidgetsRequest) (dashboard.SchemaMigrationReport, error)
+}
+
+// MigrateWidgetsCommand wraps Service.MigrateWidgets for bulk schema upgrades.
+type MigrateWidgetsCommand struct {
+	service   migrateService
+	telemetry Telemetry
+}
+
+// NewMigrateWidgetsCommand creates the command.
+func NewMigrateWidgetsCommand(service migrateService, telemetry Telemetry) *MigrateWidgetsCommand {
+	return &MigrateWidgetsCommand{service: service, telemetry: normalizeTelemetry(telemetry)}
+}
+
+var _ gocommand.Commander[MigrateWidgetsInput] = (*MigrateWidgetsCommand)(nil)
+
+// Execute migrates stored widget configurations and fails when any instance
+// could not be migrated.
+func (c *MigrateWidgetsCommand) Execute(ctx context.Context, msg MigrateWidgetsInput) error {
+	if c.service == nil {
+		return errors.New("migrate command requires service")
+	}
+	report, err := c.service.MigrateWidgets(ctx, msg.MigrateWidgetsRequest)
+	if msg.Result != nil {
+		*msg.Result = report
+	}
+	if err != nil {
+		return err
+	}
+	failed := report.Count(dashboard.SchemaMigrationFailed)
+	c.telemetry.Record(ctx, "dashboard.widget.migrate", map[string]any{
+		"dry_run":  msg.DryRun,
+		"migrated": report.Count(dashboard.SchemaMigrationMigrated),
+		"failed":   failed,
+	})
+	if failed > 0 {
+		return fmt.Errorf("migrate command: %d of %d widgets failed to migrate", failed, len(report.Results))
+	}
+	return nil
+}
@@ -45,12 +45,12 @@ func (c *SaveLayoutPreferencesCommand) Execute(ctx context.Context, msg SaveLayo
 		UserID:  msg.Viewer.UserID,
 	})
 	overrides := dashboard.LayoutOverrides{
-		AreaOrder:     msg.AreaOrder,
-		AreaRows:      convertLayoutRows(msg.LayoutRows),
-		HiddenWidgets: make(map[string]bool, len(msg.HiddenWidgets)),
-	}
-	for _, id := range msg.HiddenWidgets {
-		overrides.HiddenWidgets[id] = true
+		AreaOrder:       msg.AreaOrder,
+		AreaRows:        convertLayoutRows(msg.LayoutRows),
+		HiddenWidgets:   dashboard.HiddenWidgetSet(msg.HiddenWidgets),
+		Filters:         msg.Filters,
+		BreakpointOrder: msg.BreakpointOrder,
+		AreaGrids:       msg.LayoutGrid,
 	}
 	if err := c.service.SavePreferences(ctx, msg.Viewer, overrides); err != nil {
 		return err
@@ -64,7 +64,7 @@ func (c *SaveLayoutPreferencesCommand) Execute(ctx context.Context, msg SaveLayo
 }
 
 func convertLayoutRows(input map[string][]LayoutRowInput) map[string][]dashboard.LayoutRow {
-	if len(input) == 0 {
+	if input == nil {
 		return nil
 	}
 	output := make(map[string][]dashboard.LayoutRow, len(input))
@@ -80,8 +80,9 @@ func convertLayoutRows(input map[string][]LayoutRowInput) map[string][]dashboard
 					continue
 				}
 				slots = append(slots, dashboard.WidgetSlot{
-					ID:    widget.ID,
-					Width: widget.Width,
+					ID:     widget.ID,
+					Width:  widget.Width,
+					Widths: widget.Widths,
 				})
 			}
 			if len(slots) == 0 {
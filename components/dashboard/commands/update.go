@@ -9,14 +9,7 @@ import (
 )
 
 // UpdateWidgetInput captures widget update payloads.
-type UpdateWidgetInput struct {
-	WidgetID      string         `json:"widget_id"`
-	Configuration map[string]any `json:"configuration"`
-	Metadata      map[string]any `json:"metadata"`
-	ActorID       string         `json:"actor_id"`
-	UserID        string         `json:"user_id"`
-	TenantID      string         `json:"tenant_id"`
-}
+type UpdateWidgetInput = dashboard.UpdateWidgetInput
 
 type updateService interface {
 	UpdateWidget(ctx context.Context, widgetID string, req dashboard.UpdateWidgetRequest) error
@@ -0,0 +1,844 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Config form field widgets. Templates and SPA hosts switch on
+// ConfigField.Widget to pick an input.
+const (
+	ConfigFieldText     = "text"
+	ConfigFieldNumber   = "number"
+	ConfigFieldCheckbox = "checkbox"
+	ConfigFieldSelect   = "select"
+	// ConfigFieldLines edits arrays of scalars, one value per line.
+	ConfigFieldLines = "lines"
+	// ConfigFieldGroup is a nested object; its inputs live in Fields.
+	ConfigFieldGroup = "group"
+	// ConfigFieldList is an array of objects; Items holds one group per
+	// entry and Template the group for the next entry.
+	ConfigFieldList = "list"
+	// ConfigFieldJSON is the fallback for schemas the form cannot express
+	// (oneOf, free-form objects); Text carries the JSON value.
+	ConfigFieldJSON = "json"
+)
+
+// Config form partials; config_field.html includes itself for groups and
+// list items.
+const (
+	configFormTemplate  = "components/dashboard/config_form.html"
+	configFieldTemplate = "components/dashboard/config_field.html"
+)
+
+// configFormRootField names the single JSON field used when a definition has
+// no schema properties.
+const configFormRootField = "configuration"
+
+// Localizable text inputs render existing translations as `<path>.<locale>`
+// plus one empty locale/text pair for adding a translation.
+const (
+	configFormNewLocaleSuffix = ".__locale"
+	configFormNewTextSuffix   = ".__text"
+)
+
+var (
+	// ErrWidgetConfigUnsupported is returned when the controller service
+	// cannot build configuration forms.
+	ErrWidgetConfigUnsupported = errors.New("dashboard: widget configuration editor not supported")
+	// ErrWidgetConfigForbidden is returned when the viewer cannot configure
+	// the widget or add widgets of the definition.
+	ErrWidgetConfigForbidden = errors.New("dashboard: widget configuration forbidden")
+
+	missingPropertyPattern = regexp.MustCompile(`'([^']+)'`)
+	configFieldIDPattern   = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+)
+
+// ConfigForm is a form descriptor derived from WidgetDefinition.Schema. The
+// embedded templates render it as an HTML partial; SPA hosts can consume the
+// JSON directly and submit `{"configuration": {...}}`.
+type ConfigForm struct {
+	WidgetID   string             `json:"widget_id,omitempty"`
+	Definition string             `json:"definition"`
+	Title      string             `json:"title,omitempty"`
+	Locale     string             `json:"locale,omitempty"`
+	Action     string             `json:"action,omitempty"`
+	Method     string             `json:"method,omitempty"`
+	Fields     []ConfigField      `json:"fields"`
+	Values     map[string]any     `json:"values,omitempty"`
+	Schema     map[string]any     `json:"schema,omitempty"`
+	Errors     []ConfigFieldError `json:"errors,omitempty"`
+	Valid      bool               `json:"valid"`
+	Saved      bool               `json:"saved,omitempty"`
+	// Theme is the viewer's theme, so renderers apply its template overrides.
+	Theme *ThemeSelection `json:"theme,omitempty"`
+}
+
+// ConfigField describes one input. Path is the dotted location of the value
+// in the configuration (`series.0.name`) and doubles as the HTML input name.
+type ConfigField struct {
+	Name         string                   `json:"name"`
+	Path         string                   `json:"path"`
+	ID           string                   `json:"id"`
+	Label        string                   `json:"label"`
+	Description  string                   `json:"description,omitempty"`
+	Type         string                   `json:"type,omitempty"`
+	ItemType     string                   `json:"item_type,omitempty"`
+	Widget       string                   `json:"widget"`
+	Required     bool                     `json:"required,omitempty"`
+	Localizable  bool                     `json:"localizable,omitempty"`
+	Default      any                      `json:"default,omitempty"`
+	Value        any                      `json:"value,omitempty"`
+	Text         string                   `json:"text,omitempty"`
+	Translations []ConfigFieldTranslation `json:"translations,omitempty"`
+	Options      []ConfigFieldOption      `json:"options,omitempty"`
+	Minimum      *float64                 `json:"minimum,omitempty"`
+	Maximum      *float64                 `json:"maximum,omitempty"`
+	Step         string                   `json:"step,omitempty"`
+	MinLength    *int                     `json:"min_length,omitempty"`
+	MaxLength    *int                     `json:"max_length,omitempty"`
+	MinItems     *int                     `json:"min_items,omitempty"`
+	MaxItems     *int                     `json:"max_items,omitempty"`
+	Fields       []ConfigField            `json:"fields,omitempty"`
+	Items        []ConfigField            `json:"items,omitempty"`
+	Template     *ConfigField             `json:"template,omitempty"`
+	Error        string                   `json:"error,omitempty"`
+}
+
+// ConfigFieldOption is one enum choice of a select field.
+type ConfigFieldOption struct {
+	Value    any    `json:"value"`
+	Label    string `json:"label"`
+	Selected bool   `json:"selected,omitempty"`
+}
+
+// ConfigFieldTranslation is a per-locale value of a localizable field.
+type ConfigFieldTranslation struct {
+	Locale string `json:"locale"`
+	Value  string `json:"value"`
+}
+
+// ConfigFieldError reports a validation problem at a configuration path. An
+// empty Path applies to the whole configuration.
+type ConfigFieldError struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// WidgetConfigFormRequest selects the form to build: an existing widget
+// (WidgetID) or a definition for a new widget (DefinitionID). When
+// Configuration is set it replaces the stored values and is validated.
+type WidgetConfigFormRequest struct {
+	WidgetID      string
+	DefinitionID  string
+	Viewer        ViewerContext
+	Configuration map[string]any
+}
+
+// WidgetConfigEditor builds configuration forms for transports.
+type WidgetConfigEditor interface {
+	WidgetConfigForm(ctx context.Context, req WidgetConfigFormRequest) (ConfigForm, error)
+}
+
+var _ WidgetConfigEditor = (*Service)(nil)
+
+// WidgetConfigForm builds the configuration form for a widget instance or
+// definition, validating req.Configuration when present.
+func (s *Service) WidgetConfigForm(ctx context.Context, req WidgetConfigFormRequest) (ConfigForm, error) {
+	definitionID := strings.TrimSpace(req.DefinitionID)
+	values := req.Configuration
+	var migrationErr error
+	if widgetID := strings.TrimSpace(req.WidgetID); widgetID != "" {
+		store, err := s.widgetStore()
+		if err != nil {
+			return ConfigForm{}, err
+		}
+		inst, err := store.GetInstance(ctx, widgetID)
+		if err != nil {
+			return ConfigForm{}, err
+		}
+		if !s.canConfigureWidget(ctx, req.Viewer, inst) {
+			return ConfigForm{}, ErrWidgetConfigForbidden
+		}
+		definitionID = inst.DefinitionID
+		if values == nil {
+			migrated, _, err := s.migrateInstance(inst)
+			if err != nil {
+				// The stored values are shown so the viewer can fix them.
+				migrationErr = err
+				migrated = inst
+			}
+			values = migrated.Configuration
+		}
+	}
+	if definitionID == "" {
+		return ConfigForm{}, fmt.Errorf("dashboard: widget id or definition is required")
+	}
+	def, ok := s.opts.Providers.Definition(definitionID)
+	if !ok {
+		return ConfigForm{}, fmt.Errorf("dashboard: widget definition %s not registered", definitionID)
+	}
+	if strings.TrimSpace(req.WidgetID) == "" && !s.canAddWidget(ctx, req.Viewer, def) {
+		return ConfigForm{}, ErrWidgetConfigForbidden
+	}
+	form := BuildConfigForm(def, values)
+	form.WidgetID = strings.TrimSpace(req.WidgetID)
+	form.Title = def.NameForLocale(req.Viewer.Locale)
+	form.Locale = req.Viewer.Locale
+	form.Theme = s.resolveTheme(ctx, req.Viewer)
+	if migrationErr != nil {
+		form.SetErrors([]ConfigFieldError{{Message: migrationErr.Error()}})
+	}
+	if req.Configuration != nil {
+		if err := s.validateConfiguration(definitionID, req.Configuration); err != nil {
+			form.SetErrors(ConfigFieldErrors(err))
+		}
+	}
+	return form, nil
+}
+
+// canConfigureWidget asks the EditAuthorizer whether viewer may change inst,
+// falling back to edit mode access plus visibility.
+func (s *Service) canConfigureWidget(ctx context.Context, viewer ViewerContext, inst WidgetInstance) bool {
+	if auth, ok := s.opts.Authorizer.(EditAuthorizer); ok {
+		return auth.CanConfigureWidget(ctx, viewer, inst)
+	}
+	return s.CanEditLayout(ctx, viewer) && s.opts.Authorizer.CanViewWidget(ctx, viewer, inst)
+}
+
+// canAddWidget reports whether viewer may create a widget of def: the
+// definition must be offered to them and they must be able to edit.
+func (s *Service) canAddWidget(ctx context.Context, viewer ViewerContext, def WidgetDefinition) bool {
+	if auth, ok := s.opts.Authorizer.(CatalogAuthorizer); ok && !auth.CanUseDefinition(ctx, viewer, def) {
+		return false
+	}
+	return s.CanEditLayout(ctx, viewer)
+}
+
+// BuildConfigForm derives a form from def.Schema populated with values.
+// Missing values fall back to schema defaults.
+func BuildConfigForm(def WidgetDefinition, values map[string]any) ConfigForm {
+	schema, _ := normalizeJSONValue(def.Schema).(map[string]any)
+	normalized, _ := normalizeJSONValue(values).(map[string]any)
+	form := ConfigForm{
+		Definition: def.Code,
+		Title:      def.Name,
+		Method:     "post",
+		Values:     normalized,
+		Schema:     schema,
+		Valid:      true,
+	}
+	if len(schemaProperties(schema)) == 0 {
+		form.Fields = []ConfigField{jsonConfigField(configFormRootField, configFormRootField, "Configuration", normalized, normalized != nil)}
+		return form
+	}
+	form.Fields = configFields(schema, "", normalized)
+	return form
+}
+
+// SetErrors records errs on the form and on the closest matching fields.
+func (form *ConfigForm) SetErrors(errs []ConfigFieldError) {
+	form.Errors = errs
+	form.Valid = len(errs) == 0
+	for _, fieldErr := range errs {
+		if target := findConfigField(form.Fields, fieldErr.Path); target != nil && target.Error == "" {
+			target.Error = fieldErr.Message
+		}
+	}
+}
+
+// Decode converts an HTML form submission into a configuration map using the
+// field descriptors. Values that cannot be parsed are reported as field
+// errors; empty inputs are omitted so schema defaults apply.
+func (form ConfigForm) Decode(values url.Values) (map[string]any, []ConfigFieldError) {
+	config := map[string]any{}
+	var errs []ConfigFieldError
+	if len(form.Fields) == 1 && form.Fields[0].Path == configFormRootField && form.Fields[0].Widget == ConfigFieldJSON {
+		value, present := decodeConfigField(form.Fields[0], values, &errs)
+		if object, ok := value.(map[string]any); ok {
+			return object, errs
+		}
+		if present {
+			errs = append(errs, ConfigFieldError{Path: configFormRootField, Message: "configuration must be a JSON object"})
+		}
+		return config, errs
+	}
+	decodeConfigFields(form.Fields, values, config, &errs)
+	return config, errs
+}
+
+// ConfigFieldErrors flattens a configuration validation error into field
+// errors. JSON schema failures map to instance paths; other errors become a
+// single form-level error.
+func ConfigFieldErrors(err error) []ConfigFieldError {
+	if err == nil {
+		return nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []ConfigFieldError{{Message: err.Error()}}
+	}
+	var out []ConfigFieldError
+	seen := map[string]bool{}
+	add := func(path, message string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		out = append(out, ConfigFieldError{Path: path, Message: message})
+	}
+	var walk func(*jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+			return
+		}
+		path := strings.ReplaceAll(strings.Trim(e.InstanceLocation, "/"), "/", ".")
+		if strings.HasSuffix(e.KeywordLocation, "/required") {
+			for _, match := range missingPropertyPattern.FindAllStringSubmatch(e.Message, -1) {
+				add(joinConfigPath(path, match[1]), "is required")
+			}
+			return
+		}
+		add(path, e.Message)
+	}
+	walk(validationErr)
+	if len(out) == 0 {
+		return []ConfigFieldError{{Message: validationErr.Error()}}
+	}
+	return out
+}
+
+func configFields(schema map[string]any, prefix string, values map[string]any) []ConfigField {
+	props := schemaProperties(schema)
+	required := map[string]bool{}
+	for _, name := range schemaStrings(schema["required"]) {
+		required[name] = true
+	}
+	names := slices.Collect(maps.Keys(props))
+	slices.SortFunc(names, func(a, b string) int {
+		if oa, ob := schemaPropertyOrder(props[a]), schemaPropertyOrder(props[b]); oa != ob {
+			return oa - ob
+		}
+		if required[a] != required[b] {
+			if required[a] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	fields := make([]ConfigField, 0, len(names))
+	for _, name := range names {
+		child, _ := props[name].(map[string]any)
+		value, present := values[name]
+		field := configField(child, name, joinConfigPath(prefix, name), value, present)
+		field.Required = required[name]
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func configField(schema map[string]any, name, path string, value any, present bool) ConfigField {
+	field := ConfigField{
+		Name:        name,
+		Path:        path,
+		ID:          configFieldID(path),
+		Label:       schemaText(schema, "title", humanizeConfigName(name)),
+		Description: schemaText(schema, "description", ""),
+		Type:        schemaText(schema, "type", ""),
+		Default:     schema["default"],
+	}
+	if !present {
+		value = field.Default
+	}
+	field.Value = value
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 && field.Type != "array" {
+		field.Widget = ConfigFieldSelect
+		for _, option := range enum {
+			field.Options = append(field.Options, ConfigFieldOption{
+				Value:    option,
+				Label:    fmt.Sprint(option),
+				Selected: value != nil && fmt.Sprint(option) == fmt.Sprint(value),
+			})
+		}
+		field.Text = configScalarText(value)
+		return field
+	}
+	switch field.Type {
+	case "string":
+		field.Widget = ConfigFieldText
+		field.MinLength = schemaInt(schema, "minLength")
+		field.MaxLength = schemaInt(schema, "maxLength")
+		if localizable, _ := schema[LocalizableSchemaKeyword].(bool); localizable {
+			field.Localizable = true
+			if translations, ok := localizedValueMap(value); ok {
+				field.Value = translations["default"]
+				for _, locale := range slices.Sorted(maps.Keys(translations)) {
+					if locale != "default" {
+						field.Translations = append(field.Translations, ConfigFieldTranslation{Locale: locale, Value: translations[locale]})
+					}
+				}
+			}
+		}
+	case "integer", "number":
+		field.Widget = ConfigFieldNumber
+		field.Minimum = schemaFloat(schema, "minimum")
+		field.Maximum = schemaFloat(schema, "maximum")
+		field.Step = "any"
+		if field.Type == "integer" {
+			field.Step = "1"
+		}
+	case "boolean":
+		field.Widget = ConfigFieldCheckbox
+	case "object":
+		if len(schemaProperties(schema)) == 0 {
+			return jsonConfigField(name, path, field.Label, value, value != nil)
+		}
+		object, _ := value.(map[string]any)
+		field.Widget = ConfigFieldGroup
+		field.Fields = configFields(schema, path, object)
+	case "array":
+		field.MinItems = schemaInt(schema, "minItems")
+		field.MaxItems = schemaInt(schema, "maxItems")
+		items, _ := schema["items"].(map[string]any)
+		itemType := schemaText(items, "type", "")
+		switch {
+		case (itemType == "string" || itemType == "number" || itemType == "integer") && items[LocalizableSchemaKeyword] != true:
+			field.Widget = ConfigFieldLines
+			field.ItemType = itemType
+			var lines []string
+			list, _ := value.([]any)
+			for _, item := range list {
+				lines = append(lines, fmt.Sprint(item))
+			}
+			field.Text = strings.Join(lines, "\n")
+		case itemType == "object" && len(schemaProperties(items)) > 0:
+			field.Widget = ConfigFieldList
+			list, _ := value.([]any)
+			for i, item := range list {
+				object, _ := item.(map[string]any)
+				field.Items = append(field.Items, configListItem(items, path, i, object))
+			}
+			template := blankConfigField(configListItem(items, path, len(list), nil))
+			field.Template = &template
+		default:
+			return jsonConfigField(name, path, field.Label, value, value != nil)
+		}
+	default:
+		return jsonConfigField(name, path, field.Label, value, value != nil)
+	}
+	if field.Widget == ConfigFieldText || field.Widget == ConfigFieldNumber {
+		field.Text = configScalarText(field.Value)
+	}
+	return field
+}
+
+func configListItem(schema map[string]any, listPath string, index int, values map[string]any) ConfigField {
+	path := joinConfigPath(listPath, strconv.Itoa(index))
+	return ConfigField{
+		Name:   strconv.Itoa(index),
+		Path:   path,
+		ID:     configFieldID(path),
+		Label:  fmt.Sprintf("#%d", index+1),
+		Type:   "object",
+		Widget: ConfigFieldGroup,
+		Fields: configFields(schema, path, values),
+	}
+}
+
+// blankConfigField clears pre-filled inputs of the spare list item so an
+// untouched item submits nothing. Checkboxes keep their defaults since blank
+// detection ignores them.
+func blankConfigField(field ConfigField) ConfigField {
+	if field.Widget != ConfigFieldCheckbox {
+		field.Value = nil
+		field.Text = ""
+		field.Translations = nil
+	}
+	options := make([]ConfigFieldOption, len(field.Options))
+	for i, option := range field.Options {
+		option.Selected = false
+		options[i] = option
+	}
+	if len(options) > 0 {
+		field.Options = options
+	}
+	children := make([]ConfigField, len(field.Fields))
+	for i, child := range field.Fields {
+		children[i] = blankConfigField(child)
+	}
+	if len(children) > 0 {
+		field.Fields = children
+	}
+	return field
+}
+
+func jsonConfigField(name, path, label string, value any, present bool) ConfigField {
+	field := ConfigField{
+		Name:   name,
+		Path:   path,
+		ID:     configFieldID(path),
+		Label:  label,
+		Widget: ConfigFieldJSON,
+		Value:  value,
+	}
+	if present {
+		if raw, err := json.MarshalIndent(value, "", "  "); err == nil {
+			field.Text = string(raw)
+		}
+	}
+	return field
+}
+
+func decodeConfigFields(fields []ConfigField, values url.Values, out map[string]any, errs *[]ConfigFieldError) {
+	for _, field := range fields {
+		if value, present := decodeConfigField(field, values, errs); present {
+			out[field.Name] = value
+		}
+	}
+}
+
+// decodeConfigField returns the submitted value of field and whether one was
+// present. Parse failures are appended to errs under the input path.
+func decodeConfigField(field ConfigField, values url.Values, errs *[]ConfigFieldError) (any, bool) {
+	fail := func(err error) (any, bool) {
+		*errs = append(*errs, ConfigFieldError{Path: field.Path, Message: err.Error()})
+		return nil, false
+	}
+	raw, hasRaw := lastFormValue(values, field.Path)
+	switch field.Widget {
+	case ConfigFieldText:
+		if field.Localizable {
+			return decodeLocalizedConfigField(field.Path, raw, values)
+		}
+		return raw, raw != ""
+	case ConfigFieldSelect:
+		if raw == "" {
+			return nil, false
+		}
+		for _, option := range field.Options {
+			if fmt.Sprint(option.Value) == raw {
+				return option.Value, true
+			}
+		}
+		return fail(fmt.Errorf("%q is not an allowed value", raw))
+	case ConfigFieldNumber:
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			return nil, false
+		}
+		value, err := parseConfigNumber(raw, field.Type)
+		if err != nil {
+			return fail(err)
+		}
+		return value, true
+	case ConfigFieldCheckbox:
+		if !hasRaw {
+			return nil, false
+		}
+		switch strings.ToLower(raw) {
+		case "true", "on", "1", "yes":
+			return true, true
+		default:
+			return false, true
+		}
+	case ConfigFieldLines:
+		var list []any
+		for line := range strings.SplitSeq(raw, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if field.ItemType == "number" || field.ItemType == "integer" {
+				value, err := parseConfigNumber(line, field.ItemType)
+				if err != nil {
+					return fail(err)
+				}
+				list = append(list, value)
+				continue
+			}
+			list = append(list, line)
+		}
+		return list, len(list) > 0
+	case ConfigFieldGroup:
+		object := map[string]any{}
+		decodeConfigFields(field.Fields, values, object, errs)
+		return object, len(object) > 0
+	case ConfigFieldList:
+		if field.Template == nil {
+			return nil, false
+		}
+		var list []any
+		for _, index := range configListIndexes(values, field.Path) {
+			path := joinConfigPath(field.Path, strconv.Itoa(index))
+			item := repathConfigField(*field.Template, field.Template.Path, path)
+			if configItemBlank(item, values) {
+				continue
+			}
+			if value, present := decodeConfigField(item, values, errs); present {
+				list = append(list, value)
+			}
+		}
+		return list, len(list) > 0
+	case ConfigFieldJSON:
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			return nil, false
+		}
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return fail(fmt.Errorf("invalid JSON: %v", err))
+		}
+		return value, true
+	default:
+		return nil, false
+	}
+}
+
+func decodeLocalizedConfigField(path, base string, values url.Values) (any, bool) {
+	translations := map[string]string{}
+	for key := range values {
+		locale, ok := strings.CutPrefix(key, path+".")
+		if !ok || strings.Contains(locale, ".") || strings.HasPrefix(locale, "__") {
+			continue
+		}
+		if text, _ := lastFormValue(values, key); text != "" {
+			translations[normalizeLocale(locale)] = text
+		}
+	}
+	newLocale, _ := lastFormValue(values, path+configFormNewLocaleSuffix)
+	newText, _ := lastFormValue(values, path+configFormNewTextSuffix)
+	if locale := normalizeLocale(newLocale); locale != "" && newText != "" {
+		translations[locale] = newText
+	}
+	if len(translations) == 0 {
+		return base, base != ""
+	}
+	if base != "" {
+		translations["default"] = base
+	}
+	return translations, true
+}
+
+func parseConfigNumber(raw, kind string) (any, error) {
+	if kind == "integer" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", raw)
+		}
+		return value, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", raw)
+	}
+	return value, nil
+}
+
+func configListIndexes(values url.Values, path string) []int {
+	seen := map[int]bool{}
+	for key := range values {
+		rest, ok := strings.CutPrefix(key, path+".")
+		if !ok {
+			continue
+		}
+		segment, _, _ := strings.Cut(rest, ".")
+		if index, err := strconv.Atoi(segment); err == nil && index >= 0 {
+			seen[index] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// configItemBlank reports whether every non-checkbox input of a list item was
+// left empty, so the spare item rendered for new entries is ignored.
+func configItemBlank(field ConfigField, values url.Values) bool {
+	switch field.Widget {
+	case ConfigFieldGroup:
+		for _, child := range field.Fields {
+			if !configItemBlank(child, values) {
+				return false
+			}
+		}
+		return true
+	case ConfigFieldList:
+		return len(configListIndexes(values, field.Path)) == 0
+	case ConfigFieldCheckbox:
+		return true
+	default:
+		for key, list := range values {
+			if key != field.Path && !strings.HasPrefix(key, field.Path+".") {
+				continue
+			}
+			for _, value := range list {
+				if strings.TrimSpace(value) != "" {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
+
+func repathConfigField(field ConfigField, from, to string) ConfigField {
+	if rest, ok := strings.CutPrefix(field.Path, from); ok {
+		field.Path = to + rest
+		field.ID = configFieldID(field.Path)
+	}
+	if field.Widget == ConfigFieldGroup && field.Path == to {
+		field.Name = to[strings.LastIndex(to, ".")+1:]
+	}
+	children := make([]ConfigField, len(field.Fields))
+	for i, child := range field.Fields {
+		children[i] = repathConfigField(child, from, to)
+	}
+	field.Fields = children
+	if field.Template != nil {
+		template := repathConfigField(*field.Template, from, to)
+		field.Template = &template
+	}
+	return field
+}
+
+// findConfigField returns the field whose path is path or its closest
+// ancestor, so errors inside JSON fields land on the JSON input.
+func findConfigField(fields []ConfigField, path string) *ConfigField {
+	for i := range fields {
+		field := &fields[i]
+		if field.Path != path && !strings.HasPrefix(path, field.Path+".") {
+			continue
+		}
+		if field.Path == path {
+			return field
+		}
+		if nested := findConfigField(field.Fields, path); nested != nil {
+			return nested
+		}
+		if nested := findConfigField(field.Items, path); nested != nil {
+			return nested
+		}
+		return field
+	}
+	return nil
+}
+
+// configScalarText formats scalar values for input attributes; templates
+// print floats with a fixed precision otherwise.
+func configScalarText(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func lastFormValue(values url.Values, key string) (string, bool) {
+	list, ok := values[key]
+	if !ok || len(list) == 0 {
+		return "", false
+	}
+	return list[len(list)-1], true
+}
+
+func joinConfigPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	if name == "" {
+		return prefix
+	}
+	return prefix + "." + name
+}
+
+func configFieldID(path string) string {
+	return "dashboard-config-" + strings.Trim(configFieldIDPattern.ReplaceAllString(strings.ReplaceAll(path, ".", "-"), "-"), "-")
+}
+
+func humanizeConfigName(name string) string {
+	name = strings.TrimSpace(strings.NewReplacer("_", " ", "-", " ").Replace(name))
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func schemaProperties(schema map[string]any) map[string]any {
+	props, _ := schema["properties"].(map[string]any)
+	return props
+}
+
+func schemaPropertyOrder(prop any) int {
+	schema, _ := prop.(map[string]any)
+	if order, ok := schema["propertyOrder"].(float64); ok {
+		return int(order)
+	}
+	return 1 << 20
+}
+
+func schemaText(schema map[string]any, key, fallback string) string {
+	if value, ok := schema[key].(string); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func schemaStrings(value any) []string {
+	list, _ := value.([]any)
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if text, ok := item.(string); ok {
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+func schemaFloat(schema map[string]any, key string) *float64 {
+	if value, ok := schema[key].(float64); ok {
+		return &value
+	}
+	return nil
+}
+
+func schemaInt(schema map[string]any, key string) *int {
+	if value, ok := schema[key].(float64); ok {
+		n := int(value)
+		return &n
+	}
+	return nil
+}
+
+// normalizeJSONValue round-trips value through JSON so Go literals
+// ([]string, []map[string]any, ints) match what stores and clients send.
+func normalizeJSONValue(value any) any {
+	if value == nil {
+		return nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return value
+	}
+	return out
+}
@@ -0,0 +1,242 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestBuildConfigFormDerivesFieldsFromSchema(t *testing.T) {
+	def := WidgetDefinition{Code: "admin.widget.kpi", Name: "KPI", Schema: kpiSchema()}
+	form := BuildConfigForm(def, map[string]any{
+		"metric":     "revenue",
+		"format":     "currency",
+		"decimals":   2,
+		"title":      map[string]any{"default": "Revenue", "es": "Ingresos"},
+		"thresholds": []map[string]any{{"value": 10, "level": "warning"}},
+	})
+	if !form.Valid || form.Definition != "admin.widget.kpi" {
+		t.Fatalf("unexpected form header %+v", form)
+	}
+	if form.Fields[0].Name != "metric" || !form.Fields[0].Required {
+		t.Fatalf("expected required fields first, got %+v", form.Fields[0])
+	}
+	format := findConfigField(form.Fields, "format")
+	if format.Widget != ConfigFieldSelect || len(format.Options) != 5 || format.Text != "currency" {
+		t.Fatalf("expected enum select, got %+v", format)
+	}
+	decimals := findConfigField(form.Fields, "decimals")
+	if decimals.Widget != ConfigFieldNumber || *decimals.Minimum != 0 || *decimals.Maximum != 6 || decimals.Step != "1" || decimals.Text != "2" {
+		t.Fatalf("expected bounded integer field, got %+v", decimals)
+	}
+	title := findConfigField(form.Fields, "title")
+	if !title.Localizable || title.Text != "Revenue" || len(title.Translations) != 1 || title.Translations[0].Locale != "es" {
+		t.Fatalf("expected localized text field, got %+v", title)
+	}
+	sparkline := findConfigField(form.Fields, "sparkline")
+	if sparkline.Widget != ConfigFieldCheckbox || sparkline.Value != true {
+		t.Fatalf("expected checkbox with schema default, got %+v", sparkline)
+	}
+	thresholds := findConfigField(form.Fields, "thresholds")
+	if thresholds.Widget != ConfigFieldList || len(thresholds.Items) != 1 || thresholds.Template == nil {
+		t.Fatalf("expected list of groups, got %+v", thresholds)
+	}
+	if item := findConfigField(form.Fields, "thresholds.0.value"); item == nil || item.Text != "10" {
+		t.Fatalf("expected list item field, got %+v", item)
+	}
+	if thresholds.Template.Path != "thresholds.1" || thresholds.Template.Fields[0].Text != "" {
+		t.Fatalf("expected blank template for next item, got %+v", thresholds.Template)
+	}
+}
+
+func TestBuildConfigFormFallsBackToJSONFields(t *testing.T) {
+	def := WidgetDefinition{Code: "admin.widget.bar_chart", Schema: chartConfigSchema(true)}
+	form := BuildConfigForm(def, map[string]any{"series": []any{map[string]any{"name": "Sales", "data": []any{1, 2}}}})
+	if data := findConfigField(form.Fields, "series.0.data"); data == nil || data.Widget != ConfigFieldJSON || data.Text == "" {
+		t.Fatalf("expected oneOf items to use a JSON field, got %+v", data)
+	}
+	if axis := findConfigField(form.Fields, "x_axis"); axis.Widget != ConfigFieldLines || !strings.HasPrefix(axis.Text, "Mon\nTue") {
+		t.Fatalf("expected string arrays as lines, got %+v", axis)
+	}
+
+	bare := BuildConfigForm(WidgetDefinition{Code: "custom"}, map[string]any{"a": 1})
+	if len(bare.Fields) != 1 || bare.Fields[0].Widget != ConfigFieldJSON {
+		t.Fatalf("expected single JSON field without schema properties, got %+v", bare.Fields)
+	}
+	decoded, errs := bare.Decode(url.Values{"configuration": {`{"b": true}`}})
+	if len(errs) != 0 || decoded["b"] != true {
+		t.Fatalf("expected JSON configuration decoded, got %+v %+v", decoded, errs)
+	}
+}
+
+func TestConfigFormDecodeRoundTripsThroughValidation(t *testing.T) {
+	def := WidgetDefinition{Code: "admin.widget.kpi", Schema: kpiSchema()}
+	form := BuildConfigForm(def, nil)
+	values := url.Values{
+		"metric":                    {"revenue"},
+		"format":                    {"percent"},
+		"decimals":                  {"1"},
+		"sparkline":                 {"false", "true"},
+		"lower_is_better":           {"false"},
+		"title":                     {"Revenue"},
+		"title.fr":                  {"Chiffre"},
+		"title.__locale":            {"es"},
+		"title.__text":              {"Ingresos"},
+		"thresholds.0.value":        {"90.5"},
+		"thresholds.0.level":        {"critical"},
+		"thresholds.1.value":        {""},
+		"thresholds.1.level":        {""},
+		"comparison_label":          {""},
+		"comparison_label.__locale": {""},
+	}
+	config, errs := form.Decode(values)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected decode errors %+v", errs)
+	}
+	if config["decimals"] != 1 || config["sparkline"] != true || config["lower_is_better"] != false {
+		t.Fatalf("expected typed scalars, got %+v", config)
+	}
+	if _, ok := config["comparison_label"]; ok {
+		t.Fatalf("expected empty inputs omitted, got %+v", config)
+	}
+	title := config["title"].(map[string]string)
+	if title["default"] != "Revenue" || title["fr"] != "Chiffre" || title["es"] != "Ingresos" {
+		t.Fatalf("expected locale map, got %+v", title)
+	}
+	thresholds := config["thresholds"].([]any)
+	if len(thresholds) != 1 || thresholds[0].(map[string]any)["value"] != 90.5 {
+		t.Fatalf("expected blank spare item skipped, got %+v", thresholds)
+	}
+	if err := NewJSONSchemaValidator().Validate(def, config); err != nil {
+		t.Fatalf("expected decoded config to validate, got %v", err)
+	}
+
+	_, errs = form.Decode(url.Values{"metric": {"x"}, "decimals": {"two"}, "format": {"bogus"}})
+	if len(errs) != 2 || errs[0].Path == errs[1].Path {
+		t.Fatalf("expected parse errors per field, got %+v", errs)
+	}
+}
+
+func TestServiceWidgetConfigFormReportsFieldErrors(t *testing.T) {
+	store := &fakeWidgetStore{instances: map[string]WidgetInstance{
+		"kpi-1": {ID: "kpi-1", DefinitionID: "admin.widget.kpi", Configuration: map[string]any{"metric": "revenue"}},
+	}}
+	svc := NewService(Options{WidgetStore: store})
+
+	form, err := svc.WidgetConfigForm(context.Background(), WidgetConfigFormRequest{WidgetID: "kpi-1", Viewer: ViewerContext{UserID: "editor", Locale: "en"}})
+	if err != nil {
+		t.Fatalf("WidgetConfigForm returned error: %v", err)
+	}
+	if form.WidgetID != "kpi-1" || !form.Valid || findConfigField(form.Fields, "metric").Text != "revenue" {
+		t.Fatalf("expected stored configuration, got %+v", form)
+	}
+
+	form, err = svc.WidgetConfigForm(context.Background(), WidgetConfigFormRequest{
+		DefinitionID:  "admin.widget.table",
+		Viewer:        ViewerContext{UserID: "editor"},
+		Configuration: map[string]any{"page_size": 0, "columns": []any{map[string]any{"label": "Total"}}},
+	})
+	if err != nil {
+		t.Fatalf("WidgetConfigForm returned error: %v", err)
+	}
+	if form.Valid {
+		t.Fatalf("expected invalid form")
+	}
+	for _, path := range []string{"dataset", "page_size", "columns.0.key"} {
+		if got := findConfigField(form.Fields, path); got == nil || got.Path != path || got.Error == "" {
+			t.Fatalf("expected error on %s, got %+v (errors %+v)", path, got, form.Errors)
+		}
+	}
+
+	denied := NewService(Options{WidgetStore: store, Authorizer: allowListAuthorizer{allowed: map[string]bool{}}})
+	if _, err := denied.WidgetConfigForm(context.Background(), WidgetConfigFormRequest{WidgetID: "kpi-1", Viewer: ViewerContext{UserID: "editor"}}); !errors.Is(err, ErrWidgetConfigForbidden) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+	if _, err := svc.WidgetConfigForm(context.Background(), WidgetConfigFormRequest{WidgetID: "kpi-1"}); !errors.Is(err, ErrWidgetConfigForbidden) {
+		t.Fatalf("expected anonymous viewers to be refused, got %v", err)
+	}
+}
+
+func TestServiceWidgetConfigFormCarriesViewerTheme(t *testing.T) {
+	theme := &ThemeSelection{Name: "acme", Templates: map[string]string{configFormTemplate: "acme/config_form.html"}}
+	svc := NewService(Options{ThemeProvider: &stubThemeProvider{selection: theme}})
+
+	form, err := svc.WidgetConfigForm(context.Background(), WidgetConfigFormRequest{DefinitionID: "admin.widget.kpi", Viewer: ViewerContext{UserID: "editor"}})
+	if err != nil {
+		t.Fatalf("WidgetConfigForm returned error: %v", err)
+	}
+	if form.Theme == nil || form.Theme.Templates[configFormTemplate] != "acme/config_form.html" {
+		t.Fatalf("expected form to carry the viewer theme, got %+v", form.Theme)
+	}
+}
+
+func TestServiceWidgetConfigFormUsesEditAuthorizer(t *testing.T) {
+	store := &fakeWidgetStore{instances: map[string]WidgetInstance{
+		"kpi-1": {ID: "kpi-1", DefinitionID: "admin.widget.kpi", Configuration: map[string]any{"metric": "revenue"}},
+	}}
+	svc := NewService(Options{WidgetStore: store, Authorizer: editorAuthorizer{
+		editors:     map[string]bool{"editor": true},
+		definitions: map[string]bool{"admin.widget.kpi": true},
+	}})
+	ctx := context.Background()
+	if _, err := svc.WidgetConfigForm(ctx, WidgetConfigFormRequest{WidgetID: "kpi-1", Viewer: ViewerContext{UserID: "viewer"}}); !errors.Is(err, ErrWidgetConfigForbidden) {
+		t.Fatalf("expected read-only viewer to be refused, got %v", err)
+	}
+	if _, err := svc.WidgetConfigForm(ctx, WidgetConfigFormRequest{WidgetID: "kpi-1", Viewer: ViewerContext{UserID: "editor"}}); err != nil {
+		t.Fatalf("expected editor to configure the widget, got %v", err)
+	}
+	if _, err := svc.WidgetConfigForm(ctx, WidgetConfigFormRequest{DefinitionID: "admin.widget.table", Viewer: ViewerContext{UserID: "editor"}}); !errors.Is(err, ErrWidgetConfigForbidden) {
+		t.Fatalf("expected definitions outside the catalog to be refused, got %v", err)
+	}
+	if _, err := svc.WidgetConfigForm(ctx, WidgetConfigFormRequest{DefinitionID: "admin.widget.kpi", Viewer: ViewerContext{UserID: "editor"}}); err != nil {
+		t.Fatalf("expected editor to add catalog definitions, got %v", err)
+	}
+}
+
+func TestControllerRendersConfigFormPartial(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	svc := NewService(Options{WidgetStore: &fakeWidgetStore{}})
+	controller := NewController(ControllerOptions{Service: svc, Renderer: renderer})
+	form, err := controller.ConfigForm(context.Background(), WidgetConfigFormRequest{
+		DefinitionID:  "admin.widget.kpi",
+		Viewer:        ViewerContext{UserID: "editor", Locale: "ar"},
+		Configuration: map[string]any{"metric": "revenue", "decimals": 9, "thresholds": []any{map[string]any{"value": 5, "level": "ok"}}},
+	})
+	if err != nil {
+		t.Fatalf("ConfigForm returned error: %v", err)
+	}
+	form.Action = "/admin/dashboard/widgets/kpi-1/config"
+	var buf bytes.Buffer
+	if err := controller.RenderConfigForm(form, &buf); err != nil {
+		t.Fatalf("RenderConfigForm returned error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`action="/admin/dashboard/widgets/kpi-1/config"`,
+		`dir="rtl"`,
+		`name="metric" value="revenue"`,
+		`min="0" max="6"`,
+		`<option value="number" selected>`,
+		`name="thresholds.0.value" value="5"`,
+		`name="thresholds.1.level"`,
+		`name="title.__locale"`,
+		`<input type="hidden" name="sparkline" value="false">`,
+		`data-config-field="decimals"`,
+		"dashboard-config-field__error",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in rendered form:\n%s", want, html)
+		}
+	}
+
+	bare := NewController(ControllerOptions{Service: &stubLayoutResolver{}, Renderer: renderer})
+	if _, err := bare.ConfigForm(context.Background(), WidgetConfigFormRequest{}); !errors.Is(err, ErrWidgetConfigUnsupported) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path"
+	"slices"
 	"strings"
+	"time"
 )
 
 // LayoutResolver fetches layouts for a viewer.
@@ -21,6 +24,9 @@ type Controller struct {
 	areas            []AreaSlot
 	pageDecorator    PageDecorator
 	payloadDecorator PayloadDecorator
+	timeRangePresets []string
+	edit             *EditModeOptions
+	preferencesPath  string
 }
 
 // PageDecorator mutates the canonical typed page before transport-specific
@@ -39,6 +45,17 @@ type ControllerOptions struct {
 	Areas            []AreaSlot
 	PageDecorator    PageDecorator
 	PayloadDecorator PayloadDecorator
+	// TimeRangePresets enables the dashboard-wide time picker with the given
+	// presets. Leave empty to render without a picker.
+	TimeRangePresets []string
+	// EditMode enables the in-page layout editor (`?edit=1` with the
+	// go-router adapter). Leave nil to render without editing controls.
+	EditMode *EditModeOptions
+	// PreferencesPath is the preferences endpoint the toolbar posts the
+	// selected filters to, so they persist across visits (DefaultPreferencesPath
+	// with the go-router adapter). Leave empty to keep filter selections in
+	// the URL only.
+	PreferencesPath string
 }
 
 // AreaSlot describes the mapping between a payload slot (main/sidebar/etc.)
@@ -61,6 +78,9 @@ func NewController(opts ControllerOptions) *Controller {
 		areas:            normalizeAreaSlots(opts.Areas),
 		pageDecorator:    opts.PageDecorator,
 		payloadDecorator: opts.PayloadDecorator,
+		timeRangePresets: append([]string{}, opts.TimeRangePresets...),
+		edit:             normalizeEditModeOptions(opts.EditMode),
+		preferencesPath:  opts.PreferencesPath,
 	}
 }
 
@@ -69,7 +89,7 @@ func (c *Controller) Render(ctx context.Context, viewer ViewerContext) (Layout,
 	if c == nil || c.service == nil {
 		return Layout{}, fmt.Errorf("dashboard: controller missing service")
 	}
-	return c.service.ConfigureLayout(ctx, viewer)
+	return c.service.ConfigureLayout(ctx, c.editingViewer(ctx, viewer))
 }
 
 func (c *Controller) pageFromLayout(layout Layout, viewer ViewerContext) (Page, error) {
@@ -78,16 +98,22 @@ func (c *Controller) pageFromLayout(layout Layout, viewer ViewerContext) (Page,
 		Description: "Admin overview",
 		Locale:      viewer.Locale,
 		Theme:       layout.Theme,
+		Filters:     clonePageFilters(layout.Filters),
 		Areas:       make([]PageArea, 0, len(c.areas)),
 	}
 	assets := PageAssets{}
+	interactive := false
+	responsive := false
 	for idx, section := range c.areas {
-		widgets, widgetAssets, err := c.widgetFrames(section.Code, layout.Areas[section.Code])
+		widgets, widgetAssets, err := c.widgetFrames(section.Code, layout.Areas[section.Code], layout.Theme)
 		if err != nil {
 			return Page{}, err
 		}
 		assets.AddJS(widgetAssets.JS...)
 		assets.AddCSS(widgetAssets.CSS...)
+		interactive = interactive || hasInteractiveWidget(layout.Areas[section.Code])
+		responsive = responsive || slices.ContainsFunc(widgets, WidgetFrame.responsive) ||
+			slices.ContainsFunc(widgets, WidgetFrame.gridPlaced)
 		page.Areas = append(page.Areas, PageArea{
 			Slot:    section.Slot,
 			Code:    section.Code,
@@ -95,13 +121,32 @@ func (c *Controller) pageFromLayout(layout Layout, viewer ViewerContext) (Page,
 			Widgets: widgets,
 		})
 	}
+	if interactive {
+		assets.AddJS(InteractionsScriptURL(""))
+	}
+	if layout.Theme.Switchable() {
+		assets.AddJS(ThemeScriptURL(""))
+	}
+	if responsive {
+		assets.AddCSS(LayoutStylesURL(""))
+	}
+	if c.preferencesPath != "" && len(page.Filters) > 0 {
+		page.PreferencesURL = c.preferencesPath
+		assets.AddJS(InteractionsScriptURL(""))
+	}
 	if !assets.Empty() {
 		page.Assets = &assets
 	}
+	if len(c.timeRangePresets) > 0 {
+		page.TimeRange = &PageTimeRange{
+			Presets:  append([]string{}, c.timeRangePresets...),
+			Selected: viewer.TimeRange.Resolve(time.Now()),
+		}
+	}
 	return page, nil
 }
 
-func (c *Controller) widgetFrames(code string, instances []WidgetInstance) ([]WidgetFrame, PageAssets, error) {
+func (c *Controller) widgetFrames(code string, instances []WidgetInstance, theme *ThemeSelection) ([]WidgetFrame, PageAssets, error) {
 	if len(instances) == 0 {
 		return nil, PageAssets{}, nil
 	}
@@ -125,7 +170,7 @@ func (c *Controller) widgetFrames(code string, instances []WidgetInstance) ([]Wi
 		widgets = append(widgets, WidgetFrame{
 			ID:         inst.ID,
 			Definition: inst.DefinitionID,
-			Template:   templatePathFor(inst.DefinitionID),
+			Template:   widgetTemplateFor(theme, inst.DefinitionID),
 			Config:     inst.Configuration,
 			Data:       data,
 			Area:       areaCode,
@@ -143,6 +188,46 @@ func (c *Controller) widgetFrames(code string, instances []WidgetInstance) ([]Wi
 	return widgets, assets, nil
 }
 
+// WidgetAction runs a provider action (e.g. table paging or export) when the
+// controller service supports widget actions.
+func (c *Controller) WidgetAction(ctx context.Context, req WidgetActionRequest) (WidgetActionResult, error) {
+	if c == nil || c.service == nil {
+		return WidgetActionResult{}, fmt.Errorf("dashboard: controller missing service")
+	}
+	executor, ok := c.service.(WidgetActionExecutor)
+	if !ok {
+		return WidgetActionResult{}, ErrWidgetActionUnsupported
+	}
+	return executor.ExecuteWidgetAction(ctx, req)
+}
+
+// ConfigForm builds a widget configuration form when the controller service
+// supports it.
+func (c *Controller) ConfigForm(ctx context.Context, req WidgetConfigFormRequest) (ConfigForm, error) {
+	if c == nil || c.service == nil {
+		return ConfigForm{}, fmt.Errorf("dashboard: controller missing service")
+	}
+	editor, ok := c.service.(WidgetConfigEditor)
+	if !ok {
+		return ConfigForm{}, ErrWidgetConfigUnsupported
+	}
+	return editor.WidgetConfigForm(ctx, req)
+}
+
+// RenderConfigForm renders form as an HTML partial when the renderer supports
+// configuration forms.
+func (c *Controller) RenderConfigForm(form ConfigForm, out io.Writer) error {
+	if c == nil || c.renderer == nil {
+		return fmt.Errorf("dashboard: renderer not configured")
+	}
+	renderer, ok := c.renderer.(ConfigFormRenderer)
+	if !ok {
+		return ErrWidgetConfigUnsupported
+	}
+	_, err := renderer.RenderConfigForm(form, out)
+	return err
+}
+
 func (c *Controller) templatePath() string {
 	return c.template
 }
@@ -182,6 +267,11 @@ func (c *Controller) LayoutPayload(ctx context.Context, viewer ViewerContext) (m
 
 // Page resolves and decorates the canonical typed page for the current viewer.
 func (c *Controller) Page(ctx context.Context, viewer ViewerContext) (Page, error) {
+	if c == nil || c.service == nil {
+		return Page{}, fmt.Errorf("dashboard: controller missing service")
+	}
+	viewer = c.editingViewer(ctx, viewer)
+	viewer.TimeRange = viewer.TimeRange.Resolve(time.Now())
 	layout, err := c.Render(ctx, viewer)
 	if err != nil {
 		return Page{}, err
@@ -190,6 +280,16 @@ func (c *Controller) Page(ctx context.Context, viewer ViewerContext) (Page, erro
 	if err != nil {
 		return Page{}, err
 	}
+	if page.Edit, err = c.pageEdit(ctx, viewer); err != nil {
+		return Page{}, err
+	}
+	if page.Edit != nil && page.Edit.Active {
+		if page.Assets == nil {
+			page.Assets = &PageAssets{}
+		}
+		page.Assets.AddCSS(EditStylesURL(""))
+		page.Assets.AddJS(EditScriptURL(""))
+	}
 	decorated, err := c.decoratePage(ctx, viewer, page)
 	if err != nil {
 		return Page{}, err
@@ -230,6 +330,18 @@ func templatePathFor(definition string) string {
 	return fmt.Sprintf("widgets/%s.html", name)
 }
 
+// widgetTemplateFor resolves a widget template through the active theme,
+// which may override it by definition code or by default template path.
+func widgetTemplateFor(theme *ThemeSelection, definition string) string {
+	fallback := templatePathFor(definition)
+	for _, key := range []string{definition, fallback} {
+		if override := theme.TemplatePath(key); override != "" && isSafeThemeTemplatePath(override) {
+			return path.Clean(override)
+		}
+	}
+	return fallback
+}
+
 func normalizeAreaSlots(slots []AreaSlot) []AreaSlot {
 	if len(slots) == 0 {
 		return []AreaSlot{
@@ -310,6 +422,14 @@ func widgetLayout(metadata map[string]any) *WidgetLayout {
 	} else {
 		layout.Columns = layout.Width
 	}
+	layout.Breakpoints = parseBreakpointLayouts(raw["breakpoints"])
+	layout.Widths = parseBreakpointWidths(raw["widths"])
+	if h := intValue(raw["h"]); h > 0 {
+		layout.X = intValue(raw["x"])
+		layout.Y = intValue(raw["y"])
+		layout.W = intValue(raw["w"])
+		layout.H = h
+	}
 	return layout
 }
 
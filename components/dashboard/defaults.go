@@ -54,6 +54,7 @@ var defaultWidgetDefinitions = []WidgetDefinition{
 		Description: "Sales overview chart",
 		Category:    "charts",
 		Schema:      salesChartSchema(),
+		Variables:   []string{"segment", "region"},
 	},
 	{
 		Code: "admin.widget.quick_actions",
@@ -67,8 +68,16 @@ var defaultWidgetDefinitions = []WidgetDefinition{
 			"type": "object",
 			"properties": map[string]any{
 				"actions": map[string]any{
-					"type":  "array",
-					"items": map[string]any{"type": "object"},
+					"type": "array",
+					"items": map[string]any{
+						"type":     "object",
+						"required": []string{"label", "route"},
+						"properties": map[string]any{
+							"label": map[string]any{"type": "string", "minLength": 1, LocalizableSchemaKeyword: true},
+							"route": map[string]any{"type": "string", "minLength": 1},
+							"icon":  map[string]any{"type": "string"},
+						},
+					},
 				},
 			},
 		},
@@ -99,6 +108,10 @@ var defaultWidgetDefinitions = []WidgetDefinition{
 		Name:        "Conversion Funnel",
 		Description: "Tracks drop-off through key funnel stages.",
 		Category:    "analytics",
+		Variables:   []string{"segment", "region"},
+		Interactions: &WidgetInteractions{
+			Publish: map[string]string{"funnel_step": "label"},
+		},
 		Schema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -126,6 +139,10 @@ var defaultWidgetDefinitions = []WidgetDefinition{
 		Name:        "Cohort Overview",
 		Description: "Retention grid grouped by signup cohort.",
 		Category:    "analytics",
+		Variables:   []string{"region", "funnel_step"},
+		Interactions: &WidgetInteractions{
+			DrillDown: []DrillDownTarget{{Kind: DrillDownDetail, Title: "Cohort detail"}},
+		},
 		Schema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -154,6 +171,7 @@ var defaultWidgetDefinitions = []WidgetDefinition{
 		Name:        "Alert Trends",
 		Description: "Compares alert volume/severity over time.",
 		Category:    "analytics",
+		Variables:   []string{"region"},
 		Schema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -180,6 +198,22 @@ var defaultWidgetDefinitions = []WidgetDefinition{
 			"additionalProperties": false,
 		},
 	},
+	{
+		Code:        "admin.widget.kpi",
+		Name:        "KPI",
+		Description: "Single metric with period comparison, sparkline and thresholds.",
+		Category:    "stats",
+		Variables:   []string{"segment", "region"},
+		Schema:      kpiSchema(),
+	},
+	{
+		Code:        "admin.widget.table",
+		Name:        "Data Table",
+		Description: "Sortable, paged table with CSV export.",
+		Category:    "data",
+		Variables:   []string{"segment", "region"},
+		Schema:      tableSchema(),
+	},
 	{
 		Code:        "admin.widget.bar_chart",
 		Name:        "Bar Chart",
@@ -268,11 +302,13 @@ func chartSeriesSchema() map[string]any {
 func chartConfigSchema(includeAxis bool) map[string]any {
 	props := map[string]any{
 		"title": map[string]any{
-			"type":    "string",
-			"default": "Chart",
+			"type":                   "string",
+			"default":                "Chart",
+			LocalizableSchemaKeyword: true,
 		},
 		"subtitle": map[string]any{
-			"type": "string",
+			"type":                   "string",
+			LocalizableSchemaKeyword: true,
 		},
 		"series": map[string]any{
 			"type":     "array",
@@ -280,7 +316,8 @@ func chartConfigSchema(includeAxis bool) map[string]any {
 			"minItems": 1,
 		},
 		"footer_note": map[string]any{
-			"type": "string",
+			"type":                   "string",
+			LocalizableSchemaKeyword: true,
 		},
 		"theme": map[string]any{
 			"type": "string",
@@ -319,6 +356,71 @@ func chartConfigSchema(includeAxis bool) map[string]any {
 	}
 }
 
+func kpiSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"metric"},
+		"properties": map[string]any{
+			"metric":   map[string]any{"type": "string", "minLength": 1},
+			"title":    map[string]any{"type": "string", LocalizableSchemaKeyword: true},
+			"period":   map[string]any{"type": "string", "default": "30d"},
+			"format":   map[string]any{"type": "string", "enum": []string{"number", "currency", "percent", "duration", "compact"}, "default": "number"},
+			"currency": map[string]any{"type": "string", "minLength": 3, "maxLength": 3},
+			"unit":     map[string]any{"type": "string"},
+			"decimals": map[string]any{"type": "integer", "minimum": 0, "maximum": 6},
+			"thresholds": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":     "object",
+					"required": []string{"value", "level"},
+					"properties": map[string]any{
+						"value": map[string]any{"type": "number"},
+						"level": map[string]any{"type": "string", "enum": []string{"ok", "warning", "critical"}},
+					},
+				},
+			},
+			"lower_is_better":  map[string]any{"type": "boolean", "default": false},
+			"sparkline":        map[string]any{"type": "boolean", "default": true},
+			"comparison_label": map[string]any{"type": "string", LocalizableSchemaKeyword: true},
+		},
+		"additionalProperties": false,
+	}
+}
+
+func tableSchema() map[string]any {
+	columnTypes := []string{"string", "number", "currency", "percent", "date", "datetime", "bool"}
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"dataset"},
+		"properties": map[string]any{
+			"dataset": map[string]any{"type": "string", "minLength": 1},
+			"title":   map[string]any{"type": "string", LocalizableSchemaKeyword: true},
+			"columns": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":     "object",
+					"required": []string{"key"},
+					"properties": map[string]any{
+						"key":      map[string]any{"type": "string", "minLength": 1},
+						"label":    map[string]any{"type": "string", LocalizableSchemaKeyword: true},
+						"type":     map[string]any{"type": "string", "enum": columnTypes, "default": "string"},
+						"sortable": map[string]any{"type": "boolean", "default": false},
+						"currency": map[string]any{"type": "string", "minLength": 3, "maxLength": 3},
+						"decimals": map[string]any{"type": "integer", "minimum": 0, "maximum": 6},
+						"format":   map[string]any{"type": "string"},
+					},
+					"additionalProperties": false,
+				},
+			},
+			"sort":      map[string]any{"type": "string"},
+			"sort_desc": map[string]any{"type": "boolean", "default": false},
+			"page_size": map[string]any{"type": "integer", "minimum": 1, "maximum": maxTablePageSize, "default": defaultTablePageSize},
+			"export":    map[string]any{"type": "boolean", "default": true},
+		},
+		"additionalProperties": false,
+	}
+}
+
 func salesChartSchema() map[string]any {
 	metrics := []string{"revenue", "orders", "customers", "seats", "pipeline"}
 	periods := []string{"7d", "14d", "30d", "60d", "90d", "180d"}
@@ -365,7 +467,8 @@ func salesChartSchema() map[string]any {
 				"default": false,
 			},
 			"footer_note": map[string]any{
-				"type": "string",
+				"type":                   "string",
+				LocalizableSchemaKeyword: true,
 			},
 		},
 		"additionalProperties": false,
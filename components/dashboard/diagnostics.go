@@ -1,6 +1,9 @@
 package dashboard
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // AreaDiagnostics captures resolved widget state for a specific area in stable
 // area order.
@@ -23,6 +26,9 @@ type DashboardDiagnostics struct {
 	Theme       *ThemeSelection   `json:"theme,omitempty"`
 	Layout      LayoutDiagnostics `json:"layout"`
 	Page        *Page             `json:"page,omitempty"`
+	// MissingTranslations lists keys rendered with their fallback, per
+	// locale, when the translation service records them.
+	MissingTranslations map[string][]MissingTranslation `json:"missing_translations,omitempty"`
 }
 
 func buildLayoutDiagnostics(areaOrder []string, layout Layout) LayoutDiagnostics {
@@ -43,10 +49,11 @@ func (s *Service) Diagnostics(ctx context.Context, viewer ViewerContext) (Dashbo
 		return DashboardDiagnostics{}, err
 	}
 	return DashboardDiagnostics{
-		Viewer:      viewer,
-		Preferences: cloneLayoutOverrides(overrides),
-		Theme:       cloneThemeSelection(layout.Theme),
-		Layout:      buildLayoutDiagnostics(s.areaList(), layout),
+		Viewer:              viewer,
+		Preferences:         cloneLayoutOverrides(overrides),
+		Theme:               cloneThemeSelection(layout.Theme),
+		Layout:              buildLayoutDiagnostics(s.areaList(), layout),
+		MissingTranslations: s.MissingTranslations(),
 	}, nil
 }
 
@@ -61,6 +68,7 @@ type layoutStateProvider interface {
 // Diagnostics returns typed operational state plus the resolved typed page when
 // the controller is available.
 func (c *Controller) Diagnostics(ctx context.Context, viewer ViewerContext) (DashboardDiagnostics, error) {
+	viewer.TimeRange = viewer.TimeRange.Resolve(time.Now())
 	if provider, ok := c.service.(layoutStateProvider); ok {
 		layout, overrides, err := provider.resolveLayoutState(ctx, viewer)
 		if err != nil {
@@ -75,11 +83,12 @@ func (c *Controller) Diagnostics(ctx context.Context, viewer ViewerContext) (Das
 			return DashboardDiagnostics{}, err
 		}
 		return DashboardDiagnostics{
-			Viewer:      viewer,
-			Preferences: cloneLayoutOverrides(overrides),
-			Theme:       cloneThemeSelection(layout.Theme),
-			Layout:      buildLayoutDiagnostics(c.areaCodes(), layout),
-			Page:        new(clonePage(page)),
+			Viewer:              viewer,
+			Preferences:         cloneLayoutOverrides(overrides),
+			Theme:               cloneThemeSelection(layout.Theme),
+			Layout:              buildLayoutDiagnostics(c.areaCodes(), layout),
+			Page:                new(clonePage(page)),
+			MissingTranslations: c.missingTranslations(),
 		}, nil
 	}
 	if provider, ok := c.service.(diagnosticsProvider); ok {
@@ -95,6 +104,9 @@ func (c *Controller) Diagnostics(ctx context.Context, viewer ViewerContext) (Das
 		if resolved.Theme == nil {
 			resolved.Theme = cloneThemeSelection(page.Theme)
 		}
+		if missing := c.missingTranslations(); missing != nil {
+			resolved.MissingTranslations = missing
+		}
 		return resolved, nil
 	}
 	page, err := c.Page(ctx, viewer)
@@ -102,12 +114,20 @@ func (c *Controller) Diagnostics(ctx context.Context, viewer ViewerContext) (Das
 		return DashboardDiagnostics{}, err
 	}
 	return DashboardDiagnostics{
-		Viewer: viewer,
-		Theme:  cloneThemeSelection(page.Theme),
-		Page:   new(clonePage(page)),
+		Viewer:              viewer,
+		Theme:               cloneThemeSelection(page.Theme),
+		Page:                new(clonePage(page)),
+		MissingTranslations: c.missingTranslations(),
 	}, nil
 }
 
+func (c *Controller) missingTranslations() map[string][]MissingTranslation {
+	if reporter, ok := c.service.(missingTranslationReporter); ok {
+		return reporter.MissingTranslations()
+	}
+	return nil
+}
+
 func (c *Controller) areaCodes() []string {
 	if len(c.areas) == 0 {
 		return nil
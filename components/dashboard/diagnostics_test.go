@@ -113,3 +113,9 @@ func TestControllerDiagnosticsIncludeTypedPage(t *testing.T) {
 		t.Fatalf("expected controller diagnostics page snapshot to be cloned, got %+v", refreshed.Page.Areas[0].Widgets)
 	}
 }
+
+func TestClonePageKeepsTextDirection(t *testing.T) {
+	if got := clonePage(Page{Locale: "ar", Dir: "rtl"}).Dir; got != "rtl" {
+		t.Fatalf("expected cloned page to keep dir, got %q", got)
+	}
+}
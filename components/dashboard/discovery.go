@@ -6,6 +6,9 @@ type CatalogSnapshot struct {
 	Areas       []WidgetAreaDefinition `json:"areas,omitempty"`
 	Definitions []WidgetDefinition     `json:"definitions,omitempty"`
 	Providers   []ProviderDiscovery    `json:"providers,omitempty"`
+	// Index lists the widgets merged from catalog indexes (see
+	// Registry.MergeCatalogIndex), in merge order.
+	Index []CatalogEntry `json:"index,omitempty"`
 }
 
 // ProviderDiscovery captures stable provider/discovery metadata for a widget.
@@ -13,6 +16,7 @@ type ProviderDiscovery struct {
 	DefinitionCode string            `json:"definition_code"`
 	Registered     bool              `json:"registered"`
 	RuntimeBacked  bool              `json:"runtime_backed,omitempty"`
+	Version        string            `json:"version,omitempty"`
 	Manifest       *ManifestProvider `json:"manifest,omitempty"`
 }
 
@@ -32,6 +36,7 @@ func (r *Registry) Catalog() CatalogSnapshot {
 		Areas:       r.Areas(),
 		Definitions: definitions,
 		Providers:   providers,
+		Index:       r.catalogEntries(),
 	}
 }
 
@@ -54,6 +59,7 @@ func (r *Registry) ProviderDiscovery(code string) (ProviderDiscovery, bool) {
 		DefinitionCode: code,
 		Registered:     providerOK && provider != nil,
 		RuntimeBacked:  runtimeOK && runtime != nil,
+		Version:        r.versions[code],
 	}
 	if manifestOK {
 		cloned := cloneManifestProvider(manifest)
@@ -0,0 +1,233 @@
+package dashboard
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	DrillDownDashboard = "dashboard"
+	DrillDownURL       = "url"
+	DrillDownDetail    = "detail"
+
+	// InteractionSelect is the default interaction emitted when a viewer clicks
+	// a data point (chart bar, funnel step, cohort row).
+	InteractionSelect = "select"
+
+	// widgetInteractionsMetadataKey exposes the resolved interaction contract to
+	// templates and the browser runtime via widget metadata.
+	widgetInteractionsMetadataKey = "interactions"
+)
+
+var drillDownPlaceholder = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// WidgetInteractions declares how a widget reacts to viewer clicks.
+//
+// Publish maps dashboard filter names to datum fields: a select interaction on
+// the widget sets those filters for every widget that consumes them (see
+// WidgetDefinition.Variables), enabling cross-widget linking. DrillDown targets
+// navigate away or open an in-place detail view instead.
+type WidgetInteractions struct {
+	Publish   map[string]string `json:"publish,omitempty" yaml:"publish,omitempty"`
+	DrillDown []DrillDownTarget `json:"drilldown,omitempty" yaml:"drilldown,omitempty"`
+}
+
+// DrillDownTarget describes where an interaction leads.
+type DrillDownTarget struct {
+	// Event is the interaction name; empty matches InteractionSelect.
+	Event string `json:"event,omitempty" yaml:"event,omitempty"`
+	Kind  string `json:"kind" yaml:"kind"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Dashboard is the path of another dashboard; Filters maps its filter
+	// names to datum fields and are appended as `var-<name>` parameters.
+	Dashboard string            `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`
+	Filters   map[string]string `json:"filters,omitempty" yaml:"filters,omitempty"`
+	// URL is a template whose `{field}` placeholders are replaced with
+	// URL-escaped datum values.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// Validate checks the interaction contract for missing or unknown fields.
+func (i *WidgetInteractions) Validate() error {
+	if i == nil {
+		return nil
+	}
+	for name, field := range i.Publish {
+		if strings.TrimSpace(name) == "" || strings.TrimSpace(field) == "" {
+			return fmt.Errorf("dashboard: interaction publish entries require filter and field names")
+		}
+	}
+	for idx, target := range i.DrillDown {
+		if err := target.Validate(); err != nil {
+			return fmt.Errorf("dashboard: drilldown[%d]: %w", idx, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the target carries the fields its kind requires.
+func (t DrillDownTarget) Validate() error {
+	switch t.Kind {
+	case DrillDownDashboard:
+		if strings.TrimSpace(t.Dashboard) == "" {
+			return fmt.Errorf("dashboard target requires a dashboard path")
+		}
+		if !safeDrillDownURL(t.Dashboard) {
+			return fmt.Errorf("dashboard target must be an http(s) or relative url")
+		}
+	case DrillDownURL:
+		if strings.TrimSpace(t.URL) == "" {
+			return fmt.Errorf("url target requires a url template")
+		}
+		if !safeDrillDownURL(t.URL) {
+			return fmt.Errorf("url target must be an http(s) or relative url")
+		}
+	case DrillDownDetail:
+	default:
+		return fmt.Errorf("unsupported drilldown kind %q", t.Kind)
+	}
+	return nil
+}
+
+// Href resolves the navigation URL for a clicked datum. Detail targets render
+// in place and return an empty string, as do targets that are not http(s) or
+// relative URLs.
+func (t DrillDownTarget) Href(datum map[string]any) string {
+	if t.Kind != DrillDownDetail && t.Validate() != nil {
+		return ""
+	}
+	switch t.Kind {
+	case DrillDownURL:
+		return drillDownPlaceholder.ReplaceAllStringFunc(t.URL, func(match string) string {
+			field := drillDownPlaceholder.FindStringSubmatch(match)[1]
+			return url.PathEscape(datumString(datum, field))
+		})
+	case DrillDownDashboard:
+		if len(t.Filters) == 0 {
+			return t.Dashboard
+		}
+		query := url.Values{}
+		for _, name := range slices.Sorted(maps.Keys(t.Filters)) {
+			if value := datumString(datum, t.Filters[name]); value != "" {
+				query.Set(FilterQueryPrefix+name, value)
+			}
+		}
+		if len(query) == 0 {
+			return t.Dashboard
+		}
+		separator := "?"
+		if strings.Contains(t.Dashboard, "?") {
+			separator = "&"
+		}
+		return t.Dashboard + separator + query.Encode()
+	default:
+		return ""
+	}
+}
+
+// safeDrillDownURL reports whether raw is a relative URL or an absolute http
+// or https URL. Placeholders are blanked before parsing and control
+// characters are rejected outright, since browsers strip them when resolving
+// schemes ("java\tscript:").
+func safeDrillDownURL(raw string) bool {
+	raw = strings.TrimSpace(drillDownPlaceholder.ReplaceAllString(raw, "x"))
+	if raw == "" || strings.IndexFunc(raw, func(r rune) bool { return r < 0x20 || r == 0x7f }) >= 0 {
+		return false
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "":
+		return true
+	case "http", "https":
+		return parsed.Host != ""
+	default:
+		return false
+	}
+}
+
+func datumString(datum map[string]any, field string) string {
+	value, ok := datum[field]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// interactionsPayload builds the metadata exposed to templates: the declared
+// contract plus the filters the widget subscribes to.
+func interactionsPayload(def WidgetDefinition) map[string]any {
+	if def.Interactions == nil && len(def.Variables) == 0 {
+		return nil
+	}
+	payload := map[string]any{}
+	if len(def.Variables) > 0 {
+		payload["subscribes"] = slices.Clone(def.Variables)
+	}
+	if def.Interactions != nil {
+		if len(def.Interactions.Publish) > 0 {
+			payload["publish"] = maps.Clone(def.Interactions.Publish)
+		}
+		if len(def.Interactions.DrillDown) > 0 {
+			targets := make([]map[string]any, 0, len(def.Interactions.DrillDown))
+			for _, target := range def.Interactions.DrillDown {
+				event := target.Event
+				if event == "" {
+					event = InteractionSelect
+				}
+				entry := map[string]any{"event": event, "kind": target.Kind}
+				if target.Title != "" {
+					entry["title"] = target.Title
+				}
+				if target.Dashboard != "" {
+					entry["dashboard"] = target.Dashboard
+				}
+				if len(target.Filters) > 0 {
+					entry["filters"] = maps.Clone(target.Filters)
+				}
+				if target.URL != "" {
+					entry["url"] = target.URL
+				}
+				targets = append(targets, entry)
+			}
+			payload["drilldown"] = targets
+		}
+	}
+	return payload
+}
+
+// hasInteractiveWidget reports whether any widget publishes filters or
+// declares drill-down targets, i.e. whether the page needs the browser runtime.
+func hasInteractiveWidget(instances []WidgetInstance) bool {
+	for _, inst := range instances {
+		payload, ok := inst.Metadata[widgetInteractionsMetadataKey].(map[string]any)
+		if !ok {
+			continue
+		}
+		if payload["publish"] != nil || payload["drilldown"] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneWidgetInteractions(in *WidgetInteractions) *WidgetInteractions {
+	if in == nil {
+		return nil
+	}
+	out := &WidgetInteractions{Publish: cloneFilterValues(in.Publish)}
+	if len(in.DrillDown) > 0 {
+		out.DrillDown = make([]DrillDownTarget, len(in.DrillDown))
+		for i, target := range in.DrillDown {
+			target.Filters = cloneFilterValues(target.Filters)
+			out.DrillDown[i] = target
+		}
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDrillDownTargetHref(t *testing.T) {
+	datum := map[string]any{"label": "Sign up", "region": "emea", "value": 42}
+	cases := []struct {
+		target DrillDownTarget
+		want   string
+	}{
+		{DrillDownTarget{Kind: DrillDownURL, URL: "/reports/{label}/{value}"}, "/reports/Sign%20up/42"},
+		{DrillDownTarget{Kind: DrillDownDashboard, Dashboard: "/admin/cohorts", Filters: map[string]string{"step": "label", "region": "region"}}, "/admin/cohorts?var-region=emea&var-step=Sign+up"},
+		{DrillDownTarget{Kind: DrillDownDashboard, Dashboard: "/admin/cohorts?tab=1", Filters: map[string]string{"region": "region"}}, "/admin/cohorts?tab=1&var-region=emea"},
+		{DrillDownTarget{Kind: DrillDownDetail}, ""},
+		{DrillDownTarget{Kind: DrillDownURL, URL: "https://example.com/{label}"}, "https://example.com/Sign%20up"},
+		{DrillDownTarget{Kind: DrillDownURL, URL: "javascript:alert({label})"}, ""},
+		{DrillDownTarget{Kind: DrillDownDashboard, Dashboard: "data:text/html,hi"}, ""},
+	}
+	for _, tc := range cases {
+		if got := tc.target.Href(datum); got != tc.want {
+			t.Fatalf("expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestRegisterDefinitionValidatesInteractions(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.RegisterDefinition(WidgetDefinition{
+		Code:         "custom.widget.links",
+		Interactions: &WidgetInteractions{DrillDown: []DrillDownTarget{{Kind: DrillDownURL}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "url template") {
+		t.Fatalf("expected url target validation error, got %v", err)
+	}
+	err = registry.RegisterDefinition(WidgetDefinition{
+		Code:         "custom.widget.links",
+		Interactions: &WidgetInteractions{DrillDown: []DrillDownTarget{{Kind: "popup"}}},
+	})
+	if err == nil {
+		t.Fatalf("expected unsupported kind error")
+	}
+	for _, raw := range []string{"javascript:alert(1)", " JavaScript:alert(1)", "java\tscript:alert(1)", "vbscript:msgbox"} {
+		err = registry.RegisterDefinition(WidgetDefinition{
+			Code:         "custom.widget.links",
+			Interactions: &WidgetInteractions{DrillDown: []DrillDownTarget{{Kind: DrillDownURL, URL: raw}}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "http(s) or relative") {
+			t.Fatalf("expected %q to be rejected, got %v", raw, err)
+		}
+	}
+}
+
+func TestConfigureLayoutAttachesInteractionMetadata(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolveAreaFn: func(input ResolveAreaInput) (ResolvedArea, error) {
+			return ResolvedArea{
+				AreaCode: input.AreaCode,
+				Widgets: []WidgetInstance{
+					{ID: "funnel", DefinitionID: "admin.widget.analytics_funnel"},
+					{ID: "cohort", DefinitionID: "admin.widget.cohort_overview"},
+				},
+			}, nil
+		},
+	}
+	registry := NewRegistry()
+	service := NewService(Options{
+		WidgetStore: store,
+		Providers:   registry,
+		Areas:       []string{"admin.dashboard.main"},
+	})
+	layout, err := service.ConfigureLayout(context.Background(), ViewerContext{})
+	if err != nil {
+		t.Fatalf("ConfigureLayout returned error: %v", err)
+	}
+	widgets := layout.Areas["admin.dashboard.main"]
+	funnel, _ := widgets[0].Metadata[widgetInteractionsMetadataKey].(map[string]any)
+	publish, _ := funnel["publish"].(map[string]string)
+	if publish["funnel_step"] != "label" {
+		t.Fatalf("expected funnel to publish funnel_step, got %+v", funnel)
+	}
+	cohort, _ := widgets[1].Metadata[widgetInteractionsMetadataKey].(map[string]any)
+	subscribes, _ := cohort["subscribes"].([]string)
+	if !slices.Contains(subscribes, "funnel_step") {
+		t.Fatalf("expected cohort to subscribe to funnel_step, got %+v", cohort)
+	}
+
+	controller := NewController(ControllerOptions{Service: &stubLayoutResolver{layout: layout}})
+	page, err := controller.Page(context.Background(), ViewerContext{})
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if page.Assets == nil || !slices.Contains(page.Assets.JS, InteractionsScriptURL("")) {
+		t.Fatalf("expected interaction runtime in page assets, got %+v", page.Assets)
+	}
+
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `data-widget-interactions="{&quot;publish&quot;`) {
+		t.Fatalf("expected interaction contract on widget section, got %s", out)
+	}
+	if !strings.Contains(out, `data-datum-label=`) {
+		t.Fatalf("expected funnel steps to expose datum attributes, got %s", out)
+	}
+}
@@ -0,0 +1,244 @@
+package dashboard
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Default endpoints used by the edit mode runtime. They match the go-router
+// adapter defaults under the `/admin` base path.
+const (
+	DefaultPreferencesPath      = "/admin/dashboard/preferences"
+	DefaultDefinitionConfigPath = "/admin/dashboard/definitions/:code/config"
+	DefaultWidgetConfigPath     = "/admin/dashboard/widgets/:id/config"
+	// EditModeQueryParam switches the page into edit mode (`?edit=1`).
+	EditModeQueryParam = "edit"
+)
+
+// EditModeOptions enables the in-page layout editor. Paths default to the
+// go-router adapter routes; set them when BasePath or RouteConfig change.
+type EditModeOptions struct {
+	PreferencesPath      string
+	DefinitionConfigPath string
+	WidgetConfigPath     string
+	// DisableCatalog hides "add widget" from the editor.
+	DisableCatalog bool
+}
+
+// PageEdit describes the layout editor for the page. When Active is false the
+// page only renders the control that enters edit mode.
+type PageEdit struct {
+	Active              bool               `json:"active"`
+	PreferencesURL      string             `json:"preferences_url,omitempty"`
+	DefinitionConfigURL string             `json:"definition_config_url,omitempty"`
+	WidgetConfigURL     string             `json:"widget_config_url,omitempty"`
+	Catalog             []PageCatalogEntry `json:"catalog,omitempty"`
+}
+
+// PageCatalogEntry is a widget definition offered by the editor's catalog.
+type PageCatalogEntry struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+}
+
+// WidgetCatalog lists the definitions a viewer may add in edit mode.
+type WidgetCatalog interface {
+	WidgetCatalog(ctx context.Context, viewer ViewerContext) ([]WidgetDefinition, error)
+}
+
+// EditModeGate decides whether a viewer asking for edit mode gets it.
+// Controllers whose service does not implement it trust viewer.Editing.
+type EditModeGate interface {
+	CanEditLayout(ctx context.Context, viewer ViewerContext) bool
+}
+
+var (
+	_ WidgetCatalog = (*Service)(nil)
+	_ EditModeGate  = (*Service)(nil)
+)
+
+// WidgetCatalog returns the registered widget definitions the viewer may use.
+func (s *Service) WidgetCatalog(ctx context.Context, viewer ViewerContext) ([]WidgetDefinition, error) {
+	defs := s.opts.Providers.Definitions()
+	auth, ok := s.opts.Authorizer.(CatalogAuthorizer)
+	if !ok {
+		return defs, nil
+	}
+	return slices.DeleteFunc(defs, func(def WidgetDefinition) bool {
+		return !auth.CanUseDefinition(ctx, viewer, def)
+	}), nil
+}
+
+// CanEditLayout asks the authorizer whether viewer may enter edit mode.
+// Authorizers without EditAuthorizer allow any identified viewer, since
+// layout preferences are stored per user.
+func (s *Service) CanEditLayout(ctx context.Context, viewer ViewerContext) bool {
+	if auth, ok := s.opts.Authorizer.(EditAuthorizer); ok {
+		return auth.CanEditLayout(ctx, viewer)
+	}
+	return viewer.UserID != ""
+}
+
+// editingViewer clears viewer.Editing unless edit mode is enabled and the
+// service lets the viewer edit.
+func (c *Controller) editingViewer(ctx context.Context, viewer ViewerContext) ViewerContext {
+	if !viewer.Editing {
+		return viewer
+	}
+	if c.edit == nil {
+		viewer.Editing = false
+		return viewer
+	}
+	if gate, ok := c.service.(EditModeGate); ok {
+		viewer.Editing = gate.CanEditLayout(ctx, viewer)
+	}
+	return viewer
+}
+
+// EditScriptURL returns the URL of the layout editor runtime served alongside
+// the shell assets.
+func EditScriptURL(host string) string {
+	if host == "" {
+		host = DefaultShellAssetsHost()
+	}
+	return ensureTrailingSlash(host) + "edit.js"
+}
+
+// EditStylesURL returns the URL of the layout editor styles.
+func EditStylesURL(host string) string {
+	if host == "" {
+		host = DefaultShellAssetsHost()
+	}
+	return ensureTrailingSlash(host) + "edit.css"
+}
+
+func normalizeEditModeOptions(opts *EditModeOptions) *EditModeOptions {
+	if opts == nil {
+		return nil
+	}
+	out := *opts
+	if out.PreferencesPath == "" {
+		out.PreferencesPath = DefaultPreferencesPath
+	}
+	if out.DefinitionConfigPath == "" {
+		out.DefinitionConfigPath = DefaultDefinitionConfigPath
+	}
+	if out.WidgetConfigPath == "" {
+		out.WidgetConfigPath = DefaultWidgetConfigPath
+	}
+	return &out
+}
+
+// pageEdit builds the editor description for viewer. The catalog is only
+// resolved while editing.
+func (c *Controller) pageEdit(ctx context.Context, viewer ViewerContext) (*PageEdit, error) {
+	if c.edit == nil {
+		return nil, nil
+	}
+	edit := &PageEdit{
+		Active:          viewer.Editing,
+		PreferencesURL:  c.edit.PreferencesPath,
+		WidgetConfigURL: c.edit.WidgetConfigPath,
+	}
+	if !viewer.Editing || c.edit.DisableCatalog {
+		return edit, nil
+	}
+	catalog, ok := c.service.(WidgetCatalog)
+	if !ok {
+		return edit, nil
+	}
+	defs, err := catalog.WidgetCatalog(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	edit.DefinitionConfigURL = c.edit.DefinitionConfigPath
+	edit.Catalog = pageCatalog(defs, viewer.Locale)
+	return edit, nil
+}
+
+func pageCatalog(defs []WidgetDefinition, locale string) []PageCatalogEntry {
+	entries := make([]PageCatalogEntry, 0, len(defs))
+	for _, def := range defs {
+		if def.Code == "" {
+			continue
+		}
+		entries = append(entries, PageCatalogEntry{
+			Code:        def.Code,
+			Name:        def.NameForLocale(locale),
+			Description: def.DescriptionForLocale(locale),
+			Category:    def.Category,
+		})
+	}
+	slices.SortStableFunc(entries, func(a, b PageCatalogEntry) int {
+		if a.Category != b.Category {
+			return strings.Compare(a.Category, b.Category)
+		}
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	if len(entries) == 0 {
+		return nil
+	}
+	return entries
+}
+
+// markHiddenWidgets keeps hidden widgets in the layout for the editor and
+// flags them through the `hidden` metadata key instead of dropping them.
+func markHiddenWidgets(widgets []WidgetInstance, hidden map[string]bool) []WidgetInstance {
+	if len(widgets) == 0 || len(hidden) == 0 {
+		return widgets
+	}
+	for i, w := range widgets {
+		if !hidden[w.ID] {
+			continue
+		}
+		metadata := maps.Clone(w.Metadata)
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		metadata["hidden"] = true
+		widgets[i].Metadata = metadata
+	}
+	return widgets
+}
+
+func (edit *PageEdit) legacyPayload() map[string]any {
+	if edit == nil {
+		return nil
+	}
+	payload := map[string]any{
+		"active":          edit.Active,
+		"preferences_url": edit.PreferencesURL,
+	}
+	if edit.DefinitionConfigURL != "" {
+		payload["definition_config_url"] = edit.DefinitionConfigURL
+	}
+	if edit.WidgetConfigURL != "" {
+		payload["widget_config_url"] = edit.WidgetConfigURL
+	}
+	if len(edit.Catalog) > 0 {
+		catalog := make([]map[string]any, 0, len(edit.Catalog))
+		for _, entry := range edit.Catalog {
+			catalog = append(catalog, map[string]any{
+				"code":        entry.Code,
+				"name":        entry.Name,
+				"description": entry.Description,
+				"category":    entry.Category,
+			})
+		}
+		payload["catalog"] = catalog
+	}
+	return payload
+}
+
+func clonePageEdit(edit *PageEdit) *PageEdit {
+	if edit == nil {
+		return nil
+	}
+	out := *edit
+	out.Catalog = slices.Clone(edit.Catalog)
+	return &out
+}
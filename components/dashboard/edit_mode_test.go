@@ -0,0 +1,249 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestControllerPageEditModeKeepsHiddenWidgets(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolveAreaFn: func(input ResolveAreaInput) (ResolvedArea, error) {
+			return ResolvedArea{
+				AreaCode: input.AreaCode,
+				Widgets: []WidgetInstance{
+					{ID: "funnel", DefinitionID: "admin.widget.analytics_funnel"},
+					{ID: "cohort", DefinitionID: "admin.widget.cohort_overview"},
+				},
+			}, nil
+		},
+	}
+	prefs := NewInMemoryPreferenceStore()
+	viewer := ViewerContext{UserID: "editor", Locale: "en"}
+	_ = prefs.SaveLayoutOverrides(context.Background(), viewer, LayoutOverrides{
+		HiddenWidgets: map[string]bool{"cohort": true},
+	})
+	service := NewService(Options{
+		WidgetStore:     store,
+		PreferenceStore: prefs,
+		Areas:           []string{"admin.dashboard.main"},
+	})
+	controller := NewController(ControllerOptions{Service: service, EditMode: &EditModeOptions{}})
+
+	page, err := controller.Page(context.Background(), viewer)
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if page.Edit == nil || page.Edit.Active || len(page.Edit.Catalog) != 0 {
+		t.Fatalf("expected inactive editor without catalog, got %+v", page.Edit)
+	}
+	if widgets := page.Areas[0].Widgets; len(widgets) != 1 || widgets[0].ID != "funnel" {
+		t.Fatalf("expected hidden widget filtered outside edit mode, got %+v", widgets)
+	}
+	if page.Assets != nil && slices.Contains(page.Assets.JS, EditScriptURL("")) {
+		t.Fatalf("expected editor runtime only while editing, got %+v", page.Assets)
+	}
+
+	viewer.Editing = true
+	page, err = controller.Page(context.Background(), viewer)
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if !page.Edit.Active || page.Edit.PreferencesURL != DefaultPreferencesPath || page.Edit.DefinitionConfigURL != DefaultDefinitionConfigPath {
+		t.Fatalf("expected active editor with default endpoints, got %+v", page.Edit)
+	}
+	if !slices.ContainsFunc(page.Edit.Catalog, func(entry PageCatalogEntry) bool { return entry.Code == "admin.widget.analytics_funnel" }) {
+		t.Fatalf("expected registered definitions in catalog, got %+v", page.Edit.Catalog)
+	}
+	widgets := page.Areas[0].Widgets
+	if len(widgets) != 2 || widgets[0].Hidden || !widgets[1].Hidden {
+		t.Fatalf("expected hidden widget kept and flagged while editing, got %+v", widgets)
+	}
+	if !slices.Contains(page.Assets.JS, EditScriptURL("")) || !slices.Contains(page.Assets.CSS, EditStylesURL("")) {
+		t.Fatalf("expected editor assets, got %+v", page.Assets)
+	}
+
+	plain := NewController(ControllerOptions{Service: service})
+	if page, err := plain.Page(context.Background(), viewer); err != nil || page.Edit != nil {
+		t.Fatalf("expected no editor without EditMode, got %+v (%v)", page.Edit, err)
+	}
+}
+
+func TestTemplateRendersEditControls(t *testing.T) {
+	layout := Layout{Areas: map[string][]WidgetInstance{
+		"admin.dashboard.main": {
+			{ID: "w1", DefinitionID: "admin.widget.user_stats", Metadata: map[string]any{
+				"data":   WidgetData{"value": 1},
+				"layout": map[string]any{"row": 0, "width": 6},
+			}},
+			{ID: "w2", DefinitionID: "admin.widget.user_stats", Metadata: map[string]any{
+				"data":   WidgetData{"value": 2},
+				"hidden": true,
+			}},
+		},
+	}}
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	controller := NewController(ControllerOptions{
+		Service:  &stubLayoutResolver{layout: layout},
+		Renderer: renderer,
+		EditMode: &EditModeOptions{PreferencesPath: "/app/dashboard/preferences"},
+	})
+
+	var buf bytes.Buffer
+	if err := controller.RenderPage(context.Background(), ViewerContext{Editing: true}, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`data-dashboard-edit="{&quot;active&quot;:true`,
+		`/app/dashboard/preferences`,
+		`data-widget="w1" data-widget-definition="admin.widget.user_stats" data-widget-span="6" data-widget-row="0"`,
+		`data-widget-hidden="true"`,
+		`tabindex="0" draggable="true" aria-describedby="dashboard-edit-help"`,
+		`data-edit-action="move-next"`,
+		`data-edit-action="configure"`,
+		`data-edit-save`,
+		`id="dashboard-edit-help"`,
+		`data-edit-status role="status"`,
+		EditScriptURL(""),
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in edit mode page:\n%s", want, html)
+		}
+	}
+
+	buf.Reset()
+	if err := controller.RenderPage(context.Background(), ViewerContext{}, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	html = buf.String()
+	if !strings.Contains(html, `href="?edit=1"`) || strings.Contains(html, "data-edit-action") || strings.Contains(html, "data-dashboard-edit") {
+		t.Fatalf("expected only the enter link outside edit mode:\n%s", html)
+	}
+}
+
+type editorAuthorizer struct {
+	editors     map[string]bool
+	definitions map[string]bool
+}
+
+func (editorAuthorizer) CanViewWidget(context.Context, ViewerContext, WidgetInstance) bool {
+	return true
+}
+
+func (a editorAuthorizer) CanEditLayout(_ context.Context, viewer ViewerContext) bool {
+	return a.editors[viewer.UserID]
+}
+
+func (a editorAuthorizer) CanConfigureWidget(_ context.Context, viewer ViewerContext, _ WidgetInstance) bool {
+	return a.editors[viewer.UserID]
+}
+
+func (a editorAuthorizer) CanUseDefinition(_ context.Context, _ ViewerContext, def WidgetDefinition) bool {
+	return a.definitions[def.Code]
+}
+
+func (a editorAuthorizer) CanBrowseCatalogChannel(_ context.Context, viewer ViewerContext, _ string) bool {
+	return a.editors[viewer.UserID]
+}
+
+func TestEditModeRequiresAuthorizedViewer(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolveAreaFn: func(input ResolveAreaInput) (ResolvedArea, error) {
+			return ResolvedArea{AreaCode: input.AreaCode, Widgets: []WidgetInstance{
+				{ID: "funnel", DefinitionID: "admin.widget.analytics_funnel"},
+				{ID: "cohort", DefinitionID: "admin.widget.cohort_overview"},
+			}}, nil
+		},
+	}
+	prefs := NewInMemoryPreferenceStore()
+	hide := LayoutOverrides{HiddenWidgets: map[string]bool{"cohort": true}}
+	for _, user := range []string{"viewer", "editor"} {
+		_ = prefs.SaveLayoutOverrides(context.Background(), ViewerContext{UserID: user}, hide)
+	}
+	newController := func(auth Authorizer) *Controller {
+		service := NewService(Options{
+			WidgetStore:     store,
+			PreferenceStore: prefs,
+			Authorizer:      auth,
+			Areas:           []string{"admin.dashboard.main"},
+		})
+		return NewController(ControllerOptions{Service: service, EditMode: &EditModeOptions{}})
+	}
+
+	page, err := newController(nil).Page(context.Background(), ViewerContext{Editing: true})
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if page.Edit.Active {
+		t.Fatalf("expected anonymous viewers to be refused edit mode, got %+v", page.Edit)
+	}
+
+	controller := newController(editorAuthorizer{
+		editors:     map[string]bool{"editor": true},
+		definitions: map[string]bool{"admin.widget.user_stats": true},
+	})
+	page, err = controller.Page(context.Background(), ViewerContext{UserID: "viewer", Editing: true})
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if page.Edit.Active || len(page.Edit.Catalog) != 0 || len(page.Areas[0].Widgets) != 1 {
+		t.Fatalf("expected authorizer to refuse edit mode, got %+v", page.Edit)
+	}
+	page, err = controller.Page(context.Background(), ViewerContext{UserID: "editor", Editing: true})
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if !page.Edit.Active || len(page.Areas[0].Widgets) != 2 {
+		t.Fatalf("expected editor to enter edit mode, got %+v", page.Edit)
+	}
+	if len(page.Edit.Catalog) != 1 || page.Edit.Catalog[0].Code != "admin.widget.user_stats" {
+		t.Fatalf("expected catalog filtered by the authorizer, got %+v", page.Edit.Catalog)
+	}
+}
+
+func TestEditorSaveKeepsFiltersAndBreakpointLayout(t *testing.T) {
+	prefs := NewInMemoryPreferenceStore()
+	service := NewService(Options{PreferenceStore: prefs})
+	viewer := ViewerContext{UserID: "editor"}
+	ctx := context.Background()
+	if err := service.SavePreferences(ctx, viewer, LayoutOverrides{
+		Filters:         map[string]string{"region": "emea"},
+		BreakpointOrder: map[string]map[Breakpoint][]string{"admin.dashboard.main": {BreakpointSM: {"w2", "w1"}}},
+	}); err != nil {
+		t.Fatalf("SavePreferences returned error: %v", err)
+	}
+
+	// Body shaped like edit.js preferencesPayload.
+	body := `{
+		"area_order": {"admin.dashboard.main": ["w1", "w2"]},
+		"layout_rows": {"admin.dashboard.main": [{"widgets": [{"id": "w1", "width": 6, "widths": {"sm": 12, "lg": 4}}, {"id": "w2", "width": 6}]}]},
+		"hidden_widget_ids": []
+	}`
+	var input SaveLayoutPreferencesInput
+	if err := json.Unmarshal([]byte(body), &input); err != nil {
+		t.Fatalf("decode editor payload: %v", err)
+	}
+	input.Viewer = viewer
+	if err := NewServiceExecutor(service).Preferences(ctx, input); err != nil {
+		t.Fatalf("Preferences returned error: %v", err)
+	}
+
+	stored, err := prefs.LayoutOverrides(ctx, viewer)
+	if err != nil {
+		t.Fatalf("LayoutOverrides returned error: %v", err)
+	}
+	if stored.Filters["region"] != "emea" || len(stored.BreakpointOrder["admin.dashboard.main"][BreakpointSM]) != 2 {
+		t.Fatalf("expected editor save to keep filters and breakpoint order, got %+v", stored)
+	}
+	rows := stored.AreaRows["admin.dashboard.main"]
+	if len(rows) != 1 || rows[0].Widgets[0].Widths[BreakpointLG] != 4 {
+		t.Fatalf("expected posted breakpoint widths to be saved, got %+v", rows)
+	}
+}
@@ -0,0 +1,199 @@
+package dashboard
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// FilterQueryPrefix prefixes dashboard filter values in URLs, e.g.
+// `?var-region=emea`.
+const FilterQueryPrefix = "var-"
+
+// DashboardFilter declares a named dashboard-level variable (region, product,
+// tenant segment) that widgets can opt into via WidgetDefinition.Variables.
+type DashboardFilter struct {
+	Name    string         `json:"name" yaml:"name"`
+	Label   string         `json:"label,omitempty" yaml:"label,omitempty"`
+	Options []FilterOption `json:"options,omitempty" yaml:"options,omitempty"`
+	Default string         `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// FilterOption is a selectable value for a DashboardFilter. An empty option
+// list accepts any value.
+type FilterOption struct {
+	Value string `json:"value" yaml:"value"`
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+// PageFilter is a declared filter paired with the value selected for the
+// current viewer.
+type PageFilter struct {
+	Name    string         `json:"name"`
+	Label   string         `json:"label,omitempty"`
+	Options []FilterOption `json:"options,omitempty"`
+	Value   string         `json:"value,omitempty"`
+}
+
+// Accepts reports whether value is allowed by the filter options.
+func (f DashboardFilter) Accepts(value string) bool {
+	if len(f.Options) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(f.Options, func(opt FilterOption) bool {
+		return opt.Value == value
+	})
+}
+
+// ParseFilterValues extracts prefixed filter values from query parameters.
+func ParseFilterValues(query map[string]string) map[string]string {
+	var values map[string]string
+	for key, value := range query {
+		name, ok := strings.CutPrefix(key, FilterQueryPrefix)
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		if values == nil {
+			values = map[string]string{}
+		}
+		values[name] = strings.TrimSpace(value)
+	}
+	return values
+}
+
+// resolveFilterValues merges URL selections over persisted preferences and
+// declared defaults, dropping values the filter does not accept.
+func resolveFilterValues(filters []DashboardFilter, viewer, saved map[string]string) map[string]string {
+	if len(filters) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		candidates := []string{saved[filter.Name], filter.Default}
+		if selected, ok := viewer[filter.Name]; ok {
+			// An explicit empty selection clears the filter ("All").
+			if selected == "" {
+				continue
+			}
+			candidates = append([]string{selected}, candidates...)
+		}
+		for _, candidate := range candidates {
+			if candidate != "" && filter.Accepts(candidate) {
+				values[filter.Name] = candidate
+				break
+			}
+		}
+	}
+	return values
+}
+
+// filterValues resolves the declared filters and adds the URL selections of
+// variables published by widget interactions (such as the funnel's
+// `funnel_step`), so subscribers receive them without a declared filter.
+// Published selections are never persisted.
+func (s *Service) filterValues(viewer, saved map[string]string) map[string]string {
+	values := resolveFilterValues(s.opts.Filters, viewer, saved)
+	if len(viewer) == 0 || s.opts.Providers == nil {
+		return values
+	}
+	for _, def := range s.opts.Providers.Definitions() {
+		if def.Interactions == nil {
+			continue
+		}
+		for name := range def.Interactions.Publish {
+			if slices.ContainsFunc(s.opts.Filters, func(filter DashboardFilter) bool { return filter.Name == name }) {
+				continue
+			}
+			if value := strings.TrimSpace(viewer[name]); value != "" {
+				if values == nil {
+					values = map[string]string{}
+				}
+				values[name] = value
+			}
+		}
+	}
+	return values
+}
+
+// widgetFilterValues narrows the dashboard values to the variables the widget
+// definition consumes.
+func widgetFilterValues(def WidgetDefinition, values map[string]string) map[string]string {
+	if len(def.Variables) == 0 || len(values) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for _, name := range def.Variables {
+		if value, ok := values[name]; ok && value != "" {
+			out[name] = value
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func pageFilters(filters []DashboardFilter, values map[string]string) []PageFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	out := make([]PageFilter, 0, len(filters))
+	for _, filter := range filters {
+		out = append(out, PageFilter{
+			Name:    filter.Name,
+			Label:   filter.Label,
+			Options: append([]FilterOption{}, filter.Options...),
+			Value:   values[filter.Name],
+		})
+	}
+	return out
+}
+
+func clonePageFilters(filters []PageFilter) []PageFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	out := make([]PageFilter, len(filters))
+	for i, filter := range filters {
+		filter.Options = append([]FilterOption{}, filter.Options...)
+		out[i] = filter
+	}
+	return out
+}
+
+func cloneFilterValues(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	return maps.Clone(values)
+}
+
+func filtersPayload(filters []PageFilter) []map[string]any {
+	if len(filters) == 0 {
+		return nil
+	}
+	out := make([]map[string]any, 0, len(filters))
+	for _, filter := range filters {
+		options := make([]map[string]any, 0, len(filter.Options))
+		for _, opt := range filter.Options {
+			label := opt.Label
+			if label == "" {
+				label = opt.Value
+			}
+			options = append(options, map[string]any{"value": opt.Value, "label": label})
+		}
+		label := filter.Label
+		if label == "" {
+			label = filter.Name
+		}
+		out = append(out, map[string]any{
+			"name":    filter.Name,
+			"param":   FilterQueryPrefix + filter.Name,
+			"label":   label,
+			"options": options,
+			"value":   filter.Value,
+		})
+	}
+	return out
+}
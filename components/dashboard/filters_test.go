@@ -0,0 +1,138 @@
+package dashboard
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilterValuesUsesPrefix(t *testing.T) {
+	values := ParseFilterValues(map[string]string{
+		"var-region": " emea ",
+		"var-":       "ignored",
+		"range":      "7d",
+	})
+	if !reflect.DeepEqual(values, map[string]string{"region": "emea"}) {
+		t.Fatalf("unexpected filter values %+v", values)
+	}
+}
+
+func TestResolveFilterValuesPrecedence(t *testing.T) {
+	filters := []DashboardFilter{
+		{Name: "region", Options: []FilterOption{{Value: "emea"}, {Value: "apac"}}, Default: "emea"},
+		{Name: "product"},
+		{Name: "segment", Default: "smb"},
+	}
+	values := resolveFilterValues(filters,
+		map[string]string{"region": "mars", "segment": ""},
+		map[string]string{"region": "apac", "product": "pro", "segment": "enterprise"},
+	)
+	want := map[string]string{"region": "apac", "product": "pro"}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("expected %+v, got %+v", want, values)
+	}
+}
+
+func TestConfigureLayoutInjectsDeclaredFilters(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolveAreaFn: func(input ResolveAreaInput) (ResolvedArea, error) {
+			return ResolvedArea{
+				AreaCode: input.AreaCode,
+				Widgets: []WidgetInstance{
+					{ID: "scoped", DefinitionID: "custom.widget.scoped"},
+					{ID: "global", DefinitionID: "custom.widget.global"},
+				},
+			}, nil
+		},
+	}
+	registry := NewRegistry()
+	received := map[string]map[string]string{}
+	provider := ProviderFunc(func(ctx context.Context, meta WidgetContext) (WidgetData, error) {
+		received[meta.Instance.ID] = meta.Filters
+		return WidgetData{}, nil
+	})
+	for _, def := range []WidgetDefinition{
+		{Code: "custom.widget.scoped", Variables: []string{"region"}},
+		{Code: "custom.widget.global"},
+	} {
+		if err := registry.RegisterDefinition(def); err != nil {
+			t.Fatalf("RegisterDefinition returned error: %v", err)
+		}
+		if err := registry.RegisterProvider(def.Code, provider); err != nil {
+			t.Fatalf("RegisterProvider returned error: %v", err)
+		}
+	}
+	prefs := NewInMemoryPreferenceStore()
+	viewer := ViewerContext{UserID: "user-filters"}
+	if err := prefs.SaveLayoutOverrides(context.Background(), viewer, LayoutOverrides{
+		Filters: map[string]string{"region": "apac", "product": "pro"},
+	}); err != nil {
+		t.Fatalf("SaveLayoutOverrides returned error: %v", err)
+	}
+	service := NewService(Options{
+		WidgetStore:     store,
+		Providers:       registry,
+		PreferenceStore: prefs,
+		Areas:           []string{"admin.dashboard.main"},
+		Filters: []DashboardFilter{
+			{Name: "region", Label: "Region", Options: []FilterOption{{Value: "emea"}, {Value: "apac"}}},
+			{Name: "product"},
+		},
+	})
+	layout, err := service.ConfigureLayout(context.Background(), viewer)
+	if err != nil {
+		t.Fatalf("ConfigureLayout returned error: %v", err)
+	}
+	if !reflect.DeepEqual(received["scoped"], map[string]string{"region": "apac"}) {
+		t.Fatalf("expected scoped widget to receive region only, got %+v", received["scoped"])
+	}
+	if received["global"] != nil {
+		t.Fatalf("expected widget without variables to receive no filters, got %+v", received["global"])
+	}
+	if len(layout.Filters) != 2 || layout.Filters[0].Value != "apac" || layout.Filters[1].Value != "pro" {
+		t.Fatalf("expected layout to expose resolved filters, got %+v", layout.Filters)
+	}
+
+	viewer.Filters = map[string]string{"region": "emea"}
+	if _, err := service.ConfigureLayout(context.Background(), viewer); err != nil {
+		t.Fatalf("ConfigureLayout returned error: %v", err)
+	}
+	if received["scoped"]["region"] != "emea" {
+		t.Fatalf("expected URL selection to win over preferences, got %+v", received["scoped"])
+	}
+}
+
+func TestDefaultOptionsPassPublishedVariablesToSubscribers(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolveAreaFn: func(input ResolveAreaInput) (ResolvedArea, error) {
+			return ResolvedArea{AreaCode: input.AreaCode, Widgets: []WidgetInstance{
+				{ID: "cohort", DefinitionID: "admin.widget.cohort_overview"},
+			}}, nil
+		},
+	}
+	registry := NewRegistry()
+	var received map[string]string
+	if err := registry.RegisterProvider("admin.widget.cohort_overview", ProviderFunc(func(_ context.Context, meta WidgetContext) (WidgetData, error) {
+		received = meta.Filters
+		return WidgetData{}, nil
+	})); err != nil {
+		t.Fatalf("RegisterProvider returned error: %v", err)
+	}
+	prefs := NewInMemoryPreferenceStore()
+	service := NewService(Options{WidgetStore: store, Providers: registry, PreferenceStore: prefs, Areas: []string{"admin.dashboard.main"}})
+	viewer := ViewerContext{UserID: "user-1", Filters: ParseFilterValues(map[string]string{"var-funnel_step": "Activated", "var-unknown": "x"})}
+
+	layout, err := service.ConfigureLayout(context.Background(), viewer)
+	if err != nil {
+		t.Fatalf("ConfigureLayout returned error: %v", err)
+	}
+	if !reflect.DeepEqual(received, map[string]string{"funnel_step": "Activated"}) {
+		t.Fatalf("expected cohort widget to receive the published funnel step, got %+v", received)
+	}
+	if len(layout.Filters) != 0 {
+		t.Fatalf("expected published variables to stay out of the filter toolbar, got %+v", layout.Filters)
+	}
+	if stored, _ := prefs.LayoutOverrides(context.Background(), viewer); len(stored.Filters) != 0 {
+		t.Fatalf("expected published variables not to be persisted, got %+v", stored.Filters)
+	}
+}
@@ -0,0 +1,739 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter renders numbers, dates, relative times and ICU MessageFormat
+// messages for a single locale. WidgetContext.Formatter carries one bound to
+// the viewer's locale; hosts can plug in their own implementation (for
+// example one backed by go-i18n or full CLDR data) through Options.Formatter.
+type Formatter interface {
+	// Locale reports the locale the formatter is bound to.
+	Locale() string
+	// Number groups digits and uses the locale decimal separator. A negative
+	// decimals value keeps the shortest representation.
+	Number(value float64, decimals int) string
+	// Currency formats an amount in the ISO 4217 currency code. A negative
+	// decimals value defaults to two.
+	Currency(value float64, currency string, decimals int) string
+	// Percent formats percentage points: 12.5 renders as "12.5%".
+	Percent(value float64, decimals int) string
+	// Compact abbreviates large values ("1.2K", "3.4M").
+	Compact(value float64) string
+	Date(t time.Time) string
+	DateTime(t time.Time) string
+	// RelativeTime describes how long ago d was ("5 minutes ago"); negative
+	// durations are in the future ("in 5 minutes").
+	RelativeTime(d time.Duration) string
+	// Message formats an ICU MessageFormat pattern such as
+	// `{count, plural, =0 {no rows} one {# row} other {# rows}}`. Simple
+	// `{name}` arguments, `number`/`date` arguments, `plural` (with `offset:`
+	// and `=N` exact matches) and `select` are supported.
+	Message(pattern string, args map[string]any) string
+}
+
+// NewLocaleFormatter returns the built-in Formatter for locale. It ships rules
+// for English, Spanish, French, German, Italian, Portuguese and Dutch and
+// falls back to English number and date conventions for other locales while
+// still applying their plural rules.
+func NewLocaleFormatter(locale string) Formatter {
+	locale = normalizeLocale(locale)
+	lang := locale
+	if idx := strings.Index(lang, "-"); idx > 0 {
+		lang = lang[:idx]
+	}
+	rules := localeFormatRules["en"]
+	for _, candidate := range localeCandidates(locale) {
+		if found, ok := localeFormatRules[candidate]; ok {
+			rules = found
+			break
+		}
+	}
+	return localeFormatter{locale: locale, lang: lang, rules: rules}
+}
+
+// formatterOrDefault returns f, or the built-in formatter for locale when the
+// widget context was built without one.
+func formatterOrDefault(f Formatter, locale string) Formatter {
+	if f != nil {
+		return f
+	}
+	return NewLocaleFormatter(locale)
+}
+
+// Number formats understood by formatNumber, shared by the KPI `format` and
+// table column `type` settings.
+const (
+	numberFormatCurrency = "currency"
+	numberFormatPercent  = "percent"
+	numberFormatDuration = "duration"
+	numberFormatCompact  = "compact"
+)
+
+// numberFormat describes how a widget displays a configured numeric value.
+type numberFormat struct {
+	Kind     string
+	Currency string
+	Unit     string
+	Decimals *int
+}
+
+// formatNumber renders value through f according to format. Unset decimals
+// default to two for currencies, one for percentages and zero otherwise;
+// durations are read as seconds.
+func formatNumber(f Formatter, value float64, format numberFormat) string {
+	switch format.Kind {
+	case numberFormatCurrency:
+		return f.Currency(value, format.Currency, formatDecimals(format.Decimals, 2))
+	case numberFormatPercent:
+		return f.Percent(value, formatDecimals(format.Decimals, 1))
+	case numberFormatDuration:
+		return formatDuration(f, time.Duration(value*float64(time.Second)))
+	case numberFormatCompact:
+		return withUnit(f.Compact(value), format.Unit)
+	default:
+		return withUnit(f.Number(value, formatDecimals(format.Decimals, 0)), format.Unit)
+	}
+}
+
+func withUnit(formatted, unit string) string {
+	if unit == "" {
+		return formatted
+	}
+	return formatted + " " + unit
+}
+
+func formatDecimals(decimals *int, fallback int) int {
+	if decimals == nil || *decimals < 0 {
+		return fallback
+	}
+	return *decimals
+}
+
+// durationFormatter is implemented by formatters that spell out durations
+// themselves. Other formatters get the built-in unit patterns for their
+// locale, rendered through their own Message.
+type durationFormatter interface {
+	Duration(d time.Duration) string
+}
+
+func formatDuration(f Formatter, d time.Duration) string {
+	if df, ok := f.(durationFormatter); ok {
+		return df.Duration(d)
+	}
+	return spellDuration(f, NewLocaleFormatter(f.Locale()).(localeFormatter).rules.units, d)
+}
+
+// spellDuration renders d with the two largest units ("1 hour 2 minutes"),
+// dropping a zero second unit; durations under a second use milliseconds.
+// units maps unit names to plural patterns over `count`.
+func spellDuration(f Formatter, units map[string]string, d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	unit := func(name string, count time.Duration) string {
+		return f.Message(units[name], map[string]any{"count": int64(count)})
+	}
+	if d < time.Second {
+		return sign + unit("millisecond", d.Round(time.Millisecond)/time.Millisecond)
+	}
+	d = d.Round(time.Second)
+	hours := d / time.Hour
+	minutes := d % time.Hour / time.Minute
+	seconds := d % time.Minute / time.Second
+	switch {
+	case hours > 0 && minutes > 0:
+		return sign + unit("hour", hours) + " " + unit("minute", minutes)
+	case hours > 0:
+		return sign + unit("hour", hours)
+	case minutes > 0 && seconds > 0:
+		return sign + unit("minute", minutes) + " " + unit("second", seconds)
+	case minutes > 0:
+		return sign + unit("minute", minutes)
+	default:
+		return sign + unit("second", seconds)
+	}
+}
+
+type localeFormat struct {
+	decimal       string
+	group         string
+	currencyAfter bool
+	percentSpace  bool
+	// compact holds suffixes for thousands, millions, billions and trillions;
+	// an empty suffix leaves that magnitude unabbreviated.
+	compact        [4]string
+	date           string
+	dateTime       string
+	relativePast   string
+	relativeFuture string
+	relativeNow    string
+	// units maps millisecond..year to plural patterns over `count`.
+	units map[string]string
+}
+
+var localeFormatRules = map[string]localeFormat{
+	"en": {
+		decimal: ".", group: ",",
+		compact:        [4]string{"K", "M", "B", "T"},
+		date:           "Jan 2, 2006",
+		dateTime:       "Jan 2, 2006, 3:04 PM",
+		relativePast:   "{0} ago",
+		relativeFuture: "in {0}",
+		relativeNow:    "just now",
+		units: map[string]string{
+			"millisecond": "{count, plural, one {# millisecond} other {# milliseconds}}",
+			"second":      "{count, plural, one {# second} other {# seconds}}",
+			"minute":      "{count, plural, one {# minute} other {# minutes}}",
+			"hour":        "{count, plural, one {# hour} other {# hours}}",
+			"day":         "{count, plural, one {# day} other {# days}}",
+			"month":       "{count, plural, one {# month} other {# months}}",
+			"year":        "{count, plural, one {# year} other {# years}}",
+		},
+	},
+	"es": {
+		decimal: ",", group: ".", currencyAfter: true, percentSpace: true,
+		compact:        [4]string{" mil", " M", " mil M", " B"},
+		date:           "02/01/2006",
+		dateTime:       "02/01/2006 15:04",
+		relativePast:   "hace {0}",
+		relativeFuture: "dentro de {0}",
+		relativeNow:    "ahora",
+		units: map[string]string{
+			"millisecond": "{count, plural, one {# milisegundo} other {# milisegundos}}",
+			"second":      "{count, plural, one {# segundo} other {# segundos}}",
+			"minute":      "{count, plural, one {# minuto} other {# minutos}}",
+			"hour":        "{count, plural, one {# hora} other {# horas}}",
+			"day":         "{count, plural, one {# día} other {# días}}",
+			"month":       "{count, plural, one {# mes} other {# meses}}",
+			"year":        "{count, plural, one {# año} other {# años}}",
+		},
+	},
+	"fr": {
+		decimal: ",", group: " ", currencyAfter: true, percentSpace: true,
+		compact:        [4]string{" k", " M", " Md", " Bn"},
+		date:           "02/01/2006",
+		dateTime:       "02/01/2006 15:04",
+		relativePast:   "il y a {0}",
+		relativeFuture: "dans {0}",
+		relativeNow:    "à l'instant",
+		units: map[string]string{
+			"millisecond": "{count, plural, one {# milliseconde} other {# millisecondes}}",
+			"second":      "{count, plural, one {# seconde} other {# secondes}}",
+			"minute":      "{count, plural, one {# minute} other {# minutes}}",
+			"hour":        "{count, plural, one {# heure} other {# heures}}",
+			"day":         "{count, plural, one {# jour} other {# jours}}",
+			"month":       "{count, plural, other {# mois}}",
+			"year":        "{count, plural, one {# an} other {# ans}}",
+		},
+	},
+	"de": {
+		decimal: ",", group: ".", currencyAfter: true, percentSpace: true,
+		compact:        [4]string{" Tsd.", " Mio.", " Mrd.", " Bio."},
+		date:           "02.01.2006",
+		dateTime:       "02.01.2006, 15:04",
+		relativePast:   "vor {0}",
+		relativeFuture: "in {0}",
+		relativeNow:    "gerade eben",
+		units: map[string]string{
+			"millisecond": "{count, plural, one {# Millisekunde} other {# Millisekunden}}",
+			"second":      "{count, plural, one {# Sekunde} other {# Sekunden}}",
+			"minute":      "{count, plural, one {# Minute} other {# Minuten}}",
+			"hour":        "{count, plural, one {# Stunde} other {# Stunden}}",
+			"day":         "{count, plural, one {# Tag} other {# Tagen}}",
+			"month":       "{count, plural, one {# Monat} other {# Monaten}}",
+			"year":        "{count, plural, one {# Jahr} other {# Jahren}}",
+		},
+	},
+	"it": {
+		decimal: ",", group: ".", currencyAfter: true,
+		compact:        [4]string{"", " Mln", " Mrd", " Bln"},
+		date:           "02/01/2006",
+		dateTime:       "02/01/2006, 15:04",
+		relativePast:   "{0} fa",
+		relativeFuture: "tra {0}",
+		relativeNow:    "ora",
+		units: map[string]string{
+			"millisecond": "{count, plural, one {# millisecondo} other {# millisecondi}}",
+			"second":      "{count, plural, one {# secondo} other {# secondi}}",
+			"minute":      "{count, plural, one {# minuto} other {# minuti}}",
+			"hour":        "{count, plural, one {# ora} other {# ore}}",
+			"day":         "{count, plural, one {# giorno} other {# giorni}}",
+			"month":       "{count, plural, one {# mese} other {# mesi}}",
+			"year":        "{count, plural, one {# anno} other {# anni}}",
+		},
+	},
+	"pt": {
+		decimal: ",", group: ".", currencyAfter: true,
+		compact:        [4]string{" mil", " mi", " bi", " tri"},
+		date:           "02/01/2006",
+		dateTime:       "02/01/2006 15:04",
+		relativePast:   "há {0}",
+		relativeFuture: "em {0}",
+		relativeNow:    "agora",
+		units: map[string]string{
+			"millisecond": "{count, plural, one {# milissegundo} other {# milissegundos}}",
+			"second":      "{count, plural, one {# segundo} other {# segundos}}",
+			"minute":      "{count, plural, one {# minuto} other {# minutos}}",
+			"hour":        "{count, plural, one {# hora} other {# horas}}",
+			"day":         "{count, plural, one {# dia} other {# dias}}",
+			"month":       "{count, plural, one {# mês} other {# meses}}",
+			"year":        "{count, plural, one {# ano} other {# anos}}",
+		},
+	},
+	"nl": {
+		decimal: ",", group: ".", currencyAfter: true,
+		compact:        [4]string{"K", " mln.", " mld.", " bln."},
+		date:           "02-01-2006",
+		dateTime:       "02-01-2006 15:04",
+		relativePast:   "{0} geleden",
+		relativeFuture: "over {0}",
+		relativeNow:    "zojuist",
+		units: map[string]string{
+			"millisecond": "{count, plural, one {# milliseconde} other {# milliseconden}}",
+			"second":      "{count, plural, one {# seconde} other {# seconden}}",
+			"minute":      "{count, plural, one {# minuut} other {# minuten}}",
+			"hour":        "{count, plural, other {# uur}}",
+			"day":         "{count, plural, one {# dag} other {# dagen}}",
+			"month":       "{count, plural, one {# maand} other {# maanden}}",
+			"year":        "{count, plural, other {# jaar}}",
+		},
+	},
+}
+
+func init() {
+	gb := localeFormatRules["en"]
+	gb.date = "2 Jan 2006"
+	gb.dateTime = "2 Jan 2006, 15:04"
+	localeFormatRules["en-gb"] = gb
+}
+
+type localeFormatter struct {
+	locale string
+	lang   string
+	rules  localeFormat
+}
+
+func (f localeFormatter) Locale() string {
+	return f.locale
+}
+
+func (f localeFormatter) Number(value float64, decimals int) string {
+	return f.localizeDigits(strconv.FormatFloat(value, 'f', decimals, 64))
+}
+
+func (f localeFormatter) Currency(value float64, currency string, decimals int) string {
+	if decimals < 0 {
+		decimals = 2
+	}
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	amount := f.Number(value, decimals)
+	symbol := currencySymbol(currency)
+	if f.rules.currencyAfter {
+		return sign + amount + " " + strings.TrimSpace(symbol)
+	}
+	return sign + symbol + amount
+}
+
+// currencySymbol returns the symbol for an ISO 4217 code, falling back to the
+// code itself followed by a space.
+func currencySymbol(code string) string {
+	switch strings.ToUpper(code) {
+	case "", "USD":
+		return "$"
+	case "EUR":
+		return "€"
+	case "GBP":
+		return "£"
+	case "JPY":
+		return "¥"
+	default:
+		return strings.ToUpper(code) + " "
+	}
+}
+
+func (f localeFormatter) Percent(value float64, decimals int) string {
+	if f.rules.percentSpace {
+		return f.Number(value, decimals) + " %"
+	}
+	return f.Number(value, decimals) + "%"
+}
+
+func (f localeFormatter) Compact(value float64) string {
+	magnitude := -1
+	scaled := value
+	for idx := range f.rules.compact {
+		threshold := math.Pow(1000, float64(idx+1))
+		if math.Abs(value) < threshold {
+			break
+		}
+		if f.rules.compact[idx] == "" {
+			continue
+		}
+		magnitude, scaled = idx, value/threshold
+	}
+	decimals := 1
+	if math.Abs(scaled) >= 100 || magnitude < 0 {
+		decimals = 0
+	}
+	// Rounding can carry into the next magnitude (999,950 -> "1000K").
+	if rounded, _ := strconv.ParseFloat(strconv.FormatFloat(scaled, 'f', decimals, 64), 64); math.Abs(rounded) >= 1000 && magnitude+1 < len(f.rules.compact) && f.rules.compact[magnitude+1] != "" {
+		magnitude++
+		scaled = value / math.Pow(1000, float64(magnitude+1))
+		decimals = 1
+	}
+	number := strconv.FormatFloat(scaled, 'f', decimals, 64)
+	if strings.Contains(number, ".") {
+		number = strings.TrimSuffix(strings.TrimRight(number, "0"), ".")
+	}
+	if magnitude < 0 {
+		return f.localizeDigits(number)
+	}
+	return f.localizeDigits(number) + f.rules.compact[magnitude]
+}
+
+func (f localeFormatter) Date(t time.Time) string {
+	return t.Format(f.rules.date)
+}
+
+func (f localeFormatter) DateTime(t time.Time) string {
+	return t.Format(f.rules.dateTime)
+}
+
+// Duration spells out d with the locale's unit plurals.
+func (f localeFormatter) Duration(d time.Duration) string {
+	return spellDuration(f, f.rules.units, d)
+}
+
+func (f localeFormatter) RelativeTime(d time.Duration) string {
+	pattern := f.rules.relativePast
+	if d < 0 {
+		pattern = f.rules.relativeFuture
+		d = -d
+	}
+	unit, count := relativeTimeUnit(d)
+	if unit == "" {
+		return f.rules.relativeNow
+	}
+	amount := f.Message(f.rules.units[unit], map[string]any{"count": count})
+	return strings.Replace(pattern, "{0}", amount, 1)
+}
+
+func relativeTimeUnit(d time.Duration) (string, int64) {
+	const (
+		day   = 24 * time.Hour
+		month = 30 * day
+		year  = 365 * day
+	)
+	switch {
+	case d < 10*time.Second:
+		return "", 0
+	case d < time.Minute:
+		return "second", int64(d / time.Second)
+	case d < time.Hour:
+		return "minute", int64(d / time.Minute)
+	case d < day:
+		return "hour", int64(d / time.Hour)
+	case d < month:
+		return "day", int64(d / day)
+	case d < year:
+		return "month", int64(d / month)
+	default:
+		return "year", int64(d / year)
+	}
+}
+
+// localizeDigits groups the integer part of a plain formatted number and
+// swaps in the locale separators.
+func (f localeFormatter) localizeDigits(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(number, ".")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(f.rules.group)
+		}
+		b.WriteRune(r)
+	}
+	if hasFraction {
+		b.WriteString(f.rules.decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// pluralCategory returns the CLDR cardinal plural category of n for the
+// formatter language.
+func (f localeFormatter) pluralCategory(n float64) string {
+	n = math.Abs(n)
+	formatted := strconv.FormatFloat(n, 'f', -1, 64)
+	_, fraction, _ := strings.Cut(formatted, ".")
+	i, v := int64(n), len(fraction)
+	switch f.lang {
+	case "fr", "pt":
+		if i == 0 || i == 1 {
+			return "one"
+		}
+	case "ja", "zh", "ko", "th", "vi", "id":
+	case "ru", "uk":
+		if v != 0 {
+			return "other"
+		}
+		switch mod10, mod100 := i%10, i%100; {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "pl":
+		if v != 0 {
+			return "other"
+		}
+		switch mod10, mod100 := i%10, i%100; {
+		case i == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if i == 1 && v == 0 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+func (f localeFormatter) Message(pattern string, args map[string]any) string {
+	var b strings.Builder
+	f.writeMessage(&b, pattern, args, "")
+	return b.String()
+}
+
+// writeMessage formats pattern into b. hash is the formatted count that `#`
+// expands to inside the innermost plural branch.
+func (f localeFormatter) writeMessage(b *strings.Builder, pattern string, args map[string]any, hash string) {
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			next := i + 1
+			if next < len(pattern) && pattern[next] == '\'' {
+				b.WriteByte('\'')
+				i += 2
+				continue
+			}
+			if next < len(pattern) && strings.IndexByte("{}#", pattern[next]) >= 0 {
+				end := strings.IndexByte(pattern[next:], '\'')
+				if end < 0 {
+					b.WriteString(pattern[next:])
+					return
+				}
+				b.WriteString(pattern[next : next+end])
+				i = next + end + 1
+				continue
+			}
+			b.WriteByte(c)
+			i++
+		case c == '#' && hash != "":
+			b.WriteString(hash)
+			i++
+		case c == '{':
+			end := messageBraceEnd(pattern, i)
+			if end < 0 {
+				b.WriteString(pattern[i:])
+				return
+			}
+			f.writeArgument(b, pattern[i+1:end], args, hash)
+			i = end + 1
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+}
+
+func (f localeFormatter) writeArgument(b *strings.Builder, body string, args map[string]any, hash string) {
+	name, rest, _ := strings.Cut(body, ",")
+	value, ok := args[strings.TrimSpace(name)]
+	if !ok {
+		b.WriteString("{" + body + "}")
+		return
+	}
+	kind, style, _ := strings.Cut(rest, ",")
+	switch strings.TrimSpace(kind) {
+	case "":
+		b.WriteString(f.messageValue(value))
+	case "number":
+		number, ok := tableNumber(value)
+		if !ok {
+			b.WriteString(f.messageValue(value))
+			return
+		}
+		switch strings.TrimSpace(style) {
+		case "integer":
+			b.WriteString(f.Number(math.Round(number), 0))
+		case "percent":
+			b.WriteString(f.Percent(math.Round(number*10000)/100, -1))
+		default:
+			b.WriteString(f.Number(number, -1))
+		}
+	case "date":
+		if ts, ok := tableTime(value); ok {
+			b.WriteString(f.Date(ts))
+			return
+		}
+		b.WriteString(f.messageValue(value))
+	case "time":
+		if ts, ok := tableTime(value); ok {
+			b.WriteString(f.DateTime(ts))
+			return
+		}
+		b.WriteString(f.messageValue(value))
+	case "plural":
+		number, _ := tableNumber(value)
+		offset, branches := messageBranches(style)
+		selected, found := "", false
+		for _, branch := range branches {
+			if exact, ok := strings.CutPrefix(branch.key, "="); ok {
+				if n, err := strconv.ParseFloat(exact, 64); err == nil && n == number {
+					selected, found = branch.text, true
+					break
+				}
+			}
+		}
+		if !found {
+			selected, found = pickMessageBranch(branches, f.pluralCategory(number-offset))
+		}
+		if found {
+			f.writeMessage(b, selected, args, f.Number(number-offset, -1))
+		}
+	case "select":
+		_, branches := messageBranches(style)
+		if selected, found := pickMessageBranch(branches, f.messageValue(value)); found {
+			f.writeMessage(b, selected, args, hash)
+		}
+	default:
+		b.WriteString(f.messageValue(value))
+	}
+}
+
+func (f localeFormatter) messageValue(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case time.Time:
+		return f.DateTime(typed)
+	case time.Duration:
+		return f.RelativeTime(typed)
+	}
+	if number, ok := tableNumber(value); ok {
+		return f.Number(number, -1)
+	}
+	return fmt.Sprint(value)
+}
+
+type messageBranch struct {
+	key  string
+	text string
+}
+
+// messageBranches parses `[offset:n] key {text} key {text}` plural/select
+// bodies.
+func messageBranches(style string) (float64, []messageBranch) {
+	var (
+		offset   float64
+		branches []messageBranch
+	)
+	for i := 0; i < len(style); {
+		for i < len(style) && isMessageSpace(style[i]) {
+			i++
+		}
+		start := i
+		for i < len(style) && style[i] != '{' && !isMessageSpace(style[i]) {
+			i++
+		}
+		key := style[start:i]
+		if value, ok := strings.CutPrefix(key, "offset:"); ok {
+			offset, _ = strconv.ParseFloat(value, 64)
+			continue
+		}
+		for i < len(style) && isMessageSpace(style[i]) {
+			i++
+		}
+		if key == "" || i >= len(style) || style[i] != '{' {
+			break
+		}
+		end := messageBraceEnd(style, i)
+		if end < 0 {
+			break
+		}
+		branches = append(branches, messageBranch{key: key, text: style[i+1 : end]})
+		i = end + 1
+	}
+	return offset, branches
+}
+
+func pickMessageBranch(branches []messageBranch, key string) (string, bool) {
+	fallback, hasFallback := "", false
+	for _, branch := range branches {
+		if branch.key == key {
+			return branch.text, true
+		}
+		if branch.key == "other" {
+			fallback, hasFallback = branch.text, true
+		}
+	}
+	return fallback, hasFallback
+}
+
+// messageBraceEnd returns the index of the brace closing the one at start,
+// skipping quoted literals, or -1 when it is unbalanced.
+func messageBraceEnd(pattern string, start int) int {
+	depth := 0
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\'':
+			if i+1 < len(pattern) && strings.IndexByte("{}#'", pattern[i+1]) >= 0 {
+				if pattern[i+1] == '\'' {
+					i++
+					continue
+				}
+				end := strings.IndexByte(pattern[i+1:], '\'')
+				if end < 0 {
+					return -1
+				}
+				i += end + 1
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isMessageSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
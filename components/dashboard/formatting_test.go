@@ -0,0 +1,203 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocaleFormatterNumbers(t *testing.T) {
+	cases := []struct {
+		locale string
+		got    func(Formatter) string
+		want   string
+	}{
+		{"en-US", func(f Formatter) string { return f.Number(1234567.891, 2) }, "1,234,567.89"},
+		{"de", func(f Formatter) string { return f.Number(1234567.891, 2) }, "1.234.567,89"},
+		{"fr-CA", func(f Formatter) string { return f.Number(-9876.5, -1) }, "-9 876,5"},
+		{"en", func(f Formatter) string { return f.Currency(-1200, "USD", -1) }, "-$1,200.00"},
+		{"es", func(f Formatter) string { return f.Currency(1250.5, "EUR", 2) }, "1.250,50 €"},
+		{"en", func(f Formatter) string { return f.Currency(10, "CHF", 0) }, "CHF 10"},
+		{"en", func(f Formatter) string { return f.Percent(42.345, 1) }, "42.3%"},
+		{"fr", func(f Formatter) string { return f.Percent(42.345, 1) }, "42,3 %"},
+		{"en", func(f Formatter) string { return f.Compact(1234) }, "1.2K"},
+		{"en", func(f Formatter) string { return f.Compact(999950) }, "1M"},
+		{"en", func(f Formatter) string { return f.Compact(512) }, "512"},
+		{"de", func(f Formatter) string { return f.Compact(2500000) }, "2,5 Mio."},
+		{"it", func(f Formatter) string { return f.Compact(4500) }, "4.500"},
+		{"xx", func(f Formatter) string { return f.Number(1000, 0) }, "1,000"},
+	}
+	for _, tc := range cases {
+		if got := tc.got(NewLocaleFormatter(tc.locale)); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.locale, got, tc.want)
+		}
+	}
+}
+
+func TestFormatNumberDispatchesOnFormat(t *testing.T) {
+	zero := 0
+	cases := []struct {
+		format numberFormat
+		value  float64
+		want   string
+	}{
+		{numberFormat{}, 1234.6, "1,235"},
+		{numberFormat{Unit: "ms"}, 42, "42 ms"},
+		{numberFormat{Kind: numberFormatCurrency, Currency: "gbp"}, 9.5, "£9.50"},
+		{numberFormat{Kind: numberFormatCurrency, Currency: "EUR", Decimals: &zero}, 10, "€10"},
+		{numberFormat{Kind: numberFormatPercent}, 12.34, "12.3%"},
+		{numberFormat{Kind: numberFormatDuration}, 0.25, "250 milliseconds"},
+		{numberFormat{Kind: numberFormatCompact, Unit: "users"}, 2500, "2.5K users"},
+	}
+	for _, tc := range cases {
+		if got := formatNumber(NewLocaleFormatter("en"), tc.value, tc.format); got != tc.want {
+			t.Fatalf("formatNumber(%v, %+v) = %q, want %q", tc.value, tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestLocaleFormatterDatesAndRelativeTime(t *testing.T) {
+	ts := time.Date(2026, time.March, 1, 14, 5, 0, 0, time.UTC)
+	if got := NewLocaleFormatter("en").Date(ts); got != "Mar 1, 2026" {
+		t.Fatalf("unexpected en date %q", got)
+	}
+	if got := NewLocaleFormatter("en-GB").DateTime(ts); got != "1 Mar 2026, 14:05" {
+		t.Fatalf("unexpected en-GB datetime %q", got)
+	}
+	if got := NewLocaleFormatter("de-AT").Date(ts); got != "01.03.2026" {
+		t.Fatalf("unexpected de date %q", got)
+	}
+
+	cases := []struct {
+		locale string
+		d      time.Duration
+		want   string
+	}{
+		{"en", 5 * time.Second, "just now"},
+		{"en", time.Minute, "1 minute ago"},
+		{"en", 22 * time.Minute, "22 minutes ago"},
+		{"en", -2 * time.Hour, "in 2 hours"},
+		{"es", 3 * 24 * time.Hour, "hace 3 días"},
+		{"fr", 90 * time.Second, "il y a 1 minute"},
+		{"de", 2 * 24 * time.Hour, "vor 2 Tagen"},
+		{"en", 400 * 24 * time.Hour, "1 year ago"},
+	}
+	for _, tc := range cases {
+		if got := NewLocaleFormatter(tc.locale).RelativeTime(tc.d); got != tc.want {
+			t.Fatalf("%s %v: got %q, want %q", tc.locale, tc.d, got, tc.want)
+		}
+	}
+}
+
+// upperFormatter wraps the built-in formatter without its Duration method,
+// like a host formatter would.
+type upperFormatter struct{ Formatter }
+
+func (f upperFormatter) Message(pattern string, args map[string]any) string {
+	return strings.ToUpper(f.Formatter.Message(pattern, args))
+}
+
+func TestFormatDurationUsesLocaleUnits(t *testing.T) {
+	cases := []struct {
+		f    Formatter
+		d    time.Duration
+		want string
+	}{
+		{NewLocaleFormatter("en"), time.Hour, "1 hour"},
+		{NewLocaleFormatter("fr"), 3725 * time.Second, "1 heure 2 minutes"},
+		{NewLocaleFormatter("de"), 95 * time.Second, "1 Minute 35 Sekunden"},
+		{NewLocaleFormatter("es"), 1500 * time.Millisecond, "2 segundos"},
+		{NewLocaleFormatter("nl"), 2 * time.Hour, "2 uur"},
+		{NewLocaleFormatter("en"), -90 * time.Second, "-1 minute 30 seconds"},
+		{upperFormatter{NewLocaleFormatter("it")}, 61 * time.Second, "1 MINUTO 1 SECONDO"},
+	}
+	for _, tc := range cases {
+		if got := formatDuration(tc.f, tc.d); got != tc.want {
+			t.Fatalf("%s %v: got %q, want %q", tc.f.Locale(), tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestLocaleFormatterMessageFormat(t *testing.T) {
+	rows := "{count, plural, =0 {no rows} one {# row} other {# rows}}"
+	cases := []struct {
+		locale  string
+		pattern string
+		args    map[string]any
+		want    string
+	}{
+		{"en", rows, map[string]any{"count": 0}, "no rows"},
+		{"en", rows, map[string]any{"count": 1}, "1 row"},
+		{"en", rows, map[string]any{"count": 1200}, "1,200 rows"},
+		{"en", rows, map[string]any{"count": 1.5}, "1.5 rows"},
+		{"fr", "{count, plural, one {# ligne} other {# lignes}}", map[string]any{"count": 0}, "0 ligne"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", map[string]any{"n": 22}, "22 файла"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", map[string]any{"n": 11}, "11 файлов"},
+		{"en", "{n, plural, offset:1 =0 {nobody} =1 {{name}} one {{name} and # other} other {{name} and # others}}", map[string]any{"n": 3, "name": "Ana"}, "Ana and 2 others"},
+		{"en", "{role, select, admin {Administrator} other {Member {name}}}", map[string]any{"role": "viewer", "name": "Kim"}, "Member Kim"},
+		{"de", "{share, number, percent} of {total, number}", map[string]any{"share": 0.125, "total": 4096}, "12,5 % of 4.096"},
+		{"en", "Use '{name}' or it''s {missing}", map[string]any{"name": "x"}, "Use {name} or it's {missing}"},
+	}
+	for _, tc := range cases {
+		if got := NewLocaleFormatter(tc.locale).Message(tc.pattern, tc.args); got != tc.want {
+			t.Fatalf("%s %q: got %q, want %q", tc.locale, tc.pattern, got, tc.want)
+		}
+	}
+}
+
+func TestServiceWidgetContextUsesFormatterOption(t *testing.T) {
+	var requested string
+	svc := NewService(Options{Formatter: func(locale string) Formatter {
+		requested = locale
+		return NewLocaleFormatter("de")
+	}})
+	meta := svc.widgetContext(context.Background(), ViewerContext{Locale: "de-CH"}, nil, WidgetInstance{ID: "w1"}, nil)
+	if requested != "de-CH" || meta.Formatter == nil || meta.Formatter.Number(1000, 0) != "1.000" {
+		t.Fatalf("expected host formatter for viewer locale, got %q %+v", requested, meta.Formatter)
+	}
+	if got := NewService(Options{}).widgetContext(context.Background(), ViewerContext{Locale: "fr"}, nil, WidgetInstance{}, nil).Formatter; got == nil || got.Locale() != "fr" {
+		t.Fatalf("expected default formatter bound to viewer locale, got %+v", got)
+	}
+}
+
+func TestTemplateRendererFormattingHelpers(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Title:  "Dashboard",
+		Locale: "de",
+		Areas: []PageArea{{
+			Slot: "main",
+			Code: "admin.dashboard.main",
+			Widgets: []WidgetFrame{{
+				ID:         "f1",
+				Definition: "admin.widget.analytics_funnel",
+				Template:   "widgets/analytics_funnel.html",
+				Area:       "admin.dashboard.main",
+				Data: WidgetData{
+					"conversion_rate": 12.34,
+					"goal":            45.0,
+					"steps": []map[string]any{
+						{"label": "Visit", "value": 12500.0, "position": 0, "percent": 100.0},
+						{"label": "Buy", "value": 1543.0, "position": 1, "percent": 12.3, "dropoff": 87.7},
+					},
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"12,3 % conversion", "Goal 45,0 %", "12.500 records", "87,7 % drop"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in rendered funnel, got %s", want, out)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package gorouter
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
 	"strings"
 
 	router "github.com/goliatone/go-router"
@@ -13,6 +17,10 @@ import (
 	"github.com/goliatone/go-dashboard/components/dashboard/httpapi"
 )
 
+// defaultBasePath is the group the dashboard routes mount under when
+// Config.BasePath is empty; dashboard's Default*Path constants include it.
+const defaultBasePath = "/admin"
+
 // ViewerResolver converts a router.Context into a dashboard.ViewerContext.
 type ViewerResolver func(router.Context) dashboard.ViewerContext
 
@@ -57,6 +65,29 @@ type RouteConfig struct {
 	WebSocket   string
 	Assets      string
 	ShellAssets string
+	// WidgetAction serves provider actions such as table paging and CSV
+	// export. Keep dashboard.Options.WidgetActionPath in sync when changed.
+	WidgetAction string
+	// Diagnostics serves dashboard.DashboardDiagnostics as JSON, including
+	// missing translation keys. It is not mounted unless set, since it
+	// exposes operational state (e.g. "/dashboard/_diagnostics").
+	Diagnostics string
+	// Catalog serves the widget catalog browser: installed definitions and
+	// widgets merged from catalog indexes, filtered by the `channel`
+	// (comma-separated), `status`, `tag` and `q` query parameters. GET
+	// returns JSON, or the HTML page for `?format=html` and
+	// `Accept: text/html`. It is not mounted unless set (e.g.
+	// "/dashboard/catalog").
+	Catalog string
+	// WidgetConfig serves the configuration form of a widget instance: GET
+	// returns the JSON descriptor (or the HTML partial for `?format=html` and
+	// `Accept: text/html`); POST validates and saves JSON or form-encoded
+	// submissions when API implements dashboard.WidgetUpdater.
+	WidgetConfig string
+	// DefinitionConfig serves the form for a new widget of a definition.
+	// POST validates; when API is set and the submission carries an
+	// `area_code` (and is not validate-only) it also creates the widget.
+	DefinitionConfig string
 }
 
 // Register mounts dashboard routes (HTML, JSON, REST, WebSocket) on a go-router router.
@@ -73,7 +104,7 @@ func Register[T any](cfg Config[T]) error {
 	routes := cfg.routes()
 	base := cfg.BasePath
 	if base == "" {
-		base = "/admin"
+		base = defaultBasePath
 	}
 	viewerResolver := cfg.ViewerResolver
 	if viewerResolver == nil {
@@ -122,6 +153,50 @@ func Register[T any](cfg Config[T]) error {
 		return ctx.JSON(http.StatusOK, page)
 	}))
 
+	group.Get(routes.WidgetAction, router.WrapHandler(func(ctx router.Context) error {
+		req := dashboard.WidgetActionRequest{
+			WidgetID: ctx.Param("id"),
+			Action:   ctx.Param("action"),
+			Viewer:   viewerResolver(ctx),
+			Params:   ctx.Queries(),
+		}
+		result, err := httpapi.WidgetAction(ctx.Context(), cfg.Controller, req)
+		if err != nil {
+			return respondError(ctx, widgetActionStatus(err), err)
+		}
+		return writeWidgetActionResult(ctx, result)
+	}))
+
+	registerConfigForms(group, cfg, viewerResolver, routes, base)
+
+	if routes.Diagnostics != "" {
+		group.Get(routes.Diagnostics, router.WrapHandler(func(ctx router.Context) error {
+			diagnostics, err := httpapi.Diagnostics(ctx.Context(), cfg.Controller, viewerResolver(ctx))
+			if err != nil {
+				return respondError(ctx, http.StatusInternalServerError, err)
+			}
+			return ctx.JSON(http.StatusOK, diagnostics)
+		}))
+	}
+
+	if routes.Catalog != "" {
+		group.Get(routes.Catalog, router.WrapHandler(func(ctx router.Context) error {
+			page, err := httpapi.CatalogPage(ctx.Context(), cfg.Controller, catalogRequest(ctx, viewerResolver(ctx)))
+			if err != nil {
+				return respondError(ctx, catalogStatus(err), err)
+			}
+			if !wantsHTML(ctx) {
+				return ctx.JSON(http.StatusOK, page)
+			}
+			html, err := httpapi.RenderCatalog(cfg.Controller, page)
+			if err != nil {
+				return respondError(ctx, catalogStatus(err), err)
+			}
+			ctx.SetHeader("Content-Type", "text/html; charset=utf-8")
+			return ctx.Send(html)
+		}))
+	}
+
 	if cfg.API != nil {
 		registerAPI(group, cfg.API, viewerResolver, routes)
 	}
@@ -195,6 +270,174 @@ func registerAPI[T any](r router.Router[T], api dashboard.Executor, resolver Vie
 	}))
 }
 
+func registerConfigForms[T any](r router.Router[T], cfg Config[T], resolver ViewerResolver, routes RouteConfig, base string) {
+	formRequest := func(ctx router.Context) dashboard.WidgetConfigFormRequest {
+		return dashboard.WidgetConfigFormRequest{
+			WidgetID:     ctx.Param("id"),
+			DefinitionID: ctx.Param("code"),
+			Viewer:       resolver(ctx),
+		}
+	}
+	get := func(ctx router.Context) error {
+		req := formRequest(ctx)
+		form, err := httpapi.ConfigForm(ctx.Context(), cfg.Controller, req)
+		if err != nil {
+			return respondError(ctx, configFormStatus(err), err)
+		}
+		if req.WidgetID != "" {
+			form.Action = joinRoutePath(base, strings.Replace(routes.WidgetConfig, ":id", url.PathEscape(req.WidgetID), 1))
+		}
+		return writeConfigForm(ctx, cfg.Controller, http.StatusOK, form)
+	}
+	updater, _ := cfg.API.(dashboard.WidgetUpdater)
+	submit := func(ctx router.Context) error {
+		submission, err := configSubmission(ctx, cfg.Controller, formRequest(ctx))
+		if err != nil {
+			return respondError(ctx, configFormStatus(err), err)
+		}
+		var (
+			reply httpapi.Response
+			form  dashboard.ConfigForm
+		)
+		if submission.Request.WidgetID == "" && !submission.ValidateOnly && submission.AreaCode != "" && cfg.API != nil {
+			reply, form, err = httpapi.SubmitNewWidget(ctx.Context(), cfg.Controller, cfg.API, submission)
+		} else {
+			if submission.Request.WidgetID == "" {
+				submission.ValidateOnly = true
+			}
+			reply, form, err = httpapi.SubmitConfig(ctx.Context(), cfg.Controller, updater, submission)
+		}
+		if err != nil {
+			return respondError(ctx, configFormStatus(err), err)
+		}
+		if submission.Request.WidgetID != "" {
+			form.Action = joinRoutePath(base, strings.Replace(routes.WidgetConfig, ":id", url.PathEscape(submission.Request.WidgetID), 1))
+		}
+		if wantsHTML(ctx) {
+			return writeConfigForm(ctx, cfg.Controller, reply.StatusCode, form)
+		}
+		return ctx.JSON(reply.StatusCode, reply.Payload)
+	}
+
+	r.Get(routes.WidgetConfig, router.WrapHandler(get))
+	r.Get(routes.DefinitionConfig, router.WrapHandler(get))
+	r.Post(routes.DefinitionConfig, router.WrapHandler(submit))
+	if updater != nil {
+		r.Post(routes.WidgetConfig, router.WrapHandler(submit))
+	}
+}
+
+// configSubmission reads `{"configuration": {...}}` JSON bodies or
+// form-encoded bodies produced by the config form partial. The submission is
+// scoped to the `tenant_id` local when set.
+func configSubmission(ctx router.Context, controller *dashboard.Controller, req dashboard.WidgetConfigFormRequest) (httpapi.ConfigSubmission, error) {
+	submission := httpapi.ConfigSubmission{ValidateOnly: isTruthy(ctx.Query("validate"))}
+	// The tenant comes from the host's middleware, like the user ID.
+	submission.TenantID, _ = ctx.Locals("tenant_id").(string)
+	body := ctx.Body()
+	if strings.HasPrefix(strings.ToLower(ctx.Header("Content-Type")), "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return submission, errConfigPayload{err}
+		}
+		config, errs, err := httpapi.DecodeConfigForm(ctx.Context(), controller, req, values)
+		if err != nil {
+			return submission, err
+		}
+		req.Configuration = config
+		submission.DecodeErrors = errs
+		submission.ValidateOnly = submission.ValidateOnly || isTruthy(values.Get("validate"))
+		submission.AreaCode = values.Get("area_code")
+		submission.Request = req
+		return submission, nil
+	}
+	var payload struct {
+		Configuration map[string]any `json:"configuration"`
+		AreaCode      string         `json:"area_code"`
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return submission, errConfigPayload{err}
+		}
+	}
+	req.Configuration = payload.Configuration
+	submission.AreaCode = payload.AreaCode
+	submission.Request = req
+	return submission, nil
+}
+
+func writeConfigForm(ctx router.Context, controller *dashboard.Controller, status int, form dashboard.ConfigForm) error {
+	if !wantsHTML(ctx) {
+		return ctx.JSON(status, form)
+	}
+	html, err := httpapi.RenderConfigForm(controller, form)
+	if err != nil {
+		return respondError(ctx, configFormStatus(err), err)
+	}
+	ctx.SetHeader("Content-Type", "text/html; charset=utf-8")
+	return ctx.Status(status).Send(html)
+}
+
+// errConfigPayload marks malformed submissions so they reply 400.
+type errConfigPayload struct{ err error }
+
+func (e errConfigPayload) Error() string { return e.err.Error() }
+func (e errConfigPayload) Unwrap() error { return e.err }
+
+func configFormStatus(err error) int {
+	var payloadErr errConfigPayload
+	switch {
+	case errors.As(err, &payloadErr):
+		return http.StatusBadRequest
+	case errors.Is(err, dashboard.ErrWidgetConfigUnsupported):
+		return http.StatusNotFound
+	case errors.Is(err, dashboard.ErrWidgetConfigForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func catalogRequest(ctx router.Context, viewer dashboard.ViewerContext) dashboard.CatalogPageRequest {
+	req := dashboard.CatalogPageRequest{
+		Viewer: viewer,
+		Status: ctx.Query("status"),
+		Tag:    ctx.Query("tag"),
+		Query:  ctx.Query("q"),
+	}
+	if channels := strings.TrimSpace(ctx.Query("channel")); channels != "" {
+		req.Channels = strings.Split(channels, ",")
+	}
+	return req
+}
+
+func catalogStatus(err error) int {
+	if errors.Is(err, dashboard.ErrCatalogUnsupported) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func wantsHTML(ctx router.Context) bool {
+	if format := strings.TrimSpace(ctx.Query("format")); format != "" {
+		return strings.EqualFold(format, "html")
+	}
+	return strings.Contains(ctx.Header("Accept"), "text/html")
+}
+
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+func joinRoutePath(base, route string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(route, "/")
+}
+
 func registerWebSocket[T any](r router.Router[T], hook *dashboard.BroadcastHook, path string) {
 	cfg := router.DefaultWebSocketConfig()
 	r.WebSocket(path, cfg, func(ws router.WebSocketContext) error {
@@ -225,9 +468,25 @@ func defaultViewerResolver(ctx router.Context) dashboard.ViewerContext {
 		viewer.Roles = roles
 	}
 	viewer.Locale = inferLocale(ctx)
+	viewer.TimeRange = inferTimeRange(ctx)
+	viewer.Filters = dashboard.ParseFilterValues(ctx.Queries())
+	viewer.ThemeVariant = strings.TrimSpace(ctx.Cookies(dashboard.ThemeVariantCookie))
+	// A request only asks for edit mode; the controller grants it when edit
+	// mode is enabled and the service's authorizer allows the viewer.
+	viewer.Editing = isTruthy(ctx.Query(dashboard.EditModeQueryParam))
 	return viewer
 }
 
+// inferTimeRange reads the dashboard time picker query parameters. Invalid
+// values are ignored so widgets fall back to their configured periods.
+func inferTimeRange(ctx router.Context) *dashboard.TimeRange {
+	timeRange, err := dashboard.ParseTimeRange(ctx.Query("range"), ctx.Query("from"), ctx.Query("to"))
+	if err != nil {
+		return nil
+	}
+	return timeRange
+}
+
 func inferLocale(ctx router.Context) string {
 	if locale, ok := ctx.Locals("locale").(string); ok && locale != "" {
 		return locale
@@ -262,6 +521,85 @@ func parseAcceptLanguage(header string) string {
 	return ""
 }
 
+func widgetActionStatus(err error) int {
+	switch {
+	case errors.Is(err, dashboard.ErrWidgetActionUnsupported):
+		return http.StatusNotFound
+	case errors.Is(err, dashboard.ErrWidgetActionForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeWidgetActionResult writes download results to the response inside the
+// handler, while the request context is still live. Adapters exposing
+// net/http primitives get the body as it is produced; net/http streams it to
+// the client while fasthttp holds it in memory, which is why table exports are
+// capped. Other adapters receive the buffered download.
+func writeWidgetActionResult(ctx router.Context, result dashboard.WidgetActionResult) error {
+	if result.Stream == nil {
+		return ctx.JSON(http.StatusOK, result.Data)
+	}
+	if httpCtx, ok := router.AsHTTPContext(ctx); ok {
+		if w := httpCtx.Response(); w != nil {
+			return streamWidgetDownload(ctx, w, result)
+		}
+	}
+	var body bytes.Buffer
+	if err := result.Stream(&body); err != nil {
+		return respondError(ctx, http.StatusInternalServerError, err)
+	}
+	return router.NewDownloadResponder(ctx).WriteDownload(ctx.Context(), router.DownloadPayload{
+		ContentType: result.ContentType,
+		Filename:    result.Filename,
+		Bytes:       body.Bytes(),
+	})
+}
+
+// streamWidgetDownload writes result straight to w. Failures before the first
+// byte still produce a JSON error; later ones can only cut the download short.
+func streamWidgetDownload(ctx router.Context, w http.ResponseWriter, result dashboard.WidgetActionResult) error {
+	out := &downloadWriter{w: w, result: result}
+	if err := result.Stream(out); err != nil {
+		if !out.started {
+			return respondError(ctx, http.StatusInternalServerError, err)
+		}
+		return err
+	}
+	if !out.started {
+		out.start()
+	}
+	return nil
+}
+
+// downloadWriter sends the download headers on the first write.
+type downloadWriter struct {
+	w       http.ResponseWriter
+	result  dashboard.WidgetActionResult
+	started bool
+}
+
+func (d *downloadWriter) start() {
+	d.started = true
+	contentType := d.result.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	d.w.Header().Set("Content-Type", contentType)
+	if d.result.Filename != "" {
+		d.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(d.result.Filename)}))
+	}
+	d.w.WriteHeader(http.StatusOK)
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.start()
+	}
+	return d.w.Write(p)
+}
+
 func respondError(ctx router.Context, status int, err error) error {
 	return ctx.JSON(status, map[string]string{"error": err.Error()})
 }
@@ -302,5 +640,14 @@ func defaultRouteConfig(routes RouteConfig) RouteConfig {
 	if routes.ShellAssets == "" {
 		routes.ShellAssets = dashboard.DefaultShellAssetsPath
 	}
+	if routes.WidgetAction == "" {
+		routes.WidgetAction = strings.TrimPrefix(dashboard.DefaultWidgetActionPath, defaultBasePath)
+	}
+	if routes.WidgetConfig == "" {
+		routes.WidgetConfig = "/dashboard/widgets/:id/config"
+	}
+	if routes.DefinitionConfig == "" {
+		routes.DefinitionConfig = "/dashboard/definitions/:code/config"
+	}
 	return routes
 }
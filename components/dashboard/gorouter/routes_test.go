@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -500,3 +501,381 @@ func (noopExecutor) Refresh(context.Context, commands.RefreshWidgetInput) error
 func (noopExecutor) Preferences(context.Context, commands.SaveLayoutPreferencesInput) error {
 	return nil
 }
+
+func TestDefaultViewerResolverParsesTimeRange(t *testing.T) {
+	server := router.NewFiberAdapter()
+	service := &stubLayoutResolver{layout: dashboard.Layout{Areas: map[string][]dashboard.WidgetInstance{}}}
+	controller := dashboard.NewController(dashboard.ControllerOptions{
+		Service:  service,
+		Renderer: &stubRenderer{},
+	})
+	if err := Register(Config[*fiber.App]{
+		Router:     server.Router(),
+		Controller: controller,
+		API:        noopExecutor{},
+	}); err != nil {
+		t.Fatalf("register returned error: %v", err)
+	}
+	fiberAdapter, ok := server.(interface {
+		WrappedRouter() *fiber.App
+	})
+	if !ok {
+		t.Fatalf("adapter does not expose wrapped router")
+	}
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/_layout?range=7d", nil)
+	resp, err := fiberAdapter.WrappedRouter().Test(req)
+	if err != nil {
+		t.Fatalf("layout request failed: %v", err)
+	}
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		t.Errorf("close layout response body: %v", closeErr)
+	}
+	if service.lastViewer.TimeRange == nil || service.lastViewer.TimeRange.Preset != dashboard.TimeRangePreset7d {
+		t.Fatalf("expected 7d time range on viewer, got %+v", service.lastViewer.TimeRange)
+	}
+}
+
+func TestDefaultViewerResolverParsesFilterVariables(t *testing.T) {
+	server := router.NewFiberAdapter()
+	service := &stubLayoutResolver{layout: dashboard.Layout{Areas: map[string][]dashboard.WidgetInstance{}}}
+	controller := dashboard.NewController(dashboard.ControllerOptions{
+		Service:  service,
+		Renderer: &stubRenderer{},
+	})
+	if err := Register(Config[*fiber.App]{
+		Router:     server.Router(),
+		Controller: controller,
+		API:        noopExecutor{},
+	}); err != nil {
+		t.Fatalf("register returned error: %v", err)
+	}
+	fiberAdapter, ok := server.(interface {
+		WrappedRouter() *fiber.App
+	})
+	if !ok {
+		t.Fatalf("adapter does not expose wrapped router")
+	}
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/_layout?var-region=emea&locale=en", nil)
+	resp, err := fiberAdapter.WrappedRouter().Test(req)
+	if err != nil {
+		t.Fatalf("layout request failed: %v", err)
+	}
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		t.Errorf("close layout response body: %v", closeErr)
+	}
+	if got := service.lastViewer.Filters; len(got) != 1 || got["region"] != "emea" {
+		t.Fatalf("expected region filter on viewer, got %+v", got)
+	}
+}
+
+type stubActionService struct {
+	stubLayoutResolver
+	lastAction dashboard.WidgetActionRequest
+}
+
+func (s *stubActionService) ExecuteWidgetAction(_ context.Context, req dashboard.WidgetActionRequest) (dashboard.WidgetActionResult, error) {
+	s.lastAction = req
+	if req.Action == "broken" {
+		return dashboard.WidgetActionResult{
+			ContentType: "text/csv; charset=utf-8",
+			Stream:      func(io.Writer) error { return errors.New("repository unavailable") },
+		}, nil
+	}
+	if req.Action != "export" {
+		return dashboard.WidgetActionResult{}, dashboard.ErrWidgetActionUnsupported
+	}
+	return dashboard.WidgetActionResult{
+		ContentType: "text/csv; charset=utf-8",
+		Filename:    "orders-20260301.csv",
+		Stream: func(w io.Writer) error {
+			_, err := io.WriteString(w, "Order,Amount\nSO-1,10\n")
+			return err
+		},
+	}, nil
+}
+
+func TestWidgetActionRouteStreamsDownloads(t *testing.T) {
+	server := router.NewFiberAdapter()
+	service := &stubActionService{}
+	controller := dashboard.NewController(dashboard.ControllerOptions{
+		Service:  service,
+		Renderer: &stubRenderer{},
+	})
+	if err := Register(Config[*fiber.App]{Router: server.Router(), Controller: controller}); err != nil {
+		t.Fatalf("register returned error: %v", err)
+	}
+	app := server.(interface{ WrappedRouter() *fiber.App }).WrappedRouter()
+
+	resp, err := app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/widgets/w1/actions/export?sort=amount&var-region=emea", nil))
+	if err != nil {
+		t.Fatalf("action request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Content-Disposition"); !strings.Contains(got, "orders-20260301.csv") {
+		t.Fatalf("expected attachment filename, got %q", got)
+	}
+	if string(body) != "Order,Amount\nSO-1,10\n" {
+		t.Fatalf("unexpected csv body %q", body)
+	}
+	if service.lastAction.WidgetID != "w1" || service.lastAction.Params["sort"] != "amount" {
+		t.Fatalf("unexpected action request %+v", service.lastAction)
+	}
+	if service.lastAction.Viewer.Filters["region"] != "emea" {
+		t.Fatalf("expected viewer filters resolved from query, got %+v", service.lastAction.Viewer.Filters)
+	}
+
+	missing, err := app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/widgets/w1/actions/unknown", nil))
+	if err != nil {
+		t.Fatalf("action request failed: %v", err)
+	}
+	_ = missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unsupported action, got %d", missing.StatusCode)
+	}
+
+	broken, err := app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/widgets/w1/actions/broken", nil))
+	if err != nil {
+		t.Fatalf("action request failed: %v", err)
+	}
+	body, _ = io.ReadAll(broken.Body)
+	_ = broken.Body.Close()
+	if broken.StatusCode != http.StatusInternalServerError || !strings.Contains(string(body), "repository unavailable") {
+		t.Fatalf("expected 500 when the export fails before writing, got %d: %s", broken.StatusCode, body)
+	}
+}
+
+type stubTranslationReporter struct {
+	*stubLayoutResolver
+	*dashboard.TranslationDiagnostics
+}
+
+func TestDiagnosticsRouteReportsMissingTranslations(t *testing.T) {
+	translations := dashboard.NewTranslationDiagnostics(nil)
+	translations.RecordMissingTranslation("es", "dashboard.widget.kpi.previous_period", "vs previous period")
+	service := &stubTranslationReporter{stubLayoutResolver: &stubLayoutResolver{}, TranslationDiagnostics: translations}
+	controller := dashboard.NewController(dashboard.ControllerOptions{
+		Service:  service,
+		Renderer: &stubRenderer{},
+	})
+	server := router.NewFiberAdapter()
+	if err := Register(Config[*fiber.App]{
+		Router:     server.Router(),
+		Controller: controller,
+		Routes:     RouteConfig{Diagnostics: "/dashboard/_diagnostics"},
+	}); err != nil {
+		t.Fatalf("register returned error: %v", err)
+	}
+	app := server.(interface{ WrappedRouter() *fiber.App }).WrappedRouter()
+
+	resp, err := app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/_diagnostics", nil))
+	if err != nil {
+		t.Fatalf("diagnostics request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	var payload struct {
+		Missing map[string][]dashboard.MissingTranslation `json:"missing_translations"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode diagnostics: %v", err)
+	}
+	if got := payload.Missing["es"]; len(got) != 1 || got[0].Key != "dashboard.widget.kpi.previous_period" || got[0].Count != 1 {
+		t.Fatalf("expected recorded missing key, got %s", body)
+	}
+}
+
+type stubConfigService struct {
+	stubLayoutResolver
+	stored map[string]any
+}
+
+func (s *stubConfigService) WidgetConfigForm(_ context.Context, req dashboard.WidgetConfigFormRequest) (dashboard.ConfigForm, error) {
+	def := dashboard.WidgetDefinition{Code: "admin.widget.kpi", Schema: map[string]any{
+		"type":     "object",
+		"required": []any{"metric"},
+		"properties": map[string]any{
+			"metric":   map[string]any{"type": "string", "minLength": 1},
+			"decimals": map[string]any{"type": "integer", "minimum": 0, "maximum": 6},
+		},
+	}}
+	values := req.Configuration
+	if values == nil && req.WidgetID != "" {
+		values = s.stored
+	}
+	form := dashboard.BuildConfigForm(def, values)
+	form.WidgetID = req.WidgetID
+	if req.Configuration != nil {
+		form.SetErrors(dashboard.ConfigFieldErrors(dashboard.NewJSONSchemaValidator().Validate(def, req.Configuration)))
+	}
+	return form, nil
+}
+
+type updatingExecutor struct {
+	noopExecutor
+	updates []dashboard.UpdateWidgetInput
+	assigns []dashboard.AddWidgetRequest
+}
+
+func (e *updatingExecutor) Assign(_ context.Context, req dashboard.AddWidgetRequest) error {
+	e.assigns = append(e.assigns, req)
+	return nil
+}
+
+func (e *updatingExecutor) Update(_ context.Context, input dashboard.UpdateWidgetInput) error {
+	e.updates = append(e.updates, input)
+	return nil
+}
+
+func TestWidgetConfigRoutesServeAndSubmitForms(t *testing.T) {
+	renderer, err := dashboard.NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	service := &stubConfigService{stored: map[string]any{"metric": "revenue"}}
+	controller := dashboard.NewController(dashboard.ControllerOptions{Service: service, Renderer: renderer, EditMode: &dashboard.EditModeOptions{}})
+	exec := &updatingExecutor{}
+	server := router.NewFiberAdapter()
+	if err := Register(Config[*fiber.App]{Router: server.Router(), Controller: controller, API: exec}); err != nil {
+		t.Fatalf("register returned error: %v", err)
+	}
+	app := server.(interface{ WrappedRouter() *fiber.App }).WrappedRouter()
+	do := func(method, target, contentType, body string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequestWithContext(t.Context(), method, target, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, target, err)
+		}
+		payload, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return resp.StatusCode, string(payload)
+	}
+
+	status, body := do(http.MethodGet, "/admin/dashboard/widgets/kpi-1/config", "", "")
+	var form dashboard.ConfigForm
+	if err := json.Unmarshal([]byte(body), &form); err != nil || status != http.StatusOK {
+		t.Fatalf("expected JSON descriptor, got %d %s", status, body)
+	}
+	if form.Action != "/admin/dashboard/widgets/kpi-1/config" || len(form.Fields) != 2 || form.Fields[0].Text != "revenue" {
+		t.Fatalf("unexpected descriptor %+v", form)
+	}
+
+	status, body = do(http.MethodGet, "/admin/dashboard/widgets/kpi-1/config?format=html", "", "")
+	if status != http.StatusOK || !strings.Contains(body, `action="/admin/dashboard/widgets/kpi-1/config"`) || !strings.Contains(body, `name="decimals"`) {
+		t.Fatalf("expected HTML partial, got %d %s", status, body)
+	}
+
+	status, body = do(http.MethodGet, "/admin/dashboard/definitions/admin.widget.kpi/config", "", "")
+	if status != http.StatusOK || !strings.Contains(body, `"definition":"admin.widget.kpi"`) {
+		t.Fatalf("expected definition descriptor, got %d %s", status, body)
+	}
+
+	status, body = do(http.MethodPost, "/admin/dashboard/widgets/kpi-1/config", "application/json", `{"configuration":{"metric":"orders","decimals":9}}`)
+	if status != http.StatusUnprocessableEntity || !strings.Contains(body, `"path":"decimals"`) || len(exec.updates) != 0 {
+		t.Fatalf("expected 422 with field errors, got %d %s", status, body)
+	}
+
+	status, body = do(http.MethodPost, "/admin/dashboard/widgets/kpi-1/config?validate=true", "application/json", `{"configuration":{"metric":"orders"}}`)
+	if status != http.StatusOK || len(exec.updates) != 0 {
+		t.Fatalf("expected validate-only success, got %d %s", status, body)
+	}
+
+	status, body = do(http.MethodPost, "/admin/dashboard/widgets/kpi-1/config?format=html", "application/x-www-form-urlencoded", "metric=orders&decimals=two")
+	if status != http.StatusUnprocessableEntity || !strings.Contains(body, "is not a whole number") {
+		t.Fatalf("expected HTML form with decode error, got %d %s", status, body)
+	}
+
+	status, body = do(http.MethodPost, "/admin/dashboard/widgets/kpi-1/config", "application/x-www-form-urlencoded", "metric=orders&decimals=3")
+	if status != http.StatusOK || !strings.Contains(body, "updated") || len(exec.updates) != 1 {
+		t.Fatalf("expected update, got %d %s", status, body)
+	}
+	if got := exec.updates[0]; got.WidgetID != "kpi-1" || got.Configuration["metric"] != "orders" || got.Configuration["decimals"] != 3 {
+		t.Fatalf("unexpected update input %+v", got)
+	}
+
+	status, body = do(http.MethodPost, "/admin/dashboard/definitions/admin.widget.kpi/config", "application/x-www-form-urlencoded", "metric=orders")
+	if status != http.StatusOK || len(exec.assigns) != 0 {
+		t.Fatalf("expected definition POST without area to only validate, got %d %s", status, body)
+	}
+	status, body = do(http.MethodPost, "/admin/dashboard/definitions/admin.widget.kpi/config?format=html", "application/x-www-form-urlencoded", "metric=orders&area_code=admin.dashboard.main")
+	if status != http.StatusCreated || len(exec.assigns) != 1 {
+		t.Fatalf("expected widget created from definition form, got %d %s", status, body)
+	}
+	if got := exec.assigns[0]; got.DefinitionID != "admin.widget.kpi" || got.AreaCode != "admin.dashboard.main" || got.Configuration["metric"] != "orders" {
+		t.Fatalf("unexpected assign request %+v", got)
+	}
+
+	if status, _ = do(http.MethodGet, "/admin/dashboard/_layout?edit=1", "", ""); status != http.StatusOK || !service.lastViewer.Editing {
+		t.Fatalf("expected ?edit=1 to resolve an editing viewer, got %d %+v", status, service.lastViewer)
+	}
+}
+
+func TestCatalogRouteServesJSONAndHTML(t *testing.T) {
+	registry := dashboard.NewRegistry()
+	index := dashboard.NewCatalogIndex("acme", &dashboard.WidgetManifestDocument{
+		Version: dashboard.ManifestVersion,
+		Widgets: []dashboard.ManifestWidget{
+			{Definition: dashboard.WidgetDefinition{Code: "acme.widget.weather", Name: "Weather"}, Provider: dashboard.ManifestProvider{Channel: "partner"}},
+			{Definition: dashboard.WidgetDefinition{Code: "acme.widget.news", Name: "News"}},
+		},
+	})
+	if err := registry.MergeCatalogIndex(index); err != nil {
+		t.Fatalf("merge catalog index: %v", err)
+	}
+	renderer, err := dashboard.NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("renderer: %v", err)
+	}
+	controller := dashboard.NewController(dashboard.ControllerOptions{
+		Service:  dashboard.NewService(dashboard.Options{Providers: registry}),
+		Renderer: renderer,
+	})
+	server := router.NewFiberAdapter()
+	if err := Register(Config[*fiber.App]{
+		Router:     server.Router(),
+		Controller: controller,
+		Routes:     RouteConfig{Catalog: "/dashboard/catalog"},
+	}); err != nil {
+		t.Fatalf("register returned error: %v", err)
+	}
+	app := server.(interface{ WrappedRouter() *fiber.App }).WrappedRouter()
+
+	resp, err := app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/catalog?channel=partner&status=available", nil))
+	if err != nil {
+		t.Fatalf("catalog request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	var page dashboard.CatalogPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatalf("decode catalog: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Code != "acme.widget.weather" {
+		t.Fatalf("expected only the partner widget, got %s", body)
+	}
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/dashboard/catalog", nil)
+	req.Header.Set("Accept", "text/html")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("catalog html request failed: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `data-widget-code="acme.widget.news"`) {
+		t.Fatalf("expected catalog HTML, got %d: %s", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,178 @@
+package dashboard
+
+import (
+	"maps"
+	"slices"
+)
+
+// GridColumns is the column count of the free-form grid.
+const GridColumns = 12
+
+// GridPlacement positions a widget on an area's free-form grid. X and W are
+// columns (0-based x, 1-12 wide); Y and H are rows of the area's row height.
+type GridPlacement struct {
+	ID string `json:"id" yaml:"id"`
+	X  int    `json:"x" yaml:"x"`
+	Y  int    `json:"y" yaml:"y"`
+	W  int    `json:"w" yaml:"w"`
+	H  int    `json:"h" yaml:"h"`
+}
+
+func (p GridPlacement) overlaps(other GridPlacement) bool {
+	return p.X < other.X+other.W && other.X < p.X+p.W &&
+		p.Y < other.Y+other.H && other.Y < p.Y+p.H
+}
+
+// normalize clamps the placement inside the grid: 1 <= W <= 12,
+// 0 <= X <= 12-W, H >= 1 and Y >= 0.
+func (p GridPlacement) normalize() GridPlacement {
+	if p.W <= 0 || p.W > GridColumns {
+		p.W = GridColumns
+	}
+	p.X = min(max(p.X, 0), GridColumns-p.W)
+	p.H = max(p.H, 1)
+	p.Y = max(p.Y, 0)
+	return p
+}
+
+// sortGridPlacements orders placements top-to-bottom, then left-to-right.
+// Ties keep their input order, which decides who wins a collision.
+func sortGridPlacements(items []GridPlacement) {
+	slices.SortStableFunc(items, func(a, b GridPlacement) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+}
+
+// ResolveGridCollisions clamps placements to the grid, drops duplicates and
+// pushes overlapping widgets down until nothing overlaps. Earlier widgets
+// (top-left first, then input order) keep their position.
+func ResolveGridCollisions(items []GridPlacement) []GridPlacement {
+	return placeGrid(items, false)
+}
+
+// CompactGrid resolves collisions and moves every widget up as far as it
+// can go, closing the gaps left by removed or hidden widgets.
+func CompactGrid(items []GridPlacement) []GridPlacement {
+	return placeGrid(items, true)
+}
+
+func placeGrid(items []GridPlacement, compact bool) []GridPlacement {
+	if len(items) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(items))
+	out := make([]GridPlacement, 0, len(items))
+	for _, item := range items {
+		if item.ID == "" || seen[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+		out = append(out, item.normalize())
+	}
+	sortGridPlacements(out)
+	placed := make([]GridPlacement, 0, len(out))
+	for _, item := range out {
+		if compact {
+			item.Y = 0
+		}
+		for {
+			collided := false
+			for _, other := range placed {
+				if item.overlaps(other) {
+					item.Y = other.Y + other.H
+					collided = true
+				}
+			}
+			if !collided {
+				break
+			}
+		}
+		placed = append(placed, item)
+	}
+	sortGridPlacements(placed)
+	return placed
+}
+
+// applyGridMetadata positions widgets from the area grid, appending widgets
+// without a placement below the grid at their row width, compacts the result
+// and reorders widgets top-to-bottom so the document order follows the grid.
+func applyGridMetadata(widgets []WidgetInstance, grid []GridPlacement) []WidgetInstance {
+	if len(widgets) == 0 || len(grid) == 0 {
+		return widgets
+	}
+	byID := make(map[string]GridPlacement, len(grid))
+	for _, placement := range grid {
+		byID[placement.ID] = placement
+	}
+	bottom := 0
+	for _, placement := range grid {
+		bottom = max(bottom, placement.Y+placement.H)
+	}
+	items := make([]GridPlacement, 0, len(widgets))
+	for _, w := range widgets {
+		placement, ok := byID[w.ID]
+		if !ok {
+			placement = GridPlacement{ID: w.ID, Y: bottom, W: widgetSpan(w.Metadata), H: 1}
+			bottom++
+		}
+		placement.ID = w.ID
+		items = append(items, placement)
+	}
+	items = CompactGrid(items)
+
+	index := make(map[string]int, len(widgets))
+	for i, w := range widgets {
+		index[w.ID] = i
+	}
+	result := make([]WidgetInstance, 0, len(widgets))
+	for _, placement := range items {
+		w := widgets[index[placement.ID]]
+		metadata := maps.Clone(w.Metadata)
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		metadata["layout"] = map[string]any{
+			"row":     placement.Y,
+			"column":  placement.X,
+			"width":   placement.W,
+			"columns": placement.W,
+			"x":       placement.X,
+			"y":       placement.Y,
+			"w":       placement.W,
+			"h":       placement.H,
+		}
+		w.Metadata = metadata
+		result = append(result, w)
+	}
+	return result
+}
+
+// compactAreaGrids resolves and compacts every saved area grid.
+func compactAreaGrids(grids map[string][]GridPlacement) {
+	for area, items := range grids {
+		if compacted := CompactGrid(items); len(compacted) > 0 {
+			grids[area] = compacted
+		} else {
+			delete(grids, area)
+		}
+	}
+}
+
+// gridPlaced reports whether the widget is positioned on a free-form grid.
+func (widget WidgetFrame) gridPlaced() bool {
+	return widget.Meta.Layout != nil && widget.Meta.Layout.H > 0
+}
+
+func cloneAreaGrids(grids map[string][]GridPlacement) map[string][]GridPlacement {
+	if grids == nil {
+		return nil
+	}
+	out := make(map[string][]GridPlacement, len(grids))
+	for area, items := range grids {
+		out[area] = slices.Clone(items)
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompactGridResolvesCollisionsAndClosesGaps(t *testing.T) {
+	items := []GridPlacement{
+		{ID: "a", X: 0, Y: 0, W: 6, H: 2},
+		{ID: "b", X: 4, Y: 1, W: 4, H: 1},
+		{ID: "c", X: 10, Y: 5, W: 4, H: 1},
+		{ID: "a", X: 6, Y: 0, W: 6, H: 1},
+		{ID: "", X: 0, Y: 0, W: 1, H: 1},
+	}
+	resolved := ResolveGridCollisions(items)
+	want := []GridPlacement{
+		{ID: "a", X: 0, Y: 0, W: 6, H: 2},
+		{ID: "b", X: 4, Y: 2, W: 4, H: 1},
+		{ID: "c", X: 8, Y: 5, W: 4, H: 1},
+	}
+	if !reflect.DeepEqual(resolved, want) {
+		t.Fatalf("expected collisions pushed down with gaps kept, got %+v", resolved)
+	}
+
+	compacted := CompactGrid(items)
+	want = []GridPlacement{
+		{ID: "a", X: 0, Y: 0, W: 6, H: 2},
+		{ID: "c", X: 8, Y: 0, W: 4, H: 1},
+		{ID: "b", X: 4, Y: 2, W: 4, H: 1},
+	}
+	if !reflect.DeepEqual(compacted, want) {
+		t.Fatalf("expected compacted grid, got %+v", compacted)
+	}
+}
+
+func TestConfigureLayoutPlacesWidgetsOnGrid(t *testing.T) {
+	instances := []WidgetInstance{
+		{ID: "w1", DefinitionID: "admin.widget.user_stats", Configuration: map[string]any{"metric": "total"}, Metadata: map[string]any{"data": WidgetData{"values": map[string]any{"total": 1}}}},
+		{ID: "w2", DefinitionID: "admin.widget.user_stats", Configuration: map[string]any{"metric": "total"}, Metadata: map[string]any{"data": WidgetData{"values": map[string]any{"total": 2}}}},
+		{ID: "w3", DefinitionID: "admin.widget.user_stats", Configuration: map[string]any{"metric": "total"}, Metadata: map[string]any{"data": WidgetData{"values": map[string]any{"total": 3}}}},
+	}
+	store := &fakeWidgetStore{resolved: map[string][]WidgetInstance{"admin.dashboard.main": instances}}
+	prefs := NewInMemoryPreferenceStore()
+	service := NewService(Options{WidgetStore: store, PreferenceStore: prefs})
+	viewer := ViewerContext{UserID: "ops"}
+	var input SaveLayoutPreferencesInput
+	if err := json.Unmarshal([]byte(`{"layout_grid": {"admin.dashboard.main": [
+		{"id": "w1", "x": 0, "y": 3, "w": 4, "h": 2},
+		{"id": "w2", "x": 2, "y": 4, "w": 8, "h": 1}
+	]}}`), &input); err != nil {
+		t.Fatalf("decode preferences: %v", err)
+	}
+	input.Viewer = viewer
+	if err := (&ServiceExecutor{Service: service}).Preferences(context.Background(), input); err != nil {
+		t.Fatalf("Preferences returned error: %v", err)
+	}
+	saved, _ := prefs.LayoutOverrides(context.Background(), viewer)
+	if got := saved.AreaGrids["admin.dashboard.main"]; !reflect.DeepEqual(got, []GridPlacement{
+		{ID: "w1", X: 0, Y: 0, W: 4, H: 2},
+		{ID: "w2", X: 2, Y: 2, W: 8, H: 1},
+	}) {
+		t.Fatalf("expected saved grid compacted, got %+v", got)
+	}
+
+	layout, err := service.ConfigureLayout(context.Background(), viewer)
+	if err != nil {
+		t.Fatalf("ConfigureLayout returned error: %v", err)
+	}
+	widgets := layout.Areas["admin.dashboard.main"]
+	if len(widgets) != 3 || widgets[2].ID != "w3" {
+		t.Fatalf("expected unplaced widget appended below the grid, got %+v", widgets)
+	}
+	if got := widgets[2].Metadata["layout"].(map[string]any); got["y"] != 3 || got["w"] != 12 || got["h"] != 1 {
+		t.Fatalf("expected full-width placement below the grid, got %+v", got)
+	}
+
+	store.resolved["admin.dashboard.main"] = instances[1:]
+	controller := NewController(ControllerOptions{Service: service})
+	page, err := controller.Page(context.Background(), viewer)
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	second := page.Areas[0].Widgets[0].Meta.Layout
+	if second.X != 2 || second.Y != 0 || second.W != 8 || second.H != 1 {
+		t.Fatalf("expected removed widget's space compacted, got %+v", second)
+	}
+
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`class="dashboard-area dashboard-area--grid"`,
+		`dashboard-widget--span-8 dashboard-widget--grid"`,
+		`--dashboard-widget-x: 3; --dashboard-widget-y: 1; --dashboard-widget-w: 8; --dashboard-widget-h: 1`,
+		LayoutStylesURL(""),
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in rendered page:\n%s", want, html)
+		}
+	}
+}
@@ -21,9 +21,13 @@ type CommandExecutor struct {
 	ReorderCommander gocommand.Commander[dashboard.ReorderWidgetsInput]
 	RefreshCommander gocommand.Commander[dashboard.RefreshWidgetInput]
 	PrefsCommander   gocommand.Commander[dashboard.SaveLayoutPreferencesInput]
+	UpdateCommander  gocommand.Commander[dashboard.UpdateWidgetInput]
 }
 
-var _ dashboard.Executor = (*CommandExecutor)(nil)
+var (
+	_ dashboard.Executor      = (*CommandExecutor)(nil)
+	_ dashboard.WidgetUpdater = (*CommandExecutor)(nil)
+)
 
 // NewServiceExecutor creates an executor backed directly by the shared dashboard service.
 func NewServiceExecutor(service dashboard.ServiceExecutorService) *dashboard.ServiceExecutor {
@@ -46,6 +50,14 @@ func (e *CommandExecutor) Remove(ctx context.Context, input dashboard.RemoveWidg
 	return e.RemoveCommander.Execute(ctx, input)
 }
 
+// Update delegates configuration updates to the configured command.
+func (e *CommandExecutor) Update(ctx context.Context, input dashboard.UpdateWidgetInput) error {
+	if e == nil || e.UpdateCommander == nil {
+		return errors.New("dashboard: update command not configured")
+	}
+	return e.UpdateCommander.Execute(ctx, input)
+}
+
 // Reorder delegates ordering changes to the configured command.
 func (e *CommandExecutor) Reorder(ctx context.Context, input dashboard.ReorderWidgetsInput) error {
 	if e == nil || e.ReorderCommander == nil {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/url"
 
 	"github.com/goliatone/go-dashboard/components/dashboard"
 )
@@ -52,6 +53,162 @@ func Layout(ctx context.Context, controller *dashboard.Controller, viewer dashbo
 	return controller.LayoutPayload(ctx, viewer)
 }
 
+// Diagnostics resolves typed operational state (layout, theme, page and
+// missing translations) through the shared controller.
+func Diagnostics(ctx context.Context, controller *dashboard.Controller, viewer dashboard.ViewerContext) (dashboard.DashboardDiagnostics, error) {
+	if controller == nil {
+		return dashboard.DashboardDiagnostics{}, errors.New("dashboard: controller not configured")
+	}
+	return controller.Diagnostics(ctx, viewer)
+}
+
+// CatalogPage builds the widget catalog browser page through the shared
+// controller.
+func CatalogPage(ctx context.Context, controller *dashboard.Controller, req dashboard.CatalogPageRequest) (dashboard.CatalogPage, error) {
+	if controller == nil {
+		return dashboard.CatalogPage{}, errors.New("dashboard: controller not configured")
+	}
+	return controller.CatalogPage(ctx, req)
+}
+
+// RenderCatalog renders the widget catalog browser page as HTML.
+func RenderCatalog(controller *dashboard.Controller, page dashboard.CatalogPage) ([]byte, error) {
+	if controller == nil {
+		return nil, errors.New("dashboard: controller not configured")
+	}
+	var buf bytes.Buffer
+	if err := controller.RenderCatalog(page, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WidgetAction executes a widget provider action through the shared controller.
+func WidgetAction(ctx context.Context, controller *dashboard.Controller, req dashboard.WidgetActionRequest) (dashboard.WidgetActionResult, error) {
+	if controller == nil {
+		return dashboard.WidgetActionResult{}, errors.New("dashboard: controller not configured")
+	}
+	return controller.WidgetAction(ctx, req)
+}
+
+// ConfigSubmission is a configuration form submission. Request carries the
+// submitted configuration; DecodeErrors are parse failures from an HTML form
+// (see DecodeConfigForm). ValidateOnly checks the configuration without saving.
+type ConfigSubmission struct {
+	Request      dashboard.WidgetConfigFormRequest
+	DecodeErrors []dashboard.ConfigFieldError
+	ValidateOnly bool
+	// AreaCode places a new widget created by SubmitNewWidget.
+	AreaCode string
+	// TenantID is passed to the update and create commands.
+	TenantID string
+}
+
+// ConfigForm builds a widget configuration form descriptor through the shared
+// controller.
+func ConfigForm(ctx context.Context, controller *dashboard.Controller, req dashboard.WidgetConfigFormRequest) (dashboard.ConfigForm, error) {
+	if controller == nil {
+		return dashboard.ConfigForm{}, errors.New("dashboard: controller not configured")
+	}
+	return controller.ConfigForm(ctx, req)
+}
+
+// RenderConfigForm renders a configuration form as an HTML partial.
+func RenderConfigForm(controller *dashboard.Controller, form dashboard.ConfigForm) ([]byte, error) {
+	if controller == nil {
+		return nil, errors.New("dashboard: controller not configured")
+	}
+	var buf bytes.Buffer
+	if err := controller.RenderConfigForm(form, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeConfigForm converts form-encoded values into a configuration using the
+// widget's form descriptor. req.Configuration is ignored.
+func DecodeConfigForm(ctx context.Context, controller *dashboard.Controller, req dashboard.WidgetConfigFormRequest, values url.Values) (map[string]any, []dashboard.ConfigFieldError, error) {
+	req.Configuration = nil
+	form, err := ConfigForm(ctx, controller, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	config, errs := form.Decode(values)
+	return config, errs, nil
+}
+
+// SubmitConfig validates a configuration submission and, unless the request is
+// validate-only, saves it through the updater. The returned form carries field
+// errors; invalid submissions reply 422 with the form as payload.
+func SubmitConfig(ctx context.Context, controller *dashboard.Controller, updater dashboard.WidgetUpdater, submission ConfigSubmission) (Response, dashboard.ConfigForm, error) {
+	req := submission.Request
+	if req.Configuration == nil {
+		req.Configuration = map[string]any{}
+	}
+	form, err := ConfigForm(ctx, controller, req)
+	if err != nil {
+		return Response{}, dashboard.ConfigForm{}, err
+	}
+	if len(submission.DecodeErrors) > 0 {
+		form.SetErrors(append(append([]dashboard.ConfigFieldError{}, submission.DecodeErrors...), form.Errors...))
+	}
+	if !form.Valid {
+		return Response{StatusCode: 422, Payload: form}, form, nil
+	}
+	if submission.ValidateOnly {
+		return Response{StatusCode: 200, Payload: form}, form, nil
+	}
+	if updater == nil {
+		return Response{}, form, errors.New("dashboard: executor does not support widget updates")
+	}
+	if form.WidgetID == "" {
+		return Response{}, form, errors.New("dashboard: widget id is required")
+	}
+	err = updater.Update(ctx, dashboard.UpdateWidgetInput{
+		WidgetID:      form.WidgetID,
+		Configuration: req.Configuration,
+		ActorID:       req.Viewer.UserID,
+		UserID:        req.Viewer.UserID,
+		TenantID:      submission.TenantID,
+	})
+	if err != nil {
+		return Response{}, form, err
+	}
+	form.Saved = true
+	return Response{StatusCode: 200, Payload: map[string]string{"status": "updated"}}, form, nil
+}
+
+// SubmitNewWidget validates a definition form submission and creates the
+// widget in submission.AreaCode. Invalid submissions reply 422 with the form.
+func SubmitNewWidget(ctx context.Context, controller *dashboard.Controller, api dashboard.Executor, submission ConfigSubmission) (Response, dashboard.ConfigForm, error) {
+	submission.ValidateOnly = true
+	reply, form, err := SubmitConfig(ctx, controller, nil, submission)
+	if err != nil || !form.Valid {
+		return reply, form, err
+	}
+	if submission.AreaCode == "" {
+		return Response{}, form, errors.New("dashboard: area code is required")
+	}
+	req := submission.Request
+	if req.Configuration == nil {
+		req.Configuration = map[string]any{}
+	}
+	reply, err = Assign(ctx, api, dashboard.AddWidgetRequest{
+		DefinitionID:  form.Definition,
+		AreaCode:      submission.AreaCode,
+		Configuration: req.Configuration,
+		UserID:        req.Viewer.UserID,
+		ActorID:       req.Viewer.UserID,
+		TenantID:      submission.TenantID,
+		Locale:        req.Viewer.Locale,
+	})
+	if err != nil {
+		return Response{}, form, err
+	}
+	form.Saved = true
+	return reply, form, nil
+}
+
 // Assign creates a widget and returns the canonical response envelope.
 func Assign(ctx context.Context, api dashboard.Executor, req dashboard.AddWidgetRequest) (Response, error) {
 	if api == nil {
@@ -201,6 +358,12 @@ func hasCanonicalPreferencesPayload(body map[string]any) bool {
 	if _, ok := body["hidden_widget_ids"]; ok {
 		return true
 	}
+	if _, ok := body["breakpoint_order"]; ok {
+		return true
+	}
+	if _, ok := body["layout_grid"]; ok {
+		return true
+	}
 	if _, ok := body["viewer"]; ok {
 		return true
 	}
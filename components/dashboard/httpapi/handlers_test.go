@@ -215,3 +215,67 @@ func TestLayoutSurfacesWidgetSerializeFailures(t *testing.T) {
 		t.Fatalf("expected Layout helper to surface widget serialize failure")
 	}
 }
+
+func TestSubmitConfigValidatesBeforeUpdating(t *testing.T) {
+	store := &serviceExecutorWidgetStore{instance: dashboard.WidgetInstance{
+		ID:            "kpi-1",
+		DefinitionID:  "admin.widget.kpi",
+		Configuration: map[string]any{"metric": "revenue"},
+	}}
+	controller := dashboard.NewController(dashboard.ControllerOptions{
+		Service: dashboard.NewService(dashboard.Options{WidgetStore: store}),
+	})
+	update := &stubCommander[dashboard.UpdateWidgetInput]{}
+	exec := &CommandExecutor{UpdateCommander: update}
+	viewer := dashboard.ViewerContext{UserID: "user-1"}
+
+	reply, form, err := SubmitConfig(context.Background(), controller, exec, ConfigSubmission{Request: dashboard.WidgetConfigFormRequest{
+		WidgetID:      "kpi-1",
+		Viewer:        viewer,
+		Configuration: map[string]any{"metric": "revenue", "decimals": 12},
+	}})
+	if err != nil {
+		t.Fatalf("SubmitConfig returned error: %v", err)
+	}
+	if reply.StatusCode != 422 || form.Valid || update.calls != 0 {
+		t.Fatalf("expected 422 without update, got %d %+v", reply.StatusCode, form.Errors)
+	}
+
+	reply, _, err = SubmitConfig(context.Background(), controller, exec, ConfigSubmission{
+		Request:      dashboard.WidgetConfigFormRequest{WidgetID: "kpi-1", Viewer: viewer, Configuration: map[string]any{"metric": "orders"}},
+		ValidateOnly: true,
+	})
+	if err != nil || reply.StatusCode != 200 || update.calls != 0 {
+		t.Fatalf("expected validate-only success without update, got %d %v", reply.StatusCode, err)
+	}
+
+	reply, _, err = SubmitConfig(context.Background(), controller, exec, ConfigSubmission{
+		Request:      dashboard.WidgetConfigFormRequest{WidgetID: "kpi-1", Viewer: viewer, Configuration: map[string]any{"metric": "orders"}},
+		DecodeErrors: []dashboard.ConfigFieldError{{Path: "decimals", Message: `"x" is not a whole number`}},
+	})
+	if err != nil || reply.StatusCode != 422 || update.calls != 0 {
+		t.Fatalf("expected decode errors to block update, got %d %v", reply.StatusCode, err)
+	}
+
+	reply, form, err = SubmitConfig(context.Background(), controller, exec, ConfigSubmission{Request: dashboard.WidgetConfigFormRequest{
+		WidgetID:      "kpi-1",
+		Viewer:        viewer,
+		Configuration: map[string]any{"metric": "orders"},
+	}, TenantID: "tenant-1"})
+	if err != nil {
+		t.Fatalf("SubmitConfig returned error: %v", err)
+	}
+	if reply.StatusCode != 200 || !form.Saved || update.calls != 1 {
+		t.Fatalf("expected update, got %d %+v", reply.StatusCode, reply.Payload)
+	}
+	if update.last.WidgetID != "kpi-1" || update.last.Configuration["metric"] != "orders" || update.last.ActorID != "user-1" || update.last.TenantID != "tenant-1" {
+		t.Fatalf("unexpected update input %+v", update.last)
+	}
+
+	if _, _, err := SubmitConfig(context.Background(), controller, exec, ConfigSubmission{Request: dashboard.WidgetConfigFormRequest{
+		WidgetID:      "kpi-1",
+		Configuration: map[string]any{"metric": "orders"},
+	}}); !errors.Is(err, dashboard.ErrWidgetConfigForbidden) || update.calls != 1 {
+		t.Fatalf("expected anonymous submission to be refused, got %v", err)
+	}
+}
@@ -12,6 +12,54 @@ type TranslationService interface {
 	Translate(ctx context.Context, key, locale string, args map[string]any) (string, error)
 }
 
+// Text directions reported by LocaleDirection and exposed as `dir` on pages and shells.
+const (
+	TextDirectionLTR = "ltr"
+	TextDirectionRTL = "rtl"
+)
+
+var (
+	rtlLanguages = map[string]bool{
+		"ar": true, "arc": true, "ckb": true, "dv": true, "fa": true, "he": true, "iw": true,
+		"ks": true, "ku": true, "ps": true, "sd": true, "syr": true, "ug": true, "ur": true, "yi": true,
+	}
+	rtlScripts = map[string]bool{
+		"adlm": true, "arab": true, "hebr": true, "nkoo": true, "rohg": true, "syrc": true, "thaa": true,
+	}
+)
+
+// LocaleDirection returns the text direction for a BCP 47 locale. An explicit
+// script subtag wins (`pa-Arab` is right-to-left, `ku-Latn` left-to-right);
+// otherwise the base language decides. Unknown and empty locales are
+// left-to-right.
+func LocaleDirection(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(normalizeLocale(locale), "_", "-"), "-")
+	for _, part := range parts[1:] {
+		if len(part) != 4 || part[0] < 'a' || part[0] > 'z' {
+			continue
+		}
+		if rtlScripts[part] {
+			return TextDirectionRTL
+		}
+		return TextDirectionLTR
+	}
+	if rtlLanguages[parts[0]] {
+		return TextDirectionRTL
+	}
+	return TextDirectionLTR
+}
+
+func normalizeTextDirection(dir string) string {
+	switch normalizeLocale(dir) {
+	case TextDirectionLTR:
+		return TextDirectionLTR
+	case TextDirectionRTL:
+		return TextDirectionRTL
+	default:
+		return ""
+	}
+}
+
 // ResolveLocalizedValue selects the best translation for the provided locale and falls back to the supplied value.
 // Keys are matched case-insensitively, and language-region pairs (`es-mx`) automatically fall back to their
 // base language (`es`) when present.
@@ -36,6 +84,24 @@ func ResolveLocalizedValue(values map[string]string, locale, fallback string) st
 	return fallback
 }
 
+// HasLocalizedValue reports whether values carries a translation for locale
+// itself or its base language, ignoring the `default` entry. Tooling uses it
+// to check translation coverage with the same matching as
+// ResolveLocalizedValue.
+func HasLocalizedValue(values map[string]string, locale string) bool {
+	for _, candidate := range localeCandidates(locale) {
+		if candidate == "" || candidate == "default" {
+			continue
+		}
+		for key, value := range values {
+			if strings.EqualFold(key, candidate) && value != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (def *WidgetDefinition) normalizeLocalizedFields() {
 	def.NameLocalized = normalizeLocaleMap(def.NameLocalized)
 	def.DescriptionLocalized = normalizeLocaleMap(def.DescriptionLocalized)
@@ -88,6 +154,9 @@ func translateOrFallback(ctx context.Context, svc TranslationService, key, local
 		if translated, err := svc.Translate(ctx, key, locale, params); err == nil && translated != "" {
 			return translated
 		}
+		if recorder, ok := svc.(MissingTranslationRecorder); ok {
+			recorder.RecordMissingTranslation(locale, key, fallback)
+		}
 	}
 	if fallback != "" {
 		return fallback
@@ -47,3 +47,23 @@ func TestTranslateOrFallback(t *testing.T) {
 		t.Fatalf("expected fallback on error, got %q", out)
 	}
 }
+
+func TestLocaleDirection(t *testing.T) {
+	cases := map[string]string{
+		"":        TextDirectionLTR,
+		"en-US":   TextDirectionLTR,
+		"ar":      TextDirectionRTL,
+		"he-IL":   TextDirectionRTL,
+		"fa_IR":   TextDirectionRTL,
+		"ckb":     TextDirectionRTL,
+		"pa-Arab": TextDirectionRTL,
+		"ku-Latn": TextDirectionLTR,
+		"de-1901": TextDirectionLTR,
+		"ur-PK":   TextDirectionRTL,
+	}
+	for locale, want := range cases {
+		if got := LocaleDirection(locale); got != want {
+			t.Fatalf("LocaleDirection(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package dashboard
+
+import (
+	"maps"
+	"slices"
+)
+
+// LocalizableSchemaKeyword marks a string property in a widget JSON schema as
+// localizable. Instance configuration may then store either a plain string or
+// a locale map (`{"en": "Revenue", "es": "Ingresos", "default": "Revenue"}`);
+// the service resolves the map for the viewer before providers decode the
+// configuration.
+const LocalizableSchemaKeyword = "x-localizable"
+
+// localizedConfigKeyPattern constrains locale map keys accepted by validation.
+const localizedConfigKeyPattern = `^(default|[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*)$`
+
+// ResolveLocalizedValueWithFallbacks resolves values for locale, then for each
+// fallback locale in order, before falling back to the `default` entry and
+// finally to fallback.
+func ResolveLocalizedValueWithFallbacks(values map[string]string, locale string, fallbackLocales []string, fallback string) string {
+	if len(values) == 0 {
+		return fallback
+	}
+	for _, candidate := range append([]string{locale}, fallbackLocales...) {
+		if normalizeLocale(candidate) == "" {
+			continue
+		}
+		if value := ResolveLocalizedValue(withoutDefaultLocale(values), candidate, ""); value != "" {
+			return value
+		}
+	}
+	return ResolveLocalizedValue(values, "", fallback)
+}
+
+func withoutDefaultLocale(values map[string]string) map[string]string {
+	if _, ok := values["default"]; !ok {
+		return values
+	}
+	out := maps.Clone(values)
+	delete(out, "default")
+	return out
+}
+
+// ResolveLocalizedConfig returns a copy of config in which every property the
+// schema marks as localizable is resolved to a single string for locale and
+// fallbackLocales. Plain string values pass through unchanged; config itself
+// is never modified.
+func ResolveLocalizedConfig(schema, config map[string]any, locale string, fallbackLocales []string) map[string]any {
+	if len(config) == 0 || !schemaHasLocalizable(schema) {
+		return config
+	}
+	resolved, _ := resolveLocalizedNode(schema, config, locale, fallbackLocales).(map[string]any)
+	return resolved
+}
+
+func resolveLocalizedNode(schema map[string]any, value any, locale string, fallbackLocales []string) any {
+	if localizable, _ := schema[LocalizableSchemaKeyword].(bool); localizable {
+		values, ok := localizedValueMap(value)
+		if !ok {
+			return value
+		}
+		// Keep providers on plain strings even when no locale matches and the
+		// map has no default entry.
+		first := ""
+		if keys := slices.Sorted(maps.Keys(values)); len(keys) > 0 {
+			first = values[keys[0]]
+		}
+		return ResolveLocalizedValueWithFallbacks(values, locale, fallbackLocales, first)
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		if len(props) == 0 {
+			return value
+		}
+		out := maps.Clone(typed)
+		for key, propSchema := range props {
+			child, ok := propSchema.(map[string]any)
+			if !ok {
+				continue
+			}
+			if current, exists := out[key]; exists {
+				out[key] = resolveLocalizedNode(child, current, locale, fallbackLocales)
+			}
+		}
+		return out
+	case []any:
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return value
+		}
+		out := make([]any, len(typed))
+		for i, item := range typed {
+			out[i] = resolveLocalizedNode(items, item, locale, fallbackLocales)
+		}
+		return out
+	case []map[string]any:
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return value
+		}
+		out := make([]any, len(typed))
+		for i, item := range typed {
+			out[i] = resolveLocalizedNode(items, item, locale, fallbackLocales)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func localizedValueMap(value any) (map[string]string, bool) {
+	switch typed := value.(type) {
+	case map[string]string:
+		return typed, true
+	case map[string]any:
+		out := make(map[string]string, len(typed))
+		for key, raw := range typed {
+			if text, ok := raw.(string); ok {
+				out[key] = text
+			}
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func schemaHasLocalizable(schema map[string]any) bool {
+	if localizable, _ := schema[LocalizableSchemaKeyword].(bool); localizable {
+		return true
+	}
+	if props, ok := schema["properties"].(map[string]any); ok {
+		for _, prop := range props {
+			if child, ok := prop.(map[string]any); ok && schemaHasLocalizable(child) {
+				return true
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		return schemaHasLocalizable(items)
+	}
+	return false
+}
+
+// localizableValidationSchema rewrites localizable properties so validation
+// accepts either the original string schema or a locale map whose values
+// satisfy it.
+func localizableValidationSchema(schema map[string]any) map[string]any {
+	if !schemaHasLocalizable(schema) {
+		return schema
+	}
+	out := maps.Clone(schema)
+	if localizable, _ := out[LocalizableSchemaKeyword].(bool); localizable {
+		delete(out, LocalizableSchemaKeyword)
+		return map[string]any{
+			"anyOf": []any{
+				out,
+				map[string]any{
+					"type":                 "object",
+					"minProperties":        1,
+					"propertyNames":        map[string]any{"pattern": localizedConfigKeyPattern},
+					"additionalProperties": out,
+				},
+			},
+		}
+	}
+	if props, ok := out["properties"].(map[string]any); ok {
+		rewritten := make(map[string]any, len(props))
+		for key, prop := range props {
+			if child, ok := prop.(map[string]any); ok {
+				rewritten[key] = localizableValidationSchema(child)
+				continue
+			}
+			rewritten[key] = prop
+		}
+		out["properties"] = rewritten
+	}
+	if items, ok := out["items"].(map[string]any); ok {
+		out["items"] = localizableValidationSchema(items)
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestResolveLocalizedValueWithFallbacks(t *testing.T) {
+	values := map[string]string{"fr": "Ventes", "de": "Umsatz", "default": "Sales"}
+	if got := ResolveLocalizedValueWithFallbacks(values, "fr-CA", []string{"de"}, "x"); got != "Ventes" {
+		t.Fatalf("expected base locale match, got %q", got)
+	}
+	if got := ResolveLocalizedValueWithFallbacks(values, "es", []string{"it", "de"}, "x"); got != "Umsatz" {
+		t.Fatalf("expected fallback locale before default, got %q", got)
+	}
+	if got := ResolveLocalizedValueWithFallbacks(values, "es", nil, "x"); got != "Sales" {
+		t.Fatalf("expected default entry, got %q", got)
+	}
+	if got := ResolveLocalizedValueWithFallbacks(map[string]string{"fr": "Ventes"}, "es", nil, "x"); got != "x" {
+		t.Fatalf("expected fallback string, got %q", got)
+	}
+}
+
+func TestResolveLocalizedConfigFollowsSchema(t *testing.T) {
+	schema := tableSchema()
+	config := map[string]any{
+		"dataset": "orders",
+		"title":   map[string]any{"en": "Orders", "es": "Pedidos"},
+		"columns": []any{
+			map[string]any{"key": "total", "label": map[string]any{"es": "Total", "default": "Amount"}},
+			map[string]any{"key": "status", "label": "Status"},
+		},
+		"sort": map[string]any{"es": "not localizable"},
+	}
+
+	resolved := ResolveLocalizedConfig(schema, config, "es-MX", nil)
+	if resolved["title"] != "Pedidos" || resolved["dataset"] != "orders" {
+		t.Fatalf("unexpected resolved config %+v", resolved)
+	}
+	columns := resolved["columns"].([]any)
+	if columns[0].(map[string]any)["label"] != "Total" || columns[1].(map[string]any)["label"] != "Status" {
+		t.Fatalf("expected column labels resolved, got %+v", columns)
+	}
+	if _, ok := resolved["sort"].(map[string]any); !ok {
+		t.Fatalf("expected unmarked fields untouched, got %+v", resolved["sort"])
+	}
+	if _, ok := config["title"].(map[string]any); !ok {
+		t.Fatalf("expected input config to stay unmodified")
+	}
+
+	// No matching locale and no default: the first locale (sorted) wins.
+	if got := ResolveLocalizedConfig(schema, config, "de", nil)["title"]; got != "Orders" {
+		t.Fatalf("expected deterministic fallback, got %v", got)
+	}
+}
+
+func TestJSONSchemaValidatorAcceptsLocaleMapsForLocalizableFields(t *testing.T) {
+	validator := NewJSONSchemaValidator()
+	def := WidgetDefinition{Code: "admin.widget.kpi", Schema: kpiSchema()}
+	valid := map[string]any{
+		"metric": "revenue",
+		"title":  map[string]string{"en": "Revenue", "pt-BR": "Receita", "default": "Revenue"},
+	}
+	if err := validator.Validate(def, valid); err != nil {
+		t.Fatalf("expected locale map to validate, got %v", err)
+	}
+	for name, title := range map[string]any{
+		"bad key":   map[string]any{"not a locale": "x"},
+		"non-text":  map[string]any{"en": 3},
+		"empty map": map[string]any{},
+	} {
+		err := validator.Validate(def, map[string]any{"metric": "revenue", "title": title})
+		if err == nil || !strings.Contains(err.Error(), "failed validation") {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+	if err := validator.Validate(def, map[string]any{"metric": map[string]any{"en": "revenue"}}); err == nil {
+		t.Fatalf("expected non-localizable field to reject locale maps")
+	}
+}
+
+func TestServiceResolvesLocalizedConfigBeforeProviders(t *testing.T) {
+	store := &fakeWidgetStore{resolved: map[string][]WidgetInstance{
+		"admin.dashboard.footer": {{
+			ID:           "qa-1",
+			DefinitionID: "admin.widget.quick_actions",
+			Configuration: map[string]any{"actions": []any{
+				map[string]any{"label": map[string]any{"en": "Invite", "fr": "Inviter"}, "route": "/invite"},
+			}},
+		}},
+	}}
+	svc := NewService(Options{WidgetStore: store})
+	area, err := svc.ResolveArea(context.Background(), ViewerContext{Locale: "es", FallbackLocales: []string{"fr"}}, "admin.dashboard.footer")
+	if err != nil {
+		t.Fatalf("ResolveArea returned error: %v", err)
+	}
+	widget := area.Widgets[0]
+	actions := widget.Configuration["actions"].([]any)
+	if actions[0].(map[string]any)["label"] != "Inviter" {
+		t.Fatalf("expected template config resolved via fallback locale, got %+v", actions)
+	}
+	view, ok := widget.Metadata[widgetViewModelMetadataKey].(JSONViewModel[quickActionsView])
+	if !ok || len(view.Value.Actions) != 1 || view.Value.Actions[0].Label != "Inviter" {
+		t.Fatalf("expected provider to see resolved label, got %+v", widget.Metadata[widgetViewModelMetadataKey])
+	}
+}
@@ -5,14 +5,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 const (
 	manifestVersionV1 = "1"
+	manifestVersionV2 = "2"
 	// ManifestVersion exposes the current manifest format version for tooling.
-	ManifestVersion = manifestVersionV1
+	ManifestVersion = manifestVersionV2
 )
 
 // WidgetManifestDocument models a YAML/JSON manifest describing widgets/providers.
@@ -25,19 +27,32 @@ type WidgetManifestDocument struct {
 	Source   string           `json:"-" yaml:"-"`
 }
 
-// ManifestWidget describes a single widget entry within a manifest.
+// ManifestWidget describes a single widget entry within a manifest. Version
+// and Requires are v2 fields.
 type ManifestWidget struct {
-	Definition  WidgetDefinition `json:"definition" yaml:"definition"`
-	Provider    ManifestProvider `json:"provider" yaml:"provider,omitempty"`
-	Maintainers []string         `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
-	Tags        []string         `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Definition  WidgetDefinition     `json:"definition" yaml:"definition"`
+	Version     string               `json:"version,omitempty" yaml:"version,omitempty"`
+	Requires    ManifestRequirements `json:"requires" yaml:"requires,omitempty"`
+	Provider    ManifestProvider     `json:"provider" yaml:"provider,omitempty"`
+	Maintainers []string             `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+	Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// ManifestRequirements lists the constraints a v2 widget places on the host:
+// the minimum go-dashboard version and the capabilities it depends on.
+type ManifestRequirements struct {
+	Dashboard    string   `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
 }
 
 // ManifestProvider captures discovery metadata about a provider implementation.
+// Factory (v2) names a ProviderFactory registered on the registry; Entry is
+// informational only.
 type ManifestProvider struct {
 	Name         string   `json:"name,omitempty" yaml:"name,omitempty"`
 	Summary      string   `json:"summary,omitempty" yaml:"summary,omitempty"`
 	Entry        string   `json:"entry,omitempty" yaml:"entry,omitempty"`
+	Factory      string   `json:"factory,omitempty" yaml:"factory,omitempty"`
 	Package      string   `json:"package,omitempty" yaml:"package,omitempty"`
 	DocsURL      string   `json:"docs_url,omitempty" yaml:"docs_url,omitempty"`
 	Capabilities []string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
@@ -56,20 +71,99 @@ func (r *Registry) LoadManifestFile(path string) (*WidgetManifestDocument, error
 	return doc, nil
 }
 
-// LoadManifestDocument registers definitions and provider metadata from a decoded manifest.
+// LoadManifestDocument registers definitions and provider metadata from a
+// decoded manifest. For v2 documents it first checks every widget's version
+// and capability requirements and that its provider factory is registered,
+// registering nothing when any constraint is unmet, then builds and registers
+// each widget's provider through its factory.
 func (r *Registry) LoadManifestDocument(doc *WidgetManifestDocument) error {
 	if doc == nil {
 		return fmt.Errorf("dashboard: manifest document is nil")
 	}
-	for _, widget := range doc.Widgets {
+	if err := r.checkManifestRequirements(doc); err != nil {
+		return err
+	}
+	// Validate definitions and build every provider before touching the
+	// registry so a failing widget leaves no partial registration behind.
+	providers := make([]Provider, len(doc.Widgets))
+	for i, widget := range doc.Widgets {
+		def := widget.Definition
+		if def.Code == "" {
+			return fmt.Errorf("dashboard: widget definition code is required in %s", doc.Source)
+		}
+		def.normalizeLocalizedFields()
+		if err := def.Interactions.Validate(); err != nil {
+			return fmt.Errorf("dashboard: register widget %s from %s: %w", def.Code, doc.Source, err)
+		}
+		if widget.Provider.Factory == "" {
+			continue
+		}
+		factory, _ := r.ProviderFactory(widget.Provider.Factory)
+		provider, err := factory(cloneWidgetDefinition(def))
+		if err != nil {
+			return fmt.Errorf("dashboard: provider factory %s for widget %s from %s: %w", widget.Provider.Factory, def.Code, doc.Source, err)
+		}
+		if provider == nil {
+			return fmt.Errorf("dashboard: provider factory %s for widget %s from %s returned no provider", widget.Provider.Factory, def.Code, doc.Source)
+		}
+		providers[i] = provider
+	}
+	for i, widget := range doc.Widgets {
+		code := widget.Definition.Code
 		if err := r.RegisterDefinition(widget.Definition); err != nil {
-			return fmt.Errorf("dashboard: register widget %s from %s: %w", widget.Definition.Code, doc.Source, err)
+			return fmt.Errorf("dashboard: register widget %s from %s: %w", code, doc.Source, err)
+		}
+		r.recordProviderMetadata(code, widget.Provider)
+		r.recordWidgetVersion(code, widget.Version)
+		if providers[i] == nil {
+			continue
+		}
+		if err := r.RegisterProvider(code, providers[i]); err != nil {
+			return fmt.Errorf("dashboard: register provider for widget %s from %s: %w", code, doc.Source, err)
 		}
-		r.recordProviderMetadata(widget.Definition.Code, widget.Provider)
 	}
 	return nil
 }
 
+// checkManifestRequirements reports every unmet v2 constraint at once so
+// authors can fix a manifest in one pass.
+func (r *Registry) checkManifestRequirements(doc *WidgetManifestDocument) error {
+	if doc.Version != manifestVersionV2 {
+		return nil
+	}
+	current, err := parseSemver(Version)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, widget := range doc.Widgets {
+		code := widget.Definition.Code
+		if widget.Requires.Dashboard != "" {
+			required, err := parseSemver(widget.Requires.Dashboard)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("dashboard: widget %s from %s: requires.dashboard: %w", code, doc.Source, err))
+			} else if current.compare(required) < 0 {
+				errs = append(errs, fmt.Errorf("dashboard: widget %s from %s requires go-dashboard >= %s (running %s)", code, doc.Source, widget.Requires.Dashboard, Version))
+			}
+		}
+		var missing []string
+		for _, capability := range widget.Requires.Capabilities {
+			if !r.hasCapability(capability) {
+				missing = append(missing, capability)
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Errorf("dashboard: widget %s from %s requires unavailable capabilities: %s", code, doc.Source, strings.Join(missing, ", ")))
+		}
+		if name := widget.Provider.Factory; name != "" {
+			if _, ok := r.ProviderFactory(name); !ok {
+				errs = append(errs, fmt.Errorf("dashboard: widget %s from %s references unregistered provider factory %q", code, doc.Source, name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // ReadManifest loads a manifest file from disk without registering it.
 func ReadManifest(path string) (*WidgetManifestDocument, error) {
 	f, err := os.Open(path) // #nosec G304 -- manifests are explicitly loaded from caller-provided local paths.
@@ -110,7 +204,7 @@ func DecodeManifest(r io.Reader) (*WidgetManifestDocument, error) {
 
 // Validate ensures the manifest satisfies required fields.
 func (doc *WidgetManifestDocument) Validate() error {
-	if doc.Version != manifestVersionV1 {
+	if doc.Version != manifestVersionV1 && doc.Version != manifestVersionV2 {
 		return fmt.Errorf("dashboard: unsupported manifest version %q", doc.Version)
 	}
 	seen := make(map[string]struct{}, len(doc.Widgets))
@@ -121,6 +215,19 @@ func (doc *WidgetManifestDocument) Validate() error {
 		if widget.Definition.Name == "" {
 			return fmt.Errorf("dashboard: manifest widget %s missing definition.name", widget.Definition.Code)
 		}
+		if doc.Version == manifestVersionV1 && widget.usesV2Fields() {
+			return fmt.Errorf("dashboard: manifest widget %s uses version, requires or provider.factory, which need manifest version %s", widget.Definition.Code, manifestVersionV2)
+		}
+		if widget.Version != "" {
+			if _, err := parseSemver(widget.Version); err != nil {
+				return fmt.Errorf("dashboard: manifest widget %s version: %w", widget.Definition.Code, err)
+			}
+		}
+		if widget.Requires.Dashboard != "" {
+			if _, err := parseSemver(widget.Requires.Dashboard); err != nil {
+				return fmt.Errorf("dashboard: manifest widget %s requires.dashboard: %w", widget.Definition.Code, err)
+			}
+		}
 		if _, exists := seen[widget.Definition.Code]; exists {
 			return fmt.Errorf("dashboard: manifest duplicates widget code %s", widget.Definition.Code)
 		}
@@ -135,10 +242,18 @@ func (doc *WidgetManifestDocument) applyDefaults() {
 	}
 }
 
+func (widget ManifestWidget) usesV2Fields() bool {
+	return widget.Version != "" ||
+		widget.Requires.Dashboard != "" ||
+		len(widget.Requires.Capabilities) > 0 ||
+		widget.Provider.Factory != ""
+}
+
 func (p ManifestProvider) isZero() bool {
 	return p.Name == "" &&
 		p.Summary == "" &&
 		p.Entry == "" &&
+		p.Factory == "" &&
 		p.Package == "" &&
 		p.DocsURL == "" &&
 		len(p.Capabilities) == 0 &&
@@ -1,6 +1,8 @@
 package dashboard
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -116,3 +118,125 @@ func TestDocsManifestsAreValid(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistryLoadManifestV2WiresFactoryProviders(t *testing.T) {
+	const payload = `
+version: 2
+name: community-pack
+widgets:
+  - definition:
+      code: community.widget.pipeline
+      name: Pipeline
+    version: 1.4.0
+    requires:
+      dashboard: 0.10.0
+      capabilities: ["html", "refresh", "sse"]
+    provider:
+      name: Pipeline Provider
+      factory: community.pipeline
+`
+	doc, err := DecodeManifest(strings.NewReader(payload))
+	require.NoError(t, err)
+
+	reg := NewRegistry()
+	var built WidgetDefinition
+	require.NoError(t, reg.RegisterProviderFactory("community.pipeline", func(def WidgetDefinition) (Provider, error) {
+		built = def
+		return ProviderFunc(func(context.Context, WidgetContext) (WidgetData, error) {
+			return WidgetData{"ok": true}, nil
+		}), nil
+	}))
+	reg.RegisterCapability("sse")
+
+	require.NoError(t, reg.LoadManifestDocument(doc))
+	assert.Equal(t, "Pipeline", built.Name)
+
+	provider, ok := reg.Provider("community.widget.pipeline")
+	require.True(t, ok)
+	data, err := provider.Fetch(context.Background(), WidgetContext{})
+	require.NoError(t, err)
+	assert.Equal(t, true, data["ok"])
+
+	discovery, ok := reg.ProviderDiscovery("community.widget.pipeline")
+	require.True(t, ok)
+	assert.True(t, discovery.Registered)
+	assert.Equal(t, "1.4.0", discovery.Version)
+	assert.Equal(t, "community.pipeline", discovery.Manifest.Factory)
+}
+
+func TestRegistryLoadManifestV2ReportsUnmetConstraints(t *testing.T) {
+	doc := &WidgetManifestDocument{
+		Version: manifestVersionV2,
+		Source:  "pack.yaml",
+		Widgets: []ManifestWidget{
+			{
+				Definition: WidgetDefinition{Code: "acme.widget.ok", Name: "OK"},
+			},
+			{
+				Definition: WidgetDefinition{Code: "acme.widget.future", Name: "Future"},
+				Requires:   ManifestRequirements{Dashboard: "99.0.0", Capabilities: []string{"html", "websocket"}},
+				Provider:   ManifestProvider{Factory: "acme.missing"},
+			},
+		},
+	}
+	reg := NewRegistry()
+
+	err := reg.LoadManifestDocument(doc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "acme.widget.future from pack.yaml requires go-dashboard >= 99.0.0")
+	assert.Contains(t, err.Error(), "requires unavailable capabilities: websocket")
+	assert.Contains(t, err.Error(), `unregistered provider factory "acme.missing"`)
+
+	_, ok := reg.Definition("acme.widget.ok")
+	assert.False(t, ok, "no widget should register when a constraint fails")
+}
+
+func TestRegistryLoadManifestV2LeavesRegistryUntouchedWhenFactoryFails(t *testing.T) {
+	doc := &WidgetManifestDocument{
+		Version: manifestVersionV2,
+		Source:  "pack.yaml",
+		Widgets: []ManifestWidget{
+			{
+				Definition: WidgetDefinition{Code: "acme.widget.first", Name: "First"},
+				Provider:   ManifestProvider{Factory: "acme.ok"},
+			},
+			{
+				Definition: WidgetDefinition{Code: "acme.widget.broken", Name: "Broken"},
+				Provider:   ManifestProvider{Factory: "acme.broken"},
+			},
+		},
+	}
+	reg := NewRegistry()
+	require.NoError(t, reg.RegisterProviderFactory("acme.ok", func(WidgetDefinition) (Provider, error) {
+		return ProviderFunc(func(context.Context, WidgetContext) (WidgetData, error) { return WidgetData{}, nil }), nil
+	}))
+	require.NoError(t, reg.RegisterProviderFactory("acme.broken", func(WidgetDefinition) (Provider, error) {
+		return nil, errors.New("missing credentials")
+	}))
+
+	err := reg.LoadManifestDocument(doc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "provider factory acme.broken for widget acme.widget.broken from pack.yaml: missing credentials")
+
+	for _, code := range []string{"acme.widget.first", "acme.widget.broken"} {
+		_, ok := reg.Definition(code)
+		assert.False(t, ok, "definition %s should not register when a factory fails", code)
+		_, ok = reg.Provider(code)
+		assert.False(t, ok, "provider %s should not register when a factory fails", code)
+	}
+}
+
+func TestManifestV1RejectsV2Fields(t *testing.T) {
+	const payload = `
+version: 1
+widgets:
+  - definition:
+      code: legacy.widget
+      name: Legacy
+    provider:
+      factory: legacy.factory
+`
+	_, err := DecodeManifest(strings.NewReader(payload))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "need manifest version 2")
+}
@@ -1,6 +1,10 @@
 package dashboard
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
 
 // Page is the canonical typed dashboard presentation model used for rendering
 // and JSON transport. Area ordering is preserved directly by the Areas slice.
@@ -8,12 +12,18 @@ type Page struct {
 	Title       string          `json:"title,omitempty"`
 	Description string          `json:"description,omitempty"`
 	Locale      string          `json:"locale,omitempty"`
+	Dir         string          `json:"dir,omitempty"`
 	Areas       []PageArea      `json:"areas,omitempty"`
 	Shell       *Shell          `json:"shell,omitempty"`
 	Assets      *PageAssets     `json:"assets,omitempty"`
 	Theme       *ThemeSelection `json:"theme,omitempty"`
-	State       *PageState      `json:"state,omitempty"`
-	Meta        *PageMeta       `json:"meta,omitempty"`
+	TimeRange   *PageTimeRange  `json:"time_range,omitempty"`
+	Filters     []PageFilter    `json:"filters,omitempty"`
+	// PreferencesURL is where the toolbar saves the selected filters.
+	PreferencesURL string     `json:"preferences_url,omitempty"`
+	Edit           *PageEdit  `json:"edit,omitempty"`
+	State          *PageState `json:"state,omitempty"`
+	Meta           *PageMeta  `json:"meta,omitempty"`
 }
 
 // MarshalJSON preserves the canonical typed transport while applying page-level
@@ -58,12 +68,18 @@ func (page Page) ValidatedLegacyPayload() (map[string]any, error) {
 }
 
 // Normalize applies page-level defaults and validates opt-in presentation
-// contracts such as Shell.
+// contracts such as Shell. Dir defaults to the direction of Locale and is
+// inherited by the shell unless the shell sets its own.
 func (page Page) Normalize() (Page, error) {
+	page.Dir = page.direction()
 	if page.Shell == nil {
 		return page, nil
 	}
-	shell, err := page.Shell.Normalize()
+	shell := *page.Shell
+	if normalizeTextDirection(shell.Dir) == "" {
+		shell.Dir = page.Dir
+	}
+	shell, err := shell.Normalize()
 	if err != nil {
 		return Page{}, err
 	}
@@ -71,6 +87,15 @@ func (page Page) Normalize() (Page, error) {
 	return page, nil
 }
 
+// direction returns the explicit Dir when valid, otherwise the direction
+// derived from Locale.
+func (page Page) direction() string {
+	if dir := normalizeTextDirection(page.Dir); dir != "" {
+		return dir
+	}
+	return LocaleDirection(page.Locale)
+}
+
 func (page Page) legacyPayload() map[string]any {
 	theme := themePayload(page.Theme)
 	areas := make(map[string]any, len(page.Areas))
@@ -85,6 +110,7 @@ func (page Page) legacyPayload() map[string]any {
 		"title":         page.Title,
 		"description":   page.Description,
 		"locale":        page.Locale,
+		"dir":           page.direction(),
 		"areas":         areas,
 		"ordered_areas": ordered,
 	}
@@ -97,6 +123,18 @@ func (page Page) legacyPayload() map[string]any {
 	if shell := page.ShellPayload(); shell != nil {
 		response["shell"] = shell
 	}
+	if filters := filtersPayload(page.Filters); filters != nil {
+		response["filters"] = filters
+		if page.PreferencesURL != "" {
+			response["preferences_url"] = page.PreferencesURL
+		}
+	}
+	if edit := page.Edit.legacyPayload(); edit != nil {
+		response["edit"] = edit
+	}
+	if page.TimeRange != nil {
+		response["time_range"] = timeRangePayload(page.TimeRange.Selected, page.TimeRange.Presets)
+	}
 	return response
 }
 
@@ -203,6 +241,12 @@ func (area PageArea) legacyPayload(theme map[string]any) map[string]any {
 		"slot": area.Slot,
 		"code": area.Code,
 	}
+	if slices.ContainsFunc(area.Widgets, WidgetFrame.responsive) {
+		payload["responsive"] = true
+	}
+	if slices.ContainsFunc(area.Widgets, WidgetFrame.gridPlaced) {
+		payload["grid"] = true
+	}
 	if area.Title != "" {
 		payload["title"] = area.Title
 	}
@@ -272,12 +316,25 @@ func (widget WidgetFrame) legacyMetadata() map[string]any {
 		if metadata == nil {
 			metadata = map[string]any{}
 		}
-		metadata["layout"] = map[string]any{
+		layout := map[string]any{
 			"row":     widget.Meta.Layout.Row,
 			"column":  widget.Meta.Layout.Column,
 			"width":   widget.Meta.Layout.Width,
 			"columns": widget.Meta.Layout.Columns,
 		}
+		if len(widget.Meta.Layout.Breakpoints) > 0 {
+			layout["breakpoints"] = breakpointLayoutsPayload(widget.Meta.Layout.Breakpoints)
+		}
+		if len(widget.Meta.Layout.Widths) > 0 {
+			layout["widths"] = maps.Clone(widget.Meta.Layout.Widths)
+		}
+		if widget.gridPlaced() {
+			layout["x"] = widget.Meta.Layout.X
+			layout["y"] = widget.Meta.Layout.Y
+			layout["w"] = widget.Meta.Layout.W
+			layout["h"] = widget.Meta.Layout.H
+		}
+		metadata["layout"] = layout
 	}
 	if widget.Meta.hiddenPresent {
 		if metadata == nil {
@@ -325,6 +382,18 @@ type WidgetLayout struct {
 	Column  int `json:"column"`
 	Width   int `json:"width"`
 	Columns int `json:"columns"`
+	// Breakpoints holds resolved widths and order for every breakpoint when
+	// the viewer saved responsive overrides for the widget.
+	Breakpoints map[Breakpoint]BreakpointLayout `json:"breakpoints,omitempty"`
+	// Widths holds only the per-breakpoint widths the viewer set, so the
+	// editor can post them back unchanged.
+	Widths map[Breakpoint]int `json:"widths,omitempty"`
+	// X, Y, W and H place the widget on a free-form grid area (see
+	// GridPlacement). H is zero for row-based layouts.
+	X int `json:"x,omitempty"`
+	Y int `json:"y,omitempty"`
+	W int `json:"w,omitempty"`
+	H int `json:"h,omitempty"`
 }
 
 // PageState captures viewer-scoped runtime state that may influence rendering
@@ -85,6 +85,13 @@ func clampAreaRows(rows map[string][]LayoutRow) {
 	for area, list := range rows {
 		for rowIdx, row := range list {
 			for slotIdx, slot := range row.Widgets {
+				for bp, width := range slot.Widths {
+					if !bp.Valid() || width <= 0 {
+						delete(slot.Widths, bp)
+					} else if width > 12 {
+						slot.Widths[bp] = 12
+					}
+				}
 				if slot.Width <= 0 || slot.Width > 12 {
 					if slot.Width <= 0 {
 						slot.Width = 12
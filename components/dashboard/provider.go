@@ -23,7 +23,16 @@ type WidgetContext struct {
 	Viewer     ViewerContext
 	Options    map[string]any
 	Translator TranslationService
-	Theme      *ThemeSelection
+	// Formatter renders numbers, dates and plural messages for the viewer
+	// locale. Providers fall back to NewLocaleFormatter when it is nil.
+	Formatter Formatter
+	Theme     *ThemeSelection
+	// TimeRange is the resolved dashboard time window, nil when the viewer has
+	// not selected one or the instance opted out.
+	TimeRange *TimeRange
+	// Filters holds the dashboard filter values for the variables declared by
+	// the widget definition.
+	Filters map[string]string
 }
 
 // WidgetData is an opaque payload passed to templates.
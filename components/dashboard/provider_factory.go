@@ -0,0 +1,109 @@
+package dashboard
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ProviderFactory builds the provider for a manifest widget. Factories are
+// registered by name from a WidgetHook and referenced by `provider.factory`
+// in v2 manifests.
+type ProviderFactory func(def WidgetDefinition) (Provider, error)
+
+// Host capabilities a manifest widget may require through
+// `requires.capabilities`. Hosts add their own (e.g. transports they wire)
+// with Registry.RegisterCapability.
+const (
+	CapabilityHTML            = "html"
+	CapabilityJSON            = "json"
+	CapabilityRefresh         = "refresh"
+	CapabilityInteractions    = "interactions"
+	CapabilityConfigForm      = "config_form"
+	CapabilityLocalizedConfig = "localized_config"
+	CapabilityBreakpoints     = "breakpoints"
+	CapabilityGrid            = "grid"
+	CapabilityEditMode        = "edit_mode"
+)
+
+var builtinCapabilities = []string{
+	CapabilityHTML,
+	CapabilityJSON,
+	CapabilityRefresh,
+	CapabilityInteractions,
+	CapabilityConfigForm,
+	CapabilityLocalizedConfig,
+	CapabilityBreakpoints,
+	CapabilityGrid,
+	CapabilityEditMode,
+}
+
+// RegisterProviderFactory stores a named provider factory. Call it from a
+// WidgetHook so every registry can resolve the factory:
+//
+//	func init() {
+//		dashboard.RegisterWidgetHook(func(reg *dashboard.Registry) error {
+//			return reg.RegisterProviderFactory("community.pipeline_health", NewPipelineHealthProvider)
+//		})
+//	}
+func (r *Registry) RegisterProviderFactory(name string, factory ProviderFactory) error {
+	if name == "" {
+		return fmt.Errorf("provider factory name is required")
+	}
+	if factory == nil {
+		return fmt.Errorf("provider factory %s cannot be nil", name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.factories == nil {
+		r.factories = map[string]ProviderFactory{}
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// ProviderFactory fetches a registered provider factory by name.
+func (r *Registry) ProviderFactory(name string) (ProviderFactory, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	factory, ok := r.factories[name]
+	return factory, ok
+}
+
+// RegisterCapability marks host capabilities as available to manifest
+// widgets, in addition to the built-in ones.
+func (r *Registry) RegisterCapability(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.capabilities == nil {
+		r.capabilities = map[string]struct{}{}
+	}
+	for _, name := range names {
+		if name != "" {
+			r.capabilities[name] = struct{}{}
+		}
+	}
+}
+
+// Capabilities lists the built-in and host-registered capabilities, sorted.
+func (r *Registry) Capabilities() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := slices.Clone(builtinCapabilities)
+	for name := range r.capabilities {
+		if !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func (r *Registry) hasCapability(name string) bool {
+	if slices.Contains(builtinCapabilities, name) {
+		return true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.capabilities[name]
+	return ok
+}
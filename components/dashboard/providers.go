@@ -17,6 +17,8 @@ var defaultProviders = map[string]Provider{
 	"admin.widget.analytics_funnel": NewFunnelAnalyticsProvider(DemoFunnelRepository{}),
 	"admin.widget.cohort_overview":  NewCohortAnalyticsProvider(DemoCohortRepository{}),
 	"admin.widget.alert_trends":     NewAlertTrendsProvider(DemoAlertRepository{}),
+	"admin.widget.kpi":              NewKPIProvider(DemoKPIRepository{}),
+	"admin.widget.table":            NewTableProvider(DemoTableRepository()),
 }
 
 func registerDefaultWidgetRuntimes(reg *Registry) {
@@ -84,6 +86,8 @@ type recentActivityItemView struct {
 	Action  string        `json:"action"`
 	Details string        `json:"details"`
 	Ago     time.Duration `json:"ago"`
+	// AgoDisplay is Ago rendered as localized relative time ("5 minutes ago").
+	AgoDisplay string `json:"ago_display"`
 }
 
 type recentActivityView struct {
@@ -111,14 +115,16 @@ func newRecentActivitySpec(feed ActivityFeed) WidgetSpec[recentActivityConfig, r
 			}
 			return recentActivityData{Items: items}, nil
 		},
-		BuildView: func(_ context.Context, data recentActivityData, _ WidgetViewContext[recentActivityConfig]) (JSONViewModel[recentActivityView], error) {
+		BuildView: func(_ context.Context, data recentActivityData, meta WidgetViewContext[recentActivityConfig]) (JSONViewModel[recentActivityView], error) {
+			formatter := formatterOrDefault(meta.Request.Formatter, meta.Request.Viewer.Locale)
 			items := make([]recentActivityItemView, 0, len(data.Items))
 			for _, item := range data.Items {
 				items = append(items, recentActivityItemView{
-					User:    item.User,
-					Action:  item.Action,
-					Details: item.Details,
-					Ago:     item.Ago,
+					User:       item.User,
+					Action:     item.Action,
+					Details:    item.Details,
+					Ago:        item.Ago,
+					AgoDisplay: formatter.RelativeTime(item.Ago),
 				})
 			}
 			return JSONViewModel[recentActivityView]{
@@ -134,14 +140,22 @@ type quickActionView struct {
 	Icon  string `json:"icon"`
 }
 
+type quickActionsConfig struct {
+	Actions []quickActionView `json:"actions,omitempty"`
+}
+
 type quickActionsView struct {
 	Actions []quickActionView `json:"actions"`
 }
 
 func newQuickActionsProvider() Provider {
-	return NewWidgetProvider(WidgetSpec[struct{}, quickActionsView, JSONViewModel[quickActionsView]]{
+	return NewWidgetProvider(WidgetSpec[quickActionsConfig, quickActionsView, JSONViewModel[quickActionsView]]{
 		Definition: WidgetDefinition{Code: "admin.widget.quick_actions"},
-		Fetch: func(ctx context.Context, req WidgetRequest[struct{}]) (quickActionsView, error) {
+		Fetch: func(ctx context.Context, req WidgetRequest[quickActionsConfig]) (quickActionsView, error) {
+			// Configured actions arrive with localized labels already resolved.
+			if len(req.Config.Actions) > 0 {
+				return quickActionsView{Actions: req.Config.Actions}, nil
+			}
 			inviteLabel := translateOrFallback(ctx, req.Translator, "dashboard.widget.quick_actions.invite_user", req.Viewer.Locale, "Invite user", nil)
 			pageLabel := translateOrFallback(ctx, req.Translator, "dashboard.widget.quick_actions.create_page", req.Viewer.Locale, "Create page", nil)
 			return quickActionsView{
@@ -151,7 +165,7 @@ func newQuickActionsProvider() Provider {
 				},
 			}, nil
 		},
-		BuildView: func(_ context.Context, data quickActionsView, _ WidgetViewContext[struct{}]) (JSONViewModel[quickActionsView], error) {
+		BuildView: func(_ context.Context, data quickActionsView, _ WidgetViewContext[quickActionsConfig]) (JSONViewModel[quickActionsView], error) {
 			return JSONViewModel[quickActionsView]{Value: data}, nil
 		},
 	})
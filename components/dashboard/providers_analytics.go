@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,9 @@ type FunnelQuery struct {
 	Range   string
 	Segment string
 	Goal    float64
+	From    time.Time
+	To      time.Time
+	Filters map[string]string
 }
 
 // FunnelReport captures drop-off per stage.
@@ -49,6 +53,9 @@ type CohortQuery struct {
 	Interval string
 	Periods  int
 	Metric   string
+	From     time.Time
+	To       time.Time
+	Filters  map[string]string
 }
 
 // CohortReport contains rows (cohort) and their retention.
@@ -70,6 +77,9 @@ type AlertTrendQuery struct {
 	LookbackDays int
 	Severities   []string
 	Service      string
+	From         time.Time
+	To           time.Time
+	Filters      map[string]string
 }
 
 // AlertTrendsReport carries severity counts per day.
@@ -96,7 +106,9 @@ func NewFunnelAnalyticsProvider(repo FunnelReportRepository) Provider {
 			return extractFunnelQuery(raw), nil
 		},
 		Fetch: func(ctx context.Context, req WidgetRequest[FunnelQuery]) (FunnelReport, error) {
-			return repo.FetchFunnelReport(ctx, req.Config)
+			query := req.Config.withTimeRange(req.TimeRange)
+			query.Filters = cloneFilterValues(req.Filters)
+			return repo.FetchFunnelReport(ctx, query)
 		},
 		BuildView: func(_ context.Context, report FunnelReport, _ WidgetViewContext[FunnelQuery]) (JSONViewModel[funnelView], error) {
 			baseline := 1.0
@@ -141,7 +153,9 @@ func NewCohortAnalyticsProvider(repo CohortReportRepository) Provider {
 			return extractCohortQuery(raw), nil
 		},
 		Fetch: func(ctx context.Context, req WidgetRequest[CohortQuery]) (CohortReport, error) {
-			return repo.FetchCohortReport(ctx, req.Config)
+			query := req.Config.withTimeRange(req.TimeRange)
+			query.Filters = cloneFilterValues(req.Filters)
+			return repo.FetchCohortReport(ctx, query)
 		},
 		BuildView: func(_ context.Context, report CohortReport, _ WidgetViewContext[CohortQuery]) (JSONViewModel[cohortView], error) {
 			rows := make([]cohortRowView, 0, len(report.Rows))
@@ -174,7 +188,9 @@ func NewAlertTrendsProvider(repo AlertTrendsRepository) Provider {
 			return extractAlertQuery(raw), nil
 		},
 		Fetch: func(ctx context.Context, req WidgetRequest[AlertTrendQuery]) (AlertTrendsReport, error) {
-			return repo.FetchAlertTrends(ctx, req.Config)
+			query := req.Config.withTimeRange(req.TimeRange)
+			query.Filters = cloneFilterValues(req.Filters)
+			return repo.FetchAlertTrends(ctx, query)
 		},
 		BuildView: func(_ context.Context, report AlertTrendsReport, meta WidgetViewContext[AlertTrendQuery]) (JSONViewModel[alertTrendsView], error) {
 			order := normalizeSeverities(meta.Request.Config.Severities)
@@ -187,7 +203,7 @@ func NewAlertTrendsProvider(repo AlertTrendsRepository) Provider {
 			}
 			return JSONViewModel[alertTrendsView]{
 				Value: alertTrendsView{
-					LookbackDays: meta.Request.Config.LookbackDays,
+					LookbackDays: meta.Request.Config.withTimeRange(meta.Request.TimeRange).LookbackDays,
 					Severities:   countsForOrder(order, report.Totals),
 					Service:      report.Service,
 					Series:       series,
@@ -263,6 +279,50 @@ func extractAlertQuery(config map[string]any) AlertTrendQuery {
 	}
 }
 
+// withTimeRange overrides the configured range with the dashboard time range.
+func (q FunnelQuery) withTimeRange(r *TimeRange) FunnelQuery {
+	if r == nil {
+		return q
+	}
+	q.Range = r.Label()
+	q.From, q.To = r.From, r.To
+	return q
+}
+
+// withTimeRange derives the number of cohort periods from the dashboard time
+// range so the retention grid covers the selected window.
+func (q CohortQuery) withTimeRange(r *TimeRange) CohortQuery {
+	if r == nil {
+		return q
+	}
+	q.From, q.To = r.From, r.To
+	days := r.Days()
+	if days <= 0 {
+		return q
+	}
+	switch strings.ToLower(q.Interval) {
+	case "daily":
+		q.Periods = days
+	case "monthly":
+		q.Periods = max(days/30, 1)
+	default:
+		q.Periods = max(days/7, 1)
+	}
+	return q
+}
+
+// withTimeRange overrides the lookback window with the dashboard time range.
+func (q AlertTrendQuery) withTimeRange(r *TimeRange) AlertTrendQuery {
+	if r == nil {
+		return q
+	}
+	q.From, q.To = r.From, r.To
+	if days := r.Days(); days > 0 {
+		q.LookbackDays = days
+	}
+	return q
+}
+
 func stringOr(value any, fallback string) string {
 	if v, ok := value.(string); ok && v != "" {
 		return v
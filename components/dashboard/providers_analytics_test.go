@@ -137,6 +137,42 @@ func TestBuiltInAnalyticsProviderUsesTypedRuntimeState(t *testing.T) {
 	}
 }
 
+func TestAnalyticsProvidersPassDashboardFiltersThrough(t *testing.T) {
+	filters := map[string]string{"segment": "enterprise", "region": "emea"}
+	meta := WidgetContext{
+		Instance: WidgetInstance{Configuration: map[string]any{"segment": "smb"}},
+		Filters:  filters,
+	}
+	funnel := &stubFunnelRepo{}
+	cohort := &stubCohortRepo{}
+	alerts := &stubAlertRepo{}
+	for _, provider := range []Provider{
+		NewFunnelAnalyticsProvider(funnel),
+		NewCohortAnalyticsProvider(cohort),
+		NewAlertTrendsProvider(alerts),
+	} {
+		if _, err := provider.Fetch(context.Background(), meta); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+	for name, got := range map[string]map[string]string{
+		"funnel": funnel.query.Filters,
+		"cohort": cohort.query.Filters,
+		"alerts": alerts.query.Filters,
+	} {
+		if !reflect.DeepEqual(got, filters) {
+			t.Fatalf("expected %s query to carry %v, got %v", name, filters, got)
+		}
+	}
+	if funnel.query.Segment != "smb" {
+		t.Fatalf("expected configured segment to stay on the funnel query, got %q", funnel.query.Segment)
+	}
+	funnel.query.Filters["region"] = "apac"
+	if filters["region"] != "emea" {
+		t.Fatalf("expected queries to receive a copy of the filters")
+	}
+}
+
 type stubFunnelRepo struct {
 	query FunnelQuery
 	calls int
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
+	"maps"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +32,7 @@ var sharedChartCache = NewChartCache(5 * time.Minute)
 type chartRenderContext struct {
 	Viewer ViewerContext
 	Theme  string
+	Dir    string
 }
 
 type echartsWidgetView struct {
@@ -37,6 +41,7 @@ type echartsWidgetView struct {
 	Title           string   `json:"title"`
 	Subtitle        string   `json:"subtitle"`
 	Theme           string   `json:"theme"`
+	ThemeLocked     bool     `json:"theme_locked,omitempty"`
 	JSAssets        []string `json:"js_assets,omitempty"`
 	CSSAssets       []string `json:"css_assets,omitempty"`
 	Dynamic         bool     `json:"dynamic,omitempty"`
@@ -167,9 +172,12 @@ func (p *EChartsProvider) BuildView(ctx context.Context, meta WidgetContext) (ec
 	renderCtx := chartRenderContext{
 		Viewer: meta.Viewer,
 		Theme:  p.resolveTheme(meta.Viewer, meta.Theme),
+		Dir:    LocaleDirection(meta.Viewer.Locale),
 	}
+	themeLocked := p.themeResolver != nil || p.customTheme
 	if override := strings.TrimSpace(stringValue(cfg["theme"], "")); override != "" {
 		renderCtx.Theme = override
+		themeLocked = true
 	}
 
 	showTitle := boolValue(cfg["show_chart_title"])
@@ -201,7 +209,7 @@ func (p *EChartsProvider) BuildView(ctx context.Context, meta WidgetContext) (ec
 	)
 
 	if p.cache != nil {
-		key := fmt.Sprintf("%s:%s:%s:%s", meta.Instance.DefinitionID, meta.Instance.ID, p.chartType, configHash(cfg))
+		key := fmt.Sprintf("%s:%s:%s:%s:%s:%s", meta.Instance.DefinitionID, meta.Instance.ID, p.chartType, renderCtx.Theme, renderCtx.Dir, configHash(cfg))
 		cached, err = p.cache.GetOrRender(key, renderFn)
 	} else {
 		cached, err = renderFn()
@@ -217,14 +225,28 @@ func (p *EChartsProvider) BuildView(ctx context.Context, meta WidgetContext) (ec
 	html := applySecurityDecorators(payload.Markup, nonceFrom(meta.Options))
 
 	view := echartsWidgetView{
-		ChartHTML: html,
-		ChartType: p.chartType,
-		Title:     displayTitle,
-		Subtitle:  displaySubtitle,
-		Theme:     renderCtx.Theme,
-		JSAssets:  append([]string{}, payload.JS...),
-		CSSAssets: append([]string{}, payload.CSS...),
+		ChartHTML:   html,
+		ChartType:   p.chartType,
+		Title:       displayTitle,
+		Subtitle:    displaySubtitle,
+		Theme:       renderCtx.Theme,
+		ThemeLocked: themeLocked,
+		JSAssets:    append([]string{}, payload.JS...),
+		CSSAssets:   append([]string{}, payload.CSS...),
+	}
+
+	usedThemes := []string{renderCtx.Theme}
+	if !themeLocked {
+		// Load every variant's chart theme so the page can re-theme charts
+		// when the viewer switches variants.
+		for _, chartTheme := range slices.Sorted(maps.Values(meta.Theme.variantChartThemes())) {
+			usedThemes = append(usedThemes, chartTheme)
+			if url := p.chartThemeScriptURL(chartTheme); url != "" && !slices.Contains(view.JSAssets, url) {
+				view.JSAssets = append(view.JSAssets, url)
+			}
+		}
 	}
+	view.inlineGeneratedThemes(generatedChartThemeScripts(meta.Theme, usedThemes), nonceFrom(meta.Options))
 
 	if dynamic := boolValue(cfg["dynamic"]); dynamic {
 		view.Dynamic = true
@@ -236,6 +258,22 @@ func (p *EChartsProvider) BuildView(ctx context.Context, meta WidgetContext) (ec
 	return view, nil
 }
 
+// inlineGeneratedThemes registers token generated chart themes with inline
+// scripts ahead of the chart markup, replacing the theme file URLs go-echarts
+// adds for them.
+func (view *echartsWidgetView) inlineGeneratedThemes(scripts map[string]string, nonce string) {
+	if len(scripts) == 0 {
+		return
+	}
+	var inline strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(scripts)) {
+		inline.WriteString("<script>" + scripts[name] + "</script>")
+		suffix := "themes/" + name + ".js"
+		view.JSAssets = slices.DeleteFunc(view.JSAssets, func(url string) bool { return strings.HasSuffix(url, suffix) })
+	}
+	view.ChartHTML = applySecurityDecorators(inline.String(), nonce) + view.ChartHTML
+}
+
 type echartsRuntime struct {
 	code     string
 	provider *EChartsProvider
@@ -272,6 +310,7 @@ func (p *EChartsProvider) render(title, subtitle string, xAxis []string, series
 	case "bar":
 		bar := charts.NewBar()
 		bar.SetGlobalOptions(options...)
+		bar.SetGlobalOptions(axisDirectionOptions(ctx)...)
 		bar.SetXAxis(xAxis)
 		for _, s := range series {
 			bar.AddSeries(s.Name, toBarData(s.Points))
@@ -280,6 +319,7 @@ func (p *EChartsProvider) render(title, subtitle string, xAxis []string, series
 	case "line":
 		line := charts.NewLine()
 		line.SetGlobalOptions(options...)
+		line.SetGlobalOptions(axisDirectionOptions(ctx)...)
 		line.SetXAxis(xAxis)
 		for _, s := range series {
 			line.AddSeries(s.Name, toLineData(s.Points))
@@ -296,6 +336,7 @@ func (p *EChartsProvider) render(title, subtitle string, xAxis []string, series
 	case "scatter":
 		scatter := charts.NewScatter()
 		scatter.SetGlobalOptions(options...)
+		scatter.SetGlobalOptions(axisDirectionOptions(ctx)...)
 		for _, s := range series {
 			scatter.AddSeries(s.Name, toScatterData(s.Points))
 		}
@@ -482,18 +523,37 @@ func (p *EChartsProvider) globalChartOptions(title, subtitle string, ctx chartRe
 	if p.assetsHost != "" {
 		initOpts.AssetsHost = p.assetsHost
 	}
+	titleOpts := opts.Title{Title: title, Subtitle: subtitle}
+	toolboxOpts := opts.Toolbox{Show: opts.Bool(true)}
+	if ctx.Dir == TextDirectionRTL {
+		// Title and toolbox swap sides so the chart reads from the right.
+		titleOpts.Right = "0"
+		toolboxOpts.Left = "0"
+	}
 	optsList := []charts.GlobalOpts{
 		charts.WithInitializationOpts(initOpts),
 		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true)}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
-		charts.WithToolboxOpts(opts.Toolbox{Show: opts.Bool(true)}),
+		charts.WithToolboxOpts(toolboxOpts),
 	}
 	if title != "" || subtitle != "" {
-		optsList = append([]charts.GlobalOpts{charts.WithTitleOpts(opts.Title{Title: title, Subtitle: subtitle})}, optsList...)
+		optsList = append([]charts.GlobalOpts{charts.WithTitleOpts(titleOpts)}, optsList...)
 	}
 	return optsList
 }
 
+// axisDirectionOptions mirrors cartesian charts for right-to-left viewers: the
+// category axis runs right to left and the value axis sits on the right.
+func axisDirectionOptions(ctx chartRenderContext) []charts.GlobalOpts {
+	if ctx.Dir != TextDirectionRTL {
+		return nil
+	}
+	return []charts.GlobalOpts{
+		charts.WithXAxisOpts(opts.XAxis{Inverse: opts.Bool(true)}),
+		charts.WithYAxisOpts(opts.YAxis{Position: "right"}),
+	}
+}
+
 func (p *EChartsProvider) resolveTheme(viewer ViewerContext, selection *ThemeSelection) string {
 	if p.themeResolver != nil {
 		if theme := p.themeResolver(viewer); theme != "" {
@@ -515,6 +575,22 @@ func (p *EChartsProvider) resolveTheme(viewer ViewerContext, selection *ThemeSel
 	return string(types.ThemeWesteros)
 }
 
+// chartThemeScriptURL returns the script URL of an embedded chart theme, or
+// an empty string for themes that ship with ECharts or are unknown.
+func (p *EChartsProvider) chartThemeScriptURL(name string) string {
+	if name == "" || !shellTokenPattern.MatchString(name) {
+		return ""
+	}
+	if _, err := fs.Stat(EChartsAssets(), "themes/"+name+".js"); err != nil {
+		return ""
+	}
+	host := p.assetsHost
+	if host == "" {
+		host = DefaultEChartsAssetsHost()
+	}
+	return ensureTrailingSlash(host) + "themes/" + name + ".js"
+}
+
 func chartThemeFromSelection(selection *ThemeSelection) string {
 	if selection == nil {
 		return ""
@@ -522,10 +598,10 @@ func chartThemeFromSelection(selection *ThemeSelection) string {
 	if selection.ChartTheme != "" {
 		return selection.ChartTheme
 	}
-	variant := strings.TrimSpace(strings.ToLower(selection.Variant))
-	if variant == "" {
-		return ""
+	if name, _, ok := ChartThemeFromTokens(selection.Tokens); ok {
+		return name
 	}
+	variant := strings.TrimSpace(strings.ToLower(selection.Variant))
 	if strings.Contains(variant, "dark") {
 		return string(types.ThemeWonderland)
 	}
@@ -57,6 +57,36 @@ func TestEChartsLineProvider(t *testing.T) {
 	assert.NotContains(t, html(data), "<html")
 }
 
+func TestEChartsProviderMirrorsAxesForRTLViewers(t *testing.T) {
+	t.Parallel()
+	provider := NewEChartsProvider("bar", WithChartCache(NewChartCache(time.Minute)))
+	cfg := map[string]any{
+		"title":  "Sales",
+		"x_axis": []string{"Q1", "Q2"},
+		"series": []map[string]any{{"name": "Revenue", "data": []float64{10, 20}}},
+	}
+
+	ltr, err := provider.Fetch(context.Background(), sampleChartContext("admin.widget.bar_chart", cfg))
+	require.NoError(t, err)
+	assert.NotContains(t, html(ltr), `"inverse":true`)
+
+	meta := sampleChartContext("admin.widget.bar_chart", cfg)
+	meta.Viewer.Locale = "he"
+	rtl, err := provider.Fetch(context.Background(), meta)
+	require.NoError(t, err)
+	assert.Contains(t, html(rtl), `"inverse":true`)
+	assert.Contains(t, html(rtl), `"position":"right"`)
+
+	pie := NewEChartsProvider("pie")
+	_, err = pie.Fetch(context.Background(), WidgetContext{
+		Instance: WidgetInstance{DefinitionID: "admin.widget.pie_chart", Configuration: map[string]any{
+			"series": []map[string]any{{"name": "Share", "data": []map[string]any{{"name": "A", "value": 1}}}},
+		}},
+		Viewer: ViewerContext{Locale: "ar"},
+	})
+	require.NoError(t, err)
+}
+
 func TestEChartsPieProvider(t *testing.T) {
 	t.Parallel()
 	provider := NewEChartsProvider("pie")
@@ -166,6 +196,33 @@ func TestEChartsProviderCustomThemeBeatsSelection(t *testing.T) {
 	assert.Equal(t, string(types.ThemeWalden), data["theme"])
 }
 
+func TestEChartsProviderLoadsVariantChartThemes(t *testing.T) {
+	t.Parallel()
+	provider := NewEChartsProvider("bar")
+	ctx := sampleChartContext("admin.widget.bar_chart", map[string]any{
+		"title":  "Switchable Theme",
+		"x_axis": []string{"A"},
+		"series": []map[string]any{{"name": "Series", "data": []float64{1}}},
+	})
+	ctx.Theme = &ThemeSelection{Variant: "light", Variants: map[string]ThemeVariant{
+		"light": {},
+		"dark":  {ChartTheme: "chalk"},
+	}}
+
+	data, err := provider.Fetch(context.Background(), ctx)
+	require.NoError(t, err)
+	assert.Equal(t, string(types.ThemeWesteros), data["theme"])
+	assert.Nil(t, data["theme_locked"])
+	assert.Contains(t, jsAssets(data), DefaultEChartsAssetsPath+"themes/chalk.js")
+	assert.Contains(t, jsAssets(data), DefaultEChartsAssetsPath+"themes/westeros.js")
+
+	ctx.Instance.Configuration["theme"] = "walden"
+	locked, err := provider.Fetch(context.Background(), ctx)
+	require.NoError(t, err)
+	assert.Equal(t, true, locked["theme_locked"])
+	assert.NotContains(t, jsAssets(locked), DefaultEChartsAssetsPath+"themes/chalk.js")
+}
+
 func TestEChartsProviderSanitizesStrings(t *testing.T) {
 	t.Parallel()
 	provider := NewEChartsProvider("bar")
@@ -0,0 +1,263 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KPIQuery describes the metric requested by the KPI widget. From/To bound the
+// current period; PreviousFrom/PreviousTo bound the comparison period when the
+// dashboard time range is set.
+type KPIQuery struct {
+	Metric       string
+	Period       string
+	From         time.Time
+	To           time.Time
+	PreviousFrom time.Time
+	PreviousTo   time.Time
+	Filters      map[string]string
+	Viewer       ViewerContext
+}
+
+// KPIReport carries the current value, the comparison value and an optional
+// series used to draw the sparkline (oldest first).
+type KPIReport struct {
+	Current     float64
+	Previous    float64
+	HasPrevious bool
+	Series      []float64
+}
+
+// KPIRepository loads metric values for the KPI widget.
+type KPIRepository interface {
+	FetchKPI(ctx context.Context, query KPIQuery) (KPIReport, error)
+}
+
+// KPIRepositoryFunc adapts a function into a KPIRepository.
+type KPIRepositoryFunc func(ctx context.Context, query KPIQuery) (KPIReport, error)
+
+// FetchKPI implements KPIRepository.
+func (fn KPIRepositoryFunc) FetchKPI(ctx context.Context, query KPIQuery) (KPIReport, error) {
+	return fn(ctx, query)
+}
+
+// KPIThreshold colors the value once it reaches Value. When several
+// thresholds match, the one with the highest Value wins.
+type KPIThreshold struct {
+	Value float64 `json:"value"`
+	Level string  `json:"level"`
+}
+
+type kpiConfig struct {
+	Metric          string         `json:"metric"`
+	Title           string         `json:"title,omitempty"`
+	Period          string         `json:"period,omitempty"`
+	Format          string         `json:"format,omitempty"`
+	Currency        string         `json:"currency,omitempty"`
+	Unit            string         `json:"unit,omitempty"`
+	Decimals        *int           `json:"decimals,omitempty"`
+	Thresholds      []KPIThreshold `json:"thresholds,omitempty"`
+	LowerIsBetter   bool           `json:"lower_is_better,omitempty"`
+	ShowSparkline   *bool          `json:"sparkline,omitempty"`
+	ComparisonLabel string         `json:"comparison_label,omitempty"`
+}
+
+type kpiData struct {
+	Report KPIReport
+	Period string
+}
+
+type kpiView struct {
+	Title           string  `json:"title"`
+	Metric          string  `json:"metric"`
+	Period          string  `json:"period"`
+	Value           float64 `json:"value"`
+	Formatted       string  `json:"formatted"`
+	Previous        float64 `json:"previous,omitempty"`
+	PreviousDisplay string  `json:"previous_formatted,omitempty"`
+	HasDelta        bool    `json:"has_delta"`
+	Delta           float64 `json:"delta"`
+	DeltaDisplay    string  `json:"delta_formatted,omitempty"`
+	Trend           string  `json:"trend"`
+	Sentiment       string  `json:"sentiment"`
+	Level           string  `json:"level,omitempty"`
+	Sparkline       string  `json:"sparkline,omitempty"`
+	ComparisonLabel string  `json:"comparison_label,omitempty"`
+}
+
+const (
+	kpiSparklineWidth  = 100
+	kpiSparklineHeight = 24
+)
+
+// NewKPIProvider builds the generic KPI/stat widget backed by repo.
+func NewKPIProvider(repo KPIRepository) Provider {
+	return NewWidgetProvider(newKPISpec(repo))
+}
+
+func newKPISpec(repo KPIRepository) WidgetSpec[kpiConfig, kpiData, JSONViewModel[kpiView]] {
+	if repo == nil {
+		repo = DemoKPIRepository{}
+	}
+	return WidgetSpec[kpiConfig, kpiData, JSONViewModel[kpiView]]{
+		Definition: WidgetDefinition{Code: "admin.widget.kpi"},
+		Fetch: func(ctx context.Context, req WidgetRequest[kpiConfig]) (kpiData, error) {
+			if strings.TrimSpace(req.Config.Metric) == "" {
+				return kpiData{}, fmt.Errorf("kpi provider: metric is required")
+			}
+			query := KPIQuery{
+				Metric:  req.Config.Metric,
+				Period:  stringOr(req.Config.Period, "30d"),
+				Filters: cloneFilterValues(req.Filters),
+				Viewer:  req.Viewer,
+			}
+			if req.TimeRange != nil {
+				query.Period = req.TimeRange.Label()
+				query.From, query.To = req.TimeRange.From, req.TimeRange.To
+				if span := req.TimeRange.Duration(); span > 0 {
+					query.PreviousTo = query.From
+					query.PreviousFrom = query.From.Add(-span)
+				}
+			}
+			report, err := repo.FetchKPI(ctx, query)
+			if err != nil {
+				return kpiData{}, fmt.Errorf("kpi provider: %w", err)
+			}
+			return kpiData{Report: report, Period: query.Period}, nil
+		},
+		BuildView: func(ctx context.Context, data kpiData, meta WidgetViewContext[kpiConfig]) (JSONViewModel[kpiView], error) {
+			cfg := meta.Request.Config
+			report := data.Report
+			formatter := formatterOrDefault(meta.Request.Formatter, meta.Request.Viewer.Locale)
+			title := cfg.Title
+			if title == "" {
+				title = titleize(strings.ReplaceAll(cfg.Metric, "_", " "))
+			}
+			view := kpiView{
+				Title:           title,
+				Metric:          cfg.Metric,
+				Period:          data.Period,
+				Value:           report.Current,
+				Formatted:       formatKPIValue(formatter, report.Current, cfg),
+				Trend:           "flat",
+				Sentiment:       "neutral",
+				Level:           kpiThresholdLevel(report.Current, cfg.Thresholds),
+				ComparisonLabel: cfg.ComparisonLabel,
+			}
+			if view.ComparisonLabel == "" {
+				view.ComparisonLabel = translateOrFallback(ctx, meta.Request.Translator, "dashboard.widget.kpi.previous_period", meta.Request.Viewer.Locale, "vs previous period", nil)
+			}
+			if report.HasPrevious {
+				view.Previous = report.Previous
+				view.PreviousDisplay = formatKPIValue(formatter, report.Previous, cfg)
+				if delta, ok := kpiDelta(report.Current, report.Previous); ok {
+					view.HasDelta = true
+					view.Delta = delta
+					view.DeltaDisplay = signedPercent(formatter, delta, 1)
+					view.Trend, view.Sentiment = kpiTrend(delta, cfg.LowerIsBetter)
+				}
+			}
+			if cfg.ShowSparkline == nil || *cfg.ShowSparkline {
+				view.Sparkline = kpiSparklinePoints(report.Series, kpiSparklineWidth, kpiSparklineHeight)
+			}
+			return JSONViewModel[kpiView]{Value: view}, nil
+		},
+	}
+}
+
+func kpiDelta(current, previous float64) (float64, bool) {
+	if previous == 0 {
+		return 0, false
+	}
+	return (current - previous) / math.Abs(previous) * 100, true
+}
+
+func kpiTrend(delta float64, lowerIsBetter bool) (string, string) {
+	switch {
+	case delta > 0:
+		if lowerIsBetter {
+			return "up", "negative"
+		}
+		return "up", "positive"
+	case delta < 0:
+		if lowerIsBetter {
+			return "down", "positive"
+		}
+		return "down", "negative"
+	default:
+		return "flat", "neutral"
+	}
+}
+
+func kpiThresholdLevel(value float64, thresholds []KPIThreshold) string {
+	level := ""
+	best := math.Inf(-1)
+	for _, threshold := range thresholds {
+		if value >= threshold.Value && threshold.Value >= best {
+			best = threshold.Value
+			level = threshold.Level
+		}
+	}
+	return level
+}
+
+// kpiSparklinePoints renders the series as an SVG polyline `points` attribute
+// scaled into a width x height viewBox.
+func kpiSparklinePoints(series []float64, width, height float64) string {
+	if len(series) < 2 {
+		return ""
+	}
+	lo, hi := series[0], series[0]
+	for _, v := range series {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	span := hi - lo
+	step := width / float64(len(series)-1)
+	points := make([]string, len(series))
+	for i, v := range series {
+		y := height / 2
+		if span > 0 {
+			y = height - (v-lo)/span*height
+		}
+		points[i] = strconv.FormatFloat(float64(i)*step, 'f', 1, 64) + "," + strconv.FormatFloat(y, 'f', 1, 64)
+	}
+	return strings.Join(points, " ")
+}
+
+func formatKPIValue(f Formatter, value float64, cfg kpiConfig) string {
+	return formatNumber(f, value, numberFormat{Kind: cfg.Format, Currency: cfg.Currency, Unit: cfg.Unit, Decimals: cfg.Decimals})
+}
+
+// signedPercent formats a percentage change with an explicit sign.
+func signedPercent(f Formatter, delta float64, decimals int) string {
+	if delta >= 0 {
+		return "+" + f.Percent(delta, decimals)
+	}
+	return f.Percent(delta, decimals)
+}
+
+// DemoKPIRepository serves deterministic values for demos and tests.
+type DemoKPIRepository struct{}
+
+// FetchKPI implements KPIRepository.
+func (DemoKPIRepository) FetchKPI(_ context.Context, query KPIQuery) (KPIReport, error) {
+	base := 1000.0
+	for _, r := range query.Metric {
+		base += float64(r)
+	}
+	series := make([]float64, 12)
+	for i := range series {
+		series[i] = base * (0.8 + 0.04*float64(i) + 0.03*math.Sin(float64(i)))
+	}
+	return KPIReport{
+		Current:     series[len(series)-1],
+		Previous:    series[len(series)-2] * 0.95,
+		HasPrevious: true,
+		Series:      series,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKPIProviderBuildsDeltaAndThresholds(t *testing.T) {
+	var captured KPIQuery
+	repo := KPIRepositoryFunc(func(_ context.Context, query KPIQuery) (KPIReport, error) {
+		captured = query
+		return KPIReport{
+			Current:     1250.5,
+			Previous:    1000,
+			HasPrevious: true,
+			Series:      []float64{1, 3, 2},
+		}, nil
+	})
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	data, err := NewKPIProvider(repo).Fetch(context.Background(), WidgetContext{
+		Instance: WidgetInstance{Configuration: map[string]any{
+			"metric":   "revenue",
+			"format":   "currency",
+			"currency": "EUR",
+			"thresholds": []any{
+				map[string]any{"value": 500, "level": "warning"},
+				map[string]any{"value": 1000, "level": "ok"},
+			},
+		}},
+		TimeRange: (&TimeRange{Preset: TimeRangePreset7d}).Resolve(now),
+		Filters:   map[string]string{"region": "emea"},
+	})
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if captured.Period != "7d" || !captured.PreviousTo.Equal(captured.From) || captured.PreviousFrom.IsZero() {
+		t.Fatalf("expected previous period derived from time range, got %+v", captured)
+	}
+	if captured.Filters["region"] != "emea" {
+		t.Fatalf("expected filters forwarded, got %+v", captured.Filters)
+	}
+	if data["formatted"] != "€1,250.50" {
+		t.Fatalf("expected currency formatting, got %v", data["formatted"])
+	}
+	if data["delta_formatted"] != "+25.1%" || data["trend"] != "up" || data["sentiment"] != "positive" {
+		t.Fatalf("unexpected delta fields: %+v", data)
+	}
+	if data["level"] != "ok" {
+		t.Fatalf("expected highest matching threshold, got %v", data["level"])
+	}
+	if spark, _ := data["sparkline"].(string); spark != "0.0,24.0 50.0,0.0 100.0,12.0" {
+		t.Fatalf("unexpected sparkline %q", spark)
+	}
+}
+
+func TestKPIProviderRequiresMetric(t *testing.T) {
+	_, err := NewKPIProvider(DemoKPIRepository{}).Fetch(context.Background(), WidgetContext{})
+	if err == nil || !strings.Contains(err.Error(), "metric is required") {
+		t.Fatalf("expected metric validation error, got %v", err)
+	}
+}
+
+func TestFormatKPIValue(t *testing.T) {
+	decimals := 1
+	cases := []struct {
+		cfg   kpiConfig
+		value float64
+		want  string
+	}{
+		{kpiConfig{}, 1234567, "1,234,567"},
+		{kpiConfig{Unit: "req/s"}, 950, "950 req/s"},
+		{kpiConfig{Format: "percent"}, 42.345, "42.3%"},
+		{kpiConfig{Format: "currency", Decimals: &decimals}, -1200, "-$1,200.0"},
+		{kpiConfig{Format: "duration"}, 3725, "1 hour 2 minutes"},
+		{kpiConfig{Format: "duration"}, 95, "1 minute 35 seconds"},
+		{kpiConfig{Format: "compact", Unit: "users"}, 1260000, "1.3M users"},
+	}
+	for _, tc := range cases {
+		if got := formatKPIValue(NewLocaleFormatter("en"), tc.value, tc.cfg); got != tc.want {
+			t.Fatalf("formatKPIValue(%v, %+v) = %q, want %q", tc.value, tc.cfg, got, tc.want)
+		}
+	}
+}
+
+func TestKPITrendRespectsLowerIsBetter(t *testing.T) {
+	if trend, sentiment := kpiTrend(-5, true); trend != "down" || sentiment != "positive" {
+		t.Fatalf("expected falling error rate to be positive, got %s/%s", trend, sentiment)
+	}
+}
+
+func TestKPITemplateRendersDeltaAndSparkline(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Areas: []PageArea{{
+			Slot: "main",
+			Code: "admin.dashboard.main",
+			Widgets: []WidgetFrame{{
+				ID:         "kpi-1",
+				Definition: "admin.widget.kpi",
+				Template:   templatePathFor("admin.widget.kpi"),
+				Data: map[string]any{
+					"title":              "Revenue",
+					"formatted":          "$1,250.00",
+					"has_delta":          true,
+					"delta_formatted":    "+25.0%",
+					"trend":              "up",
+					"sentiment":          "positive",
+					"previous_formatted": "$1,000.00",
+					"level":              "ok",
+					"sparkline":          "0,24 100,0",
+				},
+			}},
+		}},
+	}
+	var buf strings.Builder
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"widget--kpi-ok", "$1,250.00", "kpi__delta--positive", `points="0,24 100,0"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got %s", want, out)
+		}
+	}
+}
@@ -14,10 +14,14 @@ type SalesSeriesPoint struct {
 }
 
 // SalesSeriesQuery describes the requested metric.
+// From and To are set when a dashboard-wide time range applies.
 type SalesSeriesQuery struct {
 	Period  string
 	Metric  string
 	Segment string
+	From    time.Time
+	To      time.Time
+	Filters map[string]string
 	Viewer  ViewerContext
 }
 
@@ -103,11 +107,20 @@ func (p *SalesChartProvider) BuildView(ctx context.Context, meta WidgetContext)
 	metric := strings.ToLower(stringValue(cfg["metric"], "revenue"))
 	segment := stringValue(cfg["segment"], "all customers")
 	comparison := strings.ToLower(stringValue(cfg["comparison_metric"], ""))
+	var from, to time.Time
+	if meta.TimeRange != nil {
+		period = meta.TimeRange.Label()
+		from, to = meta.TimeRange.From, meta.TimeRange.To
+	}
+	filters := cloneFilterValues(meta.Filters)
 
 	points, err := p.repo.FetchSalesSeries(ctx, SalesSeriesQuery{
 		Period:  period,
 		Metric:  metric,
 		Segment: segment,
+		From:    from,
+		To:      to,
+		Filters: filters,
 		Viewer:  meta.Viewer,
 	})
 	if err != nil {
@@ -125,6 +138,9 @@ func (p *SalesChartProvider) BuildView(ctx context.Context, meta WidgetContext)
 			Period:  period,
 			Metric:  comparison,
 			Segment: segment,
+			From:    from,
+			To:      to,
+			Filters: filters,
 			Viewer:  meta.Viewer,
 		})
 		if altErr != nil {
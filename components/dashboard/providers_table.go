@@ -0,0 +1,725 @@
+package dashboard
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Table column types control formatting and CSV encoding.
+const (
+	TableColumnString   = "string"
+	TableColumnNumber   = "number"
+	TableColumnCurrency = "currency"
+	TableColumnPercent  = "percent"
+	TableColumnDate     = "date"
+	TableColumnDateTime = "datetime"
+	TableColumnBool     = "bool"
+)
+
+// Table widget actions served through the widget action endpoint.
+const (
+	TableActionPage   = "page"
+	TableActionExport = "export"
+)
+
+const (
+	defaultTablePageSize = 25
+	maxTablePageSize     = 500
+	// maxTableExportRows caps CSV exports. Some adapters (fasthttp) hold the
+	// response body in memory, and it also bounds repositories that never
+	// report a last page.
+	maxTableExportRows = 100000
+
+	tableParamsOptionKey = "dashboard.widget.table.params"
+)
+
+var errTableExportTooLarge = fmt.Errorf("table provider: export exceeds %d rows", maxTableExportRows)
+
+var tableFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// TableColumn describes a column of the table widget. Format is a Go time
+// layout for date columns (the viewer locale's date format when empty);
+// Currency and Decimals apply to numeric columns.
+type TableColumn struct {
+	Key      string `json:"key"`
+	Label    string `json:"label,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Sortable bool   `json:"sortable,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	Decimals *int   `json:"decimals,omitempty"`
+	Format   string `json:"format,omitempty"`
+}
+
+// TableQuery is the server-side slice requested by the table widget. Page is
+// 1-based; a zero PageSize requests every row (used by exports).
+type TableQuery struct {
+	Dataset  string
+	Sort     string
+	Desc     bool
+	Page     int
+	PageSize int
+	Search   string
+	Filters  map[string]string
+	From     time.Time
+	To       time.Time
+	Viewer   ViewerContext
+}
+
+// Offset returns the zero-based index of the first row on the page.
+func (q TableQuery) Offset() int {
+	if q.Page <= 1 || q.PageSize <= 0 {
+		return 0
+	}
+	return (q.Page - 1) * q.PageSize
+}
+
+// TablePage is one page of rows plus the total row count. Columns may be left
+// empty when the widget configuration declares them.
+type TablePage struct {
+	Columns []TableColumn
+	Rows    []map[string]any
+	Total   int
+}
+
+// TableRepository loads sorted, filtered and paged rows for the table widget.
+type TableRepository interface {
+	FetchTable(ctx context.Context, query TableQuery) (TablePage, error)
+}
+
+// TableRepositoryFunc adapts a function into a TableRepository.
+type TableRepositoryFunc func(ctx context.Context, query TableQuery) (TablePage, error)
+
+// FetchTable implements TableRepository.
+func (fn TableRepositoryFunc) FetchTable(ctx context.Context, query TableQuery) (TablePage, error) {
+	return fn(ctx, query)
+}
+
+// TableStreamer is an optional TableRepository extension used by CSV exports
+// to iterate the full result set without paging. Repositories that do not
+// implement it are exported page by page.
+type TableStreamer interface {
+	StreamTable(ctx context.Context, query TableQuery, yield func(row map[string]any) error) error
+}
+
+type tableConfig struct {
+	Dataset  string        `json:"dataset"`
+	Title    string        `json:"title,omitempty"`
+	Columns  []TableColumn `json:"columns,omitempty"`
+	Sort     string        `json:"sort,omitempty"`
+	SortDesc bool          `json:"sort_desc,omitempty"`
+	PageSize int           `json:"page_size,omitempty"`
+	Export   *bool         `json:"export,omitempty"`
+}
+
+type tableData struct {
+	Query TableQuery
+	Page  TablePage
+}
+
+type tableColumnView struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Sortable bool   `json:"sortable"`
+	Sorted   string `json:"sorted,omitempty"`
+	Align    string `json:"align"`
+}
+
+type tableCellView struct {
+	Key     string `json:"key"`
+	Display string `json:"display"`
+	Raw     string `json:"raw"`
+	Align   string `json:"align"`
+}
+
+type tableRowView struct {
+	Cells []tableCellView `json:"cells"`
+}
+
+type tableView struct {
+	Title     string            `json:"title"`
+	Dataset   string            `json:"dataset"`
+	Columns   []tableColumnView `json:"columns"`
+	Rows      []tableRowView    `json:"rows"`
+	Total     int               `json:"total"`
+	Page      int               `json:"page"`
+	PageSize  int               `json:"page_size"`
+	Pages     int               `json:"pages"`
+	Sort      string            `json:"sort,omitempty"`
+	Desc      bool              `json:"desc"`
+	Search    string            `json:"search,omitempty"`
+	PageURL   string            `json:"page_url"`
+	ExportURL string            `json:"export_url,omitempty"`
+	JSAssets  []string          `json:"js_assets,omitempty"`
+}
+
+// NewTableProvider builds the data table widget backed by repo. The returned
+// provider also implements WidgetActionHandler for the `page` and `export`
+// actions.
+func NewTableProvider(repo TableRepository) Provider {
+	if repo == nil {
+		repo = DemoTableRepository()
+	}
+	return tableProvider{Provider: NewWidgetProvider(newTableSpec(repo)), repo: repo}
+}
+
+type tableProvider struct {
+	Provider
+	repo TableRepository
+}
+
+var _ WidgetActionHandler = tableProvider{}
+
+// HandleAction serves the next page as JSON or streams the full result set as
+// CSV. Params use the same names as the browser runtime: sort, desc, page,
+// page_size and q.
+func (p tableProvider) HandleAction(ctx context.Context, meta WidgetContext, action string, params map[string]string) (WidgetActionResult, error) {
+	switch action {
+	case TableActionPage:
+		options := cloneAnyMap(meta.Options)
+		if options == nil {
+			options = map[string]any{}
+		}
+		options[tableParamsOptionKey] = params
+		meta.Options = options
+		data, err := p.Fetch(ctx, meta)
+		if err != nil {
+			return WidgetActionResult{}, err
+		}
+		return WidgetActionResult{ContentType: "application/json", Data: data}, nil
+	case TableActionExport:
+		cfg, err := DecodeWidgetConfig[tableConfig](meta.Instance.Configuration)
+		if err != nil {
+			return WidgetActionResult{}, err
+		}
+		if cfg.Export != nil && !*cfg.Export {
+			return WidgetActionResult{}, ErrWidgetActionUnsupported
+		}
+		query := tableQueryFor(cfg, meta.TimeRange, meta.Filters, meta.Viewer, params)
+		query.Page, query.PageSize = 1, 0
+		return WidgetActionResult{
+			ContentType: "text/csv; charset=utf-8",
+			Filename:    tableExportFilename(cfg.Dataset, time.Now()),
+			Stream: func(w io.Writer) error {
+				return p.exportCSV(ctx, cfg, query, w)
+			},
+		}, nil
+	default:
+		return WidgetActionResult{}, ErrWidgetActionUnsupported
+	}
+}
+
+func (p tableProvider) exportCSV(ctx context.Context, cfg tableConfig, query TableQuery, w io.Writer) error {
+	out := csv.NewWriter(w)
+	columns := cfg.Columns
+	rows := 0
+	writeRow := func(row map[string]any) error {
+		if rows++; rows > maxTableExportRows {
+			return errTableExportTooLarge
+		}
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			record[i] = tableCSVCell(row[col.Key], col)
+		}
+		return out.Write(record)
+	}
+	writeHeader := func() error {
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = csvSafeText(tableColumnLabel(col))
+		}
+		return out.Write(header)
+	}
+
+	if streamer, ok := p.repo.(TableStreamer); ok && len(columns) > 0 {
+		if err := writeHeader(); err != nil {
+			return err
+		}
+		if err := streamer.StreamTable(ctx, query, writeRow); err != nil {
+			if errors.Is(err, errTableExportTooLarge) {
+				return err
+			}
+			return fmt.Errorf("table provider: %w", err)
+		}
+		out.Flush()
+		return out.Error()
+	}
+
+	query.PageSize = maxTablePageSize
+	var previous []map[string]any
+	for page, written := 1, 0; ; page++ {
+		query.Page = page
+		result, err := p.repo.FetchTable(ctx, query)
+		if err != nil {
+			return fmt.Errorf("table provider: %w", err)
+		}
+		// A repository that ignores Page returns the same rows again.
+		if page > 1 && (len(result.Rows) == 0 || reflect.DeepEqual(result.Rows, previous)) {
+			return nil
+		}
+		previous = result.Rows
+		if page == 1 {
+			if len(columns) == 0 {
+				columns = result.Columns
+			}
+			if err := writeHeader(); err != nil {
+				return err
+			}
+		}
+		for _, row := range result.Rows {
+			if err := writeRow(row); err != nil {
+				return err
+			}
+		}
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return err
+		}
+		written += len(result.Rows)
+		// More rows than asked for means PageSize was ignored and the whole
+		// result came back at once.
+		if len(result.Rows) != query.PageSize || (result.Total > 0 && written >= result.Total) {
+			return nil
+		}
+	}
+}
+
+func newTableSpec(repo TableRepository) WidgetSpec[tableConfig, tableData, JSONViewModel[tableView]] {
+	return WidgetSpec[tableConfig, tableData, JSONViewModel[tableView]]{
+		Definition: WidgetDefinition{Code: "admin.widget.table"},
+		Fetch: func(ctx context.Context, req WidgetRequest[tableConfig]) (tableData, error) {
+			if strings.TrimSpace(req.Config.Dataset) == "" {
+				return tableData{}, fmt.Errorf("table provider: dataset is required")
+			}
+			params, _ := req.Options[tableParamsOptionKey].(map[string]string)
+			query := tableQueryFor(req.Config, req.TimeRange, req.Filters, req.Viewer, params)
+			page, err := repo.FetchTable(ctx, query)
+			if err != nil {
+				return tableData{}, fmt.Errorf("table provider: %w", err)
+			}
+			return tableData{Query: query, Page: page}, nil
+		},
+		BuildView: func(ctx context.Context, data tableData, meta WidgetViewContext[tableConfig]) (JSONViewModel[tableView], error) {
+			req := meta.Request
+			cfg := req.Config
+			columns := cfg.Columns
+			if len(columns) == 0 {
+				columns = data.Page.Columns
+			}
+			formatter := formatterOrDefault(req.Formatter, req.Viewer.Locale)
+			yes := translateOrFallback(ctx, req.Translator, "dashboard.widget.table.yes", req.Viewer.Locale, "Yes", nil)
+			no := translateOrFallback(ctx, req.Translator, "dashboard.widget.table.no", req.Viewer.Locale, "No", nil)
+			query := data.Query
+			view := tableView{
+				Title:    cfg.Title,
+				Dataset:  cfg.Dataset,
+				Columns:  make([]tableColumnView, len(columns)),
+				Rows:     make([]tableRowView, len(data.Page.Rows)),
+				Total:    data.Page.Total,
+				Page:     query.Page,
+				PageSize: query.PageSize,
+				Pages:    tablePageCount(data.Page.Total, query.PageSize),
+				Sort:     query.Sort,
+				Desc:     query.Desc,
+				Search:   query.Search,
+				PageURL:  WidgetActionURL(widgetActionPath(req.Options), req.Instance.ID, TableActionPage),
+				JSAssets: []string{TableScriptURL("")},
+			}
+			if view.Title == "" {
+				view.Title = titleize(strings.ReplaceAll(cfg.Dataset, "_", " "))
+			}
+			if cfg.Export == nil || *cfg.Export {
+				view.ExportURL = WidgetActionURL(widgetActionPath(req.Options), req.Instance.ID, TableActionExport)
+			}
+			for i, col := range columns {
+				view.Columns[i] = tableColumnView{
+					Key:      col.Key,
+					Label:    tableColumnLabel(col),
+					Type:     tableColumnType(col),
+					Sortable: col.Sortable,
+					Align:    tableColumnAlign(col),
+				}
+				if col.Key == query.Sort {
+					view.Columns[i].Sorted = "asc"
+					if query.Desc {
+						view.Columns[i].Sorted = "desc"
+					}
+				}
+			}
+			for i, row := range data.Page.Rows {
+				cells := make([]tableCellView, len(columns))
+				for j, col := range columns {
+					cells[j] = tableCellView{
+						Key:     col.Key,
+						Display: formatTableCell(formatter, row[col.Key], col, yes, no),
+						Raw:     tableCSVValue(row[col.Key], col),
+						Align:   view.Columns[j].Align,
+					}
+				}
+				view.Rows[i] = tableRowView{Cells: cells}
+			}
+			return JSONViewModel[tableView]{Value: view}, nil
+		},
+	}
+}
+
+// tableQueryFor merges the configured defaults with viewer-supplied params.
+// Sorting is only honored on columns declared sortable.
+func tableQueryFor(cfg tableConfig, timeRange *TimeRange, filters map[string]string, viewer ViewerContext, params map[string]string) TableQuery {
+	query := TableQuery{
+		Dataset:  cfg.Dataset,
+		Sort:     cfg.Sort,
+		Desc:     cfg.SortDesc,
+		Page:     1,
+		PageSize: cfg.PageSize,
+		Filters:  cloneFilterValues(filters),
+		Viewer:   viewer,
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultTablePageSize
+	}
+	if timeRange != nil {
+		query.From, query.To = timeRange.From, timeRange.To
+	}
+	if sort, ok := params["sort"]; ok && tableColumnSortable(cfg.Columns, sort) {
+		query.Sort = sort
+		query.Desc = false
+	}
+	if desc, err := strconv.ParseBool(params["desc"]); err == nil {
+		query.Desc = desc
+	}
+	if page, err := strconv.Atoi(params["page"]); err == nil && page > 0 {
+		query.Page = page
+	}
+	if size, err := strconv.Atoi(params["page_size"]); err == nil && size > 0 {
+		query.PageSize = min(size, maxTablePageSize)
+	}
+	query.Search = strings.TrimSpace(params["q"])
+	return query
+}
+
+func tableColumnSortable(columns []TableColumn, key string) bool {
+	if len(columns) == 0 {
+		// Columns come from the repository; let it validate the sort key.
+		return key != ""
+	}
+	for _, col := range columns {
+		if col.Key == key {
+			return col.Sortable
+		}
+	}
+	return false
+}
+
+func tablePageCount(total, pageSize int) int {
+	if total <= 0 || pageSize <= 0 {
+		return 1
+	}
+	return (total + pageSize - 1) / pageSize
+}
+
+func tableColumnLabel(col TableColumn) string {
+	if col.Label != "" {
+		return col.Label
+	}
+	return titleize(strings.ReplaceAll(col.Key, "_", " "))
+}
+
+func tableColumnType(col TableColumn) string {
+	if col.Type == "" {
+		return TableColumnString
+	}
+	return col.Type
+}
+
+func tableColumnAlign(col TableColumn) string {
+	switch col.Type {
+	case TableColumnNumber, TableColumnCurrency, TableColumnPercent:
+		return "end"
+	case TableColumnBool:
+		return "center"
+	default:
+		return "start"
+	}
+}
+
+// formatTableCell renders a cell for display according to the column type.
+func formatTableCell(f Formatter, value any, col TableColumn, yes, no string) string {
+	if value == nil {
+		return ""
+	}
+	switch col.Type {
+	case TableColumnNumber:
+		number, ok := tableNumber(value)
+		if !ok {
+			return fmt.Sprint(value)
+		}
+		decimals := 0
+		if number != math.Trunc(number) {
+			decimals = 2
+		}
+		return formatNumber(f, number, numberFormat{Decimals: tableDecimals(col.Decimals, decimals)})
+	case TableColumnCurrency, TableColumnPercent:
+		number, ok := tableNumber(value)
+		if !ok {
+			return fmt.Sprint(value)
+		}
+		return formatNumber(f, number, numberFormat{Kind: col.Type, Currency: col.Currency, Decimals: col.Decimals})
+	case TableColumnDate, TableColumnDateTime:
+		ts, ok := tableTime(value)
+		if !ok {
+			return fmt.Sprint(value)
+		}
+		switch {
+		case col.Format != "":
+			return ts.Format(col.Format)
+		case col.Type == TableColumnDateTime:
+			return f.DateTime(ts)
+		default:
+			return f.Date(ts)
+		}
+	case TableColumnBool:
+		if flag, ok := value.(bool); ok {
+			if flag {
+				return yes
+			}
+			return no
+		}
+		return fmt.Sprint(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// tableCSVCell encodes a cell for CSV export, escaping text that spreadsheets
+// would evaluate as a formula.
+func tableCSVCell(value any, col TableColumn) string {
+	text := tableCSVValue(value, col)
+	if _, ok := tableNumber(value); ok {
+		return text
+	}
+	return csvSafeText(text)
+}
+
+// csvSafeText prefixes text starting with a formula trigger with a quote.
+func csvSafeText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// tableCSVValue encodes a cell for CSV and datum attributes: numbers stay
+// unformatted and times use RFC 3339 so spreadsheets can parse them.
+func tableCSVValue(value any, col TableColumn) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if col.Type == TableColumnDate {
+			return typed.Format("2006-01-02")
+		}
+		return typed.Format(time.RFC3339)
+	case bool:
+		return strconv.FormatBool(typed)
+	}
+	if number, ok := tableNumber(value); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func tableDecimals(decimals *int, fallback int) *int {
+	if decimals != nil {
+		return decimals
+	}
+	return &fallback
+}
+
+func tableNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func tableTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if ts, err := time.Parse(layout, v); err == nil {
+				return ts, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func tableExportFilename(dataset string, now time.Time) string {
+	name := strings.Trim(tableFilenameUnsafe.ReplaceAllString(dataset, "-"), "-")
+	if name == "" {
+		name = "table"
+	}
+	return name + "-" + now.Format("20060102") + ".csv"
+}
+
+// NewStaticTableRepository serves rows from memory, applying search, filters,
+// sorting and paging. Filters match rows whose column with the filter's name
+// equals the value; rows without that column are kept.
+func NewStaticTableRepository(columns []TableColumn, rows []map[string]any) TableRepository {
+	return staticTableRepository{columns: columns, rows: rows}
+}
+
+type staticTableRepository struct {
+	columns []TableColumn
+	rows    []map[string]any
+}
+
+func (s staticTableRepository) FetchTable(_ context.Context, query TableQuery) (TablePage, error) {
+	matched := s.matching(query)
+	page := TablePage{Columns: slices.Clone(s.columns), Total: len(matched)}
+	start := min(query.Offset(), len(matched))
+	end := len(matched)
+	if query.PageSize > 0 {
+		end = min(start+query.PageSize, len(matched))
+	}
+	page.Rows = matched[start:end]
+	return page, nil
+}
+
+// StreamTable implements TableStreamer.
+func (s staticTableRepository) StreamTable(ctx context.Context, query TableQuery, yield func(row map[string]any) error) error {
+	for _, row := range s.matching(query) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := yield(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s staticTableRepository) matching(query TableQuery) []map[string]any {
+	search := strings.ToLower(query.Search)
+	matched := make([]map[string]any, 0, len(s.rows))
+	for _, row := range s.rows {
+		if !tableRowMatchesFilters(row, query.Filters) {
+			continue
+		}
+		if search != "" && !tableRowContains(row, search) {
+			continue
+		}
+		matched = append(matched, row)
+	}
+	if query.Sort != "" {
+		slices.SortStableFunc(matched, func(a, b map[string]any) int {
+			order := compareTableValues(a[query.Sort], b[query.Sort])
+			if query.Desc {
+				return -order
+			}
+			return order
+		})
+	}
+	return matched
+}
+
+func tableRowMatchesFilters(row map[string]any, filters map[string]string) bool {
+	for name, value := range filters {
+		if cell, ok := row[name]; ok && value != "" && !strings.EqualFold(fmt.Sprint(cell), value) {
+			return false
+		}
+	}
+	return true
+}
+
+func tableRowContains(row map[string]any, search string) bool {
+	for _, value := range row {
+		if text, ok := value.(string); ok && strings.Contains(strings.ToLower(text), search) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareTableValues(a, b any) int {
+	if an, ok := tableNumber(a); ok {
+		if bn, ok := tableNumber(b); ok {
+			return cmp.Compare(an, bn)
+		}
+	}
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Compare(bt)
+		}
+	}
+	if ab, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok {
+			switch {
+			case ab == bb:
+				return 0
+			case !ab:
+				return -1
+			default:
+				return 1
+			}
+		}
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// DemoTableRepository serves a deterministic orders dataset for demos and
+// tests.
+func DemoTableRepository() TableRepository {
+	columns := []TableColumn{
+		{Key: "order", Label: "Order", Sortable: true},
+		{Key: "customer", Label: "Customer", Sortable: true},
+		{Key: "region", Label: "Region", Sortable: true},
+		{Key: "amount", Label: "Amount", Type: TableColumnCurrency, Sortable: true},
+		{Key: "discount", Label: "Discount", Type: TableColumnPercent},
+		{Key: "created_at", Label: "Created", Type: TableColumnDate, Sortable: true},
+		{Key: "paid", Label: "Paid", Type: TableColumnBool, Sortable: true},
+	}
+	customers := []string{"Acme", "Globex", "Initech", "Umbrella", "Hooli", "Stark", "Wayne"}
+	regions := []string{"na", "emea", "apac"}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]map[string]any, 60)
+	for i := range rows {
+		rows[i] = map[string]any{
+			"order":      fmt.Sprintf("SO-%04d", 1001+i),
+			"customer":   customers[i%len(customers)],
+			"region":     regions[i%len(regions)],
+			"amount":     math.Round((250+float64((i*7919)%4800))*100) / 100,
+			"discount":   float64((i * 3) % 20),
+			"created_at": start.AddDate(0, 0, i),
+			"paid":       i%4 != 0,
+		}
+	}
+	return NewStaticTableRepository(columns, rows)
+}
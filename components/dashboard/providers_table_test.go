@@ -0,0 +1,201 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTableConfig() map[string]any {
+	return map[string]any{
+		"dataset":   "orders",
+		"page_size": 2,
+		"columns": []any{
+			map[string]any{"key": "order", "sortable": true},
+			map[string]any{"key": "amount", "type": "currency", "currency": "EUR", "sortable": true},
+			map[string]any{"key": "created_at", "type": "date"},
+			map[string]any{"key": "paid", "type": "bool"},
+		},
+	}
+}
+
+func testTableRepository() TableRepository {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return NewStaticTableRepository(nil, []map[string]any{
+		{"order": "SO-1", "amount": 1200.5, "created_at": day, "paid": true, "region": "emea"},
+		{"order": "SO-2", "amount": 80.0, "created_at": day.AddDate(0, 0, 1), "paid": false, "region": "na"},
+		{"order": "SO-3", "amount": 430.0, "created_at": day.AddDate(0, 0, 2), "paid": true, "region": "emea"},
+	})
+}
+
+func TestTableProviderPagesSortsAndFormats(t *testing.T) {
+	provider := NewTableProvider(testTableRepository())
+	handler, ok := provider.(WidgetActionHandler)
+	if !ok {
+		t.Fatalf("expected table provider to handle widget actions")
+	}
+	result, err := handler.HandleAction(context.Background(), WidgetContext{
+		Instance: WidgetInstance{ID: "t1", Configuration: testTableConfig()},
+		Filters:  map[string]string{"region": "emea"},
+	}, TableActionPage, map[string]string{"sort": "amount", "desc": "true", "page": "1"})
+	if err != nil {
+		t.Fatalf("HandleAction returned error: %v", err)
+	}
+	data, ok := result.Data.(WidgetData)
+	if !ok {
+		t.Fatalf("expected widget data payload, got %T", result.Data)
+	}
+	if intOr(data["total"], -1) != 2 || intOr(data["pages"], -1) != 1 {
+		t.Fatalf("expected filtered total of 2, got total=%v pages=%v", data["total"], data["pages"])
+	}
+	rows, _ := data["rows"].([]map[string]any)
+	if len(rows) != 2 {
+		t.Fatalf("expected two rows, got %+v", data["rows"])
+	}
+	first, _ := rows[0]["cells"].([]map[string]any)
+	if len(first) != 4 || first[0]["display"] != "SO-1" || first[1]["display"] != "€1,200.50" {
+		t.Fatalf("expected descending amount sort with currency formatting, got %+v", first)
+	}
+	if first[2]["display"] != "Mar 1, 2026" || first[3]["display"] != "Yes" {
+		t.Fatalf("unexpected date/bool formatting: %+v", first)
+	}
+	if data["page_url"] != "/admin/dashboard/widgets/t1/actions/page" || data["export_url"] != "/admin/dashboard/widgets/t1/actions/export" {
+		t.Fatalf("unexpected action urls: %v %v", data["page_url"], data["export_url"])
+	}
+}
+
+func TestTableQueryIgnoresUnsortableColumns(t *testing.T) {
+	cfg := tableConfig{Dataset: "orders", Sort: "order", Columns: []TableColumn{{Key: "order", Sortable: true}, {Key: "paid"}}}
+	query := tableQueryFor(cfg, nil, nil, ViewerContext{}, map[string]string{"sort": "paid", "page": "3", "page_size": "9999"})
+	if query.Sort != "order" || query.Page != 3 || query.PageSize != maxTablePageSize {
+		t.Fatalf("unexpected query %+v", query)
+	}
+	if query.Offset() != 2*maxTablePageSize {
+		t.Fatalf("unexpected offset %d", query.Offset())
+	}
+}
+
+func TestTableExportStreamsFullResultSet(t *testing.T) {
+	var pages []int
+	repo := TableRepositoryFunc(func(_ context.Context, query TableQuery) (TablePage, error) {
+		pages = append(pages, query.Page)
+		if query.Page > 1 {
+			return TablePage{Total: 1}, nil
+		}
+		return TablePage{
+			Columns: []TableColumn{{Key: "order"}, {Key: "amount", Type: TableColumnCurrency}},
+			Rows:    []map[string]any{{"order": "SO-1", "amount": 1200.5}},
+			Total:   1,
+		}, nil
+	})
+	result, err := NewTableProvider(repo).(WidgetActionHandler).HandleAction(context.Background(), WidgetContext{
+		Instance: WidgetInstance{ID: "t1", Configuration: map[string]any{"dataset": "orders/2026"}},
+	}, TableActionExport, nil)
+	if err != nil {
+		t.Fatalf("HandleAction returned error: %v", err)
+	}
+	if !strings.HasPrefix(result.Filename, "orders-2026-") || !strings.HasSuffix(result.Filename, ".csv") {
+		t.Fatalf("unexpected filename %q", result.Filename)
+	}
+	var buf bytes.Buffer
+	if err := result.Stream(&buf); err != nil {
+		t.Fatalf("stream returned error: %v", err)
+	}
+	if buf.String() != "Order,Amount\nSO-1,1200.5\n" {
+		t.Fatalf("unexpected csv %q", buf.String())
+	}
+	if len(pages) != 1 {
+		t.Fatalf("expected export to stop once total is reached, fetched pages %v", pages)
+	}
+}
+
+func TestTableExportEscapesFormulasAndStopsOnRepeatedPages(t *testing.T) {
+	rows := make([]map[string]any, maxTablePageSize)
+	for i := range rows {
+		rows[i] = map[string]any{"order": "SO", "amount": -5}
+	}
+	rows[0] = map[string]any{"order": "=HYPERLINK(\"http://evil\")", "amount": -5}
+	rows[1] = map[string]any{"order": "@SUM(A1)", "amount": "-2+3"}
+	var pages []int
+	repo := TableRepositoryFunc(func(_ context.Context, query TableQuery) (TablePage, error) {
+		pages = append(pages, query.Page)
+		return TablePage{Rows: rows}, nil
+	})
+	result, err := NewTableProvider(repo).(WidgetActionHandler).HandleAction(context.Background(), WidgetContext{
+		Instance: WidgetInstance{ID: "t1", Configuration: map[string]any{
+			"dataset": "orders",
+			"columns": []any{map[string]any{"key": "order"}, map[string]any{"key": "amount", "type": "number"}},
+		}},
+	}, TableActionExport, nil)
+	if err != nil {
+		t.Fatalf("HandleAction returned error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := result.Stream(&buf); err != nil {
+		t.Fatalf("stream returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != maxTablePageSize+1 || len(pages) != 2 {
+		t.Fatalf("expected export to stop when a page repeats, got %d lines from pages %v", len(lines), pages)
+	}
+	if lines[1] != `"'=HYPERLINK(""http://evil"")",-5` || lines[2] != "'@SUM(A1),'-2+3" {
+		t.Fatalf("expected formula cells escaped and numbers untouched, got %q %q", lines[1], lines[2])
+	}
+}
+
+type endlessTableStreamer struct {
+	TableRepositoryFunc
+}
+
+func (endlessTableStreamer) StreamTable(_ context.Context, _ TableQuery, yield func(map[string]any) error) error {
+	for {
+		if err := yield(map[string]any{"order": "SO"}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestTableExportStopsAtRowCap(t *testing.T) {
+	result, err := NewTableProvider(endlessTableStreamer{}).(WidgetActionHandler).HandleAction(context.Background(), WidgetContext{
+		Instance: WidgetInstance{ID: "t1", Configuration: map[string]any{
+			"dataset": "orders",
+			"columns": []any{map[string]any{"key": "order"}},
+		}},
+	}, TableActionExport, nil)
+	if err != nil {
+		t.Fatalf("HandleAction returned error: %v", err)
+	}
+	if err := result.Stream(io.Discard); !errors.Is(err, errTableExportTooLarge) {
+		t.Fatalf("expected export to stop at %d rows, got %v", maxTableExportRows, err)
+	}
+}
+
+func TestServiceExecuteWidgetActionChecksAccess(t *testing.T) {
+	store := &fakeWidgetStore{instances: map[string]WidgetInstance{
+		"t1": {ID: "t1", DefinitionID: "admin.widget.table", Configuration: map[string]any{"dataset": "orders"}},
+		"u1": {ID: "u1", DefinitionID: "admin.widget.user_stats"},
+	}}
+	svc := NewService(Options{
+		WidgetStore: store,
+		Authorizer:  allowListAuthorizer{allowed: map[string]bool{"t1": true, "u1": true}},
+	})
+	result, err := svc.ExecuteWidgetAction(context.Background(), WidgetActionRequest{WidgetID: "t1", Action: TableActionPage})
+	if err != nil {
+		t.Fatalf("ExecuteWidgetAction returned error: %v", err)
+	}
+	if data, _ := result.Data.(WidgetData); intOr(data["total"], -1) != 60 {
+		t.Fatalf("expected demo dataset total, got %v", data["total"])
+	}
+	if _, err := svc.ExecuteWidgetAction(context.Background(), WidgetActionRequest{WidgetID: "u1", Action: TableActionPage}); !errors.Is(err, ErrWidgetActionUnsupported) {
+		t.Fatalf("expected unsupported action error, got %v", err)
+	}
+
+	denied := NewService(Options{WidgetStore: store, Authorizer: allowListAuthorizer{}})
+	if _, err := denied.ExecuteWidgetAction(context.Background(), WidgetActionRequest{WidgetID: "t1", Action: TableActionPage}); !errors.Is(err, ErrWidgetActionForbidden) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+}
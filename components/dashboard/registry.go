@@ -3,6 +3,7 @@ package dashboard
 import (
 	"fmt"
 	"maps"
+	"slices"
 	"sync"
 )
 
@@ -37,6 +38,12 @@ type Registry struct {
 	providers       map[string]Provider
 	runtimes        map[string]widgetSpecRuntime
 	manifestMeta    map[string]ManifestProvider
+	versions        map[string]string
+	factories       map[string]ProviderFactory
+	capabilities    map[string]struct{}
+	migrations      map[string]map[int]SchemaMigration
+	catalog         map[string]CatalogEntry
+	catalogOrder    []string
 }
 
 // NewRegistry builds an empty registry and applies global hooks.
@@ -49,6 +56,11 @@ func NewRegistry() *Registry {
 		providers:       map[string]Provider{},
 		runtimes:        map[string]widgetSpecRuntime{},
 		manifestMeta:    map[string]ManifestProvider{},
+		versions:        map[string]string{},
+		factories:       map[string]ProviderFactory{},
+		capabilities:    map[string]struct{}{},
+		migrations:      map[string]map[int]SchemaMigration{},
+		catalog:         map[string]CatalogEntry{},
 	}
 	reg.registerDefaults()
 	_ = reg.ApplyHooks()
@@ -135,6 +147,9 @@ func (r *Registry) RegisterDefinition(def WidgetDefinition) error {
 		return fmt.Errorf("widget definition code is required")
 	}
 	def.normalizeLocalizedFields()
+	if err := def.Interactions.Validate(); err != nil {
+		return fmt.Errorf("widget definition %s: %w", def.Code, err)
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if _, ok := r.definitions[def.Code]; !ok {
@@ -217,6 +232,11 @@ func (r *Registry) Clone() *Registry {
 		providers:    map[string]Provider{},
 		runtimes:     map[string]widgetSpecRuntime{},
 		manifestMeta: map[string]ManifestProvider{},
+		versions:     map[string]string{},
+		factories:    map[string]ProviderFactory{},
+		capabilities: map[string]struct{}{},
+		migrations:   map[string]map[int]SchemaMigration{},
+		catalog:      map[string]CatalogEntry{},
 	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -233,6 +253,16 @@ func (r *Registry) Clone() *Registry {
 	for code, meta := range r.manifestMeta {
 		snapshot.manifestMeta[code] = cloneManifestProvider(meta)
 	}
+	maps.Copy(snapshot.versions, r.versions)
+	maps.Copy(snapshot.factories, r.factories)
+	maps.Copy(snapshot.capabilities, r.capabilities)
+	for code, steps := range r.migrations {
+		snapshot.migrations[code] = maps.Clone(steps)
+	}
+	snapshot.catalogOrder = append([]string{}, r.catalogOrder...)
+	for code, entry := range r.catalog {
+		snapshot.catalog[code] = cloneCatalogEntry(entry)
+	}
 	return snapshot
 }
 
@@ -269,10 +299,24 @@ func (r *Registry) recordProviderMetadata(code string, meta ManifestProvider) {
 	r.manifestMeta[code] = cloneManifestProvider(meta)
 }
 
+func (r *Registry) recordWidgetVersion(code, version string) {
+	if version == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.versions == nil {
+		r.versions = map[string]string{}
+	}
+	r.versions[code] = version
+}
+
 func cloneWidgetDefinition(def WidgetDefinition) WidgetDefinition {
 	def.NameLocalized = cloneLocalizedFields(def.NameLocalized)
 	def.DescriptionLocalized = cloneLocalizedFields(def.DescriptionLocalized)
 	def.Schema = cloneAnyMap(def.Schema)
+	def.Variables = slices.Clone(def.Variables)
+	def.Interactions = cloneWidgetInteractions(def.Interactions)
 	return def
 }
 
@@ -8,6 +8,18 @@ type Renderer interface {
 	RenderPage(name string, page Page, out ...io.Writer) (string, error)
 }
 
+// ConfigFormRenderer is implemented by renderers that can render widget
+// configuration forms as HTML partials.
+type ConfigFormRenderer interface {
+	RenderConfigForm(form ConfigForm, out ...io.Writer) (string, error)
+}
+
+// CatalogRenderer is implemented by renderers that can render the widget
+// catalog browser page.
+type CatalogRenderer interface {
+	RenderCatalog(page CatalogPage, out ...io.Writer) (string, error)
+}
+
 // LegacyRenderer describes the historical renderer contract that accepted
 // arbitrary payloads. It remains available only for migration adapters.
 type LegacyRenderer interface {
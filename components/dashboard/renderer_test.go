@@ -2,11 +2,16 @@ package dashboard
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"html/template"
 	"io"
+	"io/fs"
 	"os"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 type captureLegacyRenderer struct {
@@ -217,6 +222,48 @@ func TestTemplateRendererRendersShellMarkup(t *testing.T) {
 	}
 }
 
+func TestTemplateRendererMirrorsShellForRTLLocales(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Title:  "Workbench",
+		Locale: "ar-EG",
+		Shell: &Shell{
+			SurfaceID: "settings",
+			Regions: []ShellRegion{
+				{ID: "list", Role: ShellRegionRoleNavigation, Placement: ShellRegionPlacementLeading, Resizable: true},
+				{ID: "main", Role: ShellRegionRoleMain, Placement: ShellRegionPlacementMain},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`<html lang="ar-EG" dir="rtl">`, `class="dashboard" dir="rtl"`, `data-dashboard-shell-surface="settings"
+  dir="rtl"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in rendered page, got %s", want, out)
+		}
+	}
+
+	raw, err := json.Marshal(page)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if !strings.Contains(string(raw), `"dir":"rtl"`) {
+		t.Fatalf("expected dir in JSON payload, got %s", raw)
+	}
+	page.Dir = TextDirectionLTR
+	if payload := page.LegacyPayload(); payload["dir"] != TextDirectionLTR || payload["shell"].(map[string]any)["dir"] != TextDirectionLTR {
+		t.Fatalf("expected explicit page dir to override the locale, got %+v", payload)
+	}
+}
+
 func TestTemplateRendererRejectsInvalidShell(t *testing.T) {
 	renderer, err := NewTemplateRenderer()
 	if err != nil {
@@ -261,3 +308,341 @@ func TestTemplateRendererKeepsNoShellDashboardLayout(t *testing.T) {
 		t.Fatalf("expected existing dashboard columns to render, got %s", out)
 	}
 }
+
+func TestTemplateRendererRendersTimeRangePicker(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Title: "Dashboard",
+		TimeRange: &PageTimeRange{
+			Presets:  DefaultTimeRangePresets,
+			Selected: &TimeRange{Preset: TimeRangePreset30d},
+		},
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `data-time-range`) {
+		t.Fatalf("expected time range picker to render, got %s", out)
+	}
+	if !strings.Contains(out, `<option value="30d" selected>`) {
+		t.Fatalf("expected selected preset to be marked, got %s", out)
+	}
+}
+
+func TestTemplateRendererRendersFilterToolbar(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Title: "Dashboard",
+		Filters: []PageFilter{
+			{Name: "region", Label: "Region", Options: []FilterOption{{Value: "emea", Label: "EMEA"}, {Value: "apac"}}, Value: "apac"},
+			{Name: "product", Value: "pro"},
+		},
+		PreferencesURL: DefaultPreferencesPath,
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `name="var-region"`) || !strings.Contains(out, `<option value="apac" selected>`) {
+		t.Fatalf("expected region select with selected value, got %s", out)
+	}
+	if !strings.Contains(out, `name="var-product" value="pro"`) {
+		t.Fatalf("expected free-form product filter input, got %s", out)
+	}
+	if !strings.Contains(out, `data-preferences-url="/admin/dashboard/preferences"`) {
+		t.Fatalf("expected toolbar to carry the preferences endpoint, got %s", out)
+	}
+}
+
+func TestTemplateRendererRendersThemeVariants(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Title: "Dashboard",
+		Theme: &ThemeSelection{
+			Name:         "admin",
+			Variant:      "light",
+			Tokens:       map[string]string{"dashboard-surface": "#ffffff"},
+			FollowSystem: true,
+			Variants: map[string]ThemeVariant{
+				"light": {Tokens: map[string]string{"dashboard-surface": "#ffffff"}},
+				"dark":  {Tokens: map[string]string{"dashboard-surface": "#0f172a"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `data-theme="light" data-theme-mode="auto"`) || !strings.Contains(out, `data-theme-toggle`) {
+		t.Fatalf("expected switchable theme root and toggle, got %s", out)
+	}
+	if !strings.Contains(out, `@media (prefers-color-scheme: dark) { .dashboard[data-theme-mode="auto"] { --dashboard-surface: #0f172a; } }`) {
+		t.Fatalf("expected prefers-color-scheme rules, got %s", out)
+	}
+}
+
+func TestTemplateRendererRendersTableWidget(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	data, err := NewTableProvider(testTableRepository()).Fetch(context.Background(), WidgetContext{
+		Instance: WidgetInstance{ID: "t1", Configuration: testTableConfig()},
+	})
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	page := Page{
+		Title: "Dashboard",
+		Areas: []PageArea{{
+			Slot: "main",
+			Code: "admin.dashboard.main",
+			Widgets: []WidgetFrame{{
+				ID:         "t1",
+				Definition: "admin.widget.table",
+				Template:   "widgets/table.html",
+				Area:       "admin.dashboard.main",
+				Data:       data,
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `data-table-url="/admin/dashboard/widgets/t1/actions/page"`) {
+		t.Fatalf("expected table action url, got %s", out)
+	}
+	if !strings.Contains(out, `data-table-sort-key="amount"`) || !strings.Contains(out, `€1,200.50`) {
+		t.Fatalf("expected sortable header and formatted cell, got %s", out)
+	}
+	if !strings.Contains(out, `data-table-status>1 / 2</span>`) {
+		t.Fatalf("expected pager status, got %s", out)
+	}
+}
+
+func TestTemplateRendererResolvesThemeTemplatesBeforeOverlay(t *testing.T) {
+	overlay := fstest.MapFS{
+		"acme/activity.html":      {Data: []byte(`<div class="acme-activity">{{ widget.id }}</div>`)},
+		"acme/area.html":          {Data: []byte(`<div class="acme-area">{% for widget in area.widgets %}{% include widget.template with widget=widget %}{% endfor %}</div>`)},
+		"widgets/user_stats.html": {Data: []byte(`<p class="overlay-stats">{{ widget.id }}</p>`)},
+	}
+	renderer, err := NewTemplateRenderer(WithTemplateOverlay(overlay))
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	widgets := []WidgetFrame{
+		{ID: "a1", Definition: "admin.widget.recent_activity", Template: "widgets/recent_activity.html", Area: "admin.dashboard.main"},
+		{ID: "s1", Definition: "admin.widget.user_stats", Template: "widgets/user_stats.html", Area: "admin.dashboard.main"},
+	}
+	page := Page{
+		Title: "Dashboard",
+		Areas: []PageArea{{Slot: "main", Code: "admin.dashboard.main", Widgets: widgets}},
+	}
+
+	var plain bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &plain); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	if !strings.Contains(plain.String(), `overlay-stats`) || strings.Contains(plain.String(), `acme-area`) {
+		t.Fatalf("expected overlay template without theme overrides, got %s", plain.String())
+	}
+
+	page.Theme = &ThemeSelection{Name: "acme", Templates: map[string]string{
+		"widgets/recent_activity.html":   "acme/activity.html",
+		"components/dashboard/area.html": "acme/area.html",
+		"widgets/quick_actions.html":     "acme/missing.html",
+		"../outside.html":                "acme/activity.html",
+	}}
+	var themed bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &themed); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	out := themed.String()
+	if !strings.Contains(out, `<div class="acme-area">`) || !strings.Contains(out, `<div class="acme-activity">a1</div>`) {
+		t.Fatalf("expected theme area and widget templates, got %s", out)
+	}
+	if !strings.Contains(out, `overlay-stats`) {
+		t.Fatalf("expected overlay to back templates the theme does not override, got %s", out)
+	}
+}
+
+func TestTemplateRendererAppliesThemeTemplatesToFormsAndCatalog(t *testing.T) {
+	overlay := fstest.MapFS{
+		"acme/config_form.html": {Data: []byte(`<form class="acme-form">{{ form.definition }}</form>`)},
+		"acme/catalog.html":     {Data: []byte(`<main class="acme-catalog">{{ title }}</main>`)},
+	}
+	renderer, err := NewTemplateRenderer(WithTemplateOverlay(overlay))
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	theme := &ThemeSelection{Name: "acme", Templates: map[string]string{
+		configFormTemplate: "acme/config_form.html",
+		catalogTemplate:    "acme/catalog.html",
+	}}
+
+	form := BuildConfigForm(WidgetDefinition{Code: "admin.widget.kpi"}, nil)
+	form.Theme = theme
+	var formOut bytes.Buffer
+	if err := (&Controller{renderer: renderer}).RenderConfigForm(form, &formOut); err != nil {
+		t.Fatalf("RenderConfigForm returned error: %v", err)
+	}
+	if !strings.Contains(formOut.String(), `<form class="acme-form">admin.widget.kpi</form>`) {
+		t.Fatalf("expected themed config form template, got %s", formOut.String())
+	}
+
+	var catalogOut bytes.Buffer
+	if err := (&Controller{renderer: renderer}).RenderCatalog(CatalogPage{Title: "Widgets", Theme: theme}, &catalogOut); err != nil {
+		t.Fatalf("RenderCatalog returned error: %v", err)
+	}
+	if !strings.Contains(catalogOut.String(), `<main class="acme-catalog">Widgets</main>`) {
+		t.Fatalf("expected themed catalog template, got %s", catalogOut.String())
+	}
+}
+
+func TestWidgetTemplateForPrefersThemeOverrides(t *testing.T) {
+	theme := &ThemeSelection{Templates: map[string]string{
+		"admin.widget.kpi":    "acme/kpi.html",
+		"widgets/table.html":  "acme/./table.html",
+		"admin.widget.sneaky": "/etc/passwd",
+	}}
+	cases := map[string]string{
+		"admin.widget.kpi":        "acme/kpi.html",
+		"admin.widget.table":      "acme/table.html",
+		"admin.widget.sneaky":     "widgets/sneaky.html",
+		"admin.widget.user_stats": "widgets/user_stats.html",
+	}
+	for definition, want := range cases {
+		if got := widgetTemplateFor(theme, definition); got != want {
+			t.Fatalf("widgetTemplateFor(%s) = %s, want %s", definition, got, want)
+		}
+	}
+	if got := widgetTemplateFor(nil, "admin.widget.kpi"); got != "widgets/kpi.html" {
+		t.Fatalf("expected default template without theme, got %s", got)
+	}
+}
+
+func TestTemplateRendererLayersOverlaysAndRereadsInDevMode(t *testing.T) {
+	base := fstest.MapFS{
+		"widgets/user_stats.html":      {Data: []byte(`<p>base {{ widget.id }}</p>`)},
+		"widgets/recent_activity.html": {Data: []byte(`<p>base activity</p>`)},
+	}
+	host := fstest.MapFS{
+		"widgets/user_stats.html": {Data: []byte(`<p>host v1 {{ widget.id }}</p>`)},
+	}
+	renderer, err := NewTemplateRenderer(WithTemplateOverlay(base, host), WithTemplateDevMode(true))
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Title: "Dashboard",
+		Areas: []PageArea{{Slot: "main", Code: "admin.dashboard.main", Widgets: []WidgetFrame{
+			{ID: "s1", Definition: "admin.widget.user_stats", Template: "widgets/user_stats.html"},
+			{ID: "a1", Definition: "admin.widget.recent_activity", Template: "widgets/recent_activity.html"},
+		}}},
+	}
+	render := func() string {
+		var buf bytes.Buffer
+		if _, err := renderer.RenderPage("dashboard.html", page, &buf); err != nil {
+			t.Fatalf("RenderPage returned error: %v", err)
+		}
+		return buf.String()
+	}
+
+	out := render()
+	if !strings.Contains(out, "host v1 s1") || !strings.Contains(out, "base activity") {
+		t.Fatalf("expected later overlay to win per file, got %s", out)
+	}
+	host["widgets/user_stats.html"] = &fstest.MapFile{Data: []byte(`<p>host v2 {{ widget.id }}</p>`)}
+	if out := render(); !strings.Contains(out, "host v2 s1") {
+		t.Fatalf("expected dev mode to pick up template edits, got %s", out)
+	}
+	delete(host, "widgets/user_stats.html")
+	if out := render(); !strings.Contains(out, "base s1") {
+		t.Fatalf("expected a removed overlay file to fall back to the next layer, got %s", out)
+	}
+}
+
+func TestTemplateRendererReadsFileThemeTemplates(t *testing.T) {
+	provider, err := NewFileThemeProvider(fstest.MapFS{
+		"acme.yaml": {Data: []byte(`
+name: acme
+variants:
+  light:
+    templates:
+      widgets/user_stats.html: acme/stats.html
+`)},
+		"acme/stats.html": {Data: []byte(`<p class="acme-stats">{{ widget.id }}</p>`)},
+	})
+	if err != nil {
+		t.Fatalf("NewFileThemeProvider returned error: %v", err)
+	}
+	theme, err := provider.SelectTheme(context.Background(), ThemeSelector{})
+	if err != nil {
+		t.Fatalf("SelectTheme returned error: %v", err)
+	}
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{
+		Title: "Dashboard",
+		Theme: theme,
+		Areas: []PageArea{{Slot: "main", Code: "admin.dashboard.main", Widgets: []WidgetFrame{
+			{ID: "s1", Definition: "admin.widget.user_stats", Template: "widgets/user_stats.html"},
+		}}},
+	}
+	var out bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", page, &out); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), `<p class="acme-stats">s1</p>`) {
+		t.Fatalf("expected template from the theme filesystem, got %s", out.String())
+	}
+}
+
+func TestTemplateRendererCloseRemovesTemplateDirs(t *testing.T) {
+	renderer, err := NewTemplateRenderer(WithTemplateDevMode(true))
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	page := Page{Title: "Dashboard"}
+	var dirs []string
+	for range 2 {
+		if _, err := renderer.RenderPage("dashboard.html", page); err != nil {
+			t.Fatalf("RenderPage returned error: %v", err)
+		}
+		root, _, err := renderer.(*templatePageRenderer).rootFor(nil)
+		if err != nil {
+			t.Fatalf("rootFor returned error: %v", err)
+		}
+		dirs = append(dirs, root.dir)
+	}
+	if dirs[0] != dirs[1] {
+		t.Fatalf("expected dev mode to reuse the template dir, got %v", dirs)
+	}
+	if err := renderer.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if _, err := os.Stat(dirs[0]); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected template dir removed, got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// widgetSchemaVersionMetadataKey records the schema version an instance's
+// configuration was written against.
+const widgetSchemaVersionMetadataKey = "schema_version"
+
+// SchemaMigration upgrades a widget configuration by one schema version. It
+// receives a copy of the stored configuration and returns the new one.
+type SchemaMigration func(config map[string]any) (map[string]any, error)
+
+// RegisterSchemaMigration registers the migration that upgrades configuration
+// of definition code from schema version `from` to from+1. Register one
+// migration per step up to WidgetDefinition.SchemaVersion.
+func (r *Registry) RegisterSchemaMigration(code string, from int, migration SchemaMigration) error {
+	if code == "" {
+		return fmt.Errorf("widget definition code is required to register schema migration")
+	}
+	if from < 1 {
+		return fmt.Errorf("widget %s schema migration must start at version 1 or later", code)
+	}
+	if migration == nil {
+		return fmt.Errorf("widget %s schema migration from version %d cannot be nil", code, from)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.migrations == nil {
+		r.migrations = map[string]map[int]SchemaMigration{}
+	}
+	if r.migrations[code] == nil {
+		r.migrations[code] = map[int]SchemaMigration{}
+	}
+	r.migrations[code][from] = migration
+	return nil
+}
+
+// MigrateConfiguration runs the registered migrations that take config from
+// schema version `from` to def.SchemaVersion. The input map is not modified.
+func (r *Registry) MigrateConfiguration(def WidgetDefinition, from int, config map[string]any) (map[string]any, error) {
+	target := def.schemaVersion()
+	if from == target {
+		return config, nil
+	}
+	if from > target {
+		return nil, fmt.Errorf("dashboard: widget %s configuration has schema version %d, newer than definition version %d", def.Code, from, target)
+	}
+	r.mu.RLock()
+	steps := maps.Clone(r.migrations[def.Code])
+	r.mu.RUnlock()
+	migrated := cloneAnyMap(config)
+	for version := from; version < target; version++ {
+		migration, ok := steps[version]
+		if !ok {
+			return nil, fmt.Errorf("dashboard: widget %s has no schema migration from version %d to %d", def.Code, version, version+1)
+		}
+		next, err := migration(migrated)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard: migrate widget %s schema from version %d to %d: %w", def.Code, version, version+1, err)
+		}
+		migrated = next
+	}
+	return migrated, nil
+}
+
+// schemaVersion reports the definition's schema version; unversioned
+// definitions are at version 1.
+func (def WidgetDefinition) schemaVersion() int {
+	return max(def.SchemaVersion, 1)
+}
+
+// WidgetSchemaVersion reports the schema version recorded on an instance.
+// Instances saved before schema versioning are at version 1.
+func WidgetSchemaVersion(inst WidgetInstance) int {
+	return max(intValue(inst.Metadata[widgetSchemaVersionMetadataKey]), 1)
+}
+
+// withSchemaVersion returns a copy of metadata stamped with version.
+func withSchemaVersion(metadata map[string]any, version int) map[string]any {
+	out := maps.Clone(metadata)
+	if out == nil {
+		out = map[string]any{}
+	}
+	out[widgetSchemaVersionMetadataKey] = version
+	return out
+}
+
+type schemaMigrator interface {
+	MigrateConfiguration(def WidgetDefinition, from int, config map[string]any) (map[string]any, error)
+}
+
+// migrateInstance brings an instance's configuration up to its definition's
+// schema version. It reports whether anything changed; instances of unknown
+// or unversioned definitions are returned untouched.
+func (s *Service) migrateInstance(inst WidgetInstance) (WidgetInstance, bool, error) {
+	if s.opts.Providers == nil {
+		return inst, false, nil
+	}
+	def, ok := s.opts.Providers.Definition(inst.DefinitionID)
+	if !ok || def.SchemaVersion == 0 {
+		return inst, false, nil
+	}
+	from := WidgetSchemaVersion(inst)
+	if from == def.schemaVersion() {
+		return inst, false, nil
+	}
+	migrator, ok := s.opts.Providers.(schemaMigrator)
+	if !ok {
+		return inst, false, fmt.Errorf("dashboard: widget %s needs schema migration but the registry does not support migrations", def.Code)
+	}
+	config, err := migrator.MigrateConfiguration(def, from, inst.Configuration)
+	if err != nil {
+		return inst, false, err
+	}
+	inst.Configuration = config
+	inst.Metadata = withSchemaVersion(inst.Metadata, def.schemaVersion())
+	return inst, true, nil
+}
+
+// Schema migration statuses reported per instance.
+const (
+	SchemaMigrationMigrated = "migrated"
+	SchemaMigrationCurrent  = "current"
+	SchemaMigrationFailed   = "failed"
+)
+
+// MigrateWidgetsRequest selects the instances a bulk schema migration visits.
+// With no InstanceIDs every instance the store lists is visited (see
+// WidgetInstanceLister); DefinitionIDs narrows the set; DryRun reports without
+// saving.
+type MigrateWidgetsRequest struct {
+	InstanceIDs   []string `json:"instance_ids,omitempty"`
+	DefinitionIDs []string `json:"definition_ids,omitempty"`
+	DryRun        bool     `json:"dry_run,omitempty"`
+}
+
+// SchemaMigrationResult describes the outcome for one instance.
+type SchemaMigrationResult struct {
+	InstanceID   string `json:"instance_id"`
+	DefinitionID string `json:"definition_id"`
+	FromVersion  int    `json:"from_version"`
+	ToVersion    int    `json:"to_version"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// SchemaMigrationReport lists per-instance results of a bulk migration.
+type SchemaMigrationReport struct {
+	DryRun  bool                    `json:"dry_run,omitempty"`
+	Results []SchemaMigrationResult `json:"results"`
+}
+
+// Count returns how many results have the given status.
+func (r SchemaMigrationReport) Count(status string) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// WidgetInstanceLister is implemented by stores that can enumerate every
+// instance regardless of audience. Without it bulk migrations fall back to
+// resolving each configured area, which skips audience-restricted widgets.
+type WidgetInstanceLister interface {
+	ListInstances(ctx context.Context) ([]WidgetInstance, error)
+}
+
+// MigrateWidgets eagerly migrates stored instances to their definitions'
+// schema versions, validating and saving each migrated configuration. A
+// failing instance is reported and does not stop the run; the error return is
+// reserved for failures to list instances. Telemetry is recorded by the
+// migrate command.
+func (s *Service) MigrateWidgets(ctx context.Context, req MigrateWidgetsRequest) (SchemaMigrationReport, error) {
+	report := SchemaMigrationReport{DryRun: req.DryRun, Results: []SchemaMigrationResult{}}
+	store, err := s.widgetStore()
+	if err != nil {
+		return report, err
+	}
+	instances, err := s.migrationCandidates(ctx, store, req.InstanceIDs)
+	if err != nil {
+		return report, err
+	}
+	for _, inst := range instances {
+		if len(req.DefinitionIDs) > 0 && !slices.Contains(req.DefinitionIDs, inst.DefinitionID) {
+			continue
+		}
+		result := SchemaMigrationResult{
+			InstanceID:   inst.ID,
+			DefinitionID: inst.DefinitionID,
+			FromVersion:  WidgetSchemaVersion(inst),
+			ToVersion:    WidgetSchemaVersion(inst),
+			Status:       SchemaMigrationCurrent,
+		}
+		migrated, changed, err := s.migrateInstance(inst)
+		if err == nil && changed {
+			result.ToVersion = WidgetSchemaVersion(migrated)
+			err = s.validateConfiguration(inst.DefinitionID, migrated.Configuration)
+			if err == nil && !req.DryRun {
+				_, err = store.UpdateInstance(ctx, UpdateWidgetInstanceInput{
+					InstanceID:    inst.ID,
+					Configuration: migrated.Configuration,
+					Metadata:      migrated.Metadata,
+				})
+			}
+			if err == nil {
+				result.Status = SchemaMigrationMigrated
+			}
+		}
+		if err != nil {
+			result.Status = SchemaMigrationFailed
+			result.Error = err.Error()
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func (s *Service) migrationCandidates(ctx context.Context, store WidgetStore, ids []string) ([]WidgetInstance, error) {
+	if len(ids) > 0 {
+		instances := make([]WidgetInstance, 0, len(ids))
+		for _, id := range ids {
+			inst, err := store.GetInstance(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("dashboard: load widget %s: %w", id, err)
+			}
+			instances = append(instances, inst)
+		}
+		return instances, nil
+	}
+	if lister, ok := store.(WidgetInstanceLister); ok {
+		return lister.ListInstances(ctx)
+	}
+	var instances []WidgetInstance
+	seen := map[string]bool{}
+	for _, area := range s.areaList() {
+		resolved, err := store.ResolveArea(ctx, ResolveAreaInput{AreaCode: area})
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range resolved.Widgets {
+			if !seen[inst.ID] {
+				seen[inst.ID] = true
+				instances = append(instances, inst)
+			}
+		}
+	}
+	return instances, nil
+}
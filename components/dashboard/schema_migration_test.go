@@ -0,0 +1,202 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func migrationRegistry(t *testing.T) (*Registry, *map[string]any) {
+	t.Helper()
+	reg := NewRegistry()
+	if err := reg.RegisterDefinition(WidgetDefinition{
+		Code:          "acme.widget.counter",
+		Name:          "Counter",
+		SchemaVersion: 3,
+		Schema: map[string]any{
+			"type":                 "object",
+			"required":             []any{"label"},
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"label": map[string]any{"type": "string"},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("register definition: %v", err)
+	}
+	rename := func(from, to string) SchemaMigration {
+		return func(config map[string]any) (map[string]any, error) {
+			value, ok := config[from]
+			if !ok {
+				return nil, fmt.Errorf("missing %s", from)
+			}
+			delete(config, from)
+			config[to] = value
+			return config, nil
+		}
+	}
+	if err := reg.RegisterSchemaMigration("acme.widget.counter", 1, rename("title", "heading")); err != nil {
+		t.Fatalf("register migration: %v", err)
+	}
+	if err := reg.RegisterSchemaMigration("acme.widget.counter", 2, rename("heading", "label")); err != nil {
+		t.Fatalf("register migration: %v", err)
+	}
+	seen := map[string]any{}
+	if err := reg.RegisterProvider("acme.widget.counter", ProviderFunc(func(_ context.Context, meta WidgetContext) (WidgetData, error) {
+		seen = meta.Instance.Configuration
+		return WidgetData{"label": meta.Instance.Configuration["label"]}, nil
+	})); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	return reg, &seen
+}
+
+func TestConfigureLayoutMigratesInstancesLazily(t *testing.T) {
+	reg, seen := migrationRegistry(t)
+	stored := WidgetInstance{ID: "w1", DefinitionID: "acme.widget.counter", Configuration: map[string]any{"title": "Orders"}}
+	store := &fakeWidgetStore{resolved: map[string][]WidgetInstance{"admin.dashboard.main": {stored}}}
+	service := NewService(Options{WidgetStore: store, Providers: reg, Areas: []string{"admin.dashboard.main"}})
+
+	layout, err := service.ConfigureLayout(context.Background(), ViewerContext{UserID: "u1"})
+	if err != nil {
+		t.Fatalf("ConfigureLayout returned error: %v", err)
+	}
+	widget := layout.Areas["admin.dashboard.main"][0]
+	if widget.Configuration["label"] != "Orders" || (*seen)["label"] != "Orders" {
+		t.Fatalf("expected configuration migrated to v3, got %+v (provider saw %+v)", widget.Configuration, *seen)
+	}
+	if WidgetSchemaVersion(widget) != 3 {
+		t.Fatalf("expected schema version 3, got %d", WidgetSchemaVersion(widget))
+	}
+	if _, ok := store.resolved["admin.dashboard.main"][0].Configuration["title"]; !ok {
+		t.Fatalf("expected stored configuration to stay untouched on resolve")
+	}
+}
+
+func TestAddWidgetRecordsSchemaVersion(t *testing.T) {
+	reg, _ := migrationRegistry(t)
+	var created CreateWidgetInstanceInput
+	store := &fakeWidgetStore{createInstanceFn: func(input CreateWidgetInstanceInput) (WidgetInstance, error) {
+		created = input
+		return WidgetInstance{ID: "w1", DefinitionID: input.DefinitionID}, nil
+	}}
+	service := NewService(Options{WidgetStore: store, Providers: reg})
+	if err := service.AddWidget(context.Background(), AddWidgetRequest{
+		DefinitionID:  "acme.widget.counter",
+		AreaCode:      "admin.dashboard.main",
+		Configuration: map[string]any{"label": "Orders"},
+	}); err != nil {
+		t.Fatalf("AddWidget returned error: %v", err)
+	}
+	if created.Metadata[widgetSchemaVersionMetadataKey] != 3 {
+		t.Fatalf("expected schema version recorded, got %+v", created.Metadata)
+	}
+}
+
+func TestMigrateWidgetsReportsPerInstance(t *testing.T) {
+	reg, _ := migrationRegistry(t)
+	store := &fakeWidgetStore{
+		instances: map[string]WidgetInstance{
+			"old":     {ID: "old", DefinitionID: "acme.widget.counter", Configuration: map[string]any{"title": "Orders"}},
+			"middle":  {ID: "middle", DefinitionID: "acme.widget.counter", Configuration: map[string]any{"heading": "Users"}, Metadata: map[string]any{"schema_version": 2.0}},
+			"current": {ID: "current", DefinitionID: "acme.widget.counter", Configuration: map[string]any{"label": "Sales"}, Metadata: map[string]any{"schema_version": 3}},
+			"broken":  {ID: "broken", DefinitionID: "acme.widget.counter", Configuration: map[string]any{"name": "?"}},
+		},
+	}
+	telemetry := &testTelemetry{}
+	service := NewService(Options{WidgetStore: store, Providers: reg, Telemetry: telemetry})
+	ids := []string{"old", "middle", "current", "broken"}
+
+	dry, err := service.MigrateWidgets(context.Background(), MigrateWidgetsRequest{InstanceIDs: ids, DryRun: true})
+	if err != nil {
+		t.Fatalf("MigrateWidgets dry run returned error: %v", err)
+	}
+	if dry.Count(SchemaMigrationMigrated) != 2 || store.instances["old"].Configuration["title"] != "Orders" {
+		t.Fatalf("expected dry run to report without saving, got %+v", dry)
+	}
+
+	report, err := service.MigrateWidgets(context.Background(), MigrateWidgetsRequest{InstanceIDs: ids})
+	if err != nil {
+		t.Fatalf("MigrateWidgets returned error: %v", err)
+	}
+	byID := map[string]SchemaMigrationResult{}
+	for _, result := range report.Results {
+		byID[result.InstanceID] = result
+	}
+	if got := byID["old"]; got.Status != SchemaMigrationMigrated || got.FromVersion != 1 || got.ToVersion != 3 {
+		t.Fatalf("unexpected result for old: %+v", got)
+	}
+	if got := byID["middle"]; got.Status != SchemaMigrationMigrated || got.FromVersion != 2 {
+		t.Fatalf("unexpected result for middle: %+v", got)
+	}
+	if got := byID["current"]; got.Status != SchemaMigrationCurrent {
+		t.Fatalf("unexpected result for current: %+v", got)
+	}
+	if got := byID["broken"]; got.Status != SchemaMigrationFailed || !strings.Contains(got.Error, "missing title") {
+		t.Fatalf("unexpected result for broken: %+v", got)
+	}
+	saved := store.instances["old"]
+	if saved.Configuration["label"] != "Orders" || WidgetSchemaVersion(saved) != 3 {
+		t.Fatalf("expected migrated instance saved, got %+v", saved)
+	}
+	if telemetry.calls != 0 {
+		t.Fatalf("expected the migrate command, not the service, to record telemetry; got %d calls", telemetry.calls)
+	}
+	if _, ok := store.instances["broken"].Configuration["name"]; !ok {
+		t.Fatalf("expected failed instance left untouched")
+	}
+}
+
+func TestMigrateConfigurationRequiresEveryStep(t *testing.T) {
+	reg := NewRegistry()
+	def := WidgetDefinition{Code: "acme.widget.gap", Name: "Gap", SchemaVersion: 2}
+	if _, err := reg.MigrateConfiguration(def, 1, map[string]any{}); err == nil || !strings.Contains(err.Error(), "no schema migration from version 1 to 2") {
+		t.Fatalf("expected missing step error, got %v", err)
+	}
+	if _, err := reg.MigrateConfiguration(def, 3, map[string]any{}); err == nil {
+		t.Fatalf("expected error for configuration newer than definition")
+	}
+}
+
+type configEchoProvider struct{}
+
+func (configEchoProvider) Fetch(context.Context, WidgetContext) (WidgetData, error) {
+	return WidgetData{}, nil
+}
+
+func (configEchoProvider) HandleAction(_ context.Context, meta WidgetContext, _ string, _ map[string]string) (WidgetActionResult, error) {
+	return WidgetActionResult{Data: meta.Instance.Configuration}, nil
+}
+
+func TestWidgetActionsAndConfigFormsUseMigratedConfiguration(t *testing.T) {
+	reg, _ := migrationRegistry(t)
+	if err := reg.RegisterProvider("acme.widget.counter", configEchoProvider{}); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	store := &fakeWidgetStore{instances: map[string]WidgetInstance{
+		"w1":     {ID: "w1", DefinitionID: "acme.widget.counter", Configuration: map[string]any{"title": "Orders"}},
+		"broken": {ID: "broken", DefinitionID: "acme.widget.counter", Configuration: map[string]any{"name": "Orders"}},
+	}}
+	service := NewService(Options{WidgetStore: store, Providers: reg})
+	viewer := ViewerContext{UserID: "u1"}
+
+	result, err := service.ExecuteWidgetAction(context.Background(), WidgetActionRequest{WidgetID: "w1", Action: "echo", Viewer: viewer})
+	if err != nil {
+		t.Fatalf("ExecuteWidgetAction returned error: %v", err)
+	}
+	if config, _ := result.Data.(map[string]any); config["label"] != "Orders" {
+		t.Fatalf("expected the action to see the migrated configuration, got %+v", result.Data)
+	}
+	if _, err := service.ExecuteWidgetAction(context.Background(), WidgetActionRequest{WidgetID: "broken", Action: "echo", Viewer: viewer}); err == nil || !strings.Contains(err.Error(), "missing title") {
+		t.Fatalf("expected migration error from action, got %v", err)
+	}
+
+	form, err := service.WidgetConfigForm(context.Background(), WidgetConfigFormRequest{WidgetID: "broken", Viewer: viewer})
+	if err != nil {
+		t.Fatalf("WidgetConfigForm returned error: %v", err)
+	}
+	if form.Valid || len(form.Errors) != 1 || !strings.Contains(form.Errors[0].Message, "missing title") || form.Values["name"] != "Orders" {
+		t.Fatalf("expected migration error on the form with stored values, got %+v", form)
+	}
+}
@@ -3,6 +3,8 @@ package dashboard
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -34,12 +36,23 @@ type Options struct {
 	Telemetry       Telemetry
 	ThemeProvider   ThemeProvider
 	ThemeSelector   ThemeSelectorFunc
-	Areas           []string
-	Translation     TranslationService
-	ScriptNonce     func(context.Context) string
-	ActivityHooks   activity.Hooks
-	ActivityConfig  activity.Config
-	ActivityFeed    ActivityFeed
+	// ThemeVariants lists variants (e.g. light, dark) fetched alongside the
+	// selected one when the provider does not report them itself, so pages
+	// can switch variants without a reload.
+	ThemeVariants []string
+	Areas         []string
+	Filters       []DashboardFilter
+	Translation   TranslationService
+	// Formatter builds the locale formatter handed to widgets; it defaults to
+	// NewLocaleFormatter.
+	Formatter   func(locale string) Formatter
+	ScriptNonce func(context.Context) string
+	// WidgetActionPath overrides DefaultWidgetActionPath when the action
+	// endpoint is mounted elsewhere.
+	WidgetActionPath string
+	ActivityHooks    activity.Hooks
+	ActivityConfig   activity.Config
+	ActivityFeed     ActivityFeed
 }
 
 // Service orchestrates dashboard widgets on top of go-cms.
@@ -125,6 +138,9 @@ func (s *Service) AddWidget(ctx context.Context, req AddWidgetRequest) error {
 	if req.Locale != "" {
 		metadata["locale"] = req.Locale
 	}
+	if def, ok := s.opts.Providers.Definition(req.DefinitionID); ok && def.SchemaVersion > 0 {
+		metadata[widgetSchemaVersionMetadataKey] = def.SchemaVersion
+	}
 	instance, err := store.CreateInstance(ctx, CreateWidgetInstanceInput{
 		DefinitionID:  req.DefinitionID,
 		Configuration: req.Configuration,
@@ -259,6 +275,14 @@ func (s *Service) UpdateWidget(ctx context.Context, widgetID string, req UpdateW
 	if req.Metadata != nil {
 		updateInput.Metadata = req.Metadata
 	}
+	// New configuration is written against the current schema.
+	if def, ok := s.opts.Providers.Definition(current.DefinitionID); ok && def.SchemaVersion > 0 && req.Configuration != nil {
+		base := updateInput.Metadata
+		if base == nil {
+			base = current.Metadata
+		}
+		updateInput.Metadata = withSchemaVersion(base, def.SchemaVersion)
+	}
 	updated, err := store.UpdateInstance(ctx, updateInput)
 	if err != nil {
 		return err
@@ -268,8 +292,8 @@ func (s *Service) UpdateWidget(ctx context.Context, widgetID string, req UpdateW
 		if req.Configuration != nil {
 			updated.Configuration = req.Configuration
 		}
-		if req.Metadata != nil {
-			updated.Metadata = req.Metadata
+		if updateInput.Metadata != nil {
+			updated.Metadata = updateInput.Metadata
 		}
 	}
 	if updated.AreaCode == "" {
@@ -371,9 +395,15 @@ func (s *Service) resolveLayoutState(ctx context.Context, viewer ViewerContext)
 	if err != nil {
 		return Layout{}, LayoutOverrides{}, err
 	}
+	if viewer.Editing && !s.CanEditLayout(ctx, viewer) {
+		viewer.Editing = false
+	}
+	filterValues := s.filterValues(viewer.Filters, overrides.Filters)
+	viewer.Filters = filterValues
 	layout := Layout{
-		Areas: make(map[string][]WidgetInstance),
-		Theme: theme,
+		Areas:   make(map[string][]WidgetInstance),
+		Theme:   theme,
+		Filters: pageFilters(s.opts.Filters, filterValues),
 	}
 	for _, area := range s.areaList() {
 		resolved, err := store.ResolveArea(ctx, ResolveAreaInput{
@@ -389,8 +419,22 @@ func (s *Service) resolveLayoutState(ctx context.Context, viewer ViewerContext)
 			resolved.Widgets[i].AreaCode = area
 		}
 		filtered := s.filterAuthorized(ctx, viewer, theme, resolved.Widgets)
+		if grid := overrides.AreaGrids[area]; len(grid) > 0 {
+			if viewer.Editing {
+				filtered = markHiddenWidgets(filtered, overrides.HiddenWidgets)
+			} else {
+				filtered = applyHiddenFilter(filtered, overrides.HiddenWidgets)
+			}
+			layout.Areas[area] = applyGridMetadata(filtered, grid)
+			continue
+		}
 		ordered := applyOrderOverride(filtered, overrides.AreaOrder[area])
 		withLayout := applyRowMetadata(ordered, overrides.AreaRows[area])
+		withLayout = applyBreakpointMetadata(withLayout, overrides.AreaRows[area], overrides.BreakpointOrder[area])
+		if viewer.Editing {
+			layout.Areas[area] = markHiddenWidgets(withLayout, overrides.HiddenWidgets)
+			continue
+		}
 		layout.Areas[area] = applyHiddenFilter(withLayout, overrides.HiddenWidgets)
 	}
 	return layout, cloneLayoutOverrides(overrides), nil
@@ -412,11 +456,16 @@ func (s *Service) ResolveArea(ctx context.Context, viewer ViewerContext, areaCod
 	if err != nil {
 		return ResolvedArea{}, err
 	}
-	resolved.Widgets = s.filterAuthorized(ctx, viewer, theme, resolved.Widgets)
 	overrides, err := s.opts.PreferenceStore.LayoutOverrides(ctx, viewer)
-	if err == nil {
+	viewer.Filters = s.filterValues(viewer.Filters, overrides.Filters)
+	resolved.Widgets = s.filterAuthorized(ctx, viewer, theme, resolved.Widgets)
+	if err == nil && len(overrides.AreaGrids[areaCode]) > 0 {
+		resolved.Widgets = applyHiddenFilter(resolved.Widgets, overrides.HiddenWidgets)
+		resolved.Widgets = applyGridMetadata(resolved.Widgets, overrides.AreaGrids[areaCode])
+	} else if err == nil {
 		ordered := applyOrderOverride(resolved.Widgets, overrides.AreaOrder[areaCode])
 		resolved.Widgets = applyRowMetadata(ordered, overrides.AreaRows[areaCode])
+		resolved.Widgets = applyBreakpointMetadata(resolved.Widgets, overrides.AreaRows[areaCode], overrides.BreakpointOrder[areaCode])
 		resolved.Widgets = applyHiddenFilter(resolved.Widgets, overrides.HiddenWidgets)
 	}
 	s.recordTelemetry(ctx, "dashboard.area.resolve", map[string]any{
@@ -468,7 +517,37 @@ func (s *Service) resolveTheme(ctx context.Context, viewer ViewerContext) *Theme
 		})
 		return nil
 	}
-	return cloneThemeSelection(theme)
+	theme = cloneThemeSelection(theme)
+	s.completeThemeVariants(ctx, theme)
+	theme.applyVariant(viewer.ThemeVariant)
+	theme.FollowSystem = viewer.ThemeVariant == "" && selector.Variant == "" &&
+		theme.hasVariant("light") && theme.hasVariant("dark")
+	return theme
+}
+
+// completeThemeVariants asks the provider for each configured variant the
+// selection does not already list. Variants the provider cannot resolve are
+// skipped.
+func (s *Service) completeThemeVariants(ctx context.Context, theme *ThemeSelection) {
+	if theme == nil || len(s.opts.ThemeVariants) == 0 {
+		return
+	}
+	if theme.Variants == nil {
+		theme.Variants = map[string]ThemeVariant{}
+	}
+	if _, ok := theme.Variants[theme.Variant]; !ok && theme.Variant != "" {
+		theme.Variants[theme.Variant] = ThemeVariant{Tokens: maps.Clone(theme.Tokens), ChartTheme: theme.ChartTheme}
+	}
+	for _, name := range s.opts.ThemeVariants {
+		if _, ok := theme.Variants[name]; ok || name == "" {
+			continue
+		}
+		sibling, err := s.opts.ThemeProvider.SelectTheme(ctx, ThemeSelector{Name: theme.Name, Variant: name})
+		if err != nil || sibling == nil {
+			continue
+		}
+		theme.Variants[name] = ThemeVariant{Tokens: maps.Clone(sibling.Tokens), ChartTheme: sibling.ChartTheme}
+	}
 }
 
 func (s *Service) filterAuthorized(ctx context.Context, viewer ViewerContext, theme *ThemeSelection, widgets []WidgetInstance) []WidgetInstance {
@@ -494,26 +573,22 @@ func (s *Service) attachProviderData(ctx context.Context, viewer ViewerContext,
 		widgetRuntime(code string) (widgetSpecRuntime, bool)
 	}
 	registry, _ := s.opts.Providers.(runtimeRegistry)
+	timeRange := viewer.TimeRange.Resolve(time.Now())
 	for i, inst := range enriched {
-		var options map[string]any
-		if s.opts.ScriptNonce != nil {
-			if nonce := strings.TrimSpace(s.opts.ScriptNonce(ctx)); nonce != "" {
-				options = map[string]any{
-					scriptNonceOptionKey: nonce,
-				}
-			}
-		}
-		meta := WidgetContext{
-			Instance:   inst,
-			Viewer:     viewer,
-			Translator: s.opts.Translation,
-			Options:    options,
-			Theme:      theme,
+		inst, _, err := s.migrateInstance(inst)
+		if err != nil {
+			s.recordTelemetry(ctx, "dashboard.widget.migration_error", map[string]any{
+				"definition_id": inst.DefinitionID,
+				"widget_id":     inst.ID,
+				"error":         err.Error(),
+			})
+			continue
 		}
-		var (
-			view WidgetViewModel
-			err  error
-		)
+		enriched[i] = inst
+		meta := s.widgetContext(ctx, viewer, theme, inst, timeRange)
+		// Templates read widget.config, so they get the same resolved values.
+		enriched[i].Configuration = meta.Instance.Configuration
+		var view WidgetViewModel
 		if registry != nil {
 			if runtime, ok := registry.widgetRuntime(inst.DefinitionID); ok && runtime != nil {
 				var resolved ResolvedWidget
@@ -545,10 +620,60 @@ func (s *Service) attachProviderData(ctx context.Context, viewer ViewerContext,
 			enriched[i].Metadata = map[string]any{}
 		}
 		enriched[i].Metadata[widgetViewModelMetadataKey] = view
+		if def, ok := s.opts.Providers.Definition(inst.DefinitionID); ok {
+			if interactions := interactionsPayload(def); interactions != nil {
+				enriched[i].Metadata[widgetInteractionsMetadataKey] = interactions
+			}
+		}
 	}
 	return enriched
 }
 
+// widgetContext builds the provider context for an instance, applying the
+// script nonce, action path, time range, the widget's declared filters and
+// its localized configuration values.
+func (s *Service) widgetContext(ctx context.Context, viewer ViewerContext, theme *ThemeSelection, inst WidgetInstance, timeRange *TimeRange) WidgetContext {
+	var options map[string]any
+	if s.opts.ScriptNonce != nil {
+		if nonce := strings.TrimSpace(s.opts.ScriptNonce(ctx)); nonce != "" {
+			options = map[string]any{
+				scriptNonceOptionKey: nonce,
+			}
+		}
+	}
+	if s.opts.WidgetActionPath != "" {
+		if options == nil {
+			options = map[string]any{}
+		}
+		options[widgetActionPathOptionKey] = s.opts.WidgetActionPath
+	}
+	meta := WidgetContext{
+		Instance:   inst,
+		Viewer:     viewer,
+		Translator: s.opts.Translation,
+		Formatter:  s.formatter(viewer.Locale),
+		Options:    options,
+		Theme:      theme,
+	}
+	if !widgetIgnoresTimeRange(inst) {
+		meta.TimeRange = cloneTimeRange(timeRange)
+	}
+	if def, ok := s.opts.Providers.Definition(inst.DefinitionID); ok {
+		meta.Filters = widgetFilterValues(def, viewer.Filters)
+		meta.Instance.Configuration = ResolveLocalizedConfig(def.Schema, inst.Configuration, viewer.Locale, viewer.FallbackLocales)
+	}
+	return meta
+}
+
+func (s *Service) formatter(locale string) Formatter {
+	if s.opts.Formatter != nil {
+		if formatter := s.opts.Formatter(locale); formatter != nil {
+			return formatter
+		}
+	}
+	return NewLocaleFormatter(locale)
+}
+
 // NotifyWidgetUpdated exposes refresh hook invocation for commands/transports.
 func (s *Service) NotifyWidgetUpdated(ctx context.Context, event WidgetEvent) error {
 	if err := s.opts.RefreshHook.WidgetUpdated(ctx, event); err != nil {
@@ -585,6 +710,9 @@ func (s *Service) SavePreferences(ctx context.Context, viewer ViewerContext, ove
 	if overrides.Locale == "" {
 		overrides.Locale = viewer.Locale
 	}
+	if err := s.mergeStoredOverrides(ctx, viewer, &overrides); err != nil {
+		return err
+	}
 	s.normalizeOverrides(&overrides)
 	if err := s.opts.PreferenceStore.SaveLayoutOverrides(ctx, viewer, overrides); err != nil {
 		return err
@@ -612,6 +740,73 @@ func (s *Service) SavePreferences(ctx context.Context, viewer ViewerContext, ove
 	return nil
 }
 
+// mergeStoredOverrides keeps the stored value of every field a save leaves
+// nil, so a layout-only save keeps the viewer's filters and a filters-only
+// save keeps their layout. Slots saved without per-breakpoint widths keep the
+// stored widths of the same widget. Send an empty value to clear a field.
+// Stored values are deep-copied: stores may hand out the maps they hold, and
+// normalizeOverrides compacts grids in place.
+func (s *Service) mergeStoredOverrides(ctx context.Context, viewer ViewerContext, overrides *LayoutOverrides) error {
+	stored, err := s.opts.PreferenceStore.LayoutOverrides(ctx, viewer)
+	if err != nil {
+		return err
+	}
+	if overrides.AreaOrder == nil && stored.AreaOrder != nil {
+		overrides.AreaOrder = make(map[string][]string, len(stored.AreaOrder))
+		for area, ids := range stored.AreaOrder {
+			overrides.AreaOrder[area] = slices.Clone(ids)
+		}
+	}
+	if overrides.AreaRows == nil {
+		if stored.AreaRows != nil {
+			overrides.AreaRows = make(map[string][]LayoutRow, len(stored.AreaRows))
+			for area, rows := range stored.AreaRows {
+				overrides.AreaRows[area] = cloneLayoutRows(rows)
+			}
+		}
+	} else {
+		keepStoredWidths(overrides.AreaRows, stored.AreaRows)
+	}
+	if overrides.HiddenWidgets == nil {
+		overrides.HiddenWidgets = maps.Clone(stored.HiddenWidgets)
+	}
+	if overrides.Filters == nil {
+		overrides.Filters = maps.Clone(stored.Filters)
+	}
+	if overrides.BreakpointOrder == nil {
+		overrides.BreakpointOrder = cloneBreakpointOrder(stored.BreakpointOrder)
+	}
+	if overrides.AreaGrids == nil {
+		overrides.AreaGrids = cloneAreaGrids(stored.AreaGrids)
+	}
+	return nil
+}
+
+func keepStoredWidths(rows, stored map[string][]LayoutRow) {
+	widths := map[string]map[Breakpoint]int{}
+	for _, list := range stored {
+		for _, row := range list {
+			for _, slot := range row.Widgets {
+				if len(slot.Widths) > 0 {
+					widths[slot.ID] = slot.Widths
+				}
+			}
+		}
+	}
+	if len(widths) == 0 {
+		return
+	}
+	for _, list := range rows {
+		for _, row := range list {
+			for i, slot := range row.Widgets {
+				if slot.Widths == nil {
+					row.Widgets[i].Widths = maps.Clone(widths[slot.ID])
+				}
+			}
+		}
+	}
+}
+
 func (s *Service) normalizeOverrides(overrides *LayoutOverrides) {
 	if overrides.AreaOrder == nil {
 		overrides.AreaOrder = map[string][]string{}
@@ -619,6 +814,7 @@ func (s *Service) normalizeOverrides(overrides *LayoutOverrides) {
 	if overrides.HiddenWidgets == nil {
 		overrides.HiddenWidgets = map[string]bool{}
 	}
+	compactAreaGrids(overrides.AreaGrids)
 }
 
 type allowAllAuthorizer struct{}
@@ -664,3 +664,79 @@ func TestSavePreferencesStoresOverrides(t *testing.T) {
 		t.Fatalf("expected locale persisted on overrides, got %q", stored.Locale)
 	}
 }
+
+// sharedPreferenceStore hands out the maps it holds, as a cache-backed store
+// might, so tests can check that saves never write to them.
+type sharedPreferenceStore struct {
+	held LayoutOverrides
+}
+
+func (s *sharedPreferenceStore) LayoutOverrides(context.Context, ViewerContext) (LayoutOverrides, error) {
+	return s.held, nil
+}
+
+func (s *sharedPreferenceStore) SaveLayoutOverrides(context.Context, ViewerContext, LayoutOverrides) error {
+	return nil
+}
+
+func TestSavePreferencesDoesNotMutateStoredOverrides(t *testing.T) {
+	store := &sharedPreferenceStore{held: LayoutOverrides{
+		AreaGrids: map[string][]GridPlacement{
+			"admin.dashboard.main": {{ID: "w1", X: 0, Y: 4, W: 6, H: 2}},
+		},
+		AreaRows: map[string][]LayoutRow{
+			"admin.dashboard.main": {{Widgets: []WidgetSlot{{ID: "w1", Width: 6}}}},
+		},
+		HiddenWidgets: map[string]bool{"w2": true},
+	}}
+	service := NewService(Options{PreferenceStore: store})
+	err := service.SavePreferences(context.Background(), ViewerContext{UserID: "user-6"}, LayoutOverrides{
+		Filters: map[string]string{"region": "emea"},
+	})
+	if err != nil {
+		t.Fatalf("SavePreferences returned error: %v", err)
+	}
+	if got := store.held.AreaGrids["admin.dashboard.main"][0].Y; got != 4 {
+		t.Fatalf("expected stored grid to stay uncompacted, got y=%d", got)
+	}
+}
+
+func TestSavePreferencesKeepsFieldsMissingFromTheSave(t *testing.T) {
+	prefs := NewInMemoryPreferenceStore()
+	executor := NewServiceExecutor(NewService(Options{PreferenceStore: prefs}))
+	viewer := ViewerContext{UserID: "user-5"}
+	ctx := context.Background()
+
+	if err := executor.Preferences(ctx, SaveLayoutPreferencesInput{
+		Viewer:          viewer,
+		Filters:         map[string]string{"region": "emea"},
+		BreakpointOrder: map[string]map[Breakpoint][]string{"admin.dashboard.main": {BreakpointSM: {"w2", "w1"}}},
+	}); err != nil {
+		t.Fatalf("filters save returned error: %v", err)
+	}
+	if err := executor.Preferences(ctx, SaveLayoutPreferencesInput{
+		Viewer:        viewer,
+		AreaOrder:     map[string][]string{"admin.dashboard.main": {"w1", "w2"}},
+		HiddenWidgets: []string{"w3"},
+	}); err != nil {
+		t.Fatalf("layout save returned error: %v", err)
+	}
+	stored, err := prefs.LayoutOverrides(ctx, viewer)
+	if err != nil {
+		t.Fatalf("LayoutOverrides returned error: %v", err)
+	}
+	if stored.Filters["region"] != "emea" || len(stored.BreakpointOrder["admin.dashboard.main"][BreakpointSM]) != 2 {
+		t.Fatalf("expected layout-only save to keep filters and breakpoint order, got %+v", stored)
+	}
+	if !stored.HiddenWidgets["w3"] || len(stored.AreaOrder["admin.dashboard.main"]) != 2 {
+		t.Fatalf("expected layout to be saved, got %+v", stored)
+	}
+
+	if err := executor.Preferences(ctx, SaveLayoutPreferencesInput{Viewer: viewer, Filters: map[string]string{"region": ""}}); err != nil {
+		t.Fatalf("filters save returned error: %v", err)
+	}
+	stored, _ = prefs.LayoutOverrides(ctx, viewer)
+	if stored.Filters["region"] != "" || !stored.HiddenWidgets["w3"] {
+		t.Fatalf("expected filters-only save to keep the layout, got %+v", stored)
+	}
+}
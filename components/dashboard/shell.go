@@ -32,6 +32,7 @@ const (
 	ShellActionKindToggleRegion = "toggle-region"
 	ShellActionKindFocus        = "focus"
 	ShellActionKindExitFocus    = "exit-focus"
+	ShellActionKindThemeToggle  = "theme-toggle"
 )
 
 var shellTokenPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
@@ -41,6 +42,7 @@ var shellTokenPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
 type Shell struct {
 	SurfaceID      string             `json:"surface_id"`
 	Label          string             `json:"label,omitempty"`
+	Dir            string             `json:"dir,omitempty"`
 	Storage        ShellStorage       `json:"storage"`
 	Regions        []ShellRegion      `json:"regions"`
 	Actions        []ShellAction      `json:"actions,omitempty"`
@@ -113,6 +115,7 @@ func (shell Shell) Normalize() (Shell, error) {
 		return Shell{}, err
 	}
 	shell.Storage = normalizeShellStorage(shell.Storage)
+	shell.Dir = normalizeTextDirection(shell.Dir)
 
 	regions, seenRegions, focusTargets, err := normalizeShellRegions(shell.Regions)
 	if err != nil {
@@ -338,6 +341,7 @@ func normalizeShellAction(action ShellAction, regions map[string]bool) (ShellAct
 			return ShellAction{}, fmt.Errorf("dashboard shell: focus action %q requires a target id", action.ID)
 		}
 	case ShellActionKindExitFocus:
+	case ShellActionKindThemeToggle:
 	default:
 		return ShellAction{}, fmt.Errorf("dashboard shell: invalid action kind %q", action.Kind)
 	}
@@ -400,6 +404,7 @@ func (shell Shell) legacyPayload() map[string]any {
 	return map[string]any{
 		"surface_id":      normalized.SurfaceID,
 		"label":           normalized.Label,
+		"dir":             normalized.Dir,
 		"storage":         normalized.Storage.legacyPayload(normalized.SurfaceID),
 		"regions":         regions,
 		"region_by_id":    regionByID,
@@ -479,6 +484,7 @@ func shellActionsPayload(actions []ShellAction, regions map[string]ShellRegion)
 			"toggle_region":  action.Kind == ShellActionKindToggleRegion,
 			"focus":          action.Kind == ShellActionKindFocus,
 			"exit_focus":     action.Kind == ShellActionKindExitFocus,
+			"theme_toggle":   action.Kind == ShellActionKindThemeToggle,
 			"button":         action.Kind == ShellActionKindButton,
 			"button_pressed": action.Kind == ShellActionKindButton && action.Pressed,
 		})
@@ -15,7 +15,7 @@ const (
 	envShellAssetsCDN      = "GO_DASHBOARD_SHELL_ASSETS_CDN"
 )
 
-//go:embed assets/shell/shell.css assets/shell/shell.js
+//go:embed assets/shell/shell.css assets/shell/shell.js assets/shell/interactions.js assets/shell/table.js assets/shell/theme.js assets/shell/edit.js assets/shell/edit.css assets/shell/layout.css
 var embeddedShellAssets embed.FS
 
 // ShellAssets returns the embedded shell CSS and JavaScript as an fs.FS.
@@ -61,6 +61,43 @@ func ShellPageAssets(host string) PageAssets {
 	}
 }
 
+// InteractionsScriptURL returns the URL of the widget interaction runtime
+// (drill-down and cross-widget linking) served alongside the shell assets.
+func InteractionsScriptURL(host string) string {
+	if host == "" {
+		host = DefaultShellAssetsHost()
+	}
+	return ensureTrailingSlash(host) + "interactions.js"
+}
+
+// TableScriptURL returns the URL of the table widget runtime (sorting, paging
+// and export links) served alongside the shell assets.
+func TableScriptURL(host string) string {
+	if host == "" {
+		host = DefaultShellAssetsHost()
+	}
+	return ensureTrailingSlash(host) + "table.js"
+}
+
+// ThemeScriptURL returns the URL of the theme variant runtime (dark-mode
+// toggle, prefers-color-scheme and chart re-theming) served alongside the
+// shell assets.
+func ThemeScriptURL(host string) string {
+	if host == "" {
+		host = DefaultShellAssetsHost()
+	}
+	return ensureTrailingSlash(host) + "theme.js"
+}
+
+// LayoutStylesURL returns the URL of the grid styles used by widgets with
+// per-breakpoint widths or order and by free-form grid areas.
+func LayoutStylesURL(host string) string {
+	if host == "" {
+		host = DefaultShellAssetsHost()
+	}
+	return ensureTrailingSlash(host) + "layout.css"
+}
+
 // AddShellAssets adds the shell CSS and JavaScript URLs to the page asset set.
 func (assets *PageAssets) AddShellAssets(host string) {
 	if assets == nil {
@@ -61,8 +61,9 @@ func cloneWidgetInstance(inst WidgetInstance) WidgetInstance {
 
 func cloneLayout(layout Layout) Layout {
 	out := Layout{
-		Areas: make(map[string][]WidgetInstance, len(layout.Areas)),
-		Theme: cloneThemeSelection(layout.Theme),
+		Areas:   make(map[string][]WidgetInstance, len(layout.Areas)),
+		Theme:   cloneThemeSelection(layout.Theme),
+		Filters: clonePageFilters(layout.Filters),
 	}
 	for code, widgets := range layout.Areas {
 		out.Areas[code] = cloneWidgetInstances(widgets)
@@ -84,6 +85,9 @@ func cloneLayoutOverrides(overrides LayoutOverrides) LayoutOverrides {
 		out.AreaRows[area] = cloneLayoutRows(rows)
 	}
 	maps.Copy(out.HiddenWidgets, overrides.HiddenWidgets)
+	out.Filters = cloneFilterValues(overrides.Filters)
+	out.BreakpointOrder = cloneBreakpointOrder(overrides.BreakpointOrder)
+	out.AreaGrids = cloneAreaGrids(overrides.AreaGrids)
 	return out
 }
 
@@ -103,20 +107,28 @@ func cloneWidgetSlots(slots []WidgetSlot) []WidgetSlot {
 		return nil
 	}
 	out := make([]WidgetSlot, len(slots))
-	copy(out, slots)
+	for i, slot := range slots {
+		slot.Widths = maps.Clone(slot.Widths)
+		out[i] = slot
+	}
 	return out
 }
 
 func clonePage(page Page) Page {
 	out := Page{
-		Title:       page.Title,
-		Description: page.Description,
-		Locale:      page.Locale,
-		Areas:       clonePageAreas(page.Areas),
-		Assets:      clonePageAssets(page.Assets),
-		Theme:       cloneThemeSelection(page.Theme),
-		State:       clonePageState(page.State),
-		Meta:        clonePageMeta(page.Meta),
+		Title:          page.Title,
+		Description:    page.Description,
+		Locale:         page.Locale,
+		Dir:            page.Dir,
+		Areas:          clonePageAreas(page.Areas),
+		Assets:         clonePageAssets(page.Assets),
+		Theme:          cloneThemeSelection(page.Theme),
+		TimeRange:      clonePageTimeRange(page.TimeRange),
+		Filters:        clonePageFilters(page.Filters),
+		PreferencesURL: page.PreferencesURL,
+		Edit:           clonePageEdit(page.Edit),
+		State:          clonePageState(page.State),
+		Meta:           clonePageMeta(page.Meta),
 	}
 	return out
 }
@@ -176,6 +188,8 @@ func cloneWidgetLayout(layout *WidgetLayout) *WidgetLayout {
 		return nil
 	}
 	copy := *layout
+	copy.Breakpoints = maps.Clone(layout.Breakpoints)
+	copy.Widths = maps.Clone(layout.Widths)
 	return &copy
 }
 
@@ -183,8 +197,11 @@ func clonePageState(state *PageState) *PageState {
 	if state == nil {
 		return nil
 	}
+	viewer := state.Viewer
+	viewer.TimeRange = cloneTimeRange(viewer.TimeRange)
+	viewer.Filters = cloneFilterValues(viewer.Filters)
 	return &PageState{
-		Viewer:      state.Viewer,
+		Viewer:      viewer,
 		Preferences: cloneLayoutOverrides(state.Preferences),
 	}
 }
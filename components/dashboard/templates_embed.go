@@ -1,8 +1,12 @@
 package dashboard
 
 import (
+	"bytes"
 	"context"
 	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"maps"
@@ -10,7 +14,11 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	template "github.com/goliatone/go-template"
 )
@@ -18,18 +26,15 @@ import (
 //go:embed templates/*.html templates/**/*.html templates/**/**/*.html
 var embeddedTemplates embed.FS
 
-var (
-	embeddedTemplateRootOnce sync.Once
-	embeddedTemplateRootDir  string
-	embeddedTemplateRootErr  error
-)
-
 // TemplateRendererOption customizes the embedded renderer (function map, translation helpers, etc.).
 type TemplateRendererOption func(*templateRendererConfig)
 
 type templateRendererConfig struct {
 	funcs      map[string]any
 	translator TranslationService
+	formatter  func(locale string) Formatter
+	overlays   []fs.FS
+	devMode    bool
 }
 
 // WithTemplateFuncMap merges custom template helper functions into the renderer.
@@ -57,9 +62,51 @@ func WithTranslationHelpers(svc TranslationService) TemplateRendererOption {
 	}
 }
 
+// WithTemplateFormatter sets the locale formatter behind the formatting
+// helpers (formatNumber, formatDate, plural, ...); NewLocaleFormatter is used
+// by default.
+func WithTemplateFormatter(factory func(locale string) Formatter) TemplateRendererOption {
+	return func(cfg *templateRendererConfig) {
+		cfg.formatter = factory
+	}
+}
+
+// WithTemplateOverlay layers host filesystems over the embedded templates.
+// Files in an overlay win over embedded files with the same path; when several
+// overlays are given (or the option is repeated) later ones win. Theme
+// template overrides may point at files that only exist in an overlay.
+func WithTemplateOverlay(fsys ...fs.FS) TemplateRendererOption {
+	return func(cfg *templateRendererConfig) {
+		for _, layer := range fsys {
+			if layer != nil {
+				cfg.overlays = append(cfg.overlays, layer)
+			}
+		}
+	}
+}
+
+// WithTemplateDevMode re-reads the overlay filesystems and theme template
+// sources on every render so template edits on disk (e.g.
+// `os.DirFS("./templates")`) show up without a rebuild or restart. Intended
+// for development only.
+func WithTemplateDevMode(enabled bool) TemplateRendererOption {
+	return func(cfg *templateRendererConfig) {
+		cfg.devMode = enabled
+	}
+}
+
 // NewTemplateRenderer creates a typed dashboard page renderer backed by the
 // embedded templates. The underlying template engine still consumes template
 // data maps, but that adaptation is contained within the renderer boundary.
+//
+// Templates resolve through the active theme first (ThemeSelection.Templates,
+// keyed by template path such as `widgets/recent_activity.html`), then the
+// overlay filesystems, then the embedded defaults. Theme template sources are
+// looked up in ThemeSelection.TemplateFS first.
+//
+// The renderer writes the layered templates to temporary directories. It
+// implements io.Closer: Close removes them, as does garbage collection of the
+// renderer.
 func NewTemplateRenderer(options ...TemplateRendererOption) (Renderer, error) {
 	cfg := templateRendererConfig{}
 	for _, opt := range options {
@@ -71,72 +118,71 @@ func NewTemplateRenderer(options ...TemplateRendererOption) (Renderer, error) {
 	funcMap := map[string]any{
 		"T":        makeTemplateTranslationFunc(cfg.translator),
 		"coalesce": templateCoalesce,
+		"toJSON":   templateToJSON,
 	}
+	maps.Copy(funcMap, templateFormattingFuncs(cfg.formatter))
 	maps.Copy(funcMap, cfg.funcs)
-	baseDir, err := embeddedTemplateBaseDir()
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
 	if err != nil {
 		return nil, err
 	}
-	opts := []template.Option{
-		template.WithBaseDir(baseDir),
-		template.WithExtension(".html"),
-		template.WithTemplateFunc(funcMap),
+	renderer := &templatePageRenderer{
+		funcs:    funcMap,
+		embedded: embedded,
+		devMode:  cfg.devMode,
+		roots:    &templateRoots{byKey: map[templateRootKey]*templateRoot{}},
 	}
-	engine, err := template.NewRenderer(opts...)
-	if err != nil {
-		return nil, err
+	for i := len(cfg.overlays) - 1; i >= 0; i-- {
+		renderer.overlays = append(renderer.overlays, cfg.overlays[i])
 	}
-	return templatePageRenderer{renderer: engine, rootDir: baseDir}, nil
-}
-
-func embeddedTemplateBaseDir() (string, error) {
-	embeddedTemplateRootOnce.Do(func() {
-		dir, err := os.MkdirTemp("", "go-dashboard-templates-")
-		if err != nil {
-			embeddedTemplateRootErr = err
-			return
-		}
-		if err := copyEmbeddedTemplates(dir); err != nil {
-			removeErr := os.RemoveAll(dir)
-			if removeErr != nil {
-				embeddedTemplateRootErr = removeErr
-				return
-			}
-			embeddedTemplateRootErr = err
-			return
-		}
-		embeddedTemplateRootDir = dir
-	})
-	return embeddedTemplateRootDir, embeddedTemplateRootErr
+	runtime.AddCleanup(renderer, func(roots *templateRoots) { _ = roots.removeAll() }, renderer.roots)
+	if _, _, err := renderer.rootFor(nil); err != nil {
+		return nil, errors.Join(err, renderer.Close())
+	}
+	return renderer, nil
 }
 
-func copyEmbeddedTemplates(root string) error {
-	return fs.WalkDir(embeddedTemplates, "templates", func(path string, entry fs.DirEntry, err error) error {
+func copyTemplateFS(fsys fs.FS, base, root string) error {
+	return fs.WalkDir(fsys, base, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		rel, err := filepath.Rel("templates", path)
+		rel, err := filepath.Rel(base, path)
 		if err != nil {
 			return err
 		}
 		if rel == "." {
 			return nil
 		}
-		target := filepath.Join(root, rel)
 		if entry.IsDir() {
-			return os.MkdirAll(target, 0o750)
+			return os.MkdirAll(filepath.Join(root, rel), 0o750)
 		}
-		data, err := fs.ReadFile(embeddedTemplates, path)
+		data, err := fs.ReadFile(fsys, path)
 		if err != nil {
 			return err
 		}
-		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
-			return err
-		}
-		return os.WriteFile(target, data, 0o600)
+		return writeTemplateFile(root, filepath.ToSlash(rel), data)
 	})
 }
 
+// writeTemplateFile writes through a temporary file and a rename, so a render
+// running while dev mode rewrites a template never reads half a file.
+func writeTemplateFile(root, name string, data []byte) error {
+	target := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".template-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
 func makeTemplateTranslationFunc(svc TranslationService) func(string, string, ...any) string {
 	return func(key, locale string, extras ...any) string {
 		fallback, params := translationArgsFromExtras(extras...)
@@ -160,6 +206,14 @@ func translationArgsFromExtras(extras ...any) (string, map[string]any) {
 	return fallback, params
 }
 
+func templateToJSON(value any) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
 func templateCoalesce(values ...any) any {
 	for _, value := range values {
 		if !templateIsEmpty(value) {
@@ -194,22 +248,346 @@ func templateIsEmpty(value any) bool {
 	}
 }
 
+// templatePageRenderer keeps one template root per theme template filesystem
+// and set of theme template overrides. Each root is a directory holding the
+// embedded templates, the overlays on top, and the theme overrides written
+// over their target paths, so includes such as
+// `components/dashboard/area.html` resolve through the theme as well.
+//
+// Roots are built once and reused. In dev mode the overlays and override
+// sources are re-read before every render; only changed files are rewritten
+// and the engine is rebuilt only when something changed.
 type templatePageRenderer struct {
-	renderer LegacyRenderer
-	rootDir  string
+	funcs    map[string]any
+	overlays []fs.FS // highest priority first
+	embedded fs.FS
+	devMode  bool
+	roots    *templateRoots
+}
+
+// templateRoots is shared with the renderer's cleanup, so it must not point
+// back at the renderer. Render methods keep the renderer alive until they
+// finish so the cleanup cannot remove a directory mid-render.
+type templateRoots struct {
+	mu    sync.Mutex
+	byKey map[templateRootKey]*templateRoot
+}
+
+type templateRootKey struct {
+	themeFS   any
+	overrides string
 }
 
-func (renderer templatePageRenderer) RenderPage(name string, page Page, out ...io.Writer) (string, error) {
+type templateRoot struct {
+	dir       string
+	themeFS   fs.FS
+	overrides map[string]string
+
+	mu     sync.Mutex
+	engine LegacyRenderer
+	// files holds the overlay and override content written over the embedded
+	// templates, keyed by template path.
+	files map[string][]byte
+}
+
+// Close removes the template directories written by the renderer. The
+// renderer must not be used afterwards.
+func (renderer *templatePageRenderer) Close() error {
+	return renderer.roots.removeAll()
+}
+
+func (roots *templateRoots) removeAll() error {
+	roots.mu.Lock()
+	defer roots.mu.Unlock()
+	var errs []error
+	for key, root := range roots.byKey {
+		errs = append(errs, os.RemoveAll(root.dir))
+		delete(roots.byKey, key)
+	}
+	return errors.Join(errs...)
+}
+
+func (renderer *templatePageRenderer) RenderPage(name string, page Page, out ...io.Writer) (string, error) {
+	defer runtime.KeepAlive(renderer)
 	payload, err := page.ValidatedLegacyPayload()
 	if err != nil {
 		return "", err
 	}
-	renderer.normalizeWidgetTemplates(payload)
-	return renderer.renderer.Render(name, payload, out...)
+	root, engine, err := renderer.rootFor(page.Theme)
+	if err != nil {
+		return "", err
+	}
+	root.normalizeWidgetTemplates(payload)
+	return engine.Render(name, payload, out...)
+}
+
+// RenderConfigForm renders form through the config form partial.
+func (renderer *templatePageRenderer) RenderConfigForm(form ConfigForm, out ...io.Writer) (string, error) {
+	defer runtime.KeepAlive(renderer)
+	formPayload, _ := normalizeJSONValue(form).(map[string]any)
+	payload := map[string]any{
+		"form":           formPayload,
+		"locale":         form.Locale,
+		"dir":            LocaleDirection(form.Locale),
+		"field_template": configFieldTemplate,
+	}
+	_, engine, err := renderer.rootFor(form.Theme)
+	if err != nil {
+		return "", err
+	}
+	return engine.Render(configFormTemplate, payload, out...)
+}
+
+// RenderCatalog renders the catalog browser page.
+func (renderer *templatePageRenderer) RenderCatalog(page CatalogPage, out ...io.Writer) (string, error) {
+	defer runtime.KeepAlive(renderer)
+	pagePayload, _ := normalizeJSONValue(page).(map[string]any)
+	payload := map[string]any{
+		"catalog": pagePayload,
+		"title":   page.Title,
+		"locale":  page.Locale,
+		"dir":     LocaleDirection(page.Locale),
+		"statuses": []string{
+			CatalogStatusAvailable,
+			CatalogStatusInstalled,
+			CatalogStatusUpdateAvailable,
+			CatalogStatusIncompatible,
+		},
+	}
+	_, engine, err := renderer.rootFor(page.Theme)
+	if err != nil {
+		return "", err
+	}
+	return engine.Render(catalogTemplate, payload, out...)
+}
+
+// rootFor returns the root for the theme's template overrides together with
+// the engine to render it with.
+func (renderer *templatePageRenderer) rootFor(theme *ThemeSelection) (*templateRoot, LegacyRenderer, error) {
+	overrides := themeTemplateOverrides(theme)
+	var themeFS fs.FS
+	if len(overrides) > 0 {
+		themeFS = theme.TemplateFS
+	}
+	key := templateRootKey{themeFS: templateFSIdentity(themeFS), overrides: templateOverridesKey(overrides)}
+	renderer.roots.mu.Lock()
+	root, ok := renderer.roots.byKey[key]
+	if !ok {
+		built, err := renderer.buildRoot(themeFS, overrides)
+		if err != nil {
+			renderer.roots.mu.Unlock()
+			return nil, nil, err
+		}
+		renderer.roots.byKey[key] = built
+		renderer.roots.mu.Unlock()
+		return built, built.engine, nil
+	}
+	renderer.roots.mu.Unlock()
+	if !renderer.devMode {
+		return root, root.engine, nil
+	}
+	engine, err := renderer.syncRoot(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return root, engine, nil
+}
+
+func (renderer *templatePageRenderer) buildRoot(themeFS fs.FS, overrides map[string]string) (*templateRoot, error) {
+	files, err := renderer.layeredFiles(themeFS, overrides)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "go-dashboard-templates-")
+	if err != nil {
+		return nil, err
+	}
+	root := &templateRoot{dir: dir, themeFS: themeFS, overrides: overrides, files: files}
+	if err := copyTemplateFS(renderer.embedded, ".", dir); err != nil {
+		return nil, errors.Join(err, os.RemoveAll(dir))
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if err := writeTemplateFile(dir, name, files[name]); err != nil {
+			return nil, errors.Join(err, os.RemoveAll(dir))
+		}
+	}
+	if root.engine, err = renderer.newEngine(dir); err != nil {
+		return nil, errors.Join(err, os.RemoveAll(dir))
+	}
+	return root, nil
 }
 
-func (renderer templatePageRenderer) normalizeWidgetTemplates(payload map[string]any) {
-	if renderer.rootDir == "" || len(payload) == 0 {
+// syncRoot re-reads the overlays and override sources of root, rewrites the
+// files that changed and rebuilds the engine when any did. Files that left
+// the overlays fall back to the embedded template.
+func (renderer *templatePageRenderer) syncRoot(root *templateRoot) (LegacyRenderer, error) {
+	files, err := renderer.layeredFiles(root.themeFS, root.overrides)
+	if err != nil {
+		return nil, err
+	}
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	changed := false
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if previous, ok := root.files[name]; ok && bytes.Equal(previous, files[name]) {
+			continue
+		}
+		if err := writeTemplateFile(root.dir, name, files[name]); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	for name := range root.files {
+		if _, ok := files[name]; ok {
+			continue
+		}
+		if err := renderer.restoreEmbedded(root.dir, name); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	root.files = files
+	if changed {
+		engine, err := renderer.newEngine(root.dir)
+		if err != nil {
+			return nil, err
+		}
+		root.engine = engine
+	}
+	return root.engine, nil
+}
+
+func (renderer *templatePageRenderer) restoreEmbedded(dir, name string) error {
+	data, err := fs.ReadFile(renderer.embedded, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return writeTemplateFile(dir, name, data)
+}
+
+func (renderer *templatePageRenderer) newEngine(dir string) (LegacyRenderer, error) {
+	engine, err := template.NewRenderer(
+		template.WithBaseDir(dir),
+		template.WithExtension(".html"),
+		template.WithTemplateFunc(renderer.funcs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// layeredFiles collects the overlay files (later overlays win) and the theme
+// overrides, keyed by the template path they are written to.
+func (renderer *templatePageRenderer) layeredFiles(themeFS fs.FS, overrides map[string]string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for i := len(renderer.overlays) - 1; i >= 0; i-- {
+		layer := renderer.overlays[i]
+		err := fs.WalkDir(layer, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(layer, name)
+			if err != nil {
+				return err
+			}
+			files[name] = data
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, target := range slices.Sorted(maps.Keys(overrides)) {
+		data, err := renderer.readLayered(themeFS, overrides[target])
+		if err != nil {
+			// Missing override sources fall through to the overlay/embedded file.
+			continue
+		}
+		files[target] = data
+	}
+	return files, nil
+}
+
+// readLayered reads name from the theme filesystem, then the overlays, then
+// the embedded templates.
+func (renderer *templatePageRenderer) readLayered(themeFS fs.FS, name string) ([]byte, error) {
+	layers := make([]fs.FS, 0, len(renderer.overlays)+2)
+	if themeFS != nil {
+		layers = append(layers, themeFS)
+	}
+	layers = append(layers, renderer.overlays...)
+	layers = append(layers, renderer.embedded)
+	for _, layer := range layers {
+		data, err := fs.ReadFile(layer, name)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// templateFSIdentity returns a comparable identity for a theme filesystem:
+// the value itself when comparable, otherwise its type and pointer (maps such
+// as fstest.MapFS).
+func templateFSIdentity(fsys fs.FS) any {
+	if fsys == nil {
+		return nil
+	}
+	value := reflect.ValueOf(fsys)
+	if value.Comparable() {
+		return fsys
+	}
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Func:
+		return [2]any{value.Type(), value.Pointer()}
+	default:
+		return value.Type()
+	}
+}
+
+// themeTemplateOverrides keeps the path-keyed theme templates (keys and
+// sources must be relative template paths). Definition-keyed entries are
+// resolved by the controller instead.
+func themeTemplateOverrides(theme *ThemeSelection) map[string]string {
+	if theme == nil || len(theme.Templates) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for target, source := range theme.Templates {
+		if path.Ext(target) != ".html" || !isSafeThemeTemplatePath(target) || !isSafeThemeTemplatePath(source) {
+			continue
+		}
+		target, source = path.Clean(target), path.Clean(source)
+		if target != source {
+			out[target] = source
+		}
+	}
+	return out
+}
+
+func templateOverridesKey(overrides map[string]string) string {
+	var builder strings.Builder
+	for _, target := range slices.Sorted(maps.Keys(overrides)) {
+		builder.WriteString(target)
+		builder.WriteByte('=')
+		builder.WriteString(overrides[target])
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+func (root *templateRoot) normalizeWidgetTemplates(payload map[string]any) {
+	if root.dir == "" || len(payload) == 0 {
 		return
 	}
 	areas, ok := payload["areas"].(map[string]any)
@@ -233,7 +611,110 @@ func (renderer templatePageRenderer) normalizeWidgetTemplates(payload map[string
 			if templateName == "" || filepath.IsAbs(templateName) {
 				continue
 			}
-			widget["template"] = filepath.Join(renderer.rootDir, path.Clean(templateName))
+			resolved := filepath.Join(root.dir, path.Clean(templateName))
+			if _, err := os.Stat(resolved); err != nil {
+				// A theme template that is missing everywhere falls back to
+				// the definition's default template.
+				if definition, _ := widget["definition"].(string); definition != "" {
+					resolved = filepath.Join(root.dir, templatePathFor(definition))
+				}
+			}
+			widget["template"] = resolved
+		}
+	}
+}
+
+// templateFormattingFuncs exposes the locale Formatter to templates:
+//
+//	{{ formatNumber(value, locale) }}, {{ formatNumber(value, locale, 2) }}
+//	{{ formatCurrency(value, "EUR", locale) }}
+//	{{ formatPercent(value, locale, 1) }}, {{ formatCompact(value, locale) }}
+//	{{ formatDate(value, locale) }}, {{ formatDateTime(value, locale) }}
+//	{{ formatRelative(seconds, locale) }}
+//	{{ plural(count, "{count, plural, one {# row} other {# rows}}", locale) }}
+//	{{ formatMessage("{done} of {total}", locale, "done", 3, "total", 5) }}
+func templateFormattingFuncs(factory func(locale string) Formatter) map[string]any {
+	formatterFor := func(locale string) Formatter {
+		if factory != nil {
+			if formatter := factory(locale); formatter != nil {
+				return formatter
+			}
 		}
+		return NewLocaleFormatter(locale)
+	}
+	decimalsArg := func(decimals []int, fallback int) int {
+		if len(decimals) > 0 {
+			return decimals[0]
+		}
+		return fallback
+	}
+	return map[string]any{
+		"formatNumber": func(value any, locale string, decimals ...int) string {
+			number, ok := tableNumber(value)
+			if !ok {
+				return fmt.Sprint(value)
+			}
+			return formatterFor(locale).Number(number, decimalsArg(decimals, -1))
+		},
+		"formatCurrency": func(value any, currency, locale string, decimals ...int) string {
+			number, ok := tableNumber(value)
+			if !ok {
+				return fmt.Sprint(value)
+			}
+			return formatterFor(locale).Currency(number, currency, decimalsArg(decimals, -1))
+		},
+		"formatPercent": func(value any, locale string, decimals ...int) string {
+			number, ok := tableNumber(value)
+			if !ok {
+				return fmt.Sprint(value)
+			}
+			return formatterFor(locale).Percent(number, decimalsArg(decimals, -1))
+		},
+		"formatCompact": func(value any, locale string) string {
+			number, ok := tableNumber(value)
+			if !ok {
+				return fmt.Sprint(value)
+			}
+			return formatterFor(locale).Compact(number)
+		},
+		"formatDate": func(value any, locale string) string {
+			ts, ok := tableTime(value)
+			if !ok {
+				return fmt.Sprint(value)
+			}
+			return formatterFor(locale).Date(ts)
+		},
+		"formatDateTime": func(value any, locale string) string {
+			ts, ok := tableTime(value)
+			if !ok {
+				return fmt.Sprint(value)
+			}
+			return formatterFor(locale).DateTime(ts)
+		},
+		"formatRelative": func(value any, locale string) string {
+			switch typed := value.(type) {
+			case time.Duration:
+				return formatterFor(locale).RelativeTime(typed)
+			case time.Time:
+				return formatterFor(locale).RelativeTime(time.Since(typed))
+			}
+			seconds, ok := tableNumber(value)
+			if !ok {
+				return fmt.Sprint(value)
+			}
+			return formatterFor(locale).RelativeTime(time.Duration(seconds * float64(time.Second)))
+		},
+		"plural": func(count any, pattern, locale string) string {
+			return formatterFor(locale).Message(pattern, map[string]any{"count": count})
+		},
+		"formatMessage": func(pattern, locale string, pairs ...any) string {
+			args := map[string]any{}
+			for i := 0; i+1 < len(pairs); i += 2 {
+				if key, ok := pairs[i].(string); ok {
+					args[key] = pairs[i+1]
+				}
+			}
+			return formatterFor(locale).Message(pattern, args)
+		},
 	}
 }
@@ -3,11 +3,17 @@ package dashboard
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io/fs"
 	"maps"
 	"slices"
 	"strings"
 )
 
+// ThemeVariantCookie is the cookie the client-side theme toggle writes so
+// server-rendered pages start on the viewer's chosen variant.
+const ThemeVariantCookie = "dashboard_theme_variant"
+
 // ThemeProvider matches the go-theme provider interface used by adapters. It is
 // optional; when absent the dashboard behaves exactly as before.
 type ThemeProvider interface {
@@ -31,6 +37,23 @@ type ThemeSelection struct {
 	Assets     ThemeAssets
 	Templates  map[string]string
 	ChartTheme string
+	// TemplateFS holds the files Templates entries point at. The renderer
+	// looks sources up here before the overlays and the embedded templates;
+	// FileThemeProvider sets it to the theme filesystem.
+	TemplateFS fs.FS
+	// Variants lists the client-switchable variants of this theme (including
+	// the selected one). When two or more are present the page emits CSS
+	// variables for each so the browser can switch without a reload.
+	Variants map[string]ThemeVariant
+	// FollowSystem lets the browser pick between the light and dark variants
+	// using prefers-color-scheme until the viewer makes an explicit choice.
+	FollowSystem bool
+}
+
+// ThemeVariant carries the tokens and chart theme of one theme variant.
+type ThemeVariant struct {
+	Tokens     map[string]string
+	ChartTheme string
 }
 
 // ThemeAssets provides asset metadata plus optional prefix/resolver.
@@ -93,7 +116,90 @@ func (theme *ThemeSelection) CSSVariables() map[string]string {
 
 // CSSVariablesInline renders the CSS variable map as a style string.
 func (theme *ThemeSelection) CSSVariablesInline() string {
-	vars := theme.CSSVariables()
+	return inlineCSSVariables(theme.CSSVariables())
+}
+
+// Switchable reports whether the page can switch variants client-side.
+func (theme *ThemeSelection) Switchable() bool {
+	return theme != nil && len(theme.Variants) > 1
+}
+
+// VariantStylesheet renders one rule per variant, scoped to the dashboard
+// root's data-theme attribute, plus prefers-color-scheme rules for the light
+// and dark variants when the selection follows the system preference.
+func (theme *ThemeSelection) VariantStylesheet() string {
+	if !theme.Switchable() {
+		return ""
+	}
+	var builder strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(theme.Variants)) {
+		if !isSafeThemeVariantName(name) {
+			continue
+		}
+		if inline := theme.variantCSS(name); inline != "" {
+			fmt.Fprintf(&builder, ".dashboard[data-theme=\"%s\"] { %s }\n", name, inline)
+		}
+	}
+	if theme.FollowSystem {
+		for _, scheme := range []string{"light", "dark"} {
+			if inline := theme.variantCSS(scheme); inline != "" {
+				fmt.Fprintf(&builder, "@media (prefers-color-scheme: %s) { .dashboard[data-theme-mode=\"auto\"] { %s } }\n", scheme, inline)
+			}
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func (theme *ThemeSelection) variantCSS(name string) string {
+	variant, ok := theme.Variants[name]
+	if !ok {
+		return ""
+	}
+	return inlineCSSVariables((&ThemeSelection{Tokens: variant.Tokens}).CSSVariables())
+}
+
+// applyVariant swaps the selected variant for one of the listed alternates.
+func (theme *ThemeSelection) applyVariant(name string) bool {
+	if theme == nil || name == "" || name == theme.Variant {
+		return false
+	}
+	variant, ok := theme.Variants[name]
+	if !ok {
+		return false
+	}
+	theme.Variant = name
+	theme.Tokens = maps.Clone(variant.Tokens)
+	theme.ChartTheme = variant.ChartTheme
+	return true
+}
+
+func (theme *ThemeSelection) hasVariant(name string) bool {
+	if theme == nil {
+		return false
+	}
+	_, ok := theme.Variants[name]
+	return ok
+}
+
+// variantChartThemes maps each variant to its chart theme, deriving defaults
+// from the variant name like the ECharts providers do. Variants without a
+// chart theme map to an empty string.
+func (theme *ThemeSelection) variantChartThemes() map[string]string {
+	if !theme.Switchable() {
+		return nil
+	}
+	out := make(map[string]string, len(theme.Variants))
+	for name, variant := range theme.Variants {
+		out[name] = chartThemeFromSelection(&ThemeSelection{Variant: name, ChartTheme: variant.ChartTheme, Tokens: variant.Tokens})
+	}
+	return out
+}
+
+func isSafeThemeVariantName(name string) bool {
+	return name != "" && isSafeCSSVariableName("--"+name)
+}
+
+func inlineCSSVariables(vars map[string]string) string {
 	if len(vars) == 0 {
 		return ""
 	}
@@ -210,6 +316,12 @@ func cloneThemeSelection(selection *ThemeSelection) *ThemeSelection {
 		cloned.Assets.Values = make(map[string]string, len(selection.Assets.Values))
 		maps.Copy(cloned.Assets.Values, selection.Assets.Values)
 	}
+	if len(selection.Variants) > 0 {
+		cloned.Variants = make(map[string]ThemeVariant, len(selection.Variants))
+		for name, variant := range selection.Variants {
+			cloned.Variants[name] = ThemeVariant{Tokens: maps.Clone(variant.Tokens), ChartTheme: variant.ChartTheme}
+		}
+	}
 	return &cloned
 }
 
@@ -245,5 +357,25 @@ func themePayload(selection *ThemeSelection) map[string]any {
 	if selection.ChartTheme != "" {
 		payload["chart_theme"] = selection.ChartTheme
 	}
+	if selection.Switchable() {
+		charts := selection.variantChartThemes()
+		variants := make(map[string]any, len(selection.Variants))
+		for name := range selection.Variants {
+			if !isSafeThemeVariantName(name) {
+				continue
+			}
+			variants[name] = map[string]any{
+				"css_vars_inline": selection.variantCSS(name),
+				"chart_theme":     charts[name],
+			}
+		}
+		payload["variants"] = variants
+		payload["chart_themes"] = charts
+		payload["variant_css"] = selection.VariantStylesheet()
+		payload["mode"] = "manual"
+		if selection.FollowSystem {
+			payload["mode"] = "auto"
+		}
+	}
 	return payload
 }
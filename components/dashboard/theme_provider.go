@@ -0,0 +1,398 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultThemeReloadInterval = time.Second
+
+// ThemeDocument models a theme file (YAML or JSON). Each variant carries its own
+// tokens, assets, template overrides and chart theme; a variant can extend
+// another variant of the same theme and override only what differs.
+type ThemeDocument struct {
+	Name           string                          `json:"name" yaml:"name"`
+	Description    string                          `json:"description,omitempty" yaml:"description,omitempty"`
+	DefaultVariant string                          `json:"default_variant,omitempty" yaml:"default_variant,omitempty"`
+	Variants       map[string]ThemeVariantDocument `json:"variants" yaml:"variants"`
+	Source         string                          `json:"-" yaml:"-"`
+}
+
+// ThemeVariantDocument describes a single theme variant.
+type ThemeVariantDocument struct {
+	Extends     string            `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Tokens      map[string]string `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+	Assets      map[string]string `json:"assets,omitempty" yaml:"assets,omitempty"`
+	AssetPrefix string            `json:"asset_prefix,omitempty" yaml:"asset_prefix,omitempty"`
+	Templates   map[string]string `json:"templates,omitempty" yaml:"templates,omitempty"`
+	ChartTheme  string            `json:"chart_theme,omitempty" yaml:"chart_theme,omitempty"`
+}
+
+// DecodeThemeDocument reads a theme document from any reader and validates it.
+func DecodeThemeDocument(r io.Reader) (*ThemeDocument, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	var doc ThemeDocument
+	if err := decoder.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("dashboard: theme document is empty")
+		}
+		return nil, fmt.Errorf("dashboard: parse theme: %w", err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Validate checks required fields, variant inheritance and token safety. Token
+// names must be valid CSS custom properties and values must survive
+// sanitization; unsafe entries fail the load instead of being dropped silently.
+func (doc *ThemeDocument) Validate() error {
+	if strings.TrimSpace(doc.Name) == "" {
+		return fmt.Errorf("dashboard: theme name is required")
+	}
+	if len(doc.Variants) == 0 {
+		return fmt.Errorf("dashboard: theme %s declares no variants", doc.Name)
+	}
+	if doc.DefaultVariant != "" {
+		if _, ok := doc.Variants[doc.DefaultVariant]; !ok {
+			return fmt.Errorf("dashboard: theme %s default variant %q not found", doc.Name, doc.DefaultVariant)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(doc.Variants)) {
+		variant := doc.Variants[name]
+		if _, err := doc.resolveVariant(name); err != nil {
+			return err
+		}
+		for _, key := range slices.Sorted(maps.Keys(variant.Tokens)) {
+			if normalizeCSSVariable(key) == "" {
+				return fmt.Errorf("dashboard: theme %s/%s token %q is not a valid CSS variable name", doc.Name, name, key)
+			}
+			if sanitizeCSSVariableValue(variant.Tokens[key]) == "" {
+				return fmt.Errorf("dashboard: theme %s/%s token %q has an unsafe or empty value", doc.Name, name, key)
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(variant.Templates)) {
+			if !isSafeThemeTemplatePath(variant.Templates[key]) {
+				return fmt.Errorf("dashboard: theme %s/%s template %q must be a relative path", doc.Name, name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveVariant flattens the extends chain of a variant; later (child)
+// values win.
+func (doc *ThemeDocument) resolveVariant(name string) (ThemeVariantDocument, error) {
+	chain := []string{}
+	for current := name; current != ""; {
+		if slices.Contains(chain, current) {
+			return ThemeVariantDocument{}, fmt.Errorf("dashboard: theme %s variant %s has an extends cycle (%s)", doc.Name, name, strings.Join(append(chain, current), " -> "))
+		}
+		variant, ok := doc.Variants[current]
+		if !ok {
+			return ThemeVariantDocument{}, fmt.Errorf("dashboard: theme %s variant %s extends unknown variant %q", doc.Name, chain[len(chain)-1], current)
+		}
+		chain = append(chain, current)
+		current = variant.Extends
+	}
+	resolved := ThemeVariantDocument{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		variant := doc.Variants[chain[i]]
+		resolved.Tokens = mergeStringMaps(resolved.Tokens, variant.Tokens)
+		resolved.Assets = mergeStringMaps(resolved.Assets, variant.Assets)
+		resolved.Templates = mergeStringMaps(resolved.Templates, variant.Templates)
+		if variant.AssetPrefix != "" {
+			resolved.AssetPrefix = variant.AssetPrefix
+		}
+		if variant.ChartTheme != "" {
+			resolved.ChartTheme = variant.ChartTheme
+		}
+	}
+	return resolved, nil
+}
+
+// Selection builds the ThemeSelection for a variant, falling back to the
+// default variant (or `light`, or the first variant) when empty. Every
+// variant of the document is listed in Variants for client-side switching.
+func (doc *ThemeDocument) Selection(variant string) (*ThemeSelection, error) {
+	if variant == "" {
+		variant = doc.defaultVariant()
+	}
+	if _, ok := doc.Variants[variant]; !ok {
+		return nil, fmt.Errorf("dashboard: theme %s has no variant %q", doc.Name, variant)
+	}
+	resolved, err := doc.resolveVariant(variant)
+	if err != nil {
+		return nil, err
+	}
+	variants := make(map[string]ThemeVariant, len(doc.Variants))
+	for name := range doc.Variants {
+		sibling, err := doc.resolveVariant(name)
+		if err != nil {
+			return nil, err
+		}
+		variants[name] = ThemeVariant{Tokens: sibling.Tokens, ChartTheme: sibling.ChartTheme}
+	}
+	return &ThemeSelection{
+		Name:       doc.Name,
+		Variant:    variant,
+		Tokens:     resolved.Tokens,
+		Assets:     ThemeAssets{Values: resolved.Assets, Prefix: resolved.AssetPrefix},
+		Templates:  resolved.Templates,
+		ChartTheme: resolved.ChartTheme,
+		Variants:   variants,
+	}, nil
+}
+
+func (doc *ThemeDocument) defaultVariant() string {
+	if doc.DefaultVariant != "" {
+		return doc.DefaultVariant
+	}
+	if _, ok := doc.Variants["light"]; ok {
+		return "light"
+	}
+	return slices.Sorted(maps.Keys(doc.Variants))[0]
+}
+
+func isSafeThemeTemplatePath(p string) bool {
+	p = strings.TrimSpace(p)
+	if p == "" || strings.HasPrefix(p, "/") || strings.Contains(p, "\\") {
+		return false
+	}
+	return fs.ValidPath(path.Clean(p)) && !strings.HasPrefix(path.Clean(p), "..")
+}
+
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	out := make(map[string]string, len(base)+len(override))
+	maps.Copy(out, base)
+	maps.Copy(out, override)
+	return out
+}
+
+// FileThemeProvider implements ThemeProvider from theme documents stored in a
+// directory or embedded FS. Every `*.yaml`, `*.yml` and `*.json` file at the
+// root of the FS is one theme.
+type FileThemeProvider struct {
+	fsys          fs.FS
+	defaultTheme  string
+	assetResolver func(string) string
+	reload        bool
+	interval      time.Duration
+	onReloadError func(error)
+
+	mu        sync.RWMutex
+	themes    map[string]*ThemeDocument
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+// FileThemeOption customizes a FileThemeProvider.
+type FileThemeOption func(*FileThemeProvider)
+
+// WithDefaultTheme selects the theme used when the selector leaves Name empty.
+func WithDefaultTheme(name string) FileThemeOption {
+	return func(p *FileThemeProvider) {
+		p.defaultTheme = name
+	}
+}
+
+// WithThemeAssetResolver sets the resolver applied to theme asset paths.
+func WithThemeAssetResolver(resolver func(string) string) FileThemeOption {
+	return func(p *FileThemeProvider) {
+		p.assetResolver = resolver
+	}
+}
+
+// WithThemeReload re-reads theme files whose modification time changed, at
+// most once per interval (one second when zero). Intended for development.
+func WithThemeReload(interval time.Duration) FileThemeOption {
+	return func(p *FileThemeProvider) {
+		p.reload = true
+		p.interval = interval
+		if p.interval <= 0 {
+			p.interval = defaultThemeReloadInterval
+		}
+	}
+}
+
+// WithThemeReloadErrorHandler receives the errors of failed reloads. The
+// provider keeps serving the last themes that loaded.
+func WithThemeReloadErrorHandler(handler func(error)) FileThemeOption {
+	return func(p *FileThemeProvider) {
+		p.onReloadError = handler
+	}
+}
+
+var _ ThemeProvider = (*FileThemeProvider)(nil)
+
+// NewFileThemeProvider loads and validates every theme in fsys.
+func NewFileThemeProvider(fsys fs.FS, opts ...FileThemeOption) (*FileThemeProvider, error) {
+	if fsys == nil {
+		return nil, fmt.Errorf("dashboard: theme filesystem is required")
+	}
+	provider := &FileThemeProvider{fsys: fsys}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(provider)
+		}
+	}
+	themes, modTimes, err := loadThemeDocuments(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if provider.defaultTheme != "" {
+		if _, ok := themes[provider.defaultTheme]; !ok {
+			return nil, fmt.Errorf("dashboard: default theme %q not found", provider.defaultTheme)
+		}
+	}
+	provider.themes, provider.modTimes, provider.checkedAt = themes, modTimes, time.Now()
+	return provider, nil
+}
+
+// NewDirThemeProvider loads themes from a directory on disk.
+func NewDirThemeProvider(dir string, opts ...FileThemeOption) (*FileThemeProvider, error) {
+	return NewFileThemeProvider(os.DirFS(dir), opts...)
+}
+
+// Themes lists the loaded theme names.
+func (p *FileThemeProvider) Themes() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Sorted(maps.Keys(p.themes))
+}
+
+// SelectTheme implements ThemeProvider.
+func (p *FileThemeProvider) SelectTheme(_ context.Context, selector ThemeSelector) (*ThemeSelection, error) {
+	if p.reload {
+		p.reloadIfChanged()
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	name := selector.Name
+	if name == "" {
+		name = p.defaultTheme
+	}
+	if name == "" && len(p.themes) > 0 {
+		name = slices.Sorted(maps.Keys(p.themes))[0]
+	}
+	doc, ok := p.themes[name]
+	if !ok {
+		return nil, fmt.Errorf("dashboard: theme %q not found", name)
+	}
+	selection, err := doc.Selection(selector.Variant)
+	if err != nil {
+		return nil, err
+	}
+	selection.Assets.Resolver = p.assetResolver
+	selection.TemplateFS = p.fsys
+	return selection, nil
+}
+
+// reloadIfChanged swaps in freshly loaded themes when any file was added,
+// removed or modified. A broken edit keeps serving the previous themes and is
+// reported once; the next change to the files triggers another attempt.
+func (p *FileThemeProvider) reloadIfChanged() {
+	p.mu.RLock()
+	due := time.Since(p.checkedAt) >= p.interval
+	p.mu.RUnlock()
+	if !due {
+		return
+	}
+	current, err := themeFileModTimes(p.fsys)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checkedAt = time.Now()
+	if err != nil {
+		p.reportReloadError(err)
+		return
+	}
+	if maps.Equal(current, p.modTimes) {
+		return
+	}
+	themes, modTimes, err := loadThemeDocuments(p.fsys)
+	if err != nil {
+		p.modTimes = current
+		p.reportReloadError(err)
+		return
+	}
+	p.themes, p.modTimes = themes, modTimes
+}
+
+func (p *FileThemeProvider) reportReloadError(err error) {
+	if p.onReloadError != nil {
+		p.onReloadError(err)
+	}
+}
+
+func themeFileModTimes(fsys fs.FS) (map[string]time.Time, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: read themes: %w", err)
+	}
+	modTimes := map[string]time.Time{}
+	for _, entry := range entries {
+		if entry.IsDir() || !isThemeFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("dashboard: stat theme %s: %w", entry.Name(), err)
+		}
+		modTimes[entry.Name()] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func loadThemeDocuments(fsys fs.FS) (map[string]*ThemeDocument, map[string]time.Time, error) {
+	modTimes, err := themeFileModTimes(fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+	themes := make(map[string]*ThemeDocument, len(modTimes))
+	for _, name := range slices.Sorted(maps.Keys(modTimes)) {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("dashboard: open theme %s: %w", name, err)
+		}
+		doc, err := DecodeThemeDocument(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("dashboard: load theme %s: %w", name, err)
+		}
+		if previous, exists := themes[doc.Name]; exists {
+			return nil, nil, fmt.Errorf("dashboard: theme %s declared in both %s and %s", doc.Name, previous.Source, name)
+		}
+		doc.Source = name
+		themes[doc.Name] = doc
+	}
+	if len(themes) == 0 {
+		return nil, nil, fmt.Errorf("dashboard: no theme files found")
+	}
+	return themes, modTimes, nil
+}
+
+func isThemeFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
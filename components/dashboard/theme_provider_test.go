@@ -0,0 +1,127 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+const testThemeYAML = `
+name: acme
+default_variant: light
+variants:
+  light:
+    tokens:
+      color-bg: "#ffffff"
+      --color-text: "#111111"
+    assets:
+      logo: logo.svg
+    asset_prefix: /static/themes/acme
+    templates:
+      widgets/kpi.html: acme/kpi.html
+    chart_theme: acme-light
+  dark:
+    extends: light
+    tokens:
+      color-bg: "#0b0b0b"
+    chart_theme: acme-dark
+`
+
+func TestFileThemeProviderResolvesVariantInheritance(t *testing.T) {
+	provider, err := NewFileThemeProvider(fstest.MapFS{
+		"acme.yaml":  {Data: []byte(testThemeYAML)},
+		"plain.json": {Data: []byte(`{"name":"plain","variants":{"dark":{"tokens":{"color-bg":"#000"}}}}`)},
+		"README.md":  {Data: []byte("ignored")},
+	}, WithDefaultTheme("acme"))
+	if err != nil {
+		t.Fatalf("NewFileThemeProvider returned error: %v", err)
+	}
+	if got := strings.Join(provider.Themes(), ","); got != "acme,plain" {
+		t.Fatalf("unexpected themes %s", got)
+	}
+	selection, err := provider.SelectTheme(context.Background(), ThemeSelector{Variant: "dark"})
+	if err != nil {
+		t.Fatalf("SelectTheme returned error: %v", err)
+	}
+	if selection.Name != "acme" || selection.Variant != "dark" || selection.ChartTheme != "acme-dark" {
+		t.Fatalf("unexpected selection %+v", selection)
+	}
+	if selection.Tokens["color-bg"] != "#0b0b0b" || selection.Tokens["--color-text"] != "#111111" {
+		t.Fatalf("expected dark tokens layered over light, got %+v", selection.Tokens)
+	}
+	if selection.Assets.Prefix != "/static/themes/acme" || selection.Templates["widgets/kpi.html"] != "acme/kpi.html" {
+		t.Fatalf("expected inherited assets/templates, got %+v %+v", selection.Assets, selection.Templates)
+	}
+
+	plain, err := provider.SelectTheme(context.Background(), ThemeSelector{Name: "plain"})
+	if err != nil || plain.Variant != "dark" {
+		t.Fatalf("expected sole variant to be the default, got %+v err=%v", plain, err)
+	}
+	if _, err := provider.SelectTheme(context.Background(), ThemeSelector{Name: "missing"}); err == nil {
+		t.Fatalf("expected unknown theme error")
+	}
+}
+
+func TestFileThemeProviderRejectsInvalidThemes(t *testing.T) {
+	cases := map[string]string{
+		"unsafe token": `{"name":"x","variants":{"light":{"tokens":{"color-bg":"url(javascript:alert(1))"}}}}`,
+		"bad name":     `{"name":"x","variants":{"light":{"tokens":{"color bg;":"#fff"}}}}`,
+		"cycle":        `{"name":"x","variants":{"a":{"extends":"b"},"b":{"extends":"a"}}}`,
+		"unknown":      `{"name":"x","variants":{"dark":{"extends":"light"}}}`,
+		"template":     `{"name":"x","variants":{"light":{"templates":{"widgets/kpi.html":"../secret.html"}}}}`,
+		"field":        `{"name":"x","colors":{},"variants":{"light":{}}}`,
+	}
+	for label, doc := range cases {
+		_, err := NewFileThemeProvider(fstest.MapFS{"x.json": {Data: []byte(doc)}})
+		if err == nil || !strings.Contains(err.Error(), "x.json") {
+			t.Fatalf("%s: expected load error naming the file, got %v", label, err)
+		}
+	}
+}
+
+func TestFileThemeProviderReloadsChangedFiles(t *testing.T) {
+	fsys := fstest.MapFS{"acme.yaml": {Data: []byte(testThemeYAML), ModTime: time.Unix(1, 0)}}
+	provider, err := NewFileThemeProvider(fsys, WithThemeReload(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("NewFileThemeProvider returned error: %v", err)
+	}
+	fsys["acme.yaml"] = &fstest.MapFile{
+		Data:    []byte(strings.Replace(testThemeYAML, `"#ffffff"`, `"#fafafa"`, 1)),
+		ModTime: time.Unix(2, 0),
+	}
+	time.Sleep(time.Millisecond)
+	selection, err := provider.SelectTheme(context.Background(), ThemeSelector{})
+	if err != nil {
+		t.Fatalf("SelectTheme returned error: %v", err)
+	}
+	if selection.Tokens["color-bg"] != "#fafafa" {
+		t.Fatalf("expected reloaded token, got %+v", selection.Tokens)
+	}
+}
+
+func TestFileThemeProviderKeepsThemesWhenReloadFails(t *testing.T) {
+	fsys := fstest.MapFS{"acme.yaml": {Data: []byte(testThemeYAML), ModTime: time.Unix(1, 0)}}
+	var reported []error
+	provider, err := NewFileThemeProvider(fsys, WithThemeReload(time.Nanosecond), WithThemeReloadErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+	if err != nil {
+		t.Fatalf("NewFileThemeProvider returned error: %v", err)
+	}
+	fsys["acme.yaml"] = &fstest.MapFile{Data: []byte("name: acme\nvariants: [broken"), ModTime: time.Unix(2, 0)}
+	for range 2 {
+		time.Sleep(time.Millisecond)
+		selection, err := provider.SelectTheme(context.Background(), ThemeSelector{})
+		if err != nil {
+			t.Fatalf("SelectTheme returned error: %v", err)
+		}
+		if selection.Tokens["color-bg"] != "#ffffff" {
+			t.Fatalf("expected previous theme to be served, got %+v", selection.Tokens)
+		}
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "acme.yaml") {
+		t.Fatalf("expected the broken edit reported once, got %v", reported)
+	}
+}
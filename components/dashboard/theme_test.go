@@ -1,6 +1,8 @@
 package dashboard
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -42,3 +44,64 @@ func TestThemeSelectionCSSVariablesSanitizeUnsafeTokens(t *testing.T) {
 		t.Fatalf("expected inline css sanitized, got %q", inline)
 	}
 }
+
+type variantThemeProvider struct {
+	calls []ThemeSelector
+}
+
+func (p *variantThemeProvider) SelectTheme(_ context.Context, selector ThemeSelector) (*ThemeSelection, error) {
+	p.calls = append(p.calls, selector)
+	switch selector.Variant {
+	case "", "light":
+		return &ThemeSelection{Name: "admin", Variant: "light", Tokens: map[string]string{"dashboard-surface": "#ffffff"}}, nil
+	case "dark":
+		return &ThemeSelection{Name: "admin", Variant: "dark", Tokens: map[string]string{"dashboard-surface": "#0f172a"}, ChartTheme: "chalk"}, nil
+	default:
+		return nil, fmt.Errorf("unknown variant %s", selector.Variant)
+	}
+}
+
+func TestResolveThemeCollectsVariantsAndFollowsSystem(t *testing.T) {
+	provider := &variantThemeProvider{}
+	svc := NewService(Options{ThemeProvider: provider, ThemeVariants: []string{"light", "dark", "sepia"}})
+
+	theme := svc.resolveTheme(context.Background(), ViewerContext{})
+	if theme == nil || !theme.Switchable() || len(theme.Variants) != 2 {
+		t.Fatalf("expected light and dark variants, got %+v", theme)
+	}
+	if !theme.FollowSystem || theme.Variant != "light" {
+		t.Fatalf("expected selection without explicit variant to follow the system, got %+v", theme)
+	}
+	css := theme.VariantStylesheet()
+	for _, want := range []string{
+		`.dashboard[data-theme="dark"] { --dashboard-surface: #0f172a; }`,
+		`.dashboard[data-theme="light"] { --dashboard-surface: #ffffff; }`,
+		`@media (prefers-color-scheme: dark) { .dashboard[data-theme-mode="auto"] { --dashboard-surface: #0f172a; } }`,
+	} {
+		if !strings.Contains(css, want) {
+			t.Fatalf("expected stylesheet to contain %q, got:\n%s", want, css)
+		}
+	}
+	payload := themePayload(theme)
+	if payload["mode"] != "auto" {
+		t.Fatalf("expected auto mode payload, got %v", payload["mode"])
+	}
+	if charts, _ := payload["chart_themes"].(map[string]string); charts["dark"] != "chalk" || !strings.HasPrefix(charts["light"], GeneratedChartThemePrefix) {
+		t.Fatalf("unexpected chart themes %+v", payload["chart_themes"])
+	}
+
+	chosen := svc.resolveTheme(context.Background(), ViewerContext{ThemeVariant: "dark"})
+	if chosen.Variant != "dark" || chosen.Tokens["dashboard-surface"] != "#0f172a" || chosen.ChartTheme != "chalk" || chosen.FollowSystem {
+		t.Fatalf("expected viewer choice to select the dark variant, got %+v", chosen)
+	}
+}
+
+func TestThemeVariantStylesheetSkipsUnsafeVariantNames(t *testing.T) {
+	theme := &ThemeSelection{Variants: map[string]ThemeVariant{
+		"light":            {Tokens: map[string]string{"bg": "#fff"}},
+		`x"] { } body { x`: {Tokens: map[string]string{"bg": "#000"}},
+	}}
+	if css := theme.VariantStylesheet(); strings.Contains(css, "body") || !strings.Contains(css, `[data-theme="light"]`) {
+		t.Fatalf("expected unsafe variant name skipped, got %q", css)
+	}
+}
@@ -0,0 +1,218 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	TimeRangePreset24h    = "24h"
+	TimeRangePreset7d     = "7d"
+	TimeRangePreset30d    = "30d"
+	TimeRangePreset90d    = "90d"
+	TimeRangePresetCustom = "custom"
+
+	// widgetTimeRangeOptOutKey is the instance metadata flag that keeps a widget
+	// on its own configured period instead of the dashboard-wide range.
+	widgetTimeRangeOptOutKey = "ignore_time_range"
+)
+
+// DefaultTimeRangePresets lists the relative ranges offered by the dashboard
+// time picker in display order.
+var DefaultTimeRangePresets = []string{
+	TimeRangePreset24h,
+	TimeRangePreset7d,
+	TimeRangePreset30d,
+	TimeRangePreset90d,
+}
+
+// TimeRange describes a dashboard-wide time window. Relative presets are
+// resolved against the current time; custom ranges carry explicit bounds.
+type TimeRange struct {
+	Preset string    `json:"preset,omitempty"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// ParseTimeRange builds a TimeRange from transport values (query string,
+// preferences). Empty input returns nil so callers keep per-widget defaults.
+func ParseTimeRange(preset, from, to string) (*TimeRange, error) {
+	preset = strings.ToLower(strings.TrimSpace(preset))
+	from = strings.TrimSpace(from)
+	to = strings.TrimSpace(to)
+	if preset == "" && from == "" && to == "" {
+		return nil, nil
+	}
+	if preset == "" || preset == TimeRangePresetCustom {
+		start, err := parseTimeRangeBound(from)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard: invalid time range start: %w", err)
+		}
+		end, err := parseTimeRangeBound(to)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard: invalid time range end: %w", err)
+		}
+		if isDateOnlyBound(to) {
+			// A date-only end covers that whole day.
+			end = end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		if start.IsZero() || end.IsZero() {
+			return nil, fmt.Errorf("dashboard: custom time range requires from and to")
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("dashboard: time range end precedes start")
+		}
+		return &TimeRange{Preset: TimeRangePresetCustom, From: start, To: end}, nil
+	}
+	if _, err := timeRangePresetDuration(preset); err != nil {
+		return nil, err
+	}
+	return &TimeRange{Preset: preset}, nil
+}
+
+// Resolve returns a copy with absolute bounds computed for relative presets.
+// Ranges that already carry bounds keep them, so a request resolves its range
+// once and the page and every widget share the same window.
+func (r *TimeRange) Resolve(now time.Time) *TimeRange {
+	if r == nil {
+		return nil
+	}
+	resolved := *r
+	if resolved.Preset == "" || resolved.Preset == TimeRangePresetCustom || (!resolved.From.IsZero() && !resolved.To.IsZero()) {
+		if resolved.Preset == "" {
+			resolved.Preset = TimeRangePresetCustom
+		}
+		return &resolved
+	}
+	duration, err := timeRangePresetDuration(resolved.Preset)
+	if err != nil {
+		return nil
+	}
+	resolved.To = now.UTC()
+	resolved.From = resolved.To.Add(-duration)
+	return &resolved
+}
+
+// Duration returns the span covered by the resolved range.
+func (r *TimeRange) Duration() time.Duration {
+	if r == nil || r.From.IsZero() || r.To.IsZero() {
+		return 0
+	}
+	return r.To.Sub(r.From)
+}
+
+// Days returns the number of whole days covered, rounding partial days up.
+func (r *TimeRange) Days() int {
+	duration := r.Duration()
+	if duration <= 0 {
+		return 0
+	}
+	day := 24 * time.Hour
+	days := int(duration / day)
+	if duration%day != 0 {
+		days++
+	}
+	return days
+}
+
+// Label returns the preset code or a compact day-count label for custom
+// ranges, matching the period strings used by widget configurations.
+func (r *TimeRange) Label() string {
+	if r == nil {
+		return ""
+	}
+	if r.Preset != "" && r.Preset != TimeRangePresetCustom {
+		return r.Preset
+	}
+	if days := r.Days(); days > 0 {
+		return fmt.Sprintf("%dd", days)
+	}
+	return TimeRangePresetCustom
+}
+
+// PageTimeRange carries the time picker state rendered with a dashboard page.
+type PageTimeRange struct {
+	Presets  []string   `json:"presets,omitempty"`
+	Selected *TimeRange `json:"selected,omitempty"`
+}
+
+func timeRangePresetDuration(preset string) (time.Duration, error) {
+	switch preset {
+	case TimeRangePreset24h:
+		return 24 * time.Hour, nil
+	case TimeRangePreset7d:
+		return 7 * 24 * time.Hour, nil
+	case TimeRangePreset30d:
+		return 30 * 24 * time.Hour, nil
+	case TimeRangePreset90d:
+		return 90 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("dashboard: unsupported time range preset %q", preset)
+	}
+}
+
+func parseTimeRangeBound(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.UTC(), nil
+}
+
+func isDateOnlyBound(value string) bool {
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+func widgetIgnoresTimeRange(instance WidgetInstance) bool {
+	if len(instance.Metadata) == 0 {
+		return false
+	}
+	return boolValue(instance.Metadata[widgetTimeRangeOptOutKey])
+}
+
+func cloneTimeRange(r *TimeRange) *TimeRange {
+	if r == nil {
+		return nil
+	}
+	cloned := *r
+	return &cloned
+}
+
+func clonePageTimeRange(picker *PageTimeRange) *PageTimeRange {
+	if picker == nil {
+		return nil
+	}
+	return &PageTimeRange{
+		Presets:  append([]string{}, picker.Presets...),
+		Selected: cloneTimeRange(picker.Selected),
+	}
+}
+
+func timeRangePayload(r *TimeRange, presets []string) map[string]any {
+	if r == nil && len(presets) == 0 {
+		return nil
+	}
+	payload := map[string]any{
+		"presets": append([]string{}, presets...),
+	}
+	if r == nil {
+		return payload
+	}
+	payload["preset"] = r.Preset
+	payload["label"] = r.Label()
+	if !r.From.IsZero() {
+		payload["from"] = r.From.Format(time.RFC3339)
+	}
+	if !r.To.IsZero() {
+		payload["to"] = r.To.Format(time.RFC3339)
+	}
+	return payload
+}
@@ -0,0 +1,159 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseTimeRangePresets(t *testing.T) {
+	r, err := ParseTimeRange(" 7D ", "", "")
+	if err != nil {
+		t.Fatalf("ParseTimeRange returned error: %v", err)
+	}
+	if r == nil || r.Preset != TimeRangePreset7d {
+		t.Fatalf("expected 7d preset, got %+v", r)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	resolved := r.Resolve(now)
+	if !resolved.To.Equal(now) || !resolved.From.Equal(now.Add(-7*24*time.Hour)) {
+		t.Fatalf("unexpected resolved bounds %+v", resolved)
+	}
+	if resolved.Days() != 7 || resolved.Label() != "7d" {
+		t.Fatalf("expected 7 days labelled 7d, got %d %q", resolved.Days(), resolved.Label())
+	}
+	if !r.From.IsZero() {
+		t.Fatalf("expected Resolve to leave the receiver untouched")
+	}
+	if again := resolved.Resolve(now.Add(time.Minute)); !again.To.Equal(resolved.To) || !again.From.Equal(resolved.From) {
+		t.Fatalf("expected resolved range to keep its bounds, got %+v", again)
+	}
+
+	if r, err := ParseTimeRange("", "", ""); err != nil || r != nil {
+		t.Fatalf("expected nil range for empty input, got %+v %v", r, err)
+	}
+	if _, err := ParseTimeRange("1y", "", ""); err == nil {
+		t.Fatalf("expected unsupported preset error")
+	}
+}
+
+func TestParseTimeRangeCustom(t *testing.T) {
+	r, err := ParseTimeRange("", "2026-01-01", "2026-01-15T12:00:00Z")
+	if err != nil {
+		t.Fatalf("ParseTimeRange returned error: %v", err)
+	}
+	if r.Preset != TimeRangePresetCustom {
+		t.Fatalf("expected custom preset, got %q", r.Preset)
+	}
+	if r.Days() != 15 || r.Label() != "15d" {
+		t.Fatalf("expected partial days rounded up, got %d %q", r.Days(), r.Label())
+	}
+	day, err := ParseTimeRange("custom", "2026-01-01", "2026-01-01")
+	if err != nil {
+		t.Fatalf("ParseTimeRange returned error: %v", err)
+	}
+	if day.Days() != 1 || day.Label() != "1d" || day.To.Format(time.RFC3339) != "2026-01-01T23:59:59Z" {
+		t.Fatalf("expected a date-only end to cover the whole day, got %+v", day)
+	}
+	if _, err := ParseTimeRange("custom", "2026-01-01", ""); err == nil {
+		t.Fatalf("expected error for missing end bound")
+	}
+	if _, err := ParseTimeRange("custom", "2026-02-01", "2026-01-01"); err == nil {
+		t.Fatalf("expected error for inverted bounds")
+	}
+}
+
+func TestConfigureLayoutPropagatesTimeRange(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolveAreaFn: func(input ResolveAreaInput) (ResolvedArea, error) {
+			return ResolvedArea{
+				AreaCode: input.AreaCode,
+				Widgets: []WidgetInstance{
+					{ID: "w1", DefinitionID: "custom.widget.range"},
+					{ID: "w2", DefinitionID: "custom.widget.range", Metadata: map[string]any{"ignore_time_range": true}},
+				},
+			}, nil
+		},
+	}
+	registry := NewRegistry()
+	if err := registry.RegisterDefinition(WidgetDefinition{Code: "custom.widget.range"}); err != nil {
+		t.Fatalf("RegisterDefinition returned error: %v", err)
+	}
+	received := map[string]*TimeRange{}
+	err := registry.RegisterProvider("custom.widget.range", ProviderFunc(func(ctx context.Context, meta WidgetContext) (WidgetData, error) {
+		received[meta.Instance.ID] = meta.TimeRange
+		return WidgetData{}, nil
+	}))
+	if err != nil {
+		t.Fatalf("RegisterProvider returned error: %v", err)
+	}
+	service := NewService(Options{
+		WidgetStore: store,
+		Providers:   registry,
+		Areas:       []string{"admin.dashboard.main"},
+	})
+	viewer := ViewerContext{UserID: "user-range", TimeRange: &TimeRange{Preset: TimeRangePreset30d}}
+	if _, err := service.ConfigureLayout(context.Background(), viewer); err != nil {
+		t.Fatalf("ConfigureLayout returned error: %v", err)
+	}
+	if r := received["w1"]; r == nil || r.Days() != 30 || r.To.IsZero() {
+		t.Fatalf("expected resolved 30d range for w1, got %+v", r)
+	}
+	if r := received["w2"]; r != nil {
+		t.Fatalf("expected opted-out widget to receive no range, got %+v", r)
+	}
+}
+
+func TestControllerPageSharesResolvedTimeRangeWithWidgets(t *testing.T) {
+	store := &fakeWidgetStore{
+		resolveAreaFn: func(input ResolveAreaInput) (ResolvedArea, error) {
+			return ResolvedArea{AreaCode: input.AreaCode, Widgets: []WidgetInstance{{ID: "w1", DefinitionID: "custom.widget.range"}}}, nil
+		},
+	}
+	registry := NewRegistry()
+	if err := registry.RegisterDefinition(WidgetDefinition{Code: "custom.widget.range"}); err != nil {
+		t.Fatalf("RegisterDefinition returned error: %v", err)
+	}
+	var received *TimeRange
+	err := registry.RegisterProvider("custom.widget.range", ProviderFunc(func(ctx context.Context, meta WidgetContext) (WidgetData, error) {
+		received = meta.TimeRange
+		time.Sleep(time.Millisecond)
+		return WidgetData{}, nil
+	}))
+	if err != nil {
+		t.Fatalf("RegisterProvider returned error: %v", err)
+	}
+	service := NewService(Options{WidgetStore: store, Providers: registry, Areas: []string{"admin.dashboard.main"}})
+	controller := NewController(ControllerOptions{Service: service, TimeRangePresets: DefaultTimeRangePresets})
+
+	page, err := controller.Page(context.Background(), ViewerContext{UserID: "user-range", TimeRange: &TimeRange{Preset: TimeRangePreset7d}})
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	selected := page.TimeRange.Selected
+	if received == nil || selected == nil || !received.To.Equal(selected.To) || !received.From.Equal(selected.From) {
+		t.Fatalf("expected page and widget to share the resolved range, got page %+v widget %+v", selected, received)
+	}
+}
+
+func TestAnalyticsQueriesApplyTimeRange(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	r := (&TimeRange{Preset: TimeRangePreset90d}).Resolve(now)
+
+	funnel := extractFunnelQuery(map[string]any{"range": "7d"}).withTimeRange(r)
+	if funnel.Range != "90d" || !funnel.To.Equal(now) {
+		t.Fatalf("expected funnel range overridden, got %+v", funnel)
+	}
+	alerts := extractAlertQuery(map[string]any{"lookback_days": 7}).withTimeRange(r)
+	if alerts.LookbackDays != 90 {
+		t.Fatalf("expected 90 lookback days, got %d", alerts.LookbackDays)
+	}
+	cohort := extractCohortQuery(map[string]any{"interval": "weekly"}).withTimeRange(r)
+	if cohort.Periods != 12 {
+		t.Fatalf("expected 12 weekly periods, got %d", cohort.Periods)
+	}
+	unchanged := extractAlertQuery(map[string]any{"lookback_days": 7}).withTimeRange(nil)
+	if unchanged.LookbackDays != 7 {
+		t.Fatalf("expected nil range to keep configured lookback, got %d", unchanged.LookbackDays)
+	}
+}
@@ -0,0 +1,207 @@
+package dashboard
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TranslationKey is a catalog entry: a key the dashboard may translate, the
+// fallback rendered when no translation exists, and where the key was found.
+type TranslationKey struct {
+	Key          string            `json:"key" yaml:"key"`
+	Fallback     string            `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+	Sources      []string          `json:"sources,omitempty" yaml:"sources,omitempty"`
+	Translations map[string]string `json:"translations,omitempty" yaml:"translations,omitempty"`
+}
+
+// TranslationCatalog collects translation keys from templates, Go sources,
+// manifests and widget definitions.
+type TranslationCatalog struct {
+	entries map[string]*TranslationKey
+}
+
+var (
+	// T("key", locale, "fallback") and the tag form {{ T "key" locale }}.
+	templateTranslationCall = regexp.MustCompile(`\bT\(\s*("(?:[^"\\]|\\.)*"|'[^']*')\s*,\s*[^,()]+?(?:\s*,\s*("(?:[^"\\]|\\.)*"|'[^']*'))?\s*[,)]`)
+	templateTranslationTag  = regexp.MustCompile(`\bT\s+("(?:[^"\\]|\\.)*")`)
+	// translateOrFallback calls whose key and fallback are string literals.
+	sourceTranslationCall = regexp.MustCompile(`translateOrFallback\(\s*[^,]+,\s*[^,]+,\s*("(?:[^"\\]|\\.)*")\s*,\s*[^,]+,\s*("(?:[^"\\]|\\.)*")`)
+)
+
+// builtinProviderTranslationKeys lists the keys built-in providers translate
+// from Go code, so catalogs cover them without access to the sources. A test
+// runs the extractor over this package and fails when the list drifts.
+var builtinProviderTranslationKeys = map[string]string{
+	"dashboard.widget.user_stats.data_title":     "Users",
+	"dashboard.widget.quick_actions.invite_user": "Invite user",
+	"dashboard.widget.quick_actions.create_page": "Create page",
+	"dashboard.widget.system_status.database":    "Database",
+	"dashboard.widget.system_status.cache":       "Cache",
+	"dashboard.widget.system_status.worker":      "Worker",
+	"dashboard.widget.kpi.previous_period":       "vs previous period",
+	"dashboard.widget.table.yes":                 "Yes",
+	"dashboard.widget.table.no":                  "No",
+}
+
+// NewTranslationCatalog returns an empty catalog.
+func NewTranslationCatalog() *TranslationCatalog {
+	return &TranslationCatalog{entries: map[string]*TranslationKey{}}
+}
+
+// BuiltinTranslationCatalog returns the keys used by the embedded templates,
+// the built-in providers and the default widget definitions.
+func BuiltinTranslationCatalog() (*TranslationCatalog, error) {
+	catalog := NewTranslationCatalog()
+	templates, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := catalog.AddFS(templates, "embedded:"); err != nil {
+		return nil, err
+	}
+	for _, key := range slices.Sorted(maps.Keys(builtinProviderTranslationKeys)) {
+		catalog.Add(key, builtinProviderTranslationKeys[key], "builtin:providers")
+	}
+	catalog.AddDefinitions("builtin:definitions", DefaultWidgetDefinitions()...)
+	return catalog, nil
+}
+
+// Add records key with its fallback and source. The first non-empty fallback
+// wins; sources accumulate.
+func (c *TranslationCatalog) Add(key, fallback, source string) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return
+	}
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &TranslationKey{Key: key}
+		c.entries[key] = entry
+	}
+	if entry.Fallback == "" {
+		entry.Fallback = fallback
+	}
+	if source != "" && !slices.Contains(entry.Sources, source) {
+		entry.Sources = append(entry.Sources, source)
+	}
+}
+
+// AddFS scans `.html` templates and `.go` sources in fsys. Templates
+// contribute `T(...)` calls; Go files contribute translateOrFallback calls
+// with literal keys. prefix is prepended to the recorded source paths.
+func (c *TranslationCatalog) AddFS(fsys fs.FS, prefix string) error {
+	return fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		ext := path.Ext(name)
+		if ext != ".html" && ext != ".go" {
+			return nil
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("dashboard: read %s: %w", name, err)
+		}
+		if ext == ".go" {
+			c.addMatches(sourceTranslationCall, string(data), prefix+name)
+			return nil
+		}
+		c.addMatches(templateTranslationCall, string(data), prefix+name)
+		c.addMatches(templateTranslationTag, string(data), prefix+name)
+		return nil
+	})
+}
+
+func (c *TranslationCatalog) addMatches(pattern *regexp.Regexp, content, source string) {
+	for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+		fallback := ""
+		if len(match) > 2 {
+			fallback = unquoteTranslationLiteral(match[2])
+		}
+		c.Add(unquoteTranslationLiteral(match[1]), fallback, source)
+	}
+}
+
+// AddDefinitions records the title key providers derive from each
+// definition code (`dashboard.widget.<code>.title`) along with the localized
+// names the definition already carries.
+func (c *TranslationCatalog) AddDefinitions(source string, defs ...WidgetDefinition) {
+	for _, def := range defs {
+		if def.Code == "" {
+			continue
+		}
+		key := fmt.Sprintf("dashboard.widget.%s.title", def.Code)
+		c.Add(key, def.Name, source)
+		if len(def.NameLocalized) == 0 {
+			continue
+		}
+		entry := c.entries[key]
+		if entry.Translations == nil {
+			entry.Translations = map[string]string{}
+		}
+		for locale, value := range def.NameLocalized {
+			if _, exists := entry.Translations[locale]; !exists && value != "" {
+				entry.Translations[locale] = value
+			}
+		}
+	}
+}
+
+// AddManifest records the definition keys of every widget in doc.
+func (c *TranslationCatalog) AddManifest(doc *WidgetManifestDocument) {
+	if doc == nil {
+		return
+	}
+	source := doc.Source
+	if source == "" {
+		source = "manifest"
+	}
+	for _, widget := range doc.Widgets {
+		c.AddDefinitions(source, widget.Definition)
+	}
+}
+
+// Keys returns the catalog entries sorted by key.
+func (c *TranslationCatalog) Keys() []TranslationKey {
+	out := make([]TranslationKey, 0, len(c.entries))
+	for _, key := range slices.Sorted(maps.Keys(c.entries)) {
+		entry := *c.entries[key]
+		entry.Sources = slices.Clone(entry.Sources)
+		slices.Sort(entry.Sources)
+		entry.Translations = maps.Clone(entry.Translations)
+		out = append(out, entry)
+	}
+	return out
+}
+
+// Messages flattens the catalog into a key -> fallback map, a starting point
+// for a locale message file.
+func (c *TranslationCatalog) Messages() map[string]string {
+	out := make(map[string]string, len(c.entries))
+	for key, entry := range c.entries {
+		out[key] = entry.Fallback
+	}
+	return out
+}
+
+func unquoteTranslationLiteral(raw string) string {
+	if strings.HasPrefix(raw, "'") {
+		return strings.Trim(raw, "'")
+	}
+	if value, err := strconv.Unquote(raw); err == nil {
+		return value
+	}
+	return strings.Trim(raw, `"`)
+}
@@ -0,0 +1,144 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTranslationCatalogExtractsTemplatesSourcesAndDefinitions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"widgets/sales.html": {Data: []byte(`<h3>{{ T("acme.sales.title", locale, "Sales") }}</h3>
+<span>{{ coalesce(widget.data.label, T('acme.sales.empty', locale, 'Nothing yet')) }}</span>
+<small>{{ T "acme.sales.note" locale }}</small>`)},
+		"provider.go":      {Data: []byte(`label := translateOrFallback(ctx, req.Translator, "acme.sales.total", req.Viewer.Locale, "Total \"net\"", nil)`)},
+		"provider_test.go": {Data: []byte(`translateOrFallback(ctx, nil, "acme.ignored", "", "x", nil)`)},
+		"README.md":        {Data: []byte(`T("acme.readme", locale, "skip")`)},
+	}
+	catalog := NewTranslationCatalog()
+	if err := catalog.AddFS(fsys, "pack/"); err != nil {
+		t.Fatalf("AddFS returned error: %v", err)
+	}
+	catalog.AddManifest(&WidgetManifestDocument{
+		Source: "widgets.yaml",
+		Widgets: []ManifestWidget{{Definition: WidgetDefinition{
+			Code:          "acme.widget.sales",
+			Name:          "Sales",
+			NameLocalized: map[string]string{"es": "Ventas"},
+		}}},
+	})
+
+	keys := catalog.Keys()
+	var names []string
+	for _, key := range keys {
+		names = append(names, key.Key)
+	}
+	want := []string{"acme.sales.empty", "acme.sales.note", "acme.sales.title", "acme.sales.total", "dashboard.widget.acme.widget.sales.title"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("unexpected keys %v", names)
+	}
+	messages := catalog.Messages()
+	if messages["acme.sales.empty"] != "Nothing yet" || messages["acme.sales.total"] != `Total "net"` {
+		t.Fatalf("unexpected fallbacks %+v", messages)
+	}
+	title := keys[4]
+	if title.Fallback != "Sales" || title.Translations["es"] != "Ventas" || !slices.Equal(title.Sources, []string{"widgets.yaml"}) {
+		t.Fatalf("unexpected definition entry %+v", title)
+	}
+	if !slices.Equal(keys[2].Sources, []string{"pack/widgets/sales.html"}) {
+		t.Fatalf("unexpected template sources %+v", keys[2])
+	}
+}
+
+func TestBuiltinTranslationCatalogCoversProviderSources(t *testing.T) {
+	builtin, err := BuiltinTranslationCatalog()
+	if err != nil {
+		t.Fatalf("BuiltinTranslationCatalog returned error: %v", err)
+	}
+	sources := NewTranslationCatalog()
+	if err := sources.AddFS(os.DirFS("."), ""); err != nil {
+		t.Fatalf("AddFS returned error: %v", err)
+	}
+	known := builtin.Messages()
+	for _, key := range sources.Keys() {
+		if _, ok := known[key.Key]; !ok {
+			t.Fatalf("builtin catalog is missing %s (from %v)", key.Key, key.Sources)
+		}
+	}
+	if known["dashboard.widget.admin.widget.kpi.title"] == "" || known["dashboard.widget.recent_activity.title"] == "" {
+		t.Fatalf("expected definition and template keys in builtin catalog")
+	}
+}
+
+func TestBuiltinProviderTranslationKeysMatchSources(t *testing.T) {
+	sources := NewTranslationCatalog()
+	if err := sources.AddFS(os.DirFS("."), ""); err != nil {
+		t.Fatalf("AddFS returned error: %v", err)
+	}
+	extracted := map[string]string{}
+	for _, key := range sources.Keys() {
+		if slices.ContainsFunc(key.Sources, func(source string) bool { return strings.HasSuffix(source, ".go") }) {
+			extracted[key.Key] = key.Fallback
+		}
+	}
+	if !maps.Equal(extracted, builtinProviderTranslationKeys) {
+		t.Fatalf("builtinProviderTranslationKeys is out of date:\n have %v\n want %v", builtinProviderTranslationKeys, extracted)
+	}
+}
+
+type keyedTranslationService map[string]string
+
+func (s keyedTranslationService) Translate(_ context.Context, key, locale string, _ map[string]any) (string, error) {
+	return s[locale+":"+key], nil
+}
+
+func TestTranslationDiagnosticsRecordsFallbacks(t *testing.T) {
+	diagnostics := NewTranslationDiagnostics(keyedTranslationService{"es:known": "conocido"})
+	ctx := context.Background()
+	if got := translateOrFallback(ctx, diagnostics, "known", "es", "Known", nil); got != "conocido" {
+		t.Fatalf("expected wrapped translation, got %q", got)
+	}
+	for range 2 {
+		translateOrFallback(ctx, diagnostics, "dashboard.widget.table.yes", "ES", "Yes", nil)
+	}
+	translateOrFallback(ctx, diagnostics, "dashboard.widget.table.no", "", "No", nil)
+
+	missing := diagnostics.MissingTranslations()
+	es := missing["es"]
+	if len(es) != 1 || es[0].Key != "dashboard.widget.table.yes" || es[0].Count != 2 || es[0].Fallback != "Yes" {
+		t.Fatalf("unexpected es report %+v", es)
+	}
+	if len(missing["default"]) != 1 {
+		t.Fatalf("expected locale-less keys under default, got %+v", missing)
+	}
+
+	renderer, err := NewTemplateRenderer(WithTranslationHelpers(diagnostics))
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer returned error: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := renderer.RenderPage("dashboard.html", Page{Title: "Dashboard", Locale: "fr"}, &buf); err != nil {
+		t.Fatalf("RenderPage returned error: %v", err)
+	}
+	if len(diagnostics.MissingTranslations()["fr"]) == 0 {
+		t.Fatalf("expected template lookups to be recorded")
+	}
+
+	svc := NewService(Options{WidgetStore: &fakeWidgetStore{}, Translation: diagnostics})
+	report, err := svc.Diagnostics(ctx, ViewerContext{})
+	if err != nil {
+		t.Fatalf("Diagnostics returned error: %v", err)
+	}
+	if len(report.MissingTranslations["es"]) != 1 {
+		t.Fatalf("expected diagnostics to include missing translations, got %+v", report.MissingTranslations)
+	}
+	diagnostics.Reset()
+	if diagnostics.MissingTranslations() != nil {
+		t.Fatalf("expected reset to clear the report")
+	}
+}
@@ -0,0 +1,123 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxMissingTranslationsPerLocale bounds the report so data-driven keys
+// (chart labels, series names) cannot grow it without limit.
+const maxMissingTranslationsPerLocale = 500
+
+var errTranslationMissing = errors.New("dashboard: translation missing")
+
+// MissingTranslationRecorder is implemented by translation services that want
+// to hear about keys rendered with their fallback.
+type MissingTranslationRecorder interface {
+	RecordMissingTranslation(locale, key, fallback string)
+}
+
+// MissingTranslation describes a key that fell back for a locale.
+type MissingTranslation struct {
+	Key      string    `json:"key"`
+	Locale   string    `json:"locale"`
+	Fallback string    `json:"fallback,omitempty"`
+	Count    int       `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// TranslationDiagnostics wraps a TranslationService and records every key
+// that falls back, per locale. Use it in place of the wrapped service (both in
+// Options.Translation and WithTranslationHelpers) while auditing coverage; the
+// report is surfaced through DashboardDiagnostics.MissingTranslations.
+type TranslationDiagnostics struct {
+	svc TranslationService
+	now func() time.Time
+
+	mu      sync.Mutex
+	missing map[string]map[string]*MissingTranslation
+}
+
+// NewTranslationDiagnostics wraps svc, which may be nil to record every key.
+func NewTranslationDiagnostics(svc TranslationService) *TranslationDiagnostics {
+	return &TranslationDiagnostics{
+		svc:     svc,
+		now:     time.Now,
+		missing: map[string]map[string]*MissingTranslation{},
+	}
+}
+
+// Translate delegates to the wrapped service.
+func (d *TranslationDiagnostics) Translate(ctx context.Context, key, locale string, args map[string]any) (string, error) {
+	if d.svc == nil {
+		return "", errTranslationMissing
+	}
+	return d.svc.Translate(ctx, key, locale, args)
+}
+
+// RecordMissingTranslation notes that key fell back for locale.
+func (d *TranslationDiagnostics) RecordMissingTranslation(locale, key, fallback string) {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		locale = "default"
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	byKey := d.missing[locale]
+	if byKey == nil {
+		byKey = map[string]*MissingTranslation{}
+		d.missing[locale] = byKey
+	}
+	entry, ok := byKey[key]
+	if !ok {
+		if len(byKey) >= maxMissingTranslationsPerLocale {
+			return
+		}
+		entry = &MissingTranslation{Key: key, Locale: locale, Fallback: fallback}
+		byKey[key] = entry
+	}
+	entry.Count++
+	entry.LastSeen = d.now()
+}
+
+// MissingTranslations returns the recorded keys per locale, sorted by key.
+func (d *TranslationDiagnostics) MissingTranslations() map[string][]MissingTranslation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.missing) == 0 {
+		return nil
+	}
+	out := make(map[string][]MissingTranslation, len(d.missing))
+	for locale, byKey := range d.missing {
+		entries := make([]MissingTranslation, 0, len(byKey))
+		for _, key := range slices.Sorted(maps.Keys(byKey)) {
+			entries = append(entries, *byKey[key])
+		}
+		out[locale] = entries
+	}
+	return out
+}
+
+// Reset clears the recorded keys.
+func (d *TranslationDiagnostics) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.missing = map[string]map[string]*MissingTranslation{}
+}
+
+type missingTranslationReporter interface {
+	MissingTranslations() map[string][]MissingTranslation
+}
+
+// MissingTranslations reports keys that fell back when Options.Translation
+// records them (see TranslationDiagnostics).
+func (s *Service) MissingTranslations() map[string][]MissingTranslation {
+	if reporter, ok := s.opts.Translation.(missingTranslationReporter); ok {
+		return reporter.MissingTranslations()
+	}
+	return nil
+}
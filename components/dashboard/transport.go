@@ -14,6 +14,16 @@ type RemoveWidgetInput struct {
 	TenantID string `json:"tenant_id"`
 }
 
+// UpdateWidgetInput captures widget configuration/metadata updates.
+type UpdateWidgetInput struct {
+	WidgetID      string         `json:"widget_id"`
+	Configuration map[string]any `json:"configuration"`
+	Metadata      map[string]any `json:"metadata"`
+	ActorID       string         `json:"actor_id"`
+	UserID        string         `json:"user_id"`
+	TenantID      string         `json:"tenant_id"`
+}
+
 // ReorderWidgetsInput contains the reorder payload.
 type ReorderWidgetsInput struct {
 	AreaCode  string   `json:"area_code"`
@@ -35,6 +45,11 @@ type SaveLayoutPreferencesInput struct {
 	AreaOrder     map[string][]string         `json:"area_order"`
 	LayoutRows    map[string][]LayoutRowInput `json:"layout_rows"`
 	HiddenWidgets []string                    `json:"hidden_widget_ids"`
+	Filters       map[string]string           `json:"filters,omitempty"`
+	// BreakpointOrder maps area codes to per-breakpoint widget orders.
+	BreakpointOrder map[string]map[Breakpoint][]string `json:"breakpoint_order,omitempty"`
+	// LayoutGrid switches areas to the free-form grid with x/y/w/h placements.
+	LayoutGrid map[string][]GridPlacement `json:"layout_grid,omitempty"`
 }
 
 // LegacyLayoutPreferencesInput is a temporary migration adapter for historical
@@ -63,8 +78,9 @@ type LayoutRowInput struct {
 
 // LayoutWidgetInput describes a widget placement + width in a transport payload.
 type LayoutWidgetInput struct {
-	ID    string `json:"id"`
-	Width int    `json:"width"`
+	ID     string             `json:"id"`
+	Width  int                `json:"width"`
+	Widths map[Breakpoint]int `json:"widths,omitempty"`
 }
 
 // ToSaveLayoutPreferencesInput converts the legacy layout-array transport shape
@@ -150,6 +166,13 @@ type Executor interface {
 	Preferences(ctx context.Context, input SaveLayoutPreferencesInput) error
 }
 
+// WidgetUpdater is implemented by executors that can update widget
+// configuration. Transports only expose configuration submission when the
+// executor supports it.
+type WidgetUpdater interface {
+	Update(ctx context.Context, input UpdateWidgetInput) error
+}
+
 // ServiceExecutorService captures the shared dashboard service surface used by the default executor.
 type ServiceExecutorService interface {
 	AddWidget(ctx context.Context, req AddWidgetRequest) error
@@ -164,7 +187,10 @@ type ServiceExecutor struct {
 	Service ServiceExecutorService
 }
 
-var _ Executor = (*ServiceExecutor)(nil)
+var (
+	_ Executor      = (*ServiceExecutor)(nil)
+	_ WidgetUpdater = (*ServiceExecutor)(nil)
+)
 
 // NewServiceExecutor creates an executor backed directly by the shared dashboard service.
 func NewServiceExecutor(service ServiceExecutorService) *ServiceExecutor {
@@ -192,6 +218,32 @@ func (e *ServiceExecutor) Remove(ctx context.Context, input RemoveWidgetInput) e
 	return e.Service.RemoveWidget(ctx, input.WidgetID)
 }
 
+// Update delegates configuration/metadata updates to the configured service
+// when it supports UpdateWidget.
+func (e *ServiceExecutor) Update(ctx context.Context, input UpdateWidgetInput) error {
+	if e == nil || e.Service == nil {
+		return errors.New("dashboard: service executor not configured")
+	}
+	updater, ok := e.Service.(interface {
+		UpdateWidget(ctx context.Context, widgetID string, req UpdateWidgetRequest) error
+	})
+	if !ok {
+		return errors.New("dashboard: service does not support widget updates")
+	}
+	ctx = ContextWithActivity(ctx, ActivityContext{
+		ActorID:  input.ActorID,
+		UserID:   input.UserID,
+		TenantID: input.TenantID,
+	})
+	return updater.UpdateWidget(ctx, input.WidgetID, UpdateWidgetRequest{
+		Configuration: input.Configuration,
+		Metadata:      input.Metadata,
+		ActorID:       input.ActorID,
+		UserID:        input.UserID,
+		TenantID:      input.TenantID,
+	})
+}
+
 // Reorder delegates area ordering changes directly to the configured service.
 func (e *ServiceExecutor) Reorder(ctx context.Context, input ReorderWidgetsInput) error {
 	if e == nil || e.Service == nil {
@@ -214,19 +266,32 @@ func (e *ServiceExecutor) Preferences(ctx context.Context, input SaveLayoutPrefe
 		return errors.New("dashboard: service executor not configured")
 	}
 	overrides := LayoutOverrides{
-		AreaOrder:     input.AreaOrder,
-		AreaRows:      convertLayoutRowsInput(input.LayoutRows),
-		HiddenWidgets: make(map[string]bool, len(input.HiddenWidgets)),
-		Locale:        input.Viewer.Locale,
-	}
-	for _, id := range input.HiddenWidgets {
-		overrides.HiddenWidgets[id] = true
+		AreaOrder:       input.AreaOrder,
+		AreaRows:        convertLayoutRowsInput(input.LayoutRows),
+		HiddenWidgets:   HiddenWidgetSet(input.HiddenWidgets),
+		Filters:         input.Filters,
+		Locale:          input.Viewer.Locale,
+		BreakpointOrder: input.BreakpointOrder,
+		AreaGrids:       input.LayoutGrid,
 	}
 	return e.Service.SavePreferences(ctx, input.Viewer, overrides)
 }
 
+// HiddenWidgetSet converts hidden widget IDs into the override set. A nil
+// slice (the field was absent) stays nil so the stored set is kept.
+func HiddenWidgetSet(ids []string) map[string]bool {
+	if ids == nil {
+		return nil
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
 func convertLayoutRowsInput(input map[string][]LayoutRowInput) map[string][]LayoutRow {
-	if len(input) == 0 {
+	if input == nil {
 		return nil
 	}
 	output := make(map[string][]LayoutRow, len(input))
@@ -242,8 +307,9 @@ func convertLayoutRowsInput(input map[string][]LayoutRowInput) map[string][]Layo
 					continue
 				}
 				slots = append(slots, WidgetSlot{
-					ID:    widget.ID,
-					Width: widget.Width,
+					ID:     widget.ID,
+					Width:  widget.Width,
+					Widths: widget.Widths,
 				})
 			}
 			if len(slots) == 0 {
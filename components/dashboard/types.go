@@ -24,6 +24,23 @@ type Authorizer interface {
 	CanViewWidget(ctx context.Context, viewer ViewerContext, instance WidgetInstance) bool
 }
 
+// EditAuthorizer is an optional Authorizer extension that decides who may
+// change the dashboard. Without it, edit mode and widget configuration require
+// an identified viewer, who must also be able to see the widget.
+type EditAuthorizer interface {
+	CanEditLayout(ctx context.Context, viewer ViewerContext) bool
+	CanConfigureWidget(ctx context.Context, viewer ViewerContext, instance WidgetInstance) bool
+}
+
+// CatalogAuthorizer is an optional Authorizer extension that filters the
+// widget definitions and catalog channels offered to a viewer. Without it
+// every registered definition is offered and the catalog browser hides the
+// internal channel.
+type CatalogAuthorizer interface {
+	CanUseDefinition(ctx context.Context, viewer ViewerContext, def WidgetDefinition) bool
+	CanBrowseCatalogChannel(ctx context.Context, viewer ViewerContext, channel string) bool
+}
+
 // PreferenceStore returns layout overrides per viewer.
 type PreferenceStore interface {
 	LayoutOverrides(ctx context.Context, viewer ViewerContext) (LayoutOverrides, error)
@@ -59,7 +76,16 @@ type WidgetDefinition struct {
 	Description          string            `json:"description,omitempty" yaml:"description,omitempty"`
 	DescriptionLocalized map[string]string `json:"description_localized,omitempty" yaml:"description_localized,omitempty"`
 	Schema               map[string]any    `json:"schema,omitempty" yaml:"schema,omitempty"`
-	Category             string            `json:"category,omitempty" yaml:"category,omitempty"`
+	// SchemaVersion is bumped whenever Schema changes incompatibly; stored
+	// instances are upgraded through migrations registered with
+	// Registry.RegisterSchemaMigration. Zero means unversioned.
+	SchemaVersion int    `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+	Category      string `json:"category,omitempty" yaml:"category,omitempty"`
+	// Variables lists the dashboard filters the widget consumes.
+	Variables []string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	// Interactions declares drill-down targets and the filters a click
+	// publishes to other widgets.
+	Interactions *WidgetInteractions `json:"interactions,omitempty" yaml:"interactions,omitempty"`
 }
 
 // WidgetInstance represents a widget instance stored in go-cms.
@@ -129,6 +155,14 @@ type LayoutOverrides struct {
 	AreaOrder     map[string][]string
 	AreaRows      map[string][]LayoutRow
 	HiddenWidgets map[string]bool
+	Filters       map[string]string
+	// BreakpointOrder reorders an area's widgets per breakpoint (area code →
+	// breakpoint → widget IDs). AreaOrder stays the document order used where
+	// a breakpoint has no entry.
+	BreakpointOrder map[string]map[Breakpoint][]string
+	// AreaGrids switches areas to the free-form grid: widgets are placed by
+	// x/y/w/h instead of AreaRows, and AreaOrder no longer applies.
+	AreaGrids map[string][]GridPlacement
 }
 
 // LayoutRow represents widgets that share the same row/line within an area.
@@ -136,10 +170,12 @@ type LayoutRow struct {
 	Widgets []WidgetSlot `json:"widgets" yaml:"widgets"`
 }
 
-// WidgetSlot describes a widget placement + its width (1-12).
+// WidgetSlot describes a widget placement + its width (1-12). Widths
+// overrides the width per breakpoint (see ResolveBreakpointWidths).
 type WidgetSlot struct {
-	ID    string `json:"id" yaml:"id"`
-	Width int    `json:"width,omitempty" yaml:"width,omitempty"`
+	ID     string             `json:"id" yaml:"id"`
+	Width  int                `json:"width,omitempty" yaml:"width,omitempty"`
+	Widths map[Breakpoint]int `json:"widths,omitempty" yaml:"widths,omitempty"`
 }
 
 // ViewerContext captures the active user/locale information needed to render dashboards.
@@ -150,12 +186,26 @@ type ViewerContext struct {
 	// FallbackLocales provides an ordered locale fallback chain for
 	// widget/content consumers that support localized resolution.
 	FallbackLocales []string
+	// TimeRange is the dashboard-wide time window selected by the viewer.
+	// Nil keeps each widget on its configured period.
+	TimeRange *TimeRange
+	// Filters carries dashboard filter selections from the request (URL).
+	// They take precedence over persisted preference values.
+	Filters map[string]string
+	// ThemeVariant is the variant the viewer picked with the theme toggle
+	// (persisted client-side). It wins over the ThemeSelector variant when
+	// the selected theme offers it.
+	ThemeVariant string
+	// Editing renders the page in layout edit mode: hidden widgets stay in
+	// the layout (flagged as hidden) so the editor can show them again.
+	Editing bool
 }
 
 // Layout describes the resolved widget instances per dashboard area.
 type Layout struct {
-	Areas map[string][]WidgetInstance
-	Theme *ThemeSelection
+	Areas   map[string][]WidgetInstance
+	Theme   *ThemeSelection
+	Filters []PageFilter
 }
 
 // WidgetEvent describes changes that transports might care about.
@@ -54,6 +54,16 @@ func (v *JSONSchemaValidator) Validate(def WidgetDefinition, config map[string]a
 	return nil
 }
 
+// CompileSchema compiles def.Schema exactly as the runtime validator does,
+// including the x-localizable rewrite, and reports the first error.
+func CompileSchema(def WidgetDefinition) error {
+	if len(def.Schema) == 0 {
+		return nil
+	}
+	_, err := NewJSONSchemaValidator().schemaFor(def)
+	return err
+}
+
 func (v *JSONSchemaValidator) schemaFor(def WidgetDefinition) (*jsonschema.Schema, error) {
 	v.mu.RLock()
 	schema, ok := v.compiled[def.Code]
@@ -61,7 +71,7 @@ func (v *JSONSchemaValidator) schemaFor(def WidgetDefinition) (*jsonschema.Schem
 	if ok {
 		return schema, nil
 	}
-	data, err := json.Marshal(def.Schema)
+	data, err := json.Marshal(localizableValidationSchema(def.Schema))
 	if err != nil {
 		return nil, fmt.Errorf("dashboard: marshal schema %s: %w", def.Code, err)
 	}
@@ -0,0 +1,81 @@
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is the go-dashboard release this package belongs to. Manifest v2
+// widgets compare it against `requires.dashboard`.
+const Version = "0.14.1"
+
+// semver is a parsed MAJOR.MINOR.PATCH[-prerelease] version. Build metadata
+// is accepted and ignored.
+type semver struct {
+	major, minor, patch int
+	prerelease          string
+}
+
+// parseSemver accepts semantic versions with an optional leading "v".
+func parseSemver(raw string) (semver, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	value, _, _ = strings.Cut(value, "+")
+	value, pre, hasPre := strings.Cut(value, "-")
+	if hasPre && pre == "" {
+		return semver{}, fmt.Errorf("dashboard: invalid semantic version %q", raw)
+	}
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 {
+		return semver{}, fmt.Errorf("dashboard: invalid semantic version %q", raw)
+	}
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (len(part) > 1 && part[0] == '0') {
+			return semver{}, fmt.Errorf("dashboard: invalid semantic version %q", raw)
+		}
+		nums[i] = n
+	}
+	return semver{major: nums[0], minor: nums[1], patch: nums[2], prerelease: pre}, nil
+}
+
+// compare orders versions by precedence; a prerelease sorts before its
+// release and prereleases compare dot-separated identifiers.
+func (v semver) compare(other semver) int {
+	for _, diff := range []int{v.major - other.major, v.minor - other.minor, v.patch - other.patch} {
+		if diff != 0 {
+			return diff
+		}
+	}
+	switch {
+	case v.prerelease == other.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case other.prerelease == "":
+		return -1
+	}
+	left := strings.Split(v.prerelease, ".")
+	right := strings.Split(other.prerelease, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if c := comparePrereleaseIdentifier(left[i], right[i]); c != 0 {
+			return c
+		}
+	}
+	return len(left) - len(right)
+}
+
+func comparePrereleaseIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return an - bn
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,48 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemverCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"0.14.1", "0.14.1", 0},
+		{"v0.14.1", "0.14.0", 1},
+		{"0.9.0", "0.14.0", -1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1+build.5", "1.0.0-beta", 1},
+	}
+	for _, tc := range cases {
+		a, err := parseSemver(tc.a)
+		require.NoError(t, err)
+		b, err := parseSemver(tc.b)
+		require.NoError(t, err)
+		got := a.compare(b)
+		switch {
+		case tc.want == 0 && got != 0, tc.want < 0 && got >= 0, tc.want > 0 && got <= 0:
+			t.Fatalf("compare(%s, %s) = %d, want sign %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+	for _, invalid := range []string{"1.0", "01.0.0", "1.0.0-", "x.y.z"} {
+		_, err := parseSemver(invalid)
+		assert.Errorf(t, err, "expected %q to be rejected", invalid)
+	}
+	_, err := parseSemver(Version)
+	require.NoError(t, err)
+}
+
+func TestVersionMatchesReleaseFile(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("..", "..", ".version"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(raw)), Version, "bump dashboard.Version with .version")
+}
@@ -0,0 +1,136 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultWidgetActionPath is the action endpoint pattern mounted by the
+// go-router adapter with its default base path and routes. `:id` and `:action`
+// are replaced by WidgetActionURL.
+const DefaultWidgetActionPath = "/admin/dashboard/widgets/:id/actions/:action"
+
+const widgetActionPathOptionKey = "dashboard.widget.action_path"
+
+var (
+	// ErrWidgetActionUnsupported is returned when the widget provider does not
+	// handle the requested action.
+	ErrWidgetActionUnsupported = errors.New("dashboard: widget action not supported")
+	// ErrWidgetActionForbidden is returned when the viewer cannot see the widget.
+	ErrWidgetActionForbidden = errors.New("dashboard: widget action forbidden")
+)
+
+// WidgetActionRequest targets a provider action (paging, export, ...) on a
+// single widget instance.
+type WidgetActionRequest struct {
+	WidgetID string
+	Action   string
+	Viewer   ViewerContext
+	Params   map[string]string
+}
+
+// WidgetActionResult is either a JSON payload (Data) or a streamed body
+// (Stream) written with ContentType. Filename marks streamed results as
+// downloads.
+type WidgetActionResult struct {
+	ContentType string
+	Filename    string
+	Data        any
+	Stream      func(w io.Writer) error
+}
+
+// WidgetActionHandler is implemented by providers that expose actions besides
+// the initial render. meta is resolved exactly as for rendering, including the
+// dashboard time range and filters.
+type WidgetActionHandler interface {
+	HandleAction(ctx context.Context, meta WidgetContext, action string, params map[string]string) (WidgetActionResult, error)
+}
+
+// WidgetActionExecutor executes widget actions for transports.
+type WidgetActionExecutor interface {
+	ExecuteWidgetAction(ctx context.Context, req WidgetActionRequest) (WidgetActionResult, error)
+}
+
+var _ WidgetActionExecutor = (*Service)(nil)
+
+// WidgetActionURL expands an action path pattern for a widget instance.
+func WidgetActionURL(pattern, widgetID, action string) string {
+	if pattern == "" {
+		pattern = DefaultWidgetActionPath
+	}
+	return strings.NewReplacer(
+		":id", url.PathEscape(widgetID),
+		":action", url.PathEscape(action),
+	).Replace(pattern)
+}
+
+// widgetActionPath returns the action path pattern configured on the service.
+func widgetActionPath(options map[string]any) string {
+	if pattern, ok := options[widgetActionPathOptionKey].(string); ok && pattern != "" {
+		return pattern
+	}
+	return DefaultWidgetActionPath
+}
+
+// ExecuteWidgetAction resolves the widget instance for the viewer and
+// dispatches the action to its provider.
+func (s *Service) ExecuteWidgetAction(ctx context.Context, req WidgetActionRequest) (WidgetActionResult, error) {
+	store, err := s.widgetStore()
+	if err != nil {
+		return WidgetActionResult{}, err
+	}
+	if strings.TrimSpace(req.WidgetID) == "" {
+		return WidgetActionResult{}, fmt.Errorf("dashboard: widget id is required")
+	}
+	if strings.TrimSpace(req.Action) == "" {
+		return WidgetActionResult{}, fmt.Errorf("dashboard: widget action is required")
+	}
+	inst, err := store.GetInstance(ctx, req.WidgetID)
+	if err != nil {
+		return WidgetActionResult{}, err
+	}
+	viewer := req.Viewer
+	overrides, err := s.opts.PreferenceStore.LayoutOverrides(ctx, viewer)
+	if err != nil {
+		return WidgetActionResult{}, err
+	}
+	viewer.Filters = s.filterValues(viewer.Filters, overrides.Filters)
+	if !s.opts.Authorizer.CanViewWidget(ctx, viewer, inst) {
+		return WidgetActionResult{}, ErrWidgetActionForbidden
+	}
+	inst, _, err = s.migrateInstance(inst)
+	if err != nil {
+		s.recordTelemetry(ctx, "dashboard.widget.migration_error", map[string]any{
+			"definition_id": inst.DefinitionID,
+			"widget_id":     inst.ID,
+			"error":         err.Error(),
+		})
+		return WidgetActionResult{}, err
+	}
+	provider, ok := s.opts.Providers.Provider(inst.DefinitionID)
+	if !ok || provider == nil {
+		return WidgetActionResult{}, ErrWidgetActionUnsupported
+	}
+	handler, ok := provider.(WidgetActionHandler)
+	if !ok {
+		return WidgetActionResult{}, ErrWidgetActionUnsupported
+	}
+	theme := s.resolveTheme(ctx, viewer)
+	meta := s.widgetContext(ctx, viewer, theme, inst, viewer.TimeRange.Resolve(time.Now()))
+	result, err := handler.HandleAction(ctx, meta, req.Action, maps.Clone(req.Params))
+	if err != nil {
+		return WidgetActionResult{}, err
+	}
+	s.recordTelemetry(ctx, "dashboard.widget.action", map[string]any{
+		"definition_id": inst.DefinitionID,
+		"widget_id":     inst.ID,
+		"action":        req.Action,
+	})
+	return result, nil
+}
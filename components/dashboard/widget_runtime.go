@@ -36,7 +36,10 @@ type WidgetRequest[TConfig any] struct {
 	Viewer     ViewerContext
 	Options    map[string]any
 	Translator TranslationService
+	Formatter  Formatter
 	Theme      *ThemeSelection
+	TimeRange  *TimeRange
+	Filters    map[string]string
 	Config     TConfig
 }
 
@@ -106,7 +109,10 @@ func (runtime typedWidgetRuntime[TConfig, TData, TView]) Resolve(ctx context.Con
 		Viewer:     meta.Viewer,
 		Options:    meta.Options,
 		Translator: meta.Translator,
+		Formatter:  meta.Formatter,
 		Theme:      meta.Theme,
+		TimeRange:  meta.TimeRange,
+		Filters:    meta.Filters,
 		Config:     cfg,
 	}
 	data, err := runtime.spec.Fetch(ctx, req)
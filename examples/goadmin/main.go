@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -213,6 +214,7 @@ func main() {
 
 	log.Printf("dashboard routes ready: http://localhost:9876/admin/dashboard")
 	log.Printf("Try locale switching via http://localhost:9876/admin/dashboard?locale=es")
+	log.Printf("Edit the layout in place via http://localhost:9876/admin/dashboard?edit=1")
 	log.Printf("API endpoints: POST %s, DELETE %s, WebSocket %s",
 		"/admin/dashboard/widgets",
 		"/admin/dashboard/widgets/:id",
@@ -225,9 +227,11 @@ func main() {
 
 func newDemoController(service *dashboard.Service, translator dashboard.TranslationService, renderer dashboard.Renderer) *dashboard.Controller {
 	return dashboard.NewController(dashboard.ControllerOptions{
-		Service:       service,
-		Renderer:      renderer,
-		PageDecorator: demoPageDecorator(translator),
+		Service:         service,
+		Renderer:        renderer,
+		PageDecorator:   demoPageDecorator(translator),
+		EditMode:        &dashboard.EditModeOptions{},
+		PreferencesPath: dashboard.DefaultPreferencesPath,
 	})
 }
 
@@ -246,6 +250,7 @@ func newDemoCommandExecutor(service *dashboard.Service) *httpapi.CommandExecutor
 		ReorderCommander: commands.NewReorderWidgetsCommand(service, nil),
 		RefreshCommander: commands.NewRefreshWidgetCommand(service, nil),
 		PrefsCommander:   commands.NewSaveLayoutPreferencesCommand(service, nil),
+		UpdateCommander:  commands.NewUpdateWidgetCommand(service, nil),
 	}
 }
 
@@ -340,6 +345,18 @@ func (s *memoryWidgetStore) GetInstance(ctx context.Context, instanceID string)
 	return inst, nil
 }
 
+// ListInstances lets bulk schema migrations visit every instance.
+func (s *memoryWidgetStore) ListInstances(ctx context.Context) ([]dashboard.WidgetInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]dashboard.WidgetInstance, 0, len(s.instances))
+	for _, inst := range s.instances {
+		out = append(out, inst)
+	}
+	slices.SortFunc(out, func(a, b dashboard.WidgetInstance) int { return strings.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
 func (s *memoryWidgetStore) DeleteInstance(ctx context.Context, instanceID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
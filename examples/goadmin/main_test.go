@@ -45,8 +45,10 @@ func TestDemoControllerPageUsesTypedContracts(t *testing.T) {
 
 	salesChart := requireWidgetByDefinition(t, page, "admin.widget.sales_chart")
 	salesChartData := requireWidgetDataMap(t, salesChart)
-	assert.Equal(t, "wonderland", salesChartData["theme"])
+	chartTheme, _ := salesChartData["theme"].(string)
+	assert.True(t, strings.HasPrefix(chartTheme, dashboard.GeneratedChartThemePrefix), "expected token-derived chart theme, got %q", chartTheme)
 	markup, _ := salesChartData["chart_html"].(string)
+	assert.Contains(t, markup, `echarts.registerTheme("`+chartTheme+`"`)
 	assert.Contains(t, markup, "echarts.init")
 	assert.NotContains(t, markup, dashboard.DefaultEChartsAssetsPath+"echarts.min.js")
 	assert.NotContains(t, strings.ToLower(markup), "<!doctype html>")
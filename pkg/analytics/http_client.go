@@ -47,6 +47,9 @@ func (c *HTTPClient) FetchFunnel(ctx context.Context, query dashboard.FunnelQuer
 		Range:   query.Range,
 		Segment: query.Segment,
 		Goal:    query.Goal,
+		From:    formatQueryTime(query.From),
+		To:      formatQueryTime(query.To),
+		Filters: query.Filters,
 	}
 	var resp funnelResponse
 	if err := c.do(ctx, http.MethodPost, "/funnels/query", req, &resp); err != nil {
@@ -61,6 +64,9 @@ func (c *HTTPClient) FetchCohorts(ctx context.Context, query dashboard.CohortQue
 		Interval: query.Interval,
 		Periods:  query.Periods,
 		Metric:   query.Metric,
+		From:     formatQueryTime(query.From),
+		To:       formatQueryTime(query.To),
+		Filters:  query.Filters,
 	}
 	var resp cohortResponse
 	if err := c.do(ctx, http.MethodPost, "/cohorts/query", req, &resp); err != nil {
@@ -75,6 +81,8 @@ func (c *HTTPClient) FetchAlerts(ctx context.Context, query dashboard.AlertTrend
 		LookbackDays: query.LookbackDays,
 		Severities:   query.Severities,
 		Service:      query.Service,
+		From:         formatQueryTime(query.From),
+		To:           formatQueryTime(query.To),
 	}
 	var resp alertResponse
 	if err := c.do(ctx, http.MethodPost, "/alerts/query", req, &resp); err != nil {
@@ -116,9 +124,12 @@ func (c *HTTPClient) do(ctx context.Context, method, path string, payload any, t
 }
 
 type funnelRequest struct {
-	Range   string  `json:"range"`
-	Segment string  `json:"segment,omitempty"`
-	Goal    float64 `json:"goal"`
+	Range   string            `json:"range"`
+	Segment string            `json:"segment,omitempty"`
+	Goal    float64           `json:"goal"`
+	From    string            `json:"from,omitempty"`
+	To      string            `json:"to,omitempty"`
+	Filters map[string]string `json:"filters,omitempty"`
 }
 
 type funnelStep struct {
@@ -156,9 +167,12 @@ func (r funnelResponse) toReport() dashboard.FunnelReport {
 }
 
 type cohortRequest struct {
-	Interval string `json:"interval"`
-	Periods  int    `json:"periods"`
-	Metric   string `json:"metric"`
+	Interval string            `json:"interval"`
+	Periods  int               `json:"periods"`
+	Metric   string            `json:"metric"`
+	From     string            `json:"from,omitempty"`
+	To       string            `json:"to,omitempty"`
+	Filters  map[string]string `json:"filters,omitempty"`
 }
 
 type cohortRow struct {
@@ -193,6 +207,8 @@ type alertRequest struct {
 	LookbackDays int      `json:"lookback_days"`
 	Severities   []string `json:"severities"`
 	Service      string   `json:"service"`
+	From         string   `json:"from,omitempty"`
+	To           string   `json:"to,omitempty"`
 }
 
 type alertSeries struct {
@@ -222,3 +238,10 @@ func (r alertResponse) toReport() (dashboard.AlertTrendsReport, error) {
 	}
 	return dashboard.AlertTrendsReport{Service: r.Service, Series: series, Totals: totals}, nil
 }
+
+func formatQueryTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}